@@ -3,6 +3,7 @@ package incus
 import (
 	"context"
 	"errors"
+	"slices"
 	"sync"
 
 	"github.com/lxc/incus/v6/shared/api"
@@ -17,6 +18,12 @@ type EventListener struct {
 
 	// projectName stores which project this event listener is associated with (empty for all projects).
 	projectName string
+
+	// stream identifies the event connection this listener is attached to.
+	stream string
+
+	// pending holds the replayed events received before the first handler was added.
+	pending     []api.Event
 	targets     []*EventTarget
 	targetsLock sync.Mutex
 }
@@ -46,6 +53,17 @@ func (e *EventListener) AddHandler(types []string, function func(api.Event)) (*E
 	// And add it to the targets
 	e.targets = append(e.targets, &target)
 
+	// Deliver the replayed events received so far.
+	for _, event := range e.pending {
+		if target.types != nil && !slices.Contains(target.types, event.Type) {
+			continue
+		}
+
+		go target.function(event)
+	}
+
+	e.pending = nil
+
 	return &target, nil
 }
 
@@ -83,11 +101,11 @@ func (e *EventListener) Disconnect() {
 	}
 
 	// Locate and remove it from the global list
-	for i, listener := range e.r.eventListeners[e.projectName] {
+	for i, listener := range e.r.eventListeners[e.stream] {
 		if listener == e {
-			copy(e.r.eventListeners[e.projectName][i:], e.r.eventListeners[e.projectName][i+1:])
-			e.r.eventListeners[e.projectName][len(e.r.eventListeners[e.projectName])-1] = nil
-			e.r.eventListeners[e.projectName] = e.r.eventListeners[e.projectName][:len(e.r.eventListeners[e.projectName])-1]
+			copy(e.r.eventListeners[e.stream][i:], e.r.eventListeners[e.stream][i+1:])
+			e.r.eventListeners[e.stream][len(e.r.eventListeners[e.stream])-1] = nil
+			e.r.eventListeners[e.stream] = e.r.eventListeners[e.stream][:len(e.r.eventListeners[e.stream])-1]
 			break
 		}
 	}
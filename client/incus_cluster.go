@@ -245,6 +245,35 @@ func (r *ProtocolIncus) UpdateClusterMemberState(name string, state api.ClusterM
 	return op, nil
 }
 
+// CreateClusterRollingRestart starts restarting the cluster members one at a time.
+func (r *ProtocolIncus) CreateClusterRollingRestart(req api.ClusterRollingRestartPost) (Operation, error) {
+	if !r.HasExtension("cluster_rolling_restart") {
+		return nil, errors.New("The server is missing the required \"cluster_rolling_restart\" API extension")
+	}
+
+	op, _, err := r.queryOperation("POST", "/cluster/rolling-restart", req, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+// UpdateClusterRollingRestart pauses, resumes or aborts the running rolling restart.
+// The request must be sent to the cluster member running the rolling restart (see UseTarget).
+func (r *ProtocolIncus) UpdateClusterRollingRestart(req api.ClusterRollingRestartPut) error {
+	if !r.HasExtension("cluster_rolling_restart") {
+		return errors.New("The server is missing the required \"cluster_rolling_restart\" API extension")
+	}
+
+	_, _, err := r.query("PUT", "/cluster/rolling-restart", req, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // GetClusterGroups returns the cluster groups.
 func (r *ProtocolIncus) GetClusterGroups() ([]api.ClusterGroup, error) {
 	if !r.HasExtension("clustering_groups") {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"slices"
 	"time"
 
@@ -15,7 +16,15 @@ import (
 // Event handling functions
 
 // getEvents connects to the Incus monitoring interface.
-func (r *ProtocolIncus) getEvents(allProjects bool) (*EventListener, error) {
+// When replay is set, the events journaled after the since ID are received first, over a dedicated connection.
+func (r *ProtocolIncus) getEvents(allProjects bool, replay bool, since uint64) (*EventListener, error) {
+	if replay {
+		err := r.CheckExtension("event_journal")
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Prevent anything else from interacting with the listeners
 	r.eventListenersLock.Lock()
 	defer r.eventListenersLock.Unlock()
@@ -41,21 +50,38 @@ func (r *ProtocolIncus) getEvents(allProjects bool) (*EventListener, error) {
 		listener.projectName = connInfo.Project
 	}
 
+	listener.stream = listener.projectName
+
+	// Replayed events are specific to this listener, so don't share its connection.
+	if replay {
+		listener.stream = fmt.Sprintf("%s/since=%d/%p", listener.projectName, since, &listener)
+		listener.pending = []api.Event{}
+	}
+
 	// There is an existing Go routine for the required project filter, so just add another target.
-	if r.eventListeners[listener.projectName] != nil {
-		r.eventListeners[listener.projectName] = append(r.eventListeners[listener.projectName], &listener)
+	if r.eventListeners[listener.stream] != nil {
+		r.eventListeners[listener.stream] = append(r.eventListeners[listener.stream], &listener)
 		return &listener, nil
 	}
 
 	// Setup a new connection with Incus
-	var url string
-	var err error
+	path := "/events"
 	if allProjects {
-		url, err = r.setQueryAttributes("/events?all-projects=true")
-	} else {
-		url, err = r.setQueryAttributes("/events")
+		path += "?all-projects=true"
+	}
+
+	if replay {
+		if allProjects {
+			path += "&"
+		} else {
+			path += "?"
+		}
+
+		path += fmt.Sprintf("since=%d", since)
 	}
 
+	url, err := r.setQueryAttributes(path)
+
 	if err != nil {
 		return nil, err
 	}
@@ -67,11 +93,11 @@ func (r *ProtocolIncus) getEvents(allProjects bool) (*EventListener, error) {
 	}
 
 	r.eventConnsLock.Lock()
-	r.eventConns[listener.projectName] = wsConn // Save for others to use.
+	r.eventConns[listener.stream] = wsConn // Save for others to use.
 	r.eventConnsLock.Unlock()
 
 	// Initialize the event listener list if we were able to connect to the events websocket.
-	r.eventListeners[listener.projectName] = []*EventListener{&listener}
+	r.eventListeners[listener.stream] = []*EventListener{&listener}
 
 	// Spawn a watcher that will close the websocket connection after all
 	// listeners are gone.
@@ -86,14 +112,14 @@ func (r *ProtocolIncus) getEvents(allProjects bool) (*EventListener, error) {
 
 			r.eventListenersLock.Lock()
 			r.eventConnsLock.Lock()
-			if len(r.eventListeners[listener.projectName]) == 0 {
+			if len(r.eventListeners[listener.stream]) == 0 {
 				// We don't need the connection anymore, disconnect and clear.
-				if r.eventListeners[listener.projectName] != nil {
-					_ = r.eventConns[listener.projectName].Close()
-					delete(r.eventConns, listener.projectName)
+				if r.eventListeners[listener.stream] != nil {
+					_ = r.eventConns[listener.stream].Close()
+					delete(r.eventConns, listener.stream)
 				}
 
-				r.eventListeners[listener.projectName] = nil
+				r.eventListeners[listener.stream] = nil
 				r.eventListenersLock.Unlock()
 				r.eventConnsLock.Unlock()
 
@@ -115,14 +141,14 @@ func (r *ProtocolIncus) getEvents(allProjects bool) (*EventListener, error) {
 				defer r.eventListenersLock.Unlock()
 
 				// Tell all the current listeners about the failure
-				for _, listener := range r.eventListeners[listener.projectName] {
+				for _, listener := range r.eventListeners[listener.stream] {
 					listener.err = err
 					listener.ctxCancel()
 				}
 
 				// And remove them all from the list so that when watcher routine runs it will
 				// close the websocket connection.
-				r.eventListeners[listener.projectName] = nil
+				r.eventListeners[listener.stream] = nil
 
 				close(stopCh) // Instruct watcher go routine to cleanup.
 
@@ -143,8 +169,16 @@ func (r *ProtocolIncus) getEvents(allProjects bool) (*EventListener, error) {
 
 			// Send the message to all handlers
 			r.eventListenersLock.Lock()
-			for _, listener := range r.eventListeners[listener.projectName] {
+			for _, listener := range r.eventListeners[listener.stream] {
 				listener.targetsLock.Lock()
+
+				// Hold replayed events until a handler is added.
+				if listener.pending != nil && len(listener.targets) == 0 {
+					listener.pending = append(listener.pending, event)
+					listener.targetsLock.Unlock()
+					continue
+				}
+
 				for _, target := range listener.targets {
 					if target.types != nil && !slices.Contains(target.types, event.Type) {
 						continue
@@ -165,12 +199,24 @@ func (r *ProtocolIncus) getEvents(allProjects bool) (*EventListener, error) {
 
 // GetEvents gets the events for the project defined on the client.
 func (r *ProtocolIncus) GetEvents() (*EventListener, error) {
-	return r.getEvents(false)
+	return r.getEvents(false, false, 0)
 }
 
 // GetEventsAllProjects gets events for all projects.
 func (r *ProtocolIncus) GetEventsAllProjects() (*EventListener, error) {
-	return r.getEvents(true)
+	return r.getEvents(true, false, 0)
+}
+
+// GetEventsSince gets the events for the project defined on the client, starting with the
+// journaled events following the given event ID.
+func (r *ProtocolIncus) GetEventsSince(since uint64) (*EventListener, error) {
+	return r.getEvents(false, true, since)
+}
+
+// GetEventsAllProjectsSince gets events for all projects, starting with the journaled events
+// following the given event ID.
+func (r *ProtocolIncus) GetEventsAllProjectsSince(since uint64) (*EventListener, error) {
+	return r.getEvents(true, true, since)
 }
 
 // SendEvent send an event to the server via the client's event listener connection.
@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
 	"slices"
 	"strings"
 	"time"
@@ -592,6 +593,14 @@ func (r *ProtocolIncus) CreateImage(image api.ImagesPost, args *ImageCreateArgs)
 		req.Header.Set("X-Incus-aliases", imgProfiles.Encode())
 	}
 
+	if args.OCIReference != "" {
+		if !r.HasExtension("image_import_oci") {
+			return nil, errors.New("The server is missing the required \"image_import_oci\" API extension")
+		}
+
+		req.Header.Set("X-Incus-oci-reference", args.OCIReference)
+	}
+
 	// Set the user agent
 	if image.Source != nil && image.Source.Fingerprint != "" && image.Source.Secret != "" && image.Source.Mode == "push" {
 		// Set fingerprint
@@ -760,6 +769,29 @@ func (r *ProtocolIncus) CopyImage(source ImageServer, image api.Image, args *Ima
 		return nil, err
 	}
 
+	// Local OCI layouts and archives can't be reached by the server, upload them instead.
+	if info.Protocol == "oci" && IsLocalOCI(info.URL) {
+		imagePost := api.ImagesPost{}
+		imagePost.Profiles = image.Profiles
+
+		if args != nil {
+			imagePost.Public = args.Public
+			imagePost.Aliases = args.Aliases
+		}
+
+		rop := remoteOperation{
+			chDone: make(chan bool),
+		}
+
+		go func() {
+			defer close(rop.chDone)
+
+			rop.err = r.uploadLocalOCIImage(info.URL, image, imagePost, &rop)
+		}()
+
+		return &rop, nil
+	}
+
 	// Push mode
 	if args != nil && args.Mode == "push" {
 		// Get certificate and URL
@@ -984,6 +1016,41 @@ func (r *ProtocolIncus) CopyImage(source ImageServer, image api.Image, args *Ima
 	return r.tryCopyImage(req, info.Addresses)
 }
 
+// uploadLocalOCIImage uploads an image from a local OCI layout or archive, letting the server unpack it.
+// The upload operation is attached to the provided remote operation.
+func (r *ProtocolIncus) uploadLocalOCIImage(uri string, image api.Image, imagePost api.ImagesPost, rop *remoteOperation) error {
+	if !r.HasExtension("image_import_oci") {
+		return errors.New("The server is missing the required \"image_import_oci\" API extension")
+	}
+
+	archive, err := GetOCIArchive(uri)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = archive.Close() }()
+
+	op, err := r.CreateImage(imagePost, &ImageCreateArgs{
+		MetaFile:     archive,
+		MetaName:     filepath.Base(uri),
+		Type:         image.Type,
+		OCIReference: image.Properties["id"],
+	})
+	if err != nil {
+		return err
+	}
+
+	rop.handlerLock.Lock()
+	rop.targetOp = op
+	rop.handlerLock.Unlock()
+
+	for _, handler := range rop.handlers {
+		_, _ = rop.targetOp.AddHandler(handler)
+	}
+
+	return rop.targetOp.Wait()
+}
+
 // UpdateImage updates the image definition.
 func (r *ProtocolIncus) UpdateImage(fingerprint string, image api.ImagePut, ETag string) error {
 	// Send the request
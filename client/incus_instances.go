@@ -706,6 +706,16 @@ func (r *ProtocolIncus) tryCreateInstance(req api.InstancesPost, urls []string,
 
 // CreateInstanceFromImage is a convenience function to make it easier to create a instance from an existing image.
 func (r *ProtocolIncus) CreateInstanceFromImage(source ImageServer, image api.Image, req api.InstancesPost) (RemoteOperation, error) {
+	// Local OCI layouts and archives can't be reached by the server, upload the image first.
+	sourceInfo, err := source.GetConnectionInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	if sourceInfo.Protocol == "oci" && IsLocalOCI(sourceInfo.URL) {
+		return r.createInstanceFromLocalOCI(sourceInfo.URL, image, req)
+	}
+
 	info, err := r.getSourceImageConnectionInfo(source, image, &req.Source)
 	if err != nil {
 		return nil, err
@@ -735,6 +745,50 @@ func (r *ProtocolIncus) CreateInstanceFromImage(source ImageServer, image api.Im
 	return r.tryCreateInstance(req, info.Addresses, nil)
 }
 
+// createInstanceFromLocalOCI creates an instance from an image in a local OCI layout or archive.
+// The image is uploaded to the server unless it already has it.
+func (r *ProtocolIncus) createInstanceFromLocalOCI(uri string, image api.Image, req api.InstancesPost) (RemoteOperation, error) {
+	rop := remoteOperation{
+		chDone: make(chan bool),
+	}
+
+	go func() {
+		defer close(rop.chDone)
+
+		_, _, err := r.GetImage(image.Fingerprint)
+		if err != nil {
+			err = r.uploadLocalOCIImage(uri, image, api.ImagesPost{}, &rop)
+			if err != nil {
+				rop.err = fmt.Errorf("Failed to upload image: %w", err)
+				return
+			}
+		}
+
+		req.Source = api.InstanceSource{
+			Type:        "image",
+			Fingerprint: image.Fingerprint,
+		}
+
+		op, err := r.CreateInstance(req)
+		if err != nil {
+			rop.err = err
+			return
+		}
+
+		rop.handlerLock.Lock()
+		rop.targetOp = op
+		rop.handlerLock.Unlock()
+
+		for _, handler := range rop.handlers {
+			_, _ = rop.targetOp.AddHandler(handler)
+		}
+
+		rop.err = rop.targetOp.Wait()
+	}()
+
+	return &rop, nil
+}
+
 // CopyInstance copies a instance from a remote server. Additional options can be passed using InstanceCopyArgs.
 func (r *ProtocolIncus) CopyInstance(source InstanceServer, instance api.Instance, args *InstanceCopyArgs) (RemoteOperation, error) {
 	// Base request
@@ -2867,6 +2921,10 @@ func (r *ProtocolIncus) CreateInstanceBackup(instanceName string, backup api.Ins
 		return nil, errors.New("The server is missing the required \"container_backup\" API extension")
 	}
 
+	if backup.Parent != "" && !r.HasExtension("backup_incremental") {
+		return nil, errors.New("The server is missing the required \"backup_incremental\" API extension")
+	}
+
 	// Send the request
 	op, _, err := r.queryOperation("POST", fmt.Sprintf("%s/%s/backups", path, url.PathEscape(instanceName)), backup, "")
 	if err != nil {
@@ -832,6 +832,23 @@ func (r *ProtocolIncus) RenameStoragePoolVolume(pool string, volType string, nam
 	return nil
 }
 
+// ReplicateStoragePoolVolume triggers a replication action (sync or promote) on a storage volume.
+func (r *ProtocolIncus) ReplicateStoragePoolVolume(pool string, volType string, name string, replication api.StorageVolumeReplicationPost) (Operation, error) {
+	if !r.HasExtension("storage_volume_replication") {
+		return nil, errors.New("The server is missing the required \"storage_volume_replication\" API extension")
+	}
+
+	path := fmt.Sprintf("/storage-pools/%s/volumes/%s/%s/replication", url.PathEscape(pool), url.PathEscape(volType), url.PathEscape(name))
+
+	// Send the request
+	op, _, err := r.queryOperation("POST", path, replication, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
 // GetStorageVolumeBackupNames returns a list of volume backup names.
 func (r *ProtocolIncus) GetStorageVolumeBackupNames(pool string, volName string) ([]string, error) {
 	if !r.HasExtension("custom_volume_backup") {
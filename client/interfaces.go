@@ -175,6 +175,8 @@ type InstanceServer interface {
 	// Event handling functions
 	GetEvents() (listener *EventListener, err error)
 	GetEventsAllProjects() (listener *EventListener, err error)
+	GetEventsSince(since uint64) (listener *EventListener, err error)
+	GetEventsAllProjectsSince(since uint64) (listener *EventListener, err error)
 	SendEvent(event api.Event) error
 
 	// Image functions
@@ -365,6 +367,7 @@ type InstanceServer interface {
 	CopyStoragePoolVolume(pool string, source InstanceServer, sourcePool string, volume api.StorageVolume, args *StoragePoolVolumeCopyArgs) (op RemoteOperation, err error)
 	MoveStoragePoolVolume(pool string, source InstanceServer, sourcePool string, volume api.StorageVolume, args *StoragePoolVolumeMoveArgs) (op RemoteOperation, err error)
 	MigrateStoragePoolVolume(pool string, volume api.StorageVolumePost) (op Operation, err error)
+	ReplicateStoragePoolVolume(pool string, volType string, name string, replication api.StorageVolumeReplicationPost) (op Operation, err error)
 
 	// Storage volume snapshot functions ("storage_api_volume_snapshots" API extension)
 	CreateStoragePoolVolumeSnapshot(pool string, volumeType string, volumeName string, snapshot api.StorageVolumeSnapshotsPost) (op Operation, err error)
@@ -408,6 +411,8 @@ type InstanceServer interface {
 	UpdateClusterCertificate(certs api.ClusterCertificatePut, ETag string) (err error)
 	GetClusterMemberState(name string) (*api.ClusterMemberState, string, error)
 	UpdateClusterMemberState(name string, state api.ClusterMemberStatePost) (op Operation, err error)
+	CreateClusterRollingRestart(req api.ClusterRollingRestartPost) (op Operation, err error)
+	UpdateClusterRollingRestart(req api.ClusterRollingRestartPut) (err error)
 	GetClusterGroups() ([]api.ClusterGroup, error)
 	GetClusterGroupNames() ([]string, error)
 	RenameClusterGroup(name string, group api.ClusterGroupPost) error
@@ -477,6 +482,9 @@ type ImageCreateArgs struct {
 
 	// Type of the image (container or virtual-machine)
 	Type string
+
+	// Reference of the image to import when MetaFile is an OCI archive (optional)
+	OCIReference string
 }
 
 // The ImageFileRequest struct is used for an image download request.
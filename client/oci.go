@@ -1,8 +1,15 @@
 package incus
 
 import (
+	"archive/tar"
 	"errors"
+	"fmt"
+	"io"
+	"io/fs"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
 )
 
 // ProtocolOCI implements an OCI registry API client.
@@ -18,6 +25,85 @@ type ProtocolOCI struct {
 	tempPath string
 }
 
+// IsLocalOCI returns whether the URL points to a local OCI layout directory ("oci:") or archive ("oci-archive:")
+// rather than to an OCI registry.
+func IsLocalOCI(uri string) bool {
+	return strings.HasPrefix(uri, "oci:") || strings.HasPrefix(uri, "oci-archive:")
+}
+
+// GetOCIArchive returns an OCI archive (uncompressed tarball of an OCI image layout) for a local OCI layout
+// directory ("oci:<path>") or archive ("oci-archive:<path>").
+func GetOCIArchive(uri string) (io.ReadCloser, error) {
+	path, ok := strings.CutPrefix(uri, "oci-archive:")
+	if ok {
+		return os.Open(path)
+	}
+
+	path, ok = strings.CutPrefix(uri, "oci:")
+	if !ok {
+		return nil, fmt.Errorf("%q isn't a local OCI layout or archive", uri)
+	}
+
+	_, err := os.Stat(filepath.Join(path, "oci-layout"))
+	if err != nil {
+		return nil, fmt.Errorf("%q isn't an OCI layout: %w", path, err)
+	}
+
+	pipeRead, pipeWrite := io.Pipe()
+
+	go func() {
+		tw := tar.NewWriter(pipeWrite)
+
+		walkErr := filepath.WalkDir(path, func(filePath string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			name, err := filepath.Rel(path, filePath)
+			if err != nil || name == "." {
+				return err
+			}
+
+			fi, err := d.Info()
+			if err != nil {
+				return err
+			}
+
+			hdr, err := tar.FileInfoHeader(fi, "")
+			if err != nil {
+				return err
+			}
+
+			hdr.Name = filepath.ToSlash(name)
+			err = tw.WriteHeader(hdr)
+			if err != nil {
+				return err
+			}
+
+			if !fi.Mode().IsRegular() {
+				return nil
+			}
+
+			f, err := os.Open(filePath)
+			if err != nil {
+				return err
+			}
+
+			defer func() { _ = f.Close() }()
+
+			_, err = io.Copy(tw, f)
+			return err
+		})
+		if walkErr == nil {
+			walkErr = tw.Close()
+		}
+
+		_ = pipeWrite.CloseWithError(walkErr)
+	}()
+
+	return pipeRead, nil
+}
+
 // Disconnect is a no-op for OCI.
 func (r *ProtocolOCI) Disconnect() {
 }
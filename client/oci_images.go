@@ -16,6 +16,8 @@ import (
 	"strings"
 	"time"
 
+	ispec "github.com/opencontainers/image-spec/specs-go/v1"
+
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/ioprogress"
 	"github.com/lxc/incus/v6/shared/logger"
@@ -31,10 +33,21 @@ type ociInfo struct {
 	Created      time.Time `json:"Created"`
 	Architecture string    `json:"Architecture"`
 	LayersData   []struct {
-		Size int64 `json:"Size"`
+		MIMEType    string            `json:"MIMEType"`
+		Digest      string            `json:"Digest"`
+		Size        int64             `json:"Size"`
+		Annotations map[string]string `json:"Annotations"`
 	} `json:"LayersData"`
 }
 
+// OCIImageLayer represents a layer of an OCI image.
+type OCIImageLayer struct {
+	Digest      string
+	MediaType   string
+	Size        int64
+	Annotations map[string]string
+}
+
 // Get the proxy host value.
 func (r *ProtocolOCI) getProxyHost() (*url.URL, error) {
 	req, err := http.NewRequest("GET", r.httpHost, nil)
@@ -319,6 +332,32 @@ func (r *ProtocolOCI) GetImageAliasNames() ([]string, error) {
 }
 
 func (r *ProtocolOCI) runSkopeo(action string, image string, args ...string) (string, error) {
+	return r.runSkopeoWithRef(image, func(ref string) []string {
+		return append([]string{action, ref}, args...)
+	})
+}
+
+// runSkopeoWithRef runs skopeo with the arguments returned by getArgs, which is passed the registry reference of the image.
+func (r *ProtocolOCI) runSkopeoWithRef(image string, getArgs func(ref string) []string) (string, error) {
+	var args []string
+
+	// Local layouts and archives are referenced directly, optionally followed by the image reference.
+	if IsLocalOCI(r.httpHost) {
+		ref := r.httpHost
+		if image != "" {
+			ref = fmt.Sprintf("%s:%s", ref, image)
+		}
+
+		args = append([]string{"--insecure-policy"}, getArgs(ref)...)
+
+		stdout, _, err := subprocess.RunCommandSplit(context.TODO(), nil, nil, "skopeo", args...)
+		if err != nil {
+			return "", err
+		}
+
+		return stdout, nil
+	}
+
 	// Parse and mangle the server URL.
 	uri, err := url.Parse(r.httpHost)
 	if err != nil {
@@ -377,7 +416,7 @@ func (r *ProtocolOCI) runSkopeo(action string, image string, args ...string) (st
 
 	// Prepare the arguments.
 	uri.Scheme = "docker"
-	args = append([]string{"--insecure-policy", action, fmt.Sprintf("%s/%s", uri.String(), image)}, args...)
+	args = append(append([]string{"--insecure-policy"}, getArgs(fmt.Sprintf("%s/%s", uri.String(), image))...), args...)
 
 	// Get the image information from skopeo.
 	stdout, _, err := subprocess.RunCommandSplit(
@@ -393,6 +432,97 @@ func (r *ProtocolOCI) runSkopeo(action string, image string, args ...string) (st
 	return stdout, nil
 }
 
+// PushImage pushes the image tagged with tag in the OCI layout at layoutPath to the registry as name.
+// It returns the digest of the pushed manifest.
+func (r *ProtocolOCI) PushImage(layoutPath string, tag string, name string) (string, error) {
+	_, err := exec.LookPath("skopeo")
+	if err != nil {
+		return "", errors.New("OCI image push requires \"skopeo\" be present on the system")
+	}
+
+	uri, err := url.Parse(r.httpHost)
+	if err != nil {
+		return "", err
+	}
+
+	digestFile, err := os.CreateTemp(r.tempPath, "incus_oci_digest_")
+	if err != nil {
+		return "", err
+	}
+
+	_ = digestFile.Close()
+	defer func() { _ = os.Remove(digestFile.Name()) }()
+
+	stdout, err := r.runSkopeoWithRef(name, func(ref string) []string {
+		args := []string{"copy", fmt.Sprintf("--digestfile=%s", digestFile.Name())}
+
+		// Plain HTTP registries are only expected for local testing.
+		if uri.Scheme == "http" {
+			args = append(args, "--dest-tls-verify=false")
+		}
+
+		return append(args, fmt.Sprintf("oci:%s:%s", layoutPath, tag), ref)
+	})
+	if err != nil {
+		logger.Debug("Error pushing image to registry", logger.Ctx{"image": name, "stdout": stdout, "stderr": err})
+		return "", err
+	}
+
+	digest, err := os.ReadFile(digestFile.Name())
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(digest)), nil
+}
+
+// GetImageLayers returns the canonical reference of the image with the provided fingerprint along with its layers,
+// from the bottom one up.
+func (r *ProtocolOCI) GetImageLayers(fingerprint string) (string, []OCIImageLayer, error) {
+	info, ok := r.cache[fingerprint]
+	if !ok {
+		return "", nil, errors.New("Image not found")
+	}
+
+	if IsLocalOCI(r.httpHost) {
+		return "", nil, errors.New("Layers can only be retrieved from OCI registries")
+	}
+
+	layers := make([]OCIImageLayer, 0, len(info.LayersData))
+	for _, layer := range info.LayersData {
+		layers = append(layers, OCIImageLayer{
+			Digest:      layer.Digest,
+			MediaType:   layer.MIMEType,
+			Size:        layer.Size,
+			Annotations: layer.Annotations,
+		})
+	}
+
+	return fmt.Sprintf("%s@sha256:%s", info.Name, fingerprint), layers, nil
+}
+
+// GetImageConfig returns the OCI configuration of the image with the provided fingerprint.
+func (r *ProtocolOCI) GetImageConfig(fingerprint string) (*ispec.Image, error) {
+	info, ok := r.cache[fingerprint]
+	if !ok {
+		return nil, errors.New("Image not found")
+	}
+
+	stdout, err := r.runSkopeo("inspect", info.Alias, "--config")
+	if err != nil {
+		logger.Debug("Error getting image configuration", logger.Ctx{"image": info.Alias, "stdout": stdout, "stderr": err})
+		return nil, err
+	}
+
+	var config ispec.Image
+	err = json.Unmarshal([]byte(stdout), &config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
 // GetImageAlias returns an existing alias as an ImageAliasesEntry struct.
 func (r *ProtocolOCI) GetImageAlias(name string) (*api.ImageAliasesEntry, string, error) {
 	// Get the image information from skopeo.
@@ -412,6 +542,14 @@ func (r *ProtocolOCI) GetImageAlias(name string) (*api.ImageAliasesEntry, string
 	info.Alias = name
 	info.Digest = strings.Replace(info.Digest, "sha256:", "", 1)
 
+	// Local layouts and archives don't carry a repository name.
+	if info.Name == "" {
+		info.Name = name
+		if info.Name == "" {
+			info.Name = filepath.Base(r.httpHost)
+		}
+	}
+
 	archID, err := osarch.ArchitectureID(info.Architecture)
 	if err != nil {
 		return nil, "", err
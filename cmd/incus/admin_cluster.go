@@ -26,6 +26,10 @@ func (c *cmdAdminCluster) Command() *cobra.Command {
 	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
 		`Low level administration tools for inspecting and recovering clusters.`))
 
+	// Rolling restart
+	adminClusterRollingRestartCmd := cmdAdminClusterRollingRestart{global: c.global}
+	cmd.AddCommand(adminClusterRollingRestartCmd.Command())
+
 	cmd.Run = c.Run
 	return cmd
 }
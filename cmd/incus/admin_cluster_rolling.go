@@ -0,0 +1,304 @@
+//go:build linux
+
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	incus "github.com/lxc/incus/v6/client"
+	cli "github.com/lxc/incus/v6/internal/cmd"
+	"github.com/lxc/incus/v6/internal/i18n"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+type cmdAdminClusterRollingRestart struct {
+	global *cmdGlobal
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdAdminClusterRollingRestart) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("rolling-restart")
+	cmd.Short = i18n.G("Restart or upgrade the cluster members one at a time")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Restart or upgrade the cluster members one at a time
+
+Each cluster member is evacuated, restarted (or upgraded), checked and restored before moving on to the next one.`))
+
+	// Start
+	adminClusterRollingRestartStartCmd := cmdAdminClusterRollingRestartStart{global: c.global}
+	cmd.AddCommand(adminClusterRollingRestartStartCmd.Command())
+
+	// Show
+	adminClusterRollingRestartShowCmd := cmdAdminClusterRollingRestartShow{global: c.global}
+	cmd.AddCommand(adminClusterRollingRestartShowCmd.Command())
+
+	// Pause, resume and abort
+	for _, action := range []string{"pause", "resume", "abort"} {
+		adminClusterRollingRestartActionCmd := cmdAdminClusterRollingRestartAction{global: c.global, action: action}
+		cmd.AddCommand(adminClusterRollingRestartActionCmd.Command())
+	}
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, _ []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// adminClusterRollingRestartFind returns the running rolling restart operation.
+func adminClusterRollingRestartFind(d incus.InstanceServer) (*api.Operation, error) {
+	ops, err := d.GetOperations()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, op := range ops {
+		if op.StatusCode != api.Running {
+			continue
+		}
+
+		_, ok := op.Metadata["rolling_restart"]
+		if ok {
+			return &op, nil
+		}
+	}
+
+	return nil, errors.New(i18n.G("No rolling restart is running"))
+}
+
+// Start.
+type cmdAdminClusterRollingRestartStart struct {
+	global *cmdGlobal
+
+	flagUpgrade bool
+	flagMembers []string
+	flagAction  string
+	flagTimeout int
+	flagForce   bool
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdAdminClusterRollingRestartStart) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("start", i18n.G("[<remote>:]"))
+	cmd.Short = i18n.G("Start a rolling restart of the cluster")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Start a rolling restart of the cluster
+
+By default, the server restarts each cluster member itself.
+With --upgrade, the server instead waits for each evacuated member to be upgraded and restarted externally.`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus admin cluster rolling-restart start
+    Restart all the cluster members one at a time.
+
+incus admin cluster rolling-restart start --upgrade --members server01,server02
+    Evacuate server01 and server02 in turn, waiting for each of them to be upgraded before restoring it.`))
+
+	cmd.Flags().BoolVar(&c.flagUpgrade, "upgrade", false, i18n.G("Wait for the members to be upgraded and restarted externally"))
+	cmd.Flags().StringSliceVar(&c.flagMembers, "members", nil, i18n.G("Cluster members to process, in order")+"``")
+	cmd.Flags().StringVar(&c.flagAction, "action", "", i18n.G(`Force a particular evacuation action`)+"``")
+	cmd.Flags().IntVar(&c.flagTimeout, "timeout", 0, i18n.G("Time to wait for each member to come back, in seconds")+"``")
+	cmd.Flags().BoolVar(&c.flagForce, "force", false, i18n.G(`Start the rolling restart without user confirmation`)+"``")
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdAdminClusterRollingRestartStart) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	remote := ""
+	if len(args) > 0 {
+		remote = args[0]
+	}
+
+	resources, err := c.global.parseServers(remote)
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if !c.flagForce {
+		proceed, err := c.global.asker.AskBool(i18n.G("Are you sure you want to restart all the requested cluster members one at a time? (yes/no) [default=no]: "), "no")
+		if err != nil {
+			return err
+		}
+
+		if !proceed {
+			return nil
+		}
+	}
+
+	req := api.ClusterRollingRestartPost{
+		Mode:         "restart",
+		Members:      c.flagMembers,
+		EvacuateMode: c.flagAction,
+		Timeout:      c.flagTimeout,
+	}
+
+	if c.flagUpgrade {
+		req.Mode = "upgrade"
+	}
+
+	op, err := resource.server.CreateClusterRollingRestart(req)
+	if err != nil {
+		return err
+	}
+
+	progress := cli.ProgressRenderer{
+		Format: i18n.G("Restarting cluster members: %s"),
+		Quiet:  c.global.flagQuiet,
+	}
+
+	_, err = op.AddHandler(progress.UpdateOp)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	err = op.Wait()
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	progress.Done("")
+
+	// Report the final state of each member.
+	data, err := yaml.Marshal(op.Get().Metadata["rolling_restart"])
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s", data)
+
+	return nil
+}
+
+// Show.
+type cmdAdminClusterRollingRestartShow struct {
+	global *cmdGlobal
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdAdminClusterRollingRestartShow) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("show", i18n.G("[<remote>:]"))
+	cmd.Short = i18n.G("Show the progress of the running rolling restart")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Show the progress of the running rolling restart`))
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdAdminClusterRollingRestartShow) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	remote := ""
+	if len(args) > 0 {
+		remote = args[0]
+	}
+
+	resources, err := c.global.parseServers(remote)
+	if err != nil {
+		return err
+	}
+
+	op, err := adminClusterRollingRestartFind(resources[0].server)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(op.Metadata["rolling_restart"])
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf(i18n.G("Coordinated by: %s")+"\n", op.Location)
+	fmt.Printf("%s", data)
+
+	return nil
+}
+
+// Pause, resume and abort.
+type cmdAdminClusterRollingRestartAction struct {
+	global *cmdGlobal
+	action string
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdAdminClusterRollingRestartAction) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage(c.action, i18n.G("[<remote>:]"))
+
+	switch c.action {
+	case "pause":
+		cmd.Short = i18n.G("Pause the running rolling restart")
+		cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+			`Pause the running rolling restart
+
+The cluster member being processed is completed before pausing.`))
+	case "resume":
+		cmd.Short = i18n.G("Resume the paused rolling restart")
+		cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+			`Resume the paused rolling restart`))
+	case "abort":
+		cmd.Short = i18n.G("Abort the running rolling restart")
+		cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+			`Abort the running rolling restart
+
+The cluster member being processed is left in its current state.`))
+	}
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdAdminClusterRollingRestartAction) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	remote := ""
+	if len(args) > 0 {
+		remote = args[0]
+	}
+
+	resources, err := c.global.parseServers(remote)
+	if err != nil {
+		return err
+	}
+
+	d := resources[0].server
+
+	op, err := adminClusterRollingRestartFind(d)
+	if err != nil {
+		return err
+	}
+
+	// The request has to be handled by the member coordinating the rolling restart.
+	return d.UseTarget(op.Location).UpdateClusterRollingRestart(api.ClusterRollingRestartPut{Action: c.action})
+}
@@ -680,7 +680,10 @@ func (c *cmdImageImport) Command() *cobra.Command {
 	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
 		`Import image into the image store
 
-Directory import is only available on Linux and must be performed as root.`))
+Directory import is only available on Linux and must be performed as root.
+
+OCI images can be imported from an OCI layout directory with "oci:<path>[:<reference>]"
+or from an OCI archive with "oci-archive:<path>[:<reference>]".`))
 
 	cmd.Flags().BoolVar(&c.flagPublic, "public", false, i18n.G("Make image public"))
 	cmd.Flags().BoolVar(&c.flagReuse, "reuse", false, i18n.G("If the image alias already exists, delete and create a new one"))
@@ -819,6 +822,38 @@ func (c *cmdImageImport) Run(cmd *cobra.Command, args []string) error {
 		image.Source.Protocol = "direct"
 		image.Source.URL = imageFile
 		createArgs = nil
+	} else if incus.IsLocalOCI(imageFile) {
+		// OCI layouts and archives are sent as an OCI archive for the server to unpack.
+		if !d.HasExtension("image_import_oci") {
+			return errors.New(i18n.G("The server doesn't support importing OCI images"))
+		}
+
+		var uri string
+		var reference string
+
+		uri, reference, err = parseLocalOCI(imageFile)
+		if err != nil {
+			return err
+		}
+
+		var archive io.ReadCloser
+
+		archive, err = incus.GetOCIArchive(uri)
+		if err != nil {
+			return err
+		}
+
+		defer func() { _ = archive.Close() }()
+
+		createArgs = &incus.ImageCreateArgs{
+			MetaFile:        archive,
+			MetaName:        filepath.Base(uri),
+			ProgressHandler: progress.UpdateProgress,
+			Type:            imageType,
+			OCIReference:    reference,
+		}
+
+		image.Filename = createArgs.MetaName
 	} else {
 		var meta io.ReadCloser
 		var rootfs io.ReadCloser
@@ -1,8 +1,10 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -12,6 +14,7 @@ import (
 	"github.com/lxc/incus/v6/internal/i18n"
 	"github.com/lxc/incus/v6/shared/ioprogress"
 	"github.com/lxc/incus/v6/shared/units"
+	"github.com/lxc/incus/v6/shared/util"
 )
 
 type cmdImport struct {
@@ -23,13 +26,18 @@ type cmdImport struct {
 // Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
 func (c *cmdImport) Command() *cobra.Command {
 	cmd := &cobra.Command{}
-	cmd.Use = usage("import", i18n.G("[<remote>:] <backup file> [<instance name>]"))
+	cmd.Use = usage("import", i18n.G("[<remote>:] <backup file> [<incremental backup file>...] [<instance name>]"))
 	cmd.Short = i18n.G("Import instance backups")
 	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
-		`Import backups of instances including their snapshots.`))
+		`Import backups of instances including their snapshots.
+
+Incremental backups listed after the initial backup file are applied in order on top of the imported instance.`))
 	cmd.Example = cli.FormatSection("", i18n.G(
 		`incus import backup0.tar.gz
-    Create a new instance using backup0.tar.gz as the source.`))
+    Create a new instance using backup0.tar.gz as the source.
+
+incus import backup0.tar.gz backup1.tar.gz backup2.tar.gz c1
+    Create a new instance c1 from backup0.tar.gz and apply the incremental backups backup1.tar.gz and backup2.tar.gz to it.`))
 
 	cmd.RunE = c.Run
 	cmd.Flags().StringVarP(&c.flagStorage, "storage", "s", "", i18n.G("Storage pool name")+"``")
@@ -40,7 +48,7 @@ func (c *cmdImport) Command() *cobra.Command {
 // Run runs the actual command logic.
 func (c *cmdImport) Run(cmd *cobra.Command, args []string) error {
 	// Quick checks.
-	exit, err := c.global.checkArgs(cmd, args, 1, 3)
+	exit, err := c.global.checkArgs(cmd, args, 1, -1)
 	if exit {
 		return err
 	}
@@ -54,16 +62,25 @@ func (c *cmdImport) Run(cmd *cobra.Command, args []string) error {
 		srcFilePosition = 1
 	}
 
-	// Parse source file (this could be 1st or 2nd argument depending on whether a remote is specified first).
-	srcFile := ""
-	if len(args) >= srcFilePosition+1 {
-		srcFile = args[srcFilePosition]
+	// Parse source files (the first one is always a file, any following existing file is an incremental backup).
+	srcFiles := []string{args[srcFilePosition]}
+	instanceName := ""
+	for i, arg := range args[srcFilePosition+1:] {
+		if arg != "-" && !util.PathExists(arg) {
+			// Only the last argument may be an instance name.
+			if i != len(args[srcFilePosition+1:])-1 {
+				return fmt.Errorf(i18n.G("Backup file %q doesn't exist"), arg)
+			}
+
+			instanceName = arg
+			break
+		}
+
+		srcFiles = append(srcFiles, arg)
 	}
 
-	// Parse instance name.
-	instanceName := ""
-	if len(args) >= srcFilePosition+2 {
-		instanceName = args[srcFilePosition+1]
+	if len(srcFiles) > 1 && slices.Contains(srcFiles, "-") {
+		return errors.New(i18n.G("Reading from stdin is only supported when importing a single backup file"))
 	}
 
 	resources, err := c.global.parseServers(remote)
@@ -73,6 +90,20 @@ func (c *cmdImport) Run(cmd *cobra.Command, args []string) error {
 
 	resource := resources[0]
 
+	// Import the backups in order, incremental backups get applied on top of the previous ones.
+	for _, srcFile := range srcFiles {
+		err = c.importBackup(resource.server, srcFile, instanceName)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// importBackup imports a single backup file.
+func (c *cmdImport) importBackup(d incus.InstanceServer, srcFile string, instanceName string) error {
+	var err error
 	var file *os.File
 	if srcFile == "-" {
 		file = os.Stdin
@@ -110,7 +141,7 @@ func (c *cmdImport) Run(cmd *cobra.Command, args []string) error {
 		Name:     instanceName,
 	}
 
-	op, err := resource.server.CreateInstanceFromBackup(createArgs)
+	op, err := d.CreateInstanceFromBackup(createArgs)
 	if err != nil {
 		return err
 	}
@@ -1089,6 +1089,15 @@ func (c *cmdNetworkInfo) Run(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// WireGuard information.
+	if state.Wireguard != nil {
+		fmt.Println("")
+		fmt.Println(i18n.G("WireGuard:"))
+		fmt.Printf("  %s: %s\n", i18n.G("Interface"), state.Wireguard.Interface)
+		fmt.Printf("  %s: %s\n", i18n.G("Public key"), state.Wireguard.PublicKey)
+		fmt.Printf("  %s: %d\n", i18n.G("Listen port"), state.Wireguard.ListenPort)
+	}
+
 	return nil
 }
 
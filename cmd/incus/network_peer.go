@@ -328,7 +328,7 @@ type cmdNetworkPeerCreate struct {
 // Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
 func (c *cmdNetworkPeerCreate) Command() *cobra.Command {
 	cmd := &cobra.Command{}
-	cmd.Use = usage("create", i18n.G("[<remote>:]<network> <peer_name> <[target project/]<target network or integration> [key=value...]"))
+	cmd.Use = usage("create", i18n.G("[<remote>:]<network> <peer_name> [<[target project/]<target network or integration>] [key=value...]"))
 	cmd.Aliases = []string{"add"}
 	cmd.Short = i18n.G("Create new network peering")
 	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Create new network peering"))
@@ -340,11 +340,14 @@ incus network peer create default peer2 ovn-ic --type=remote
 
 incus network peer create default peer3 web/default < config.yaml
 	Create a new peering between network default in the current project and network default in the web project using the configuration
-	in the file config.yaml`))
+	in the file config.yaml
+
+incus network peer create wg0 site2 --type=wireguard wireguard.public_key=xTIBA5rboUvnH4htodjb6e697QjLERt1NAB4mZqp8Dg= wireguard.allowed_ips=10.2.0.0/24 wireguard.endpoint=203.0.113.2:51820
+    Create a new WireGuard peer on network "wg0" routing 10.2.0.0/24 to the remote site`))
 
 	cmd.RunE = c.Run
 
-	cmd.Flags().StringVar(&c.flagType, "type", "local", i18n.G("Type of peer (local or remote)")+"``")
+	cmd.Flags().StringVar(&c.flagType, "type", "local", i18n.G("Type of peer (local, remote or wireguard)")+"``")
 	cmd.Flags().StringVar(&c.flagDescription, "description", "", i18n.G("Peer description")+"``")
 
 	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -361,12 +364,17 @@ incus network peer create default peer3 web/default < config.yaml
 // Run runs the actual command logic.
 func (c *cmdNetworkPeerCreate) Run(cmd *cobra.Command, args []string) error {
 	// Quick checks.
-	exit, err := c.global.checkArgs(cmd, args, 3, -1)
+	minArgs := 3
+	if c.flagType == "wireguard" {
+		minArgs = 2
+	}
+
+	exit, err := c.global.checkArgs(cmd, args, minArgs, -1)
 	if exit {
 		return err
 	}
 
-	if !slices.Contains([]string{"local", "remote"}, c.flagType) {
+	if !slices.Contains([]string{"local", "remote", "wireguard"}, c.flagType) {
 		return errors.New(i18n.G("Invalid peer type"))
 	}
 
@@ -386,18 +394,23 @@ func (c *cmdNetworkPeerCreate) Run(cmd *cobra.Command, args []string) error {
 		return errors.New(i18n.G("Missing peer name"))
 	}
 
-	if args[2] == "" {
-		return errors.New(i18n.G("Missing target network or integration"))
-	}
+	// WireGuard peers have no target, their configuration directly follows the peer name.
+	configStart := 2
+	var targetProject, target string
+	if c.flagType != "wireguard" {
+		if args[2] == "" {
+			return errors.New(i18n.G("Missing target network or integration"))
+		}
 
-	targetParts := strings.SplitN(args[2], "/", 2)
+		targetParts := strings.SplitN(args[2], "/", 2)
+		if len(targetParts) == 2 {
+			targetProject = targetParts[0]
+			target = targetParts[1]
+		} else {
+			target = targetParts[0]
+		}
 
-	var targetProject, target string
-	if len(targetParts) == 2 {
-		targetProject = targetParts[0]
-		target = targetParts[1]
-	} else {
-		target = targetParts[0]
+		configStart = 3
 	}
 
 	// If stdin isn't a terminal, read yaml from it.
@@ -419,7 +432,7 @@ func (c *cmdNetworkPeerCreate) Run(cmd *cobra.Command, args []string) error {
 	}
 
 	// Get config filters from arguments.
-	for i := 3; i < len(args); i++ {
+	for i := configStart; i < len(args); i++ {
 		entry := strings.SplitN(args[i], "=", 2)
 		if len(entry) < 2 {
 			return fmt.Errorf(i18n.G("Bad key/value pair: %s"), args[i])
@@ -1156,7 +1156,7 @@ func (c *cmdProjectInfo) Run(cmd *cobra.Command, args []string) error {
 	}
 
 	// Render the output
-	byteLimits := []string{"disk", "memory"}
+	byteLimits := []string{"disk", "disk-bandwidth", "memory"}
 	data := [][]string{}
 	for k, v := range projectState.Resources {
 		shortKey := strings.SplitN(k, ".", 2)[0]
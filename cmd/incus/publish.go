@@ -100,19 +100,39 @@ func (c *cmdPublish) Run(cmd *cobra.Command, args []string) error {
 		return errors.New(i18n.G("There is no \"image name\".  Did you want an alias?"))
 	}
 
-	d, err := conf.GetInstanceServer(iRemote)
-	if err != nil {
-		return err
+	// OCI images are pushed straight to a registry remote.
+	isOCI := c.flagFormat == "oci"
+	if isOCI {
+		remote, ok := conf.Remotes[iRemote]
+		if !ok || remote.Protocol != "oci" {
+			return errors.New(i18n.G("OCI images can only be published to OCI registry remotes"))
+		}
+
+		if len(c.flagAliases) == 0 {
+			return errors.New(i18n.G("At least one --alias is required to name the OCI image"))
+		}
+	}
+
+	var d incus.InstanceServer
+	if !isOCI {
+		d, err = conf.GetInstanceServer(iRemote)
+		if err != nil {
+			return err
+		}
 	}
 
 	s := d
-	if cRemote != iRemote {
+	if cRemote != iRemote || isOCI {
 		s, err = conf.GetInstanceServer(cRemote)
 		if err != nil {
 			return err
 		}
 	}
 
+	if isOCI && !s.HasExtension("image_publish_oci") {
+		return errors.New(i18n.G("The server doesn't support publishing OCI images"))
+	}
+
 	if !instance.IsSnapshot(cName) {
 		ct, etag, err := s.GetInstance(cName)
 		if err != nil {
@@ -245,18 +265,24 @@ func (c *cmdPublish) Run(cmd *cobra.Command, args []string) error {
 		req.ExpiresAt = expiresAt
 	}
 
-	existingAliases, err := GetCommonAliases(d, aliases...)
-	if err != nil {
-		return fmt.Errorf(i18n.G("Error retrieving aliases: %w"), err)
-	}
-
-	if !c.flagReuse && len(existingAliases) > 0 {
-		names := []string{}
-		for _, alias := range existingAliases {
-			names = append(names, alias.Name)
+	if isOCI {
+		// The aliases are the names of the image in the registry.
+		req.Target = conf.Remotes[iRemote].Addr
+		req.Aliases = aliases
+	} else {
+		existingAliases, err := GetCommonAliases(d, aliases...)
+		if err != nil {
+			return fmt.Errorf(i18n.G("Error retrieving aliases: %w"), err)
 		}
 
-		return fmt.Errorf(i18n.G("Aliases already exists: %s"), strings.Join(names, ", "))
+		if !c.flagReuse && len(existingAliases) > 0 {
+			names := []string{}
+			for _, alias := range existingAliases {
+				names = append(names, alias.Name)
+			}
+
+			return fmt.Errorf(i18n.G("Aliases already exists: %s"), strings.Join(names, ", "))
+		}
 	}
 
 	req.Format = c.flagFormat
@@ -289,6 +315,16 @@ func (c *cmdPublish) Run(cmd *cobra.Command, args []string) error {
 
 	opAPI := op.Get()
 
+	if isOCI {
+		digest, ok := opAPI.Metadata["digest"].(string)
+		if !ok {
+			return errors.New("Bad digest")
+		}
+
+		fmt.Printf(i18n.G("Instance published with digest: %s")+"\n", digest)
+		return nil
+	}
+
 	// Grab the fingerprint
 	fingerprint, ok := opAPI.Metadata["fingerprint"].(string)
 	if !ok {
@@ -365,7 +365,19 @@ func (c *cmdRemoteAdd) Run(cmd *cobra.Command, args []string) error {
 
 	// Fast track image servers.
 	if slices.Contains([]string{"oci", "simplestreams"}, c.flagProtocol) {
-		if remoteURL.Scheme != "https" {
+		if c.flagProtocol == "oci" && incus.IsLocalOCI(addr) {
+			// Local OCI layouts and archives are stored with an absolute path, the image reference comes from the alias.
+			var reference string
+
+			addr, reference, err = parseLocalOCI(addr)
+			if err != nil {
+				return err
+			}
+
+			if reference != "" {
+				return errors.New(i18n.G("Local OCI remotes can't include an image reference"))
+			}
+		} else if remoteURL.Scheme != "https" {
 			return errors.New(i18n.G("Only https URLs are supported for oci and simplestreams"))
 		}
 
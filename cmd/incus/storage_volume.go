@@ -123,6 +123,10 @@ Unless specified through a prefix, all volume operations affect "custom" (user c
 	storageVolumeMoveCmd := cmdStorageVolumeMove{global: c.global, storage: c.storage, storageVolume: c, storageVolumeCopy: &storageVolumeCopyCmd, storageVolumeRename: &storageVolumeRenameCmd}
 	cmd.AddCommand(storageVolumeMoveCmd.Command())
 
+	// Replication
+	storageVolumeReplicationCmd := cmdStorageVolumeReplication{global: c.global, storage: c.storage, storageVolume: c}
+	cmd.AddCommand(storageVolumeReplicationCmd.Command())
+
 	// Set
 	storageVolumeSetCmd := cmdStorageVolumeSet{global: c.global, storage: c.storage, storageVolume: c}
 	cmd.AddCommand(storageVolumeSetCmd.Command())
@@ -3514,3 +3518,126 @@ func (c *cmdStorageVolumeImport) Run(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// Replication.
+type cmdStorageVolumeReplication struct {
+	global        *cmdGlobal
+	storage       *cmdStorage
+	storageVolume *cmdStorageVolume
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdStorageVolumeReplication) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("replication")
+	cmd.Short = i18n.G("Manage storage volume replication")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Manage storage volume replication`))
+
+	// Promote
+	storageVolumeReplicationPromoteCmd := cmdStorageVolumeReplicationAction{global: c.global, storage: c.storage, storageVolume: c.storageVolume, action: "promote"}
+	cmd.AddCommand(storageVolumeReplicationPromoteCmd.Command())
+
+	// Sync
+	storageVolumeReplicationSyncCmd := cmdStorageVolumeReplicationAction{global: c.global, storage: c.storage, storageVolume: c.storageVolume, action: "sync"}
+	cmd.AddCommand(storageVolumeReplicationSyncCmd.Command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, _ []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// Replication actions.
+type cmdStorageVolumeReplicationAction struct {
+	global        *cmdGlobal
+	storage       *cmdStorage
+	storageVolume *cmdStorageVolume
+
+	action string
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdStorageVolumeReplicationAction) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage(c.action, i18n.G("[<remote>:]<pool> <volume>"))
+
+	if c.action == "promote" {
+		cmd.Short = i18n.G("Promote a replica to a regular custom storage volume")
+		cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+			`Promote a replica to a regular custom storage volume
+
+The volume is no longer updated by the volume it was replicated from.`))
+	} else {
+		cmd.Short = i18n.G("Replicate a custom storage volume to its replication target")
+		cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+			`Replicate a custom storage volume to its replication target`))
+	}
+
+	cmd.Flags().StringVar(&c.storage.flagTarget, "target", "", i18n.G("Cluster member name")+"``")
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpStoragePools(toComplete)
+		}
+
+		if len(args) == 1 {
+			return c.global.cmpStoragePoolVolumes(args[0])
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdStorageVolumeReplicationAction) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 2, 2)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.parseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing pool name"))
+	}
+
+	client := resource.server
+
+	// Parse the input
+	volName, volType := parseVolume("custom", args[1])
+
+	if c.storage.flagTarget != "" {
+		client = client.UseTarget(c.storage.flagTarget)
+	}
+
+	op, err := client.ReplicateStoragePoolVolume(resource.name, volType, volName, api.StorageVolumeReplicationPost{Action: c.action})
+	if err != nil {
+		return err
+	}
+
+	err = op.Wait()
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		if c.action == "promote" {
+			fmt.Printf(i18n.G("Storage volume %s promoted")+"\n", volName)
+		} else {
+			fmt.Printf(i18n.G("Storage volume %s replicated")+"\n", volName)
+		}
+	}
+
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 	"os"
 	"os/exec"
 	"os/signal"
+	"path/filepath"
 	"reflect"
 	"slices"
 	"sort"
@@ -839,3 +840,26 @@ func sshSFTPServer(ctx context.Context, sftpConn func() (net.Conn, error), entit
 		}()
 	}
 }
+
+// parseLocalOCI splits a local OCI source ("oci:<path>[:<reference>]" or "oci-archive:<path>[:<reference>]")
+// into its URL, using an absolute path, and the optional image reference.
+func parseLocalOCI(source string) (string, string, error) {
+	transport, path, _ := strings.Cut(source, ":")
+	if !incus.IsLocalOCI(source) || path == "" {
+		return "", "", fmt.Errorf(i18n.G("Invalid local OCI source %q"), source)
+	}
+
+	reference := ""
+	idx := strings.LastIndex(path, ":")
+	if idx > strings.LastIndex(path, "/") {
+		reference = path[idx+1:]
+		path = path[:idx]
+	}
+
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return "", "", err
+	}
+
+	return transport + ":" + path, reference, nil
+}
@@ -90,3 +90,21 @@ func (s *utilsTestSuite) TestGetServerSupportedFilters() {
 	s.Equal([]string{"foo", "user.blah=a"}, supportedFilters)
 	s.Equal([]string{"type=container", "status=running,stopped"}, unsupportedFilters)
 }
+
+func (s *utilsTestSuite) TestParseLocalOCI() {
+	uri, reference, err := parseLocalOCI("oci:/srv/images/alpine:3.20")
+	s.NoError(err)
+	s.Equal("oci:/srv/images/alpine", uri)
+	s.Equal("3.20", reference)
+
+	uri, reference, err = parseLocalOCI("oci-archive:/srv/images/alpine.tar")
+	s.NoError(err)
+	s.Equal("oci-archive:/srv/images/alpine.tar", uri)
+	s.Empty(reference)
+
+	_, _, err = parseLocalOCI("oci:")
+	s.Error(err)
+
+	_, _, err = parseLocalOCI("docker://alpine")
+	s.Error(err)
+}
@@ -50,6 +50,7 @@ var api10 = []APIEndpoint{
 	clusterNodeCmd,
 	clusterNodeStateCmd,
 	clusterNodesCmd,
+	clusterRollingRestartCmd,
 	clusterCertificateCmd,
 	instanceBackupCmd,
 	instanceBackupExportCmd,
@@ -134,6 +135,7 @@ var api10 = []APIEndpoint{
 	storagePoolVolumeTypeCustomBackupCmd,
 	storagePoolVolumeTypeCustomBackupExportCmd,
 	storagePoolVolumeTypeStateCmd,
+	storagePoolVolumeTypeReplicationCmd,
 	warningsCmd,
 	warningCmd,
 	metricsCmd,
@@ -330,6 +332,7 @@ func api10Get(d *Daemon, r *http.Request) response.Response {
 		Project:                projectName,
 		Server:                 "incus",
 		ServerPid:              os.Getpid(),
+		ServerStartTime:        s.StartTime,
 		ServerVersion:          version.Version,
 		ServerClustered:        s.ServerClustered,
 		ServerEventMode:        string(cluster.ServerEventMode()),
@@ -831,10 +834,20 @@ func doApi10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 				d.taskPruneImages.Reset()
 			}
 
+		case "images.chunk_store":
+			if !s.OS.MockMode {
+				d.taskImageChunkStore.Reset()
+			}
+
 		case "loki.api.url", "loki.auth.username", "loki.auth.password", "loki.api.ca_cert", "loki.instance", "loki.labels", "loki.loglevel", "loki.types":
 			// Notify the logging mechanism about changes to the deprecated keys for backward compatibility.
 			loggingChanges["loki"] = struct{}{}
 
+		case "metrics.push.url", "metrics.push.interval":
+			if d.taskMetricsPush != nil {
+				d.taskMetricsPush.Reset()
+			}
+
 		case "network.ovn.northbound_connection", "network.ovn.ca_cert", "network.ovn.client_cert", "network.ovn.client_key":
 			ovnChanged = true
 
@@ -953,6 +953,7 @@ func clusterInitMember(d incus.InstanceServer, client incus.InstanceServer, memb
 
 		// Delete config keys that are automatically populated by the daemon.
 		delete(post.Config, "volatile.initial_source")
+		delete(post.Config, "volatile.dir.reflink")
 		delete(post.Config, "zfs.pool_name")
 
 		// Apply the node-specific config supplied by the user.
@@ -2944,7 +2945,8 @@ func clusterNodeStatePost(d *Daemon, r *http.Request) response.Response {
 				Live:      live,
 			}
 
-			err := migrateInstance(ctx, s, inst, req, sourceMemberInfo, targetMemberInfo, "", op)
+			// Containers which can't be live-migrated are restarted on the target instead.
+			err := migrateInstanceWithFallback(ctx, s, inst, req, sourceMemberInfo, targetMemberInfo, "", op)
 			if err != nil {
 				return fmt.Errorf("Failed to migrate instance %q in project %q: %w", inst.Name(), inst.Project().Name, err)
 			}
@@ -572,6 +572,18 @@ func evacuateClusterSelectTarget(ctx context.Context, s *state.State, inst insta
 		return nil, nil, err
 	}
 
+	// Only keep the candidates which respect the affinity rules.
+	if len(candidateMembers) > 0 {
+		candidateMembers, err = instancePlacementFilter(ctx, s, inst.Project().Name, inst.Name(), inst.ExpandedConfig(), candidateMembers, inst.Location())
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if len(candidateMembers) == 0 {
+			return nil, nil, api.StatusErrorf(http.StatusBadRequest, "No cluster member satisfies the instance placement rules")
+		}
+	}
+
 	// Run instance placement scriptlet if enabled.
 	if s.GlobalConfig.InstancesPlacementScriptlet() != "" {
 		leaderAddress, err := s.Cluster.LeaderAddress()
@@ -603,13 +615,21 @@ func evacuateClusterSelectTarget(ctx context.Context, s *state.State, inst insta
 		}
 
 		ctx, cancel := context.WithTimeout(ctx, time.Second*5)
-		targetMemberInfo, err = scriptlet.InstancePlacementRun(ctx, logger.Log, s, &reqExpanded, candidateMembers, leaderAddress)
+		targetMembers, err := scriptlet.InstancePlacementRun(ctx, logger.Log, s, &reqExpanded, candidateMembers, leaderAddress)
 		if err != nil {
 			cancel()
 			return nil, nil, fmt.Errorf("Failed instance placement scriptlet for instance %q in project %q: %w", inst.Name(), inst.Project().Name, err)
 		}
 
 		cancel()
+
+		// Use the highest ranked member which is available.
+		if len(targetMembers) > 0 {
+			targetMemberInfo = instancePlacementRanked(s, targetMembers)
+			if targetMemberInfo == nil {
+				return nil, nil, api.StatusErrorf(http.StatusServiceUnavailable, "None of the cluster members selected by the instance placement scriptlet is available")
+			}
+		}
 	}
 
 	// If target member not specified yet, then find the least loaded cluster member which
@@ -238,6 +238,16 @@ func clusterRebalanceServers(ctx context.Context, s *state.State, srcServer *Ser
 			continue
 		}
 
+		// Skip the instance if moving it would violate its affinity rules (or those of other instances).
+		allowed, err := instancePlacementFilter(ctx, s, inst.Project().Name, inst.Name(), inst.ExpandedConfig(), []db.NodeInfo{dstServer.NodeInfo}, "")
+		if err != nil {
+			return -1, err
+		}
+
+		if len(allowed) == 0 {
+			continue
+		}
+
 		// Prepare for live migration.
 		req := api.InstancePost{
 			Migration: true,
@@ -0,0 +1,883 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	internalInstance "github.com/lxc/incus/v6/internal/instance"
+	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/cluster"
+	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/db/operationtype"
+	"github.com/lxc/incus/v6/internal/server/operations"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/state"
+	internalUtil "github.com/lxc/incus/v6/internal/util"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+var clusterRollingRestartCmd = APIEndpoint{
+	Path: "cluster/rolling-restart",
+
+	Post: APIEndpointAction{Handler: clusterRollingRestartPost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Put:  APIEndpointAction{Handler: clusterRollingRestartPut, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+// clusterRollingRestartDefaultTimeout is the default time (in seconds) to wait for a member to come back.
+const clusterRollingRestartDefaultTimeout = 600
+
+// clusterRollingRestartInterval is how often the state of a restarting member is checked.
+const clusterRollingRestartInterval = 5 * time.Second
+
+// Rolling restart member statuses.
+const (
+	clusterRollingStatusPending    = "pending"
+	clusterRollingStatusEvacuating = "evacuating"
+	clusterRollingStatusRestarting = "restarting"
+	clusterRollingStatusWaiting    = "waiting"
+	clusterRollingStatusRestoring  = "restoring"
+	clusterRollingStatusUpgraded   = "upgraded"
+	clusterRollingStatusHandedOver = "handed-over"
+	clusterRollingStatusDone       = "done"
+	clusterRollingStatusFailed     = "failed"
+)
+
+// clusterRollingRestart tracks the rolling restart coordinated by this member.
+type clusterRollingRestart struct {
+	lock     sync.Mutex
+	op       *operations.Operation
+	ctx      context.Context
+	cancel   context.CancelFunc
+	resume   chan struct{} // Set while paused, closed on resume.
+	progress api.ClusterRollingRestart
+
+	// Members left evacuated until the whole cluster is upgraded.
+	pendingRestore []string
+}
+
+var (
+	clusterRollingRestartMu      sync.Mutex
+	clusterRollingRestartCurrent *clusterRollingRestart
+)
+
+// setStatus updates the status of a member and publishes the progress in the operation metadata.
+func (c *clusterRollingRestart) setStatus(index int, status string, message string) {
+	c.lock.Lock()
+	c.progress.Members[index].Status = status
+	c.progress.Members[index].Message = message
+
+	if status == clusterRollingStatusDone || status == clusterRollingStatusUpgraded || status == clusterRollingStatusHandedOver {
+		c.progress.Completed++
+		c.progress.Current = ""
+	} else {
+		c.progress.Current = c.progress.Members[index].Name
+	}
+
+	c.lock.Unlock()
+
+	c.publish()
+}
+
+// publish sends the current progress as the operation metadata.
+func (c *clusterRollingRestart) publish() {
+	c.lock.Lock()
+	progress := c.progress
+	progress.Members = slices.Clone(c.progress.Members)
+	c.lock.Unlock()
+
+	if c.op != nil {
+		_ = c.op.UpdateMetadata(clusterRollingRestartMetadata(progress))
+	}
+}
+
+// clusterRollingRestartMetadata returns the operation metadata for the rolling restart progress.
+func clusterRollingRestartMetadata(progress api.ClusterRollingRestart) map[string]any {
+	metadata := map[string]any{"rolling_restart": progress}
+
+	for _, member := range progress.Members {
+		if member.Name != progress.Current {
+			continue
+		}
+
+		message := fmt.Sprintf("%s: %s (%d/%d)", member.Name, member.Status, progress.Completed+1, len(progress.Members))
+		if progress.Paused {
+			message += " (paused)"
+		}
+
+		metadata["rolling_restart_progress"] = message
+	}
+
+	return metadata
+}
+
+// pause holds the rolling restart before the next member.
+func (c *clusterRollingRestart) pause() {
+	c.lock.Lock()
+	if c.resume == nil {
+		c.resume = make(chan struct{})
+		c.progress.Paused = true
+	}
+
+	c.lock.Unlock()
+
+	c.publish()
+}
+
+// unpause lets a paused rolling restart continue.
+func (c *clusterRollingRestart) unpause() {
+	c.lock.Lock()
+	if c.resume != nil {
+		close(c.resume)
+		c.resume = nil
+		c.progress.Paused = false
+	}
+
+	c.lock.Unlock()
+
+	c.publish()
+}
+
+// wait blocks while the rolling restart is paused.
+func (c *clusterRollingRestart) wait() error {
+	c.lock.Lock()
+	resume := c.resume
+	c.lock.Unlock()
+
+	if resume == nil {
+		return c.ctx.Err()
+	}
+
+	select {
+	case <-resume:
+		return nil
+	case <-c.ctx.Done():
+		return c.ctx.Err()
+	}
+}
+
+// swagger:operation POST /1.0/cluster/rolling-restart cluster cluster_rolling_restart_post
+//
+//	Start a rolling restart
+//
+//	Evacuates, restarts and restores the cluster members one at a time.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: cluster
+//	    description: Rolling restart request
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/ClusterRollingRestartPost"
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func clusterRollingRestartPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	if !s.ServerClustered {
+		return response.BadRequest(errors.New("This server isn't part of a cluster"))
+	}
+
+	// Parse the request.
+	req := api.ClusterRollingRestartPost{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if req.Mode == "" {
+		req.Mode = "restart"
+	}
+
+	if !slices.Contains([]string{"restart", "upgrade"}, req.Mode) {
+		return response.BadRequest(fmt.Errorf("Invalid rolling restart mode %q", req.Mode))
+	}
+
+	if req.EvacuateMode != "" {
+		// Use the validator from the instance logic.
+		validator := internalInstance.InstanceConfigKeysAny["cluster.evacuate"]
+		err = validator(req.EvacuateMode)
+		if err != nil {
+			return response.BadRequest(err)
+		}
+	}
+
+	if req.Timeout < 0 {
+		return response.BadRequest(errors.New("The timeout can't be negative"))
+	}
+
+	if req.Timeout == 0 {
+		req.Timeout = clusterRollingRestartDefaultTimeout
+	}
+
+	// Get the members to process.
+	var members []db.NodeInfo
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		// Only allow a single rolling restart at a time, other than when handed over by another member.
+		if !isClusterNotification(r) {
+			ops, err := dbCluster.GetOperations(ctx, tx.Tx())
+			if err != nil {
+				return err
+			}
+
+			for _, op := range ops {
+				if op.Type == operationtype.ClusterRollingRestart {
+					return api.StatusErrorf(http.StatusConflict, "A rolling restart is already running")
+				}
+			}
+		}
+
+		allMembers, err := tx.GetNodes(ctx)
+		if err != nil {
+			return fmt.Errorf("Failed getting cluster members: %w", err)
+		}
+
+		members, err = clusterRollingRestartMembers(allMembers, req.Members, s.ServerName)
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	rolling := &clusterRollingRestart{
+		progress: api.ClusterRollingRestart{
+			Mode:    req.Mode,
+			Members: make([]api.ClusterRollingRestartMember, 0, len(members)),
+		},
+	}
+
+	for _, member := range members {
+		rolling.progress.Members = append(rolling.progress.Members, api.ClusterRollingRestartMember{Name: member.Name, Status: clusterRollingStatusPending})
+	}
+
+	clusterRollingRestartMu.Lock()
+	defer clusterRollingRestartMu.Unlock()
+
+	if clusterRollingRestartCurrent != nil {
+		return response.SmartError(api.StatusErrorf(http.StatusConflict, "A rolling restart is already running"))
+	}
+
+	rolling.ctx, rolling.cancel = context.WithCancel(s.ShutdownCtx)
+
+	run := func(op *operations.Operation) error {
+		defer func() {
+			rolling.cancel()
+
+			clusterRollingRestartMu.Lock()
+			clusterRollingRestartCurrent = nil
+			clusterRollingRestartMu.Unlock()
+		}()
+
+		err := clusterRollingRestartRun(s, rolling, members, req)
+
+		// Record the members which can only be restored once the whole cluster is upgraded, this member
+		// restores them after its own upgrade.
+		rolling.lock.Lock()
+		pendingRestore := rolling.pendingRestore
+		rolling.lock.Unlock()
+
+		if len(pendingRestore) > 0 {
+			saveErr := clusterRollingRestartSavePendingRestore(pendingRestore)
+			if saveErr != nil {
+				logger.Warn("Failed recording the cluster members to restore after the upgrade", logger.Ctx{"members": pendingRestore, "err": saveErr})
+			}
+		}
+
+		return err
+	}
+
+	onCancel := func(op *operations.Operation) error {
+		rolling.cancel()
+		return nil
+	}
+
+	op, err := operations.OperationCreate(s, "", operations.OperationClassTask, operationtype.ClusterRollingRestart, nil, clusterRollingRestartMetadata(rolling.progress), run, onCancel, nil, r)
+	if err != nil {
+		rolling.cancel()
+		return response.InternalError(err)
+	}
+
+	rolling.op = op
+	clusterRollingRestartCurrent = rolling
+
+	return operations.OperationResponse(op)
+}
+
+// clusterRollingRestartMembers returns the members to process in order, with the local member last
+// as restarting it ends the operation.
+func clusterRollingRestartMembers(allMembers []db.NodeInfo, names []string, localName string) ([]db.NodeInfo, error) {
+	if len(names) == 0 {
+		for _, member := range allMembers {
+			names = append(names, member.Name)
+		}
+	}
+
+	var members []db.NodeInfo
+	var local *db.NodeInfo
+
+	for i, name := range names {
+		if slices.Contains(names[:i], name) {
+			return nil, api.StatusErrorf(http.StatusBadRequest, "Cluster member %q is listed more than once", name)
+		}
+
+		index := slices.IndexFunc(allMembers, func(member db.NodeInfo) bool { return member.Name == name })
+		if index < 0 {
+			return nil, api.StatusErrorf(http.StatusNotFound, "Cluster member %q not found", name)
+		}
+
+		member := allMembers[index]
+		if member.State == db.ClusterMemberStatePending {
+			return nil, api.StatusErrorf(http.StatusBadRequest, "Cluster member %q is pending", name)
+		}
+
+		if member.Name == localName {
+			local = &member
+			continue
+		}
+
+		members = append(members, member)
+	}
+
+	if local != nil {
+		members = append(members, *local)
+	}
+
+	return members, nil
+}
+
+// clusterRollingRestartRun processes the members one at a time.
+func clusterRollingRestartRun(s *state.State, rolling *clusterRollingRestart, members []db.NodeInfo, req api.ClusterRollingRestartPost) error {
+	timeout := time.Duration(req.Timeout) * time.Second
+
+	for i, member := range members {
+		err := rolling.wait()
+		if err != nil {
+			return errors.New("Rolling restart aborted")
+		}
+
+		l := logger.AddContext(logger.Ctx{"member": member.Name, "mode": req.Mode})
+
+		if member.Name == s.ServerName {
+			err = clusterRollingRestartHandover(s, rolling, i, members, req)
+		} else {
+			l.Info("Rolling restart of cluster member")
+			err = clusterRollingRestartMember(s, rolling, i, member, req.Mode, req.EvacuateMode, timeout)
+		}
+
+		if err != nil {
+			if rolling.ctx.Err() != nil {
+				err = fmt.Errorf("Rolling restart aborted: %w", err)
+			}
+
+			l.Error("Failed rolling restart of cluster member", logger.Ctx{"err": err})
+			rolling.setStatus(i, clusterRollingStatusFailed, err.Error())
+
+			return fmt.Errorf("Failed rolling restart of cluster member %q: %w", member.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// clusterRollingRestartMember evacuates, restarts and restores a remote member.
+func clusterRollingRestartMember(s *state.State, rolling *clusterRollingRestart, index int, member db.NodeInfo, mode string, evacuateMode string, timeout time.Duration) error {
+	ctx := rolling.ctx
+
+	client, err := cluster.Connect(member.Address, s.Endpoints.NetworkCert(), s.ServerCert(), nil, true)
+	if err != nil {
+		return fmt.Errorf("Failed connecting to cluster member: %w", err)
+	}
+
+	server, _, err := client.GetServer()
+	if err != nil {
+		return fmt.Errorf("Failed getting cluster member state: %w", err)
+	}
+
+	oldStartTime := server.Environment.ServerStartTime
+
+	// Leave members which were already evacuated as they are.
+	evacuated := member.State == db.ClusterMemberStateEvacuated
+	if !evacuated {
+		rolling.setStatus(index, clusterRollingStatusEvacuating, "")
+
+		op, err := client.UpdateClusterMemberState(member.Name, api.ClusterMemberStatePost{Action: "evacuate", Mode: evacuateMode})
+		if err != nil {
+			return fmt.Errorf("Failed evacuating cluster member: %w", err)
+		}
+
+		err = op.WaitContext(ctx)
+		if err != nil {
+			return fmt.Errorf("Failed evacuating cluster member: %w", err)
+		}
+	}
+
+	if mode == "restart" {
+		rolling.setStatus(index, clusterRollingStatusRestarting, "")
+
+		// The connection may get closed while the member restarts, so rely on waiting for it below.
+		_, _, err = client.RawQuery("PUT", "/internal/shutdown?restart=true", nil, "")
+		if err != nil {
+			logger.Warn("Failed restarting cluster member", logger.Ctx{"member": member.Name, "err": err})
+		}
+
+		rolling.setStatus(index, clusterRollingStatusWaiting, "Waiting for the member to come back online")
+	} else {
+		rolling.setStatus(index, clusterRollingStatusWaiting, "Waiting for the member to be upgraded and restarted")
+	}
+
+	upgraded, err := clusterRollingRestartWait(ctx, s, member, oldStartTime, timeout)
+	if err != nil {
+		return err
+	}
+
+	if upgraded {
+		// The member waits for the rest of the cluster to be upgraded before starting, so can't be restored yet.
+		if !evacuated {
+			rolling.lock.Lock()
+			rolling.pendingRestore = append(rolling.pendingRestore, member.Name)
+			rolling.lock.Unlock()
+		}
+
+		rolling.setStatus(index, clusterRollingStatusUpgraded, "Waiting for the rest of the cluster to be upgraded, the member is restored once done")
+		return nil
+	}
+
+	if !evacuated {
+		rolling.setStatus(index, clusterRollingStatusRestoring, "")
+
+		// Reconnect as the member was restarted.
+		client, err = cluster.Connect(member.Address, s.Endpoints.NetworkCert(), s.ServerCert(), nil, true)
+		if err != nil {
+			return fmt.Errorf("Failed connecting to cluster member: %w", err)
+		}
+
+		op, err := client.UpdateClusterMemberState(member.Name, api.ClusterMemberStatePost{Action: "restore"})
+		if err != nil {
+			return fmt.Errorf("Failed restoring cluster member: %w", err)
+		}
+
+		err = op.WaitContext(ctx)
+		if err != nil {
+			return fmt.Errorf("Failed restoring cluster member: %w", err)
+		}
+	}
+
+	rolling.setStatus(index, clusterRollingStatusDone, "")
+
+	return nil
+}
+
+// clusterRollingRestartWait waits for a member to be back online and healthy after its restart.
+// It returns true if the member was upgraded and is waiting for the rest of the cluster to be upgraded.
+func clusterRollingRestartWait(ctx context.Context, s *state.State, member db.NodeInfo, oldStartTime time.Time, timeout time.Duration) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(clusterRollingRestartInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return false, errors.New("Timed out waiting for the cluster member to come back online")
+			}
+
+			return false, ctx.Err()
+		case <-ticker.C:
+		}
+
+		upgraded, healthy := clusterRollingRestartCheck(ctx, s, member, oldStartTime)
+		if upgraded || healthy {
+			return upgraded, nil
+		}
+	}
+}
+
+// clusterRollingRestartCheck returns whether the member was upgraded past the local member's version
+// and whether it was restarted and is ready.
+func clusterRollingRestartCheck(ctx context.Context, s *state.State, member db.NodeInfo, oldStartTime time.Time) (bool, bool) {
+	var current db.NodeInfo
+	var local db.NodeInfo
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		current, err = tx.GetNodeByName(ctx, member.Name)
+		if err != nil {
+			return err
+		}
+
+		local, err = tx.GetNodeByName(ctx, s.ServerName)
+		return err
+	})
+	if err != nil {
+		return false, false
+	}
+
+	// Members running a newer version don't start until all the other members are upgraded.
+	currentVersion := current.Version()
+	localVersion := local.Version()
+	if currentVersion[0] > localVersion[0] || (currentVersion[0] == localVersion[0] && currentVersion[1] > localVersion[1]) {
+		return true, false
+	}
+
+	if current.IsOffline(s.GlobalConfig.OfflineThreshold()) {
+		return false, false
+	}
+
+	client, err := cluster.Connect(current.Address, s.Endpoints.NetworkCert(), s.ServerCert(), nil, true)
+	if err != nil {
+		return false, false
+	}
+
+	// The daemon keeps its PID when re-executing itself, so rely on its start time instead.
+	server, _, err := client.GetServer()
+	if err != nil || server.Environment.ServerStartTime.Equal(oldStartTime) {
+		return false, false
+	}
+
+	_, _, err = client.RawQuery("GET", "/internal/ready", nil, "")
+	if err != nil {
+		return false, false
+	}
+
+	return false, true
+}
+
+// clusterRollingRestartHandover processes the local member by handing it over to another member,
+// since restarting the local member would end the rolling restart.
+func clusterRollingRestartHandover(s *state.State, rolling *clusterRollingRestart, index int, members []db.NodeInfo, req api.ClusterRollingRestartPost) error {
+	var allMembers []db.NodeInfo
+	err := s.DB.Cluster.Transaction(rolling.ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		allMembers, err = tx.GetNodes(ctx)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed getting cluster members: %w", err)
+	}
+
+	// Prefer the members which were just restarted.
+	rolling.lock.Lock()
+	var candidates []string
+	for _, member := range rolling.progress.Members {
+		if member.Status == clusterRollingStatusDone {
+			candidates = append(candidates, member.Name)
+		}
+	}
+
+	rolling.lock.Unlock()
+
+	for _, member := range allMembers {
+		if member.Name != s.ServerName && !slices.Contains(candidates, member.Name) {
+			candidates = append(candidates, member.Name)
+		}
+	}
+
+	var local db.NodeInfo
+	for _, member := range members {
+		if member.Name == s.ServerName {
+			local = member
+		}
+	}
+
+	handoverReq := api.ClusterRollingRestartPost{
+		Mode:         req.Mode,
+		Members:      []string{s.ServerName},
+		EvacuateMode: req.EvacuateMode,
+		Timeout:      req.Timeout,
+	}
+
+	for _, name := range candidates {
+		pos := slices.IndexFunc(allMembers, func(member db.NodeInfo) bool { return member.Name == name })
+		member := allMembers[pos]
+
+		// Skip the members which can't coordinate the restart.
+		if member.State != db.ClusterMemberStateCreated || member.IsOffline(s.GlobalConfig.OfflineThreshold()) || member.Version() != local.Version() {
+			continue
+		}
+
+		client, err := cluster.Connect(member.Address, s.Endpoints.NetworkCert(), s.ServerCert(), nil, true)
+		if err != nil {
+			continue
+		}
+
+		op, err := client.CreateClusterRollingRestart(handoverReq)
+		if err != nil {
+			logger.Warn("Failed handing over rolling restart", logger.Ctx{"member": member.Name, "err": err})
+			continue
+		}
+
+		rolling.setStatus(index, clusterRollingStatusHandedOver, fmt.Sprintf("Continued by operation %q on cluster member %q", op.Get().ID, member.Name))
+
+		return nil
+	}
+
+	if req.Mode == "restart" {
+		return errors.New("No other cluster member can restart this member")
+	}
+
+	// Without any other member to coordinate, just get the local member ready for its upgrade.
+	if local.State != db.ClusterMemberStateEvacuated {
+		rolling.setStatus(index, clusterRollingStatusEvacuating, "")
+
+		client, err := cluster.Connect(local.Address, s.Endpoints.NetworkCert(), s.ServerCert(), nil, true)
+		if err != nil {
+			return fmt.Errorf("Failed connecting to cluster member: %w", err)
+		}
+
+		op, err := client.UpdateClusterMemberState(local.Name, api.ClusterMemberStatePost{Action: "evacuate", Mode: req.EvacuateMode})
+		if err != nil {
+			return fmt.Errorf("Failed evacuating cluster member: %w", err)
+		}
+
+		err = op.WaitContext(rolling.ctx)
+		if err != nil {
+			return fmt.Errorf("Failed evacuating cluster member: %w", err)
+		}
+
+		rolling.lock.Lock()
+		rolling.pendingRestore = append(rolling.pendingRestore, local.Name)
+		rolling.lock.Unlock()
+	}
+
+	rolling.setStatus(index, clusterRollingStatusWaiting, "Upgrade and restart this member, the evacuated members are then restored")
+
+	return nil
+}
+
+// clusterRollingRestartPendingRestorePath returns the path of the file recording the members to restore once
+// the whole cluster is upgraded.
+func clusterRollingRestartPendingRestorePath() string {
+	return internalUtil.VarPath("cluster-rolling-restart.json")
+}
+
+// clusterRollingRestartSavePendingRestore records the members to restore once the whole cluster is upgraded,
+// adding to those already recorded.
+func clusterRollingRestartSavePendingRestore(names []string) error {
+	existing, err := clusterRollingRestartLoadPendingRestore()
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		if !slices.Contains(existing, name) {
+			existing = append(existing, name)
+		}
+	}
+
+	data, err := json.Marshal(existing)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(clusterRollingRestartPendingRestorePath(), data, 0o600)
+}
+
+// clusterRollingRestartLoadPendingRestore returns the members to restore once the whole cluster is upgraded.
+func clusterRollingRestartLoadPendingRestore() ([]string, error) {
+	data, err := os.ReadFile(clusterRollingRestartPendingRestorePath())
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var names []string
+	err = json.Unmarshal(data, &names)
+	if err != nil {
+		return nil, err
+	}
+
+	return names, nil
+}
+
+// clusterRollingRestartRestorePending restores the members left evacuated by a rolling upgrade, waiting for
+// each of them to be back online and ready. It's run on startup, as the cluster can only be fully upgraded once
+// the member which coordinated the upgrade got upgraded too.
+func clusterRollingRestartRestorePending(s *state.State) {
+	names, err := clusterRollingRestartLoadPendingRestore()
+	if err != nil {
+		logger.Warn("Failed loading the cluster members to restore after the upgrade", logger.Ctx{"err": err})
+		return
+	}
+
+	if len(names) == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(clusterRollingRestartInterval)
+	defer ticker.Stop()
+
+	for _, name := range names {
+		l := logger.AddContext(logger.Ctx{"member": name})
+
+		for {
+			done, err := clusterRollingRestartRestoreMember(s, name)
+			if err != nil {
+				l.Warn("Failed restoring cluster member after the upgrade", logger.Ctx{"err": err})
+				break
+			}
+
+			if done {
+				break
+			}
+
+			select {
+			case <-s.ShutdownCtx.Done():
+				// Try again on the next start.
+				return
+			case <-ticker.C:
+			}
+		}
+	}
+
+	err = os.Remove(clusterRollingRestartPendingRestorePath())
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		logger.Warn("Failed removing the cluster members to restore after the upgrade", logger.Ctx{"err": err})
+	}
+}
+
+// clusterRollingRestartRestoreMember restores a member left evacuated by a rolling upgrade.
+// It returns false if the member isn't ready yet.
+func clusterRollingRestartRestoreMember(s *state.State, name string) (bool, error) {
+	var member db.NodeInfo
+	err := s.DB.Cluster.Transaction(s.ShutdownCtx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		member, err = tx.GetNodeByName(ctx, name)
+		return err
+	})
+	if err != nil {
+		if api.StatusErrorCheck(err, http.StatusNotFound) {
+			return true, nil
+		}
+
+		return false, nil
+	}
+
+	// Skip the members which were restored in the meantime.
+	if member.State != db.ClusterMemberStateEvacuated {
+		return true, nil
+	}
+
+	// Wait for the member to run the same version and be ready.
+	upgraded, healthy := clusterRollingRestartCheck(s.ShutdownCtx, s, member, time.Time{})
+	if upgraded || !healthy {
+		return false, nil
+	}
+
+	logger.Info("Restoring cluster member after the upgrade", logger.Ctx{"member": name})
+
+	client, err := cluster.Connect(member.Address, s.Endpoints.NetworkCert(), s.ServerCert(), nil, true)
+	if err != nil {
+		return false, fmt.Errorf("Failed connecting to cluster member: %w", err)
+	}
+
+	op, err := client.UpdateClusterMemberState(member.Name, api.ClusterMemberStatePost{Action: "restore"})
+	if err != nil {
+		return false, fmt.Errorf("Failed restoring cluster member: %w", err)
+	}
+
+	err = op.WaitContext(s.ShutdownCtx)
+	if err != nil {
+		return false, fmt.Errorf("Failed restoring cluster member: %w", err)
+	}
+
+	return true, nil
+}
+
+// swagger:operation PUT /1.0/cluster/rolling-restart cluster cluster_rolling_restart_put
+//
+//	Control the rolling restart
+//
+//	Pauses, resumes or aborts the rolling restart coordinated by the cluster member.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: target
+//	    description: Cluster member coordinating the rolling restart
+//	    type: string
+//	    example: server01
+//	  - in: body
+//	    name: cluster
+//	    description: Rolling restart action
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/ClusterRollingRestartPut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func clusterRollingRestartPut(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	// Forward the request to the coordinating member.
+	resp := forwardedResponseIfTargetIsRemote(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	req := api.ClusterRollingRestartPut{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	clusterRollingRestartMu.Lock()
+	rolling := clusterRollingRestartCurrent
+	clusterRollingRestartMu.Unlock()
+
+	if rolling == nil {
+		return response.NotFound(errors.New("No rolling restart is running on this cluster member"))
+	}
+
+	switch req.Action {
+	case "pause":
+		rolling.pause()
+	case "resume":
+		rolling.unpause()
+	case "abort":
+		rolling.cancel()
+	default:
+		return response.BadRequest(fmt.Errorf("Unknown action %q", req.Action))
+	}
+
+	return response.EmptySyncResponse
+}
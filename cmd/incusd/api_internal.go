@@ -58,6 +58,7 @@ var apiInternal = []APIEndpoint{
 	internalContainerOnStartCmd,
 	internalContainerOnStopCmd,
 	internalContainerOnStopNSCmd,
+	internalInstanceOnDeviceReloadCmd,
 	internalVirtualMachineOnResizeCmd,
 	internalGarbageCollectorCmd,
 	internalImageOptimizeCmd,
@@ -153,6 +154,13 @@ var internalContainerOnStopCmd = APIEndpoint{
 	Get: APIEndpointAction{Handler: internalContainerOnStop, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
 }
 
+// Instance hooks.
+var internalInstanceOnDeviceReloadCmd = APIEndpoint{
+	Path: "instances/{instanceRef}/ondevicereload",
+
+	Get: APIEndpointAction{Handler: internalInstanceOnDeviceReload, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
 // Virtual machine hooks.
 var internalVirtualMachineOnResizeCmd = APIEndpoint{
 	Path: "virtual-machines/{instanceRef}/onresize",
@@ -292,12 +300,16 @@ func internalWaitReady(d *Daemon, _ *http.Request) response.Response {
 
 func internalShutdown(d *Daemon, r *http.Request) response.Response {
 	force := request.QueryParam(r, "force")
-	logger.Info("Asked to shutdown by API", logger.Ctx{"force": force})
+	restart := request.QueryParam(r, "restart")
+	logger.Info("Asked to shutdown by API", logger.Ctx{"force": force, "restart": restart})
 
 	if d.State().ShutdownCtx.Err() != nil {
 		return response.SmartError(api.StatusErrorf(http.StatusTooManyRequests, "Shutdown already in progress"))
 	}
 
+	// Start the daemon again once stopped (used by rolling restarts).
+	d.restartOnStop.Store(restart == "true")
+
 	forceCtx, forceCtxCancel := context.WithCancel(context.Background())
 
 	if force == "true" {
@@ -501,6 +513,42 @@ func internalVirtualMachineOnResize(d *Daemon, r *http.Request) response.Respons
 	return response.EmptySyncResponse
 }
 
+func internalInstanceOnDeviceReload(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	// Get the instance ID.
+	instanceID, err := strconv.Atoi(mux.Vars(r)["instanceRef"])
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Get the devices list.
+	devices := request.QueryParam(r, "devices")
+	if devices == "" {
+		return response.BadRequest(errors.New("Device reload hook requires a list of devices"))
+	}
+
+	// Load by ID.
+	inst, err := instance.LoadByID(s, instanceID)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if !inst.IsRunning() {
+		return response.EmptySyncResponse
+	}
+
+	// Reload the devices of the local instance.
+	for _, devName := range strings.Split(devices, ",") {
+		err = inst.ReloadDevice(devName)
+		if err != nil {
+			return response.InternalError(err)
+		}
+	}
+
+	return response.EmptySyncResponse
+}
+
 // Perform a database dump.
 func internalSQLGet(d *Daemon, r *http.Request) response.Response {
 	s := d.State()
@@ -23,8 +23,10 @@ import (
 	"github.com/lxc/incus/v6/internal/server/request"
 	"github.com/lxc/incus/v6/internal/server/response"
 	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/internal/server/task"
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/util"
 )
 
 type metricsCacheEntry struct {
@@ -129,6 +131,22 @@ func metricsGet(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
+	// Add the instance metrics.
+	instanceMetrics, err := localInstanceMetrics(r.Context(), s, projectNames)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	metricSet.Merge(instanceMetrics)
+
+	return getFilteredMetrics(s, r, compress, metricSet)
+}
+
+// localInstanceMetrics returns the metrics of the local instances in the given projects.
+// Per-project results are cached for a short amount of time and shared between all callers.
+func localInstanceMetrics(ctx context.Context, s *state.State, projectNames []string) (*metrics.MetricSet, error) {
+	metricSet := metrics.NewMetricSet(nil)
+
 	// invalidProjectFilters returns project filters which are either not in cache or have expired.
 	invalidProjectFilters := func(projectNames []string) []dbCluster.InstanceFilter {
 		metricsCacheLock.Lock()
@@ -161,23 +179,23 @@ func metricsGet(d *Daemon, r *http.Request) response.Response {
 
 	// If all valid, return immediately.
 	if len(projectsToFetch) == 0 {
-		return getFilteredMetrics(s, r, compress, metricSet)
+		return metricSet, nil
 	}
 
 	cacheDuration := time.Duration(8) * time.Second
 
 	// Acquire update lock.
-	lockCtx, lockCtxCancel := context.WithTimeout(r.Context(), cacheDuration)
+	lockCtx, lockCtxCancel := context.WithTimeout(ctx, cacheDuration)
 	defer lockCtxCancel()
 
 	unlock, err := locking.Lock(lockCtx, "metricsGet")
 	if err != nil {
-		return response.SmartError(api.StatusErrorf(http.StatusLocked, "Metrics are currently being built by another request: %s", err))
+		return nil, api.StatusErrorf(http.StatusLocked, "Metrics are currently being built by another request: %s", err)
 	}
 
 	defer unlock()
 
-	// Setup a new response.
+	// Start over with a new set.
 	metricSet = metrics.NewMetricSet(nil)
 
 	// Check if any of the missing data has been filled in since acquiring the lock.
@@ -186,14 +204,14 @@ func metricsGet(d *Daemon, r *http.Request) response.Response {
 
 	// If all valid, return immediately.
 	if len(projectsToFetch) == 0 {
-		return getFilteredMetrics(s, r, compress, metricSet)
+		return metricSet, nil
 	}
 
 	// Gather information about host interfaces once.
 	hostInterfaces, _ := net.Interfaces()
 
 	var instances []instance.Instance
-	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
 		return tx.InstanceList(ctx, func(dbInst db.InstanceArgs, p api.Project) error {
 			inst, err := instance.Load(s, dbInst, p)
 			if err != nil {
@@ -206,7 +224,7 @@ func metricsGet(d *Daemon, r *http.Request) response.Response {
 		}, projectsToFetch...)
 	})
 	if err != nil {
-		return response.SmartError(err)
+		return nil, err
 	}
 
 	// Prepare temporary metrics storage.
@@ -261,7 +279,7 @@ func metricsGet(d *Daemon, r *http.Request) response.Response {
 	wg.Wait()
 	close(instMetricsCh)
 
-	// Put the new data in the global cache and in response.
+	// Put the new data in the global cache and in the result.
 	metricsCacheLock.Lock()
 
 	if metricsCache == nil {
@@ -291,7 +309,7 @@ func metricsGet(d *Daemon, r *http.Request) response.Response {
 
 	metricsCacheLock.Unlock()
 
-	return getFilteredMetrics(s, r, compress, metricSet)
+	return metricSet, nil
 }
 
 func getFilteredMetrics(s *state.State, r *http.Request, compress bool, metricSet *metrics.MetricSet) response.Response {
@@ -393,3 +411,97 @@ func internalMetrics(ctx context.Context, s *state.State, tx *db.ClusterTx) *met
 
 	return out
 }
+
+// metricsPush sends the local server and instance metrics to the configured push endpoint.
+func metricsPush(ctx context.Context, s *state.State) error {
+	address, format, username, password, caCert, projects := s.GlobalConfig.MetricsPushConfig()
+	if address == "" {
+		return nil
+	}
+
+	pusher, err := metrics.NewPusher(address, format, username, password, caCert)
+	if err != nil {
+		return err
+	}
+
+	metricSet := metrics.NewMetricSet(nil)
+
+	var projectNames []string
+
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		projects, err := dbCluster.GetProjects(ctx, tx.Tx())
+		if err != nil {
+			return fmt.Errorf("Failed loading projects: %w", err)
+		}
+
+		projectNames = make([]string, 0, len(projects))
+		for _, project := range projects {
+			projectNames = append(projectNames, project.Name)
+		}
+
+		// Add internal metrics.
+		metricSet.Merge(internalMetrics(ctx, s, tx))
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	instanceMetrics, err := localInstanceMetrics(ctx, s, projectNames)
+	if err != nil {
+		return err
+	}
+
+	metricSet.Merge(instanceMetrics)
+
+	// Only keep the instance metrics of the selected projects.
+	allowedProjects := util.SplitNTrimSpace(projects, ",", -1, true)
+	if allowedProjects != nil {
+		metricSet.FilterSamples(func(object auth.Object) bool {
+			if object.Type() == auth.ObjectTypeServer {
+				return true
+			}
+
+			return slices.Contains(allowedProjects, object.Project())
+		})
+	}
+
+	// Each member pushes its own metrics, identify them by location.
+	labels := map[string]string{}
+	if s.ServerClustered {
+		labels["location"] = s.ServerName
+	}
+
+	return pusher.Push(ctx, metricSet, labels)
+}
+
+func metricsPushTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		// Wait until daemon is fully started.
+		select {
+		case <-d.waitReady.Done():
+		case <-ctx.Done():
+			return
+		}
+
+		err := metricsPush(ctx, d.State())
+		if err != nil {
+			logger.Warn("Failed pushing metrics", logger.Ctx{"err": err})
+		}
+	}
+
+	schedule := func() (time.Duration, error) {
+		s := d.State()
+
+		address, _, _, _, _, _ := s.GlobalConfig.MetricsPushConfig()
+		if address == "" {
+			// Metrics push is disabled.
+			return 0, nil
+		}
+
+		return s.GlobalConfig.MetricsPushInterval(), nil
+	}
+
+	return f, schedule
+}
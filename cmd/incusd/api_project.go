@@ -1514,6 +1514,20 @@ func projectValidateConfig(s *state.State, config map[string]string) error {
 		//  shortdesc: Maximum disk space used by the project
 		"limits.disk": validate.Optional(validate.IsSize),
 
+		// gendoc:generate(entity=project, group=limits, key=limits.disk.bandwidth)
+		// This value is the maximum value for the sum of the individual `limits.bandwidth` configurations set on the custom volumes of the project.
+		// ---
+		//  type: string
+		//  shortdesc: Maximum I/O bandwidth of the custom volumes in the project
+		"limits.disk.bandwidth": validate.Optional(validate.IsSize),
+
+		// gendoc:generate(entity=project, group=limits, key=limits.disk.iops)
+		// This value is the maximum value for the sum of the individual `limits.iops` configurations set on the custom volumes of the project.
+		// ---
+		//  type: integer
+		//  shortdesc: Maximum I/O operations per second of the custom volumes in the project
+		"limits.disk.iops": validate.Optional(validate.IsUint32),
+
 		// gendoc:generate(entity=project, group=limits, key=limits.networks)
 		//
 		// ---
@@ -6,11 +6,14 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
+	"slices"
 	"time"
 
 	"gopkg.in/yaml.v2"
 
+	internalInstance "github.com/lxc/incus/v6/internal/instance"
 	"github.com/lxc/incus/v6/internal/instancewriter"
 	"github.com/lxc/incus/v6/internal/server/backup"
 	"github.com/lxc/incus/v6/internal/server/db"
@@ -21,6 +24,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/lifecycle"
 	"github.com/lxc/incus/v6/internal/server/operations"
 	"github.com/lxc/incus/v6/internal/server/project"
+	"github.com/lxc/incus/v6/internal/server/response"
 	"github.com/lxc/incus/v6/internal/server/state"
 	storagePools "github.com/lxc/incus/v6/internal/server/storage"
 	"github.com/lxc/incus/v6/internal/server/task"
@@ -184,7 +188,7 @@ func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.In
 
 	// Write index file.
 	l.Debug("Adding backup index file")
-	err = backupWriteIndex(sourceInst, pool, b.OptimizedStorage(), !b.InstanceOnly(), tarWriter)
+	err = backupWriteIndex(sourceInst, pool, b.OptimizedStorage(), !b.InstanceOnly(), args.Parent, tarWriter)
 
 	// Check compression errors.
 	if compressErr != nil {
@@ -196,7 +200,7 @@ func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.In
 		return fmt.Errorf("Error writing backup index file: %w", err)
 	}
 
-	err = pool.BackupInstance(sourceInst, tarWriter, b.OptimizedStorage(), !b.InstanceOnly(), nil)
+	err = pool.BackupInstance(sourceInst, tarWriter, b.OptimizedStorage(), !b.InstanceOnly(), args.Parent, nil)
 	if err != nil {
 		return fmt.Errorf("Backup create: %w", err)
 	}
@@ -229,8 +233,67 @@ func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.In
 	return nil
 }
 
+// backupParentSnapshot returns the snapshot an incremental backup of the instance should be based on.
+// The parent is either one of the instance's snapshots or one of its backups, in which case the most recent
+// snapshot included in that backup is used.
+func backupParentSnapshot(s *state.State, inst instance.Instance, parent string) (string, error) {
+	snapshots, err := inst.Snapshots()
+	if err != nil {
+		return "", err
+	}
+
+	snapNames := make([]string, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		_, snapName, _ := api.GetParentAndSnapshotName(snapshot.Name())
+		snapNames = append(snapNames, snapName)
+	}
+
+	if slices.Contains(snapNames, parent) {
+		return parent, nil
+	}
+
+	// Look for a backup with that name.
+	b, err := instance.BackupLoadByName(s, inst.Project().Name, inst.Name()+internalInstance.SnapshotDelimiter+parent)
+	if err != nil {
+		if response.IsNotFoundError(err) {
+			return "", api.StatusErrorf(http.StatusBadRequest, "No snapshot or backup named %q", parent)
+		}
+
+		return "", err
+	}
+
+	backupPath := internalUtil.VarPath("backups", "instances", project.Instance(inst.Project().Name, b.Name()))
+	backupFile, err := os.Open(backupPath)
+	if err != nil {
+		return "", fmt.Errorf("Failed opening backup %q: %w", parent, err)
+	}
+
+	defer func() { _ = backupFile.Close() }()
+
+	info, err := backup.GetInfo(backupFile, s.OS, backupPath)
+	if err != nil {
+		return "", fmt.Errorf("Failed reading backup %q: %w", parent, err)
+	}
+
+	// The new backup follows the most recent snapshot included in the backup. Backups without snapshots
+	// (including incremental ones which didn't add any) can't be used as the parent, as their content isn't
+	// available as a snapshot.
+	if len(info.Snapshots) == 0 {
+		return "", api.StatusErrorf(http.StatusBadRequest, "Backup %q doesn't include any snapshot to base an incremental backup on", parent)
+	}
+
+	snapName := info.Snapshots[len(info.Snapshots)-1]
+
+	if !slices.Contains(snapNames, snapName) {
+		return "", api.StatusErrorf(http.StatusBadRequest, "Snapshot %q included in backup %q doesn't exist anymore", snapName, parent)
+	}
+
+	return snapName, nil
+}
+
 // backupWriteIndex generates an index.yaml file and then writes it to the root of the backup tarball.
-func backupWriteIndex(sourceInst instance.Instance, pool storagePools.Pool, optimized bool, snapshots bool, tarWriter *instancewriter.InstanceTarWriter) error {
+// For incremental backups, only the snapshots taken after the parent snapshot are listed.
+func backupWriteIndex(sourceInst instance.Instance, pool storagePools.Pool, optimized bool, snapshots bool, parent string, tarWriter *instancewriter.InstanceTarWriter) error {
 	// Indicate whether the driver will include a driver-specific optimized header.
 	poolDriverOptimizedHeader := false
 	if optimized {
@@ -257,6 +320,24 @@ func backupWriteIndex(sourceInst instance.Instance, pool storagePools.Pool, opti
 		return fmt.Errorf("Failed generating instance backup config: %w", err)
 	}
 
+	var parentCreatedAt *time.Time
+	if parent != "" && snapshots {
+		index := slices.IndexFunc(config.Snapshots, func(snap *api.InstanceSnapshot) bool { return snap.Name == parent })
+		if index < 0 {
+			return fmt.Errorf("Parent snapshot %q not found", parent)
+		}
+
+		parentCreatedAt = &config.Snapshots[index].CreatedAt
+		config.Snapshots = config.Snapshots[index+1:]
+
+		index = slices.IndexFunc(config.VolumeSnapshots, func(snap *api.StorageVolumeSnapshot) bool { return snap.Name == parent })
+		if index < 0 {
+			return fmt.Errorf("Parent snapshot volume %q not found", parent)
+		}
+
+		config.VolumeSnapshots = config.VolumeSnapshots[index+1:]
+	}
+
 	indexInfo := backup.Info{
 		Name:             sourceInst.Name(),
 		Pool:             pool.Name(),
@@ -265,6 +346,8 @@ func backupWriteIndex(sourceInst instance.Instance, pool storagePools.Pool, opti
 		OptimizedStorage: &optimized,
 		OptimizedHeader:  &poolDriverOptimizedHeader,
 		Config:           config,
+		Parent:           parent,
+		ParentCreatedAt:  parentCreatedAt,
 	}
 
 	if snapshots {
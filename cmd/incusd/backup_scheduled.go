@@ -0,0 +1,554 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	internalInstance "github.com/lxc/incus/v6/internal/instance"
+	"github.com/lxc/incus/v6/internal/server/backup"
+	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/db/operationtype"
+	"github.com/lxc/incus/v6/internal/server/db/warningtype"
+	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/internal/server/lifecycle"
+	"github.com/lxc/incus/v6/internal/server/operations"
+	"github.com/lxc/incus/v6/internal/server/project"
+	"github.com/lxc/incus/v6/internal/server/state"
+	storagePools "github.com/lxc/incus/v6/internal/server/storage"
+	"github.com/lxc/incus/v6/internal/server/task"
+	"github.com/lxc/incus/v6/internal/server/warnings"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// backupScheduledPrefix is the name prefix of the backups created through scheduling.
+const backupScheduledPrefix = "scheduled-"
+
+// backupScheduledDateFormat is the format of the creation date included in scheduled backup names.
+const backupScheduledDateFormat = "20060102-150405"
+
+// backupScheduledName returns the name of a new scheduled backup.
+// The names sort in creation order which is relied on when applying the retention count.
+func backupScheduledName() string {
+	return backupScheduledPrefix + time.Now().UTC().Format(backupScheduledDateFormat)
+}
+
+// backupIsScheduled returns whether the backup name is one generated by backupScheduledName.
+func backupIsScheduled(name string) bool {
+	date, ok := strings.CutPrefix(name, backupScheduledPrefix)
+	if !ok {
+		return false
+	}
+
+	_, err := time.Parse(backupScheduledDateFormat, date)
+	return err == nil
+}
+
+func autoCreateScheduledBackupsTask(d *Daemon) (task.Func, task.Schedule) {
+	// `f` creates the scheduled instance and custom volume backups, uploads them and prunes the old ones.
+	f := func(ctx context.Context) {
+		s := d.State()
+		var instances []instance.Instance
+		var volumes []db.StorageVolumeArgs
+
+		// Get list of instances on the local member that are due to be backed up.
+		filter := dbCluster.InstanceFilter{Node: &s.ServerName}
+
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.InstanceList(ctx, func(dbInst db.InstanceArgs, p api.Project) error {
+				err := project.AllowBackupCreation(tx, p.Name)
+				if err != nil {
+					return nil
+				}
+
+				inst, err := instance.Load(s, dbInst, p)
+				if err != nil {
+					return fmt.Errorf("Failed loading instance %q (project %q) for backup task: %w", dbInst.Name, dbInst.Project, err)
+				}
+
+				// Check if instance has backup schedule enabled.
+				schedule := inst.ExpandedConfig()["backups.schedule"]
+				if schedule == "" {
+					return nil
+				}
+
+				// Check if backup is scheduled.
+				if !snapshotIsScheduledNow(schedule, int64(inst.ID())) {
+					return nil
+				}
+
+				logger.Debug("Scheduling auto instance backup", logger.Ctx{"instance": inst.Name(), "project": inst.Project().Name})
+				instances = append(instances, inst)
+
+				return nil
+			}, filter)
+		})
+		if err != nil {
+			logger.Error("Failed getting instance backup schedule info", logger.Ctx{"err": err})
+			return
+		}
+
+		// Get list of custom volumes that are due to be backed up.
+		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			allVolumes, err := tx.GetStoragePoolVolumesWithType(ctx, db.StoragePoolVolumeTypeCustom, true)
+			if err != nil {
+				return fmt.Errorf("Failed getting volumes for auto custom volume backup task: %w", err)
+			}
+
+			var dueVolumes []db.StorageVolumeArgs
+			for _, v := range allVolumes {
+				schedule := v.Config["backups.schedule"]
+				if schedule == "" {
+					continue
+				}
+
+				// Check if backup is scheduled.
+				if !snapshotIsScheduledNow(schedule, v.ID) {
+					continue
+				}
+
+				err = project.AllowBackupCreation(tx, v.ProjectName)
+				if err != nil {
+					continue
+				}
+
+				dueVolumes = append(dueVolumes, v)
+			}
+
+			volumes, err = storageVolumesForLocalMember(ctx, tx, s, dueVolumes, "backup")
+			return err
+		})
+		if err != nil {
+			logger.Error("Failed getting custom volume backup schedule info", logger.Ctx{"err": err})
+			return
+		}
+
+		if len(instances) == 0 && len(volumes) == 0 {
+			return
+		}
+
+		opRun := func(op *operations.Operation) error {
+			return autoCreateScheduledBackups(ctx, s, instances, volumes, op)
+		}
+
+		op, err := operations.OperationCreate(s, "", operations.OperationClassTask, operationtype.BackupsSchedule, nil, nil, opRun, nil, nil, nil)
+		if err != nil {
+			logger.Error("Failed creating scheduled backups operation", logger.Ctx{"err": err})
+			return
+		}
+
+		logger.Info("Creating scheduled backups")
+		err = op.Start()
+		if err != nil {
+			logger.Error("Failed starting scheduled backups operation", logger.Ctx{"err": err})
+			return
+		}
+
+		err = op.Wait(ctx)
+		if err != nil {
+			logger.Error("Failed scheduled backups", logger.Ctx{"err": err})
+			return
+		}
+
+		logger.Info("Done creating scheduled backups")
+	}
+
+	first := true
+	schedule := func() (time.Duration, error) {
+		interval := time.Minute
+
+		if first {
+			first = false
+			return interval, task.ErrSkip
+		}
+
+		return interval, nil
+	}
+
+	return f, schedule
+}
+
+// autoCreateScheduledBackups backs up the instances and custom volumes sequentially.
+// Failures are recorded as warnings against the instance or volume rather than stopping the other backups.
+func autoCreateScheduledBackups(ctx context.Context, s *state.State, instances []instance.Instance, volumes []db.StorageVolumeArgs, op *operations.Operation) error {
+	var failed int
+
+	for _, inst := range instances {
+		err := ctx.Err()
+		if err != nil {
+			return err // Stop if context is cancelled.
+		}
+
+		err = scheduledInstanceBackup(ctx, s, inst, op)
+		if err != nil {
+			failed++
+		}
+
+		scheduledBackupWarning(s, inst.Project().Name, dbCluster.TypeInstance, inst.ID(), err)
+	}
+
+	for _, v := range volumes {
+		err := ctx.Err()
+		if err != nil {
+			return err // Stop if context is cancelled.
+		}
+
+		err = scheduledVolumeBackup(ctx, s, v)
+		if err != nil {
+			failed++
+		}
+
+		scheduledBackupWarning(s, v.ProjectName, dbCluster.TypeStorageVolume, int(v.ID), err)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d scheduled backups failed", failed)
+	}
+
+	return nil
+}
+
+// scheduledBackupWarning records a warning for a failed scheduled backup or resolves it on success.
+func scheduledBackupWarning(s *state.State, projectName string, entityTypeCode int, entityID int, backupErr error) {
+	if backupErr == nil {
+		err := warnings.ResolveWarningsByLocalNodeAndProjectAndTypeAndEntity(s.DB.Cluster, projectName, warningtype.ScheduledBackupFailure, entityTypeCode, entityID)
+		if err != nil {
+			logger.Warn("Failed to resolve scheduled backup failure warning", logger.Ctx{"err": err})
+		}
+
+		return
+	}
+
+	logger.Error("Failed scheduled backup", logger.Ctx{"project": projectName, "err": backupErr})
+
+	err := s.DB.Cluster.Transaction(s.ShutdownCtx, func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.UpsertWarningLocalNode(ctx, projectName, entityTypeCode, entityID, warningtype.ScheduledBackupFailure, backupErr.Error())
+	})
+	if err != nil {
+		logger.Warn("Failed to create scheduled backup failure warning", logger.Ctx{"err": err})
+	}
+}
+
+// scheduledInstanceBackup creates a scheduled backup of the instance and either uploads it to the
+// configured target or keeps it on the server, then prunes the old scheduled backups.
+func scheduledInstanceBackup(ctx context.Context, s *state.State, inst instance.Instance, op *operations.Operation) error {
+	config := inst.ExpandedConfig()
+
+	target, err := backupTargetFromConfig(ctx, s, inst.Project().Name, config["backups.target"])
+	if err != nil {
+		return fmt.Errorf("Failed loading backup target for instance %q (project %q): %w", inst.Name(), inst.Project().Name, err)
+	}
+
+	retention, err := backupRetentionFromConfig(config["backups.retention"])
+	if err != nil {
+		return err
+	}
+
+	// Backups being uploaded are deleted from the server right away.
+	var expiry time.Time
+	if target == nil {
+		expiry, err = internalInstance.GetExpiry(time.Now(), config["backups.expiry"])
+		if err != nil {
+			return fmt.Errorf("Error getting backups.expiry date for instance %q (project %q): %w", inst.Name(), inst.Project().Name, err)
+		}
+	}
+
+	backupName := backupScheduledName()
+	fullName := inst.Name() + internalInstance.SnapshotDelimiter + backupName
+
+	args := db.InstanceBackup{
+		Name:         fullName,
+		InstanceID:   inst.ID(),
+		CreationDate: time.Now(),
+		ExpiryDate:   expiry,
+	}
+
+	err = backupCreate(s, args, inst, op)
+	if err != nil {
+		return fmt.Errorf("Error creating scheduled backup for instance %q (project %q): %w", inst.Name(), inst.Project().Name, err)
+	}
+
+	entry, err := instance.BackupLoadByName(s, inst.Project().Name, fullName)
+	if err != nil {
+		return err
+	}
+
+	if target == nil {
+		// Delete the oldest scheduled backups beyond the retention count.
+		if retention <= 0 {
+			return nil
+		}
+
+		backups, err := inst.Backups()
+		if err != nil {
+			return err
+		}
+
+		names := []string{}
+		for _, b := range backups {
+			_, name, _ := api.GetParentAndSnapshotName(b.Name())
+			if backupIsScheduled(name) {
+				names = append(names, b.Name())
+			}
+		}
+
+		for _, name := range backupsBeyondRetention(names, retention) {
+			b, err := instance.BackupLoadByName(s, inst.Project().Name, name)
+			if err != nil {
+				return err
+			}
+
+			err = b.Delete()
+			if err != nil {
+				return fmt.Errorf("Error deleting scheduled backup %q: %w", name, err)
+			}
+		}
+
+		return nil
+	}
+
+	// Upload the backup and delete it from the server.
+	targetDir := path.Join(target.Path, inst.Project().Name, "instances", inst.Name())
+	target.Path = path.Join(targetDir, backupName)
+
+	err = entry.Upload(target)
+	if err != nil {
+		_ = entry.Delete()
+		return fmt.Errorf("Error uploading scheduled backup %q: %w", fullName, err)
+	}
+
+	err = entry.Delete()
+	if err != nil {
+		return err
+	}
+
+	target.Path = targetDir
+
+	err = backup.PruneTarget(target, backupIsScheduled, retention, config["backups.expiry"])
+	if err != nil {
+		return fmt.Errorf("Error pruning scheduled backups of instance %q (project %q): %w", inst.Name(), inst.Project().Name, err)
+	}
+
+	return nil
+}
+
+// scheduledVolumeBackup creates a scheduled backup of the custom volume and either uploads it to the
+// configured target or keeps it on the server, then prunes the old scheduled backups.
+func scheduledVolumeBackup(ctx context.Context, s *state.State, v db.StorageVolumeArgs) error {
+	target, err := backupTargetFromConfig(ctx, s, v.ProjectName, v.Config["backups.target"])
+	if err != nil {
+		return fmt.Errorf("Failed loading backup target for volume %q (project %q, pool %q): %w", v.Name, v.ProjectName, v.PoolName, err)
+	}
+
+	retention, err := backupRetentionFromConfig(v.Config["backups.retention"])
+	if err != nil {
+		return err
+	}
+
+	// Backups being uploaded are deleted from the server right away.
+	var expiry time.Time
+	if target == nil {
+		expiry, err = internalInstance.GetExpiry(time.Now(), v.Config["backups.expiry"])
+		if err != nil {
+			return fmt.Errorf("Error getting backups.expiry date for volume %q (project %q, pool %q): %w", v.Name, v.ProjectName, v.PoolName, err)
+		}
+	}
+
+	backupName := backupScheduledName()
+	fullName := v.Name + internalInstance.SnapshotDelimiter + backupName
+
+	args := db.StoragePoolVolumeBackup{
+		Name:         fullName,
+		VolumeID:     v.ID,
+		CreationDate: time.Now(),
+		ExpiryDate:   expiry,
+	}
+
+	err = volumeBackupCreate(s, args, v.ProjectName, v.PoolName, v.Name)
+	if err != nil {
+		return fmt.Errorf("Error creating scheduled backup for volume %q (project %q, pool %q): %w", v.Name, v.ProjectName, v.PoolName, err)
+	}
+
+	s.Events.SendLifecycle(v.ProjectName, lifecycle.StorageVolumeBackupCreated.Event(v.PoolName, db.StoragePoolVolumeTypeNameCustom, fullName, v.ProjectName, nil, logger.Ctx{"type": db.StoragePoolVolumeTypeNameCustom}))
+
+	entry, err := storagePoolVolumeBackupLoadByName(ctx, s, v.ProjectName, v.PoolName, fullName)
+	if err != nil {
+		return err
+	}
+
+	if target == nil {
+		// Delete the oldest scheduled backups beyond the retention count.
+		if retention <= 0 {
+			return nil
+		}
+
+		var backupNames []string
+		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			backupNames, err = tx.GetStoragePoolVolumeBackupsNames(ctx, v.ProjectName, v.Name, v.PoolID)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		names := []string{}
+		for _, name := range backupNames {
+			_, backupName, _ := api.GetParentAndSnapshotName(name)
+			if backupIsScheduled(backupName) {
+				names = append(names, name)
+			}
+		}
+
+		for _, name := range backupsBeyondRetention(names, retention) {
+			b, err := storagePoolVolumeBackupLoadByName(ctx, s, v.ProjectName, v.PoolName, name)
+			if err != nil {
+				return err
+			}
+
+			err = b.Delete()
+			if err != nil {
+				return fmt.Errorf("Error deleting scheduled backup %q: %w", name, err)
+			}
+		}
+
+		return nil
+	}
+
+	// Upload the backup and delete it from the server.
+	targetDir := path.Join(target.Path, v.ProjectName, "volumes", v.PoolName, v.Name)
+	target.Path = path.Join(targetDir, backupName)
+
+	err = entry.Upload(target)
+	if err != nil {
+		_ = entry.Delete()
+		return fmt.Errorf("Error uploading scheduled backup %q: %w", fullName, err)
+	}
+
+	err = entry.Delete()
+	if err != nil {
+		return err
+	}
+
+	target.Path = targetDir
+
+	err = backup.PruneTarget(target, backupIsScheduled, retention, v.Config["backups.expiry"])
+	if err != nil {
+		return fmt.Errorf("Error pruning scheduled backups of volume %q (project %q, pool %q): %w", v.Name, v.ProjectName, v.PoolName, err)
+	}
+
+	return nil
+}
+
+// backupsBeyondRetention returns the backup names which exceed the retention count, oldest first.
+func backupsBeyondRetention(names []string, retention int) []string {
+	if len(names) <= retention {
+		return nil
+	}
+
+	slices.Sort(names)
+
+	return names[:len(names)-retention]
+}
+
+// backupRetentionFromConfig parses a backups.retention value.
+func backupRetentionFromConfig(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	retention, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid backups.retention %q: %w", value, err)
+	}
+
+	return retention, nil
+}
+
+// backupTargetFromConfig returns the backup target for a backups.target value, nil meaning the backups
+// are to be kept on the server. S3 targets are loaded from the server configuration, storage bucket
+// targets are resolved to the bucket's S3 endpoint using its first admin key.
+func backupTargetFromConfig(ctx context.Context, s *state.State, projectName string, value string) (*api.BackupTarget, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	if internalInstance.IsBackupTargetS3(value) {
+		name, err := internalInstance.ParseBackupTargetS3(value)
+		if err != nil {
+			return nil, err
+		}
+
+		targetURL, accessKey, secretKey := s.GlobalConfig.BackupsS3Target(name)
+		if targetURL == "" {
+			return nil, fmt.Errorf("S3 backup target %q isn't configured on the server", name)
+		}
+
+		return internalInstance.BackupTargetFromURL(targetURL, accessKey, secretKey)
+	}
+
+	poolName, bucketName, err := internalInstance.ParseBackupTargetBucket(value)
+	if err != nil {
+		return nil, err
+	}
+
+	pool, err := storagePools.LoadByName(s, poolName)
+	if err != nil {
+		return nil, err
+	}
+
+	if !pool.Driver().Info().Buckets {
+		return nil, fmt.Errorf("Storage pool %q does not support buckets", poolName)
+	}
+
+	var bucketKey *db.StorageBucketKey
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		bucket, err := tx.GetStoragePoolBucket(ctx, pool.ID(), projectName, !pool.Driver().Info().Remote, bucketName)
+		if err != nil {
+			return err
+		}
+
+		keys, err := tx.GetStoragePoolBucketKeys(ctx, bucket.ID)
+		if err != nil {
+			return err
+		}
+
+		for _, key := range keys {
+			if key.Role == "admin" {
+				bucketKey = key
+				break
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading storage bucket %q: %w", value, err)
+	}
+
+	if bucketKey == nil {
+		return nil, fmt.Errorf("Storage bucket %q has no admin key", value)
+	}
+
+	bucketURL := pool.GetBucketURL(bucketName)
+	if bucketURL == nil {
+		return nil, errors.New("The server is lacking a storage buckets listener address")
+	}
+
+	target := &api.BackupTarget{
+		Protocol:   "s3",
+		URL:        (&url.URL{Scheme: bucketURL.Scheme, Host: bucketURL.Host}).String(),
+		BucketName: strings.Trim(bucketURL.Path, "/"),
+		AccessKey:  bucketKey.AccessKey,
+		SecretKey:  bucketKey.SecretKey,
+	}
+
+	return target, nil
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackupsBeyondRetention(t *testing.T) {
+	tests := []struct {
+		name      string
+		names     []string
+		retention int
+		want      []string
+	}{
+		{
+			name:      "below retention",
+			names:     []string{"c1/scheduled-20240101-000000"},
+			retention: 2,
+			want:      nil,
+		},
+		{
+			name:      "at retention",
+			names:     []string{"c1/scheduled-20240101-000000", "c1/scheduled-20240102-000000"},
+			retention: 2,
+			want:      nil,
+		},
+		{
+			name:      "oldest first regardless of input order",
+			names:     []string{"c1/scheduled-20240103-000000", "c1/scheduled-20240101-000000", "c1/scheduled-20240104-000000", "c1/scheduled-20240102-000000"},
+			retention: 2,
+			want:      []string{"c1/scheduled-20240101-000000", "c1/scheduled-20240102-000000"},
+		},
+		{
+			name:      "same day",
+			names:     []string{"c1/scheduled-20240101-120000", "c1/scheduled-20240101-090000"},
+			retention: 1,
+			want:      []string{"c1/scheduled-20240101-090000"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, backupsBeyondRetention(tt.names, tt.retention))
+		})
+	}
+}
+
+func TestBackupIsScheduled(t *testing.T) {
+	assert.True(t, backupIsScheduled(backupScheduledName()))
+	assert.True(t, backupIsScheduled("scheduled-20240101-000000"))
+	assert.False(t, backupIsScheduled("scheduled-manual"))
+	assert.False(t, backupIsScheduled("backup0"))
+	assert.False(t, backupIsScheduled("scheduled-20240101-000000.tar.gz"))
+}
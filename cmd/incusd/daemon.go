@@ -18,6 +18,7 @@ import (
 	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	dqliteClient "github.com/cowsql/go-cowsql/client"
@@ -49,6 +50,8 @@ import (
 	"github.com/lxc/incus/v6/internal/server/instance"
 	instanceDrivers "github.com/lxc/incus/v6/internal/server/instance/drivers"
 	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
+	"github.com/lxc/incus/v6/internal/server/lifecycle"
+	"github.com/lxc/incus/v6/internal/server/locking"
 	"github.com/lxc/incus/v6/internal/server/logging"
 	"github.com/lxc/incus/v6/internal/server/network/ovn"
 	"github.com/lxc/incus/v6/internal/server/network/ovs"
@@ -105,6 +108,8 @@ type Daemon struct {
 	// Indexes of tasks that need to be reset when their execution interval changes
 	taskPruneImages      *task.Task
 	taskClusterHeartbeat *task.Task
+	taskMetricsPush      *task.Task
+	taskImageChunkStore  *task.Task
 
 	// Stores startup time of daemon
 	startTime time.Time
@@ -137,6 +142,7 @@ type Daemon struct {
 	shutdownCtx    context.Context    // Cancelled when shutdown starts.
 	shutdownCancel context.CancelFunc // Cancels the shutdownCtx to indicate shutdown starting.
 	shutdownDoneCh chan error         // Receives the result of the d.Stop() function and tells the daemon to end.
+	restartOnStop  atomic.Bool        // Whether the daemon should start again once stopped.
 
 	// Device monitor for watching filesystem events
 	devmonitor fsmonitor.FSMonitor
@@ -923,6 +929,14 @@ func (d *Daemon) init() error {
 		return err
 	}
 
+	// Setup the event journal.
+	eventJournal, err := events.NewJournal(filepath.Join(d.os.VarDir, "events.journal"), 10000)
+	if err != nil {
+		logger.Warn("Failed to load the event journal, missed events won't be replayed", logger.Ctx{"err": err})
+	} else {
+		d.events.SetJournal(eventJournal)
+	}
+
 	// Initialize apparmor.
 	if d.os.AppArmorAvailable {
 		err := apparmor.Init()
@@ -1527,6 +1541,56 @@ func (d *Daemon) init() error {
 		}
 
 		return resp, nil
+	}, func(name string, requestor *api.EventLifecycleRequestor, update func(content string, records []api.NetworkZoneRecord) ([]api.NetworkZoneRecord, error)) error {
+		// Serialize the updates of the zone handled by this member, those going through other members
+		// are caught when applying the changes.
+		unlock, err := locking.Lock(context.TODO(), fmt.Sprintf("network_zone_update_%s", name))
+		if err != nil {
+			return err
+		}
+
+		defer unlock()
+
+		// Fetch the zone.
+		zone, err := networkZone.LoadByName(d.State(), name)
+		if err != nil {
+			return err
+		}
+
+		// Get the records before rendering the zone, so that any change made in between is caught.
+		records, err := zone.GetRecords()
+		if err != nil {
+			return err
+		}
+
+		zoneBuilder, err := zone.Content()
+		if err != nil {
+			return err
+		}
+
+		changed, err := update(strings.TrimSpace(zoneBuilder.String()), records)
+		if err != nil {
+			return err
+		}
+
+		// Apply all the changes at once, failing if the records changed since being checked.
+		err = zone.UpdateRecords(records, changed)
+		if err != nil {
+			return err
+		}
+
+		for _, record := range changed {
+			action := lifecycle.NetworkZoneRecordUpdated
+			if !slices.ContainsFunc(records, func(existing api.NetworkZoneRecord) bool { return strings.EqualFold(existing.Name, record.Name) }) {
+				action = lifecycle.NetworkZoneRecordCreated
+			} else if len(record.Entries) == 0 {
+				action = lifecycle.NetworkZoneRecordDeleted
+			}
+
+			d.State().Events.SendLifecycle(zone.Project(), action.Event(zone, record.Name, requestor, nil))
+		}
+
+		return nil
 	})
 	if dnsAddress != "" {
 		err := d.dns.Start(dnsAddress)
@@ -1657,6 +1721,9 @@ func (d *Daemon) init() error {
 	// Start cluster tasks if needed.
 	if d.serverClustered {
 		d.startClusterTasks()
+
+		// Restore the members left evacuated by a rolling upgrade.
+		go clusterRollingRestartRestorePending(d.State())
 	}
 
 	// FIXME: There's no hard reason for which we should not run these
@@ -1675,6 +1742,9 @@ func (d *Daemon) init() error {
 		// Auto-update images (every 6 hours, configurable)
 		d.tasks.Add(autoUpdateImagesTask(d))
 
+		// Move image files in or out of the chunk store (hourly)
+		d.taskImageChunkStore = d.tasks.Add(imageChunkStoreTask(d))
+
 		// Auto-update instance types (daily)
 		d.tasks.Add(instanceRefreshTypesTask(d))
 
@@ -1687,6 +1757,15 @@ func (d *Daemon) init() error {
 		// Prune expired custom volume snapshots and take snapshots of custom volumes (minutely check of configurable cron expression)
 		d.tasks.Add(pruneExpiredAndAutoCreateCustomVolumeSnapshotsTask(d))
 
+		// Take scheduled backups of instances and custom volumes (minutely check of configurable cron expression)
+		d.tasks.Add(autoCreateScheduledBackupsTask(d))
+
+		// Replicate custom volumes to their replication target (minutely check of configurable cron expression)
+		d.tasks.Add(autoReplicateStorageVolumesTask(d))
+
+		// Check storage pool usage against the configured thresholds (every 5 minutes)
+		d.tasks.Add(autoCheckStoragePoolsUsageTask(d))
+
 		// Remove resolved warnings (daily)
 		d.tasks.Add(pruneResolvedWarningsTask(d))
 
@@ -1695,6 +1774,9 @@ func (d *Daemon) init() error {
 
 		// Remove expired tokens (hourly)
 		d.tasks.Add(autoRemoveExpiredTokensTask(d))
+
+		// Push metrics to a remote endpoint (configurable interval)
+		d.taskMetricsPush = d.tasks.Add(metricsPushTask(d))
 	}
 
 	// Start all background tasks
@@ -19,6 +19,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/operations"
 	"github.com/lxc/incus/v6/internal/server/response"
 	"github.com/lxc/incus/v6/internal/server/state"
+	storagePools "github.com/lxc/incus/v6/internal/server/storage"
 	localUtil "github.com/lxc/incus/v6/internal/server/util"
 	internalUtil "github.com/lxc/incus/v6/internal/util"
 	"github.com/lxc/incus/v6/internal/version"
@@ -26,6 +27,7 @@ import (
 	"github.com/lxc/incus/v6/shared/cancel"
 	"github.com/lxc/incus/v6/shared/ioprogress"
 	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/revert"
 	"github.com/lxc/incus/v6/shared/units"
 	"github.com/lxc/incus/v6/shared/util"
 )
@@ -398,12 +400,21 @@ func ImageDownload(ctx context.Context, r *http.Request, s *state.State, op *ope
 
 		// Download the image
 		var resp *incus.ImageFileResponse
+		var deltaReleases []revert.Hook
 		request := incus.ImageFileRequest{
 			MetaFile:        io.WriteSeeker(dest),
 			RootfsFile:      io.WriteSeeker(destRootfs),
 			ProgressHandler: progress,
 			Canceler:        canceler,
 			DeltaSourceRetriever: func(fingerprint string, file string) string {
+				// Rebuild the delta source from the chunk store if needed, until the download completes.
+				release, err := storagePools.ImageFileMaterialize(fingerprint)
+				if err != nil {
+					return ""
+				}
+
+				deltaReleases = append(deltaReleases, release)
+
 				path := internalUtil.VarPath("images", fmt.Sprintf("%s.%s", fingerprint, file))
 				if util.PathExists(path) {
 					return path
@@ -413,6 +424,12 @@ func ImageDownload(ctx context.Context, r *http.Request, s *state.State, op *ope
 			},
 		}
 
+		defer func() {
+			for _, release := range deltaReleases {
+				release()
+			}
+		}()
+
 		if args.Secret != "" {
 			resp, err = remote.GetPrivateImageFile(fp, args.Secret, request)
 		} else {
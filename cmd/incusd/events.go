@@ -2,9 +2,11 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/lxc/incus/v6/internal/server/auth"
@@ -106,6 +108,28 @@ func eventsSocket(s *state.State, r *http.Request, w http.ResponseWriter) error
 		return api.StatusErrorf(http.StatusForbidden, "Forbidden")
 	}
 
+	// Parse the journal cursor.
+	var since uint64
+	sinceStr := request.QueryParam(r, "since")
+	if sinceStr != "" {
+		var err error
+
+		since, err = strconv.ParseUint(sinceStr, 10, 64)
+		if err != nil {
+			return api.StatusErrorf(http.StatusBadRequest, "Invalid since value %q", sinceStr)
+		}
+
+		// Check before upgrading the connection so that the client gets a proper error.
+		err = s.Events.CheckSince(since)
+		if err != nil {
+			if errors.Is(err, events.ErrJournalGap) || errors.Is(err, events.ErrJournalAhead) {
+				return api.StatusErrorf(http.StatusGone, "%v", err)
+			}
+
+			return api.StatusErrorf(http.StatusServiceUnavailable, "%v", err)
+		}
+	}
+
 	l := logger.AddContext(logger.Ctx{"remote": r.RemoteAddr})
 
 	var excludeLocations []string
@@ -159,7 +183,14 @@ func eventsSocket(s *state.State, r *http.Request, w http.ResponseWriter) error
 	defer func() { _ = conn.Close() }() // Ensure listener below ends when this function ends.
 
 	listenerConnection := events.NewWebsocketListenerConnection(conn)
-	listener, err := s.Events.AddListener(projectName, allProjects, projectPermissionFunc, listenerConnection, types, excludeSources, recvFunc, excludeLocations)
+
+	var listener *events.Listener
+	if sinceStr != "" {
+		listener, err = s.Events.AddListenerSince(since, projectName, allProjects, projectPermissionFunc, listenerConnection, types, excludeSources, recvFunc, excludeLocations)
+	} else {
+		listener, err = s.Events.AddListener(projectName, allProjects, projectPermissionFunc, listenerConnection, types, excludeSources, recvFunc, excludeLocations)
+	}
+
 	if err != nil {
 		l.Warn("Failed to add event listener", logger.Ctx{"err": err})
 		return nil
@@ -194,6 +225,11 @@ func eventsSocket(s *state.State, r *http.Request, w http.ResponseWriter) error
 //	    name: all-projects
 //	    description: Retrieve instances from all projects
 //	    type: boolean
+//	  - in: query
+//	    name: since
+//	    description: Replay the journaled events with a greater ID before the live events
+//	    type: integer
+//	    example: 1234
 //	responses:
 //	  "200":
 //	    description: Websocket message (JSON)
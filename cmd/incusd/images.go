@@ -9,7 +9,6 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"io/fs"
 	"maps"
 	"math"
 	"math/rand"
@@ -44,6 +43,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
 	"github.com/lxc/incus/v6/internal/server/lifecycle"
 	"github.com/lxc/incus/v6/internal/server/node"
+	"github.com/lxc/incus/v6/internal/server/oci"
 	"github.com/lxc/incus/v6/internal/server/operations"
 	projectutils "github.com/lxc/incus/v6/internal/server/project"
 	"github.com/lxc/incus/v6/internal/server/request"
@@ -521,6 +521,101 @@ func imgPostInstanceInfo(ctx context.Context, s *state.State, r *http.Request, r
 	return &info, nil
 }
 
+// imgPostInstanceOCI publishes an instance or snapshot as an OCI image to the target registry.
+// It returns the digest of the pushed image manifest.
+func imgPostInstanceOCI(ctx context.Context, s *state.State, r *http.Request, req api.ImagesPost, op *operations.Operation, builddir string) (string, error) {
+	projectName := request.ProjectParam(r)
+	name := req.Source.Name
+
+	if name == "" {
+		return "", errors.New("No source provided")
+	}
+
+	if (req.Source.Type == "snapshot") != internalInstance.IsSnapshot(name) {
+		return "", errors.New("Source type doesn't match the source name")
+	}
+
+	inst, err := instance.LoadByProjectAndName(s, projectName, name)
+	if err != nil {
+		return "", err
+	}
+
+	if inst.Type() != instancetype.Container {
+		return "", errors.New("Only containers can be published as OCI images")
+	}
+
+	imageConfig, err := oci.ImageConfig(inst.ExpandedConfig())
+	if err != nil {
+		return "", err
+	}
+
+	architecture, err := osarch.ArchitectureName(inst.Architecture())
+	if err != nil {
+		return "", err
+	}
+
+	// Tracker instance for the export phase.
+	metadata := make(map[string]any)
+	tracker := &ioprogress.ProgressTracker{
+		Handler: func(value, speed int64) {
+			operations.SetProgressMetadata(metadata, "create_image_from_container_pack", "Exporting", value, 0, 0)
+			_ = op.UpdateMetadata(metadata)
+		},
+	}
+
+	// Build the OCI image from the exported root filesystem.
+	layoutPath := filepath.Join(builddir, "oci")
+	rootfsReader, rootfsWriter := io.Pipe()
+
+	exportDone := make(chan error, 1)
+	go func() {
+		_, exportErr := inst.Export(io.Discard, rootfsWriter, nil, time.Time{}, tracker)
+		_ = rootfsWriter.CloseWithError(exportErr)
+		exportDone <- exportErr
+	}()
+
+	err = oci.CreateImage(ctx, layoutPath, "latest", rootfsReader, architecture, imageConfig)
+
+	// Unblock and wait for the export in case the image creation failed.
+	_ = rootfsReader.Close()
+	exportErr := <-exportDone
+	if err != nil {
+		return "", err
+	}
+
+	if exportErr != nil {
+		return "", fmt.Errorf("Failed exporting instance: %w", exportErr)
+	}
+
+	// Push the image under each of the requested names.
+	remote, err := incus.ConnectOCI(req.Target, &incus.ConnectionArgs{
+		UserAgent: version.UserAgent,
+		Proxy:     s.Proxy,
+		TempPath:  builddir,
+	})
+	if err != nil {
+		return "", fmt.Errorf("Failed to connect to OCI registry %q: %w", req.Target, err)
+	}
+
+	registry, ok := remote.(*incus.ProtocolOCI)
+	if !ok {
+		return "", errors.New("Unexpected OCI registry client")
+	}
+
+	var digest string
+	for _, alias := range req.Aliases {
+		metadata["create_image_from_container_pack_progress"] = fmt.Sprintf("Pushing %s", alias.Name)
+		_ = op.UpdateMetadata(metadata)
+
+		digest, err = registry.PushImage(layoutPath, "latest", alias.Name)
+		if err != nil {
+			return "", fmt.Errorf("Failed pushing image %q: %w", alias.Name, err)
+		}
+	}
+
+	return digest, nil
+}
+
 func imgPostRemoteInfo(ctx context.Context, s *state.State, r *http.Request, req api.ImagesPost, op *operations.Operation, project string, budget int64) (*api.Image, error) {
 	var err error
 	var hash string
@@ -811,6 +906,15 @@ func getImgPostInfo(ctx context.Context, s *state.State, r *http.Request, buildd
 			})
 			return nil, err
 		}
+	} else if oci.IsArchive(post.Name()) {
+		// OCI archives are converted into a split image, keeping the manifest digest as the fingerprint.
+		imageMeta, err = imgPostOCIArchive(builddir, post.Name(), r.Header.Get("X-Incus-oci-reference"), &info)
+		if err != nil {
+			l.Error("Failed to import OCI archive", logger.Ctx{"err": err})
+			return nil, err
+		}
+
+		info.Filename = r.Header.Get("X-Incus-filename")
 	} else {
 		_, err = post.Seek(0, io.SeekStart)
 		if err != nil {
@@ -966,6 +1070,70 @@ func getImgPostInfo(ctx context.Context, s *state.State, r *http.Request, buildd
 	return &info, nil
 }
 
+// imgPostOCIArchive unpacks the image from an uploaded OCI archive into the images directory.
+// The image fingerprint, size and type are filled into info and the image metadata is returned.
+func imgPostOCIArchive(builddir string, path string, reference string, info *api.Image) (*api.ImageMetadata, error) {
+	remote, err := incus.ConnectOCI("oci-archive:"+path, &incus.ConnectionArgs{
+		UserAgent: version.UserAgent,
+		TempPath:  builddir,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entry, _, err := remote.GetImageAlias(reference)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to find image %q in OCI archive: %w", reference, err)
+	}
+
+	img, _, err := remote.GetImage(entry.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	metaFile, err := os.CreateTemp(builddir, "incus_oci_meta_")
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = metaFile.Close() }()
+
+	rootfsFile, err := os.CreateTemp(builddir, "incus_oci_rootfs_")
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = rootfsFile.Close() }()
+
+	resp, err := remote.GetImageFile(img.Fingerprint, incus.ImageFileRequest{
+		MetaFile:   metaFile,
+		RootfsFile: rootfsFile,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = internalUtil.FileMove(metaFile.Name(), internalUtil.VarPath("images", img.Fingerprint))
+	if err != nil {
+		return nil, err
+	}
+
+	err = internalUtil.FileMove(rootfsFile.Name(), internalUtil.VarPath("images", img.Fingerprint+".rootfs"))
+	if err != nil {
+		return nil, err
+	}
+
+	info.Fingerprint = img.Fingerprint
+	info.Size = resp.MetaSize + resp.RootfsSize
+	info.Type = img.Type
+
+	return &api.ImageMetadata{
+		Architecture: img.Architecture,
+		CreationDate: img.CreatedAt.Unix(),
+		Properties:   img.Properties,
+	}, nil
+}
+
 // imageCreateInPool() creates a new storage volume in a given storage pool for
 // the image. No entry in the images database will be created. This implies that
 // imageCreateinPool() should only be called when an image already exists in the
@@ -1093,6 +1261,11 @@ func imageCreateInPool(s *state.State, info *api.Image, storagePool string) erro
 //	      type: array
 //	      items:
 //	        type: string
+//	  - in: header
+//	    name: X-Incus-oci-reference
+//	    description: Reference of the image to import from an OCI archive
+//	    schema:
+//	      type: string
 //	responses:
 //	  "202":
 //	    $ref: "#/responses/Operation"
@@ -1217,6 +1390,25 @@ func imagesPost(d *Daemon, r *http.Request) response.Response {
 		return response.InternalError(errors.New("Invalid images JSON"))
 	}
 
+	if !imageUpload && req.Format == "oci" {
+		if !slices.Contains([]string{"container", "instance", "snapshot"}, req.Source.Type) {
+			cleanup(builddir, post)
+			return response.BadRequest(errors.New("OCI images can only be published from instances or snapshots"))
+		}
+
+		var targetURL *url.URL
+		targetURL, err = url.Parse(req.Target)
+		if err != nil || !slices.Contains([]string{"http", "https"}, targetURL.Scheme) || targetURL.Host == "" {
+			cleanup(builddir, post)
+			return response.BadRequest(errors.New("A valid target registry URL is required for OCI images"))
+		}
+
+		if len(req.Aliases) == 0 {
+			cleanup(builddir, post)
+			return response.BadRequest(errors.New("At least one alias is required to name the OCI image"))
+		}
+	}
+
 	/* Forward requests for containers on other nodes */
 	if !imageUpload && slices.Contains([]string{"container", "instance", "virtual-machine", "snapshot"}, req.Source.Type) {
 		name := req.Source.Name
@@ -1248,6 +1440,20 @@ func imagesPost(d *Daemon, r *http.Request) response.Response {
 		// Setup the cleanup function
 		defer cleanup(builddir, post)
 
+		// OCI images are pushed to the target registry rather than stored locally.
+		if !imageUpload && req.Format == "oci" {
+			var digest string
+
+			imagePublishLock.Lock()
+			digest, err = imgPostInstanceOCI(context.TODO(), s, r, req, op, builddir)
+			imagePublishLock.Unlock()
+			if err != nil {
+				return err
+			}
+
+			return op.UpdateMetadata(map[string]any{"digest": digest})
+		}
+
 		if imageUpload {
 			/* Processing image upload */
 			info, err = getImgPostInfo(context.TODO(), s, r, builddir, projectName, post, imageMetadata)
@@ -2097,6 +2303,13 @@ func distributeImage(ctx context.Context, s *state.State, nodes []string, oldFin
 		return err
 	}
 
+	release, err := storagePools.ImageFileMaterialize(newImage.Fingerprint)
+	if err != nil {
+		return err
+	}
+
+	defer release()
+
 	for _, nodeAddress := range nodes {
 		if nodeAddress == localClusterAddress {
 			continue
@@ -2440,26 +2653,64 @@ func autoUpdateImage(ctx context.Context, s *state.State, op *operations.Operati
 		return nil, nil
 	}
 
-	// Remove main image file.
-	fname := filepath.Join(s.OS.VarDir, "images", fingerprint)
-	if util.PathExists(fname) {
-		err = os.Remove(fname)
+	// Remove the image files.
+	err = storagePools.ImageFileDelete(fingerprint)
+	if err != nil {
+		logger.Error("Error deleting image files", logger.Ctx{"fingerprint": fingerprint, "err": err})
+	}
+
+	setRefreshResult(true)
+	return newInfo, nil
+}
+
+// imageChunkStoreTask moves the local image files in or out of the chunk store, depending on images.chunk_store,
+// and removes the chunks which aren't used by any image anymore.
+func imageChunkStoreTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+
+		var fingerprints []string
+
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			var err error
+
+			fingerprints, err = tx.GetLocalImagesFingerprints(ctx)
+
+			return err
+		})
 		if err != nil {
-			logger.Error("Error deleting image file", logger.Ctx{"fingerprint": fingerprint, "file": fname, "err": err})
+			logger.Error("Failed getting local images for chunk store", logger.Ctx{"err": err})
+			return
+		}
+
+		imageTaskMu.Lock()
+		defer imageTaskMu.Unlock()
+
+		enabled := s.GlobalConfig.ImagesChunkStore()
+		for _, fingerprint := range fingerprints {
+			if enabled {
+				err = storagePools.ImageFileChunk(fingerprint)
+			} else {
+				err = storagePools.ImageFileUnchunk(fingerprint)
+			}
+
+			if err != nil {
+				logger.Error("Failed updating image files in chunk store", logger.Ctx{"fingerprint": fingerprint, "err": err})
+			}
 		}
-	}
 
-	// Remove the rootfs file for the image.
-	fname = filepath.Join(s.OS.VarDir, "images", fingerprint) + ".rootfs"
-	if util.PathExists(fname) {
-		err = os.Remove(fname)
+		removed, err := storagePools.ImageChunksPrune()
 		if err != nil {
-			logger.Error("Error deleting image rootfs file", logger.Ctx{"fingerprint": fingerprint, "file": fname, "err": err})
+			logger.Error("Failed pruning image chunk store", logger.Ctx{"err": err})
+			return
+		}
+
+		if removed > 0 {
+			logger.Info("Pruned unused image chunks", logger.Ctx{"count": removed})
 		}
 	}
 
-	setRefreshResult(true)
-	return newInfo, nil
+	return f, task.Hourly()
 }
 
 func pruneExpiredImagesTask(d *Daemon) (task.Func, task.Schedule) {
@@ -2574,6 +2825,11 @@ func pruneLeftoverImages(s *state.State) {
 
 		// Check and delete leftovers
 		for _, entry := range entries {
+			// Skip the image chunk store.
+			if entry.Name() == storagePools.ImageChunksDir {
+				continue
+			}
+
 			fp := strings.Split(entry.Name(), ".")[0]
 			if !slices.Contains(images, fp) {
 				err = os.RemoveAll(internalUtil.VarPath("images", entry.Name()))
@@ -2755,18 +3011,10 @@ func pruneExpiredImages(ctx context.Context, s *state.State, op *operations.Oper
 			}
 		}
 
-		// Remove main image file.
-		fname := filepath.Join(s.OS.VarDir, "images", fingerprint)
-		err = os.Remove(fname)
-		if err != nil && !errors.Is(err, fs.ErrNotExist) {
-			return fmt.Errorf("Error deleting image file %q: %w", fname, err)
-		}
-
-		// Remove the rootfs file for the image.
-		fname = filepath.Join(s.OS.VarDir, "images", fingerprint) + ".rootfs"
-		err = os.Remove(fname)
-		if err != nil && !errors.Is(err, fs.ErrNotExist) {
-			return fmt.Errorf("Error deleting image file %q: %w", fname, err)
+		// Remove the image files.
+		err = storagePools.ImageFileDelete(fingerprint)
+		if err != nil {
+			return err
 		}
 
 		logger.Info("Deleted expired cached image files and volumes", logger.Ctx{"fingerprint": fingerprint})
@@ -2987,22 +3235,9 @@ func imageDelete(d *Daemon, r *http.Request) response.Response {
 
 // Helper to delete an image file from the local images directory.
 func imageDeleteFromDisk(fingerprint string) {
-	// Remove main image file.
-	fname := internalUtil.VarPath("images", fingerprint)
-	if util.PathExists(fname) {
-		err := os.Remove(fname)
-		if err != nil && !errors.Is(err, fs.ErrNotExist) {
-			logger.Errorf("Error deleting image file %s: %s", fname, err)
-		}
-	}
-
-	// Remove the rootfs file for the image.
-	fname = internalUtil.VarPath("images", fingerprint) + ".rootfs"
-	if util.PathExists(fname) {
-		err := os.Remove(fname)
-		if err != nil && !errors.Is(err, fs.ErrNotExist) {
-			logger.Errorf("Error deleting image file %s: %s", fname, err)
-		}
+	err := storagePools.ImageFileDelete(fingerprint)
+	if err != nil {
+		logger.Errorf("Error deleting image files for %s: %s", fingerprint, err)
 	}
 }
 
@@ -4276,6 +4511,15 @@ func imageExport(d *Daemon, r *http.Request) response.Response {
 		headers["X-Incus-Type"] = "oci"
 	}
 
+	// Make sure the image files are available, the files are opened right away so they remain
+	// readable while the response is sent, even if they get moved back to the chunk store.
+	release, err := storagePools.ImageFileMaterialize(imgInfo.Fingerprint)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	defer release()
+
 	imagePath := internalUtil.VarPath("images", imgInfo.Fingerprint)
 	rootfsPath := imagePath + ".rootfs"
 
@@ -4289,8 +4533,12 @@ func imageExport(d *Daemon, r *http.Request) response.Response {
 	if util.PathExists(rootfsPath) {
 		files := make([]response.FileResponseEntry, 2)
 
+		files[0], err = imageFileResponseEntry(imagePath)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
 		files[0].Identifier = "metadata"
-		files[0].Path = imagePath
 		files[0].Filename = "meta-" + filename
 
 		// Recompute the extension for the root filesystem, it may use a different
@@ -4308,15 +4556,27 @@ func imageExport(d *Daemon, r *http.Request) response.Response {
 			files[1].Identifier = "rootfs"
 		}
 
-		files[1].Path = rootfsPath
-		files[1].Filename = filename
+		rootfsEntry, err := imageFileResponseEntry(rootfsPath)
+		if err != nil {
+			files[0].Cleanup()
+			return response.SmartError(err)
+		}
+
+		rootfsEntry.Identifier = files[1].Identifier
+		rootfsEntry.Filename = filename
+		files[1] = rootfsEntry
 
 		return response.FileResponse(r, files, headers)
 	}
 
 	files := make([]response.FileResponseEntry, 1)
+
+	files[0], err = imageFileResponseEntry(imagePath)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	files[0].Identifier = filename
-	files[0].Path = imagePath
 	files[0].Filename = filename
 
 	requestor := request.CreateRequestor(r)
@@ -4325,6 +4585,27 @@ func imageExport(d *Daemon, r *http.Request) response.Response {
 	return response.FileResponse(r, files, headers)
 }
 
+// imageFileResponseEntry returns a file response entry for an already opened image file.
+func imageFileResponseEntry(path string) (response.FileResponseEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return response.FileResponseEntry{}, err
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return response.FileResponseEntry{}, err
+	}
+
+	return response.FileResponseEntry{
+		File:         f,
+		FileSize:     fi.Size(),
+		FileModified: fi.ModTime(),
+		Cleanup:      func() { _ = f.Close() },
+	}, nil
+}
+
 // swagger:operation POST /1.0/images/{fingerprint}/export images images_export_post
 //
 //	Make the server push the image to a remote server
@@ -4399,6 +4680,13 @@ func imageExportPost(d *Daemon, r *http.Request) response.Response {
 	var imageCreateOp incus.Operation
 
 	run := func(op *operations.Operation) error {
+		release, err := storagePools.ImageFileMaterialize(fingerprint)
+		if err != nil {
+			return err
+		}
+
+		defer release()
+
 		createArgs := &incus.ImageCreateArgs{}
 		imageMetaPath := internalUtil.VarPath("images", fingerprint)
 		imageRootfsPath := internalUtil.VarPath("images", fingerprint+".rootfs")
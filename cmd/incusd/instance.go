@@ -15,6 +15,7 @@ import (
 	"github.com/kballard/go-shellquote"
 	ociSpecs "github.com/opencontainers/runtime-spec/specs-go"
 
+	incus "github.com/lxc/incus/v6/client"
 	internalInstance "github.com/lxc/incus/v6/internal/instance"
 	"github.com/lxc/incus/v6/internal/server/cluster"
 	"github.com/lxc/incus/v6/internal/server/db"
@@ -24,11 +25,14 @@ import (
 	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
 	"github.com/lxc/incus/v6/internal/server/instance/operationlock"
 	"github.com/lxc/incus/v6/internal/server/locking"
+	"github.com/lxc/incus/v6/internal/server/oci"
 	"github.com/lxc/incus/v6/internal/server/operations"
 	"github.com/lxc/incus/v6/internal/server/project"
 	"github.com/lxc/incus/v6/internal/server/state"
 	storagePools "github.com/lxc/incus/v6/internal/server/storage"
 	"github.com/lxc/incus/v6/internal/server/task"
+	internalUtil "github.com/lxc/incus/v6/internal/util"
+	"github.com/lxc/incus/v6/internal/version"
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/logger"
 	"github.com/lxc/incus/v6/shared/revert"
@@ -229,53 +233,224 @@ func instanceCreateFromImage(ctx context.Context, s *state.State, img *api.Image
 			return err
 		}
 
-		// Update the config for the environment variables.
-		args.Config["volatile.container.oci"] = "true"
-		for _, env := range config.Process.Env {
-			fields := strings.SplitN(env, "=", 2)
-			if len(fields) != 2 {
-				return fmt.Errorf("Bad OCI environment variable: %s", env)
-			}
-
-			key := fmt.Sprintf("environment.%s", fields[0])
-			value := fields[1]
-
-			_, ok := args.Config[key]
-			if !ok {
-				args.Config[key] = value
-			}
+		// Update the config for the environment variables and entrypoint.
+		err = instanceApplyOCIConfig(args.Config, &config)
+		if err != nil {
+			return err
 		}
 
-		// Set the entrypoint configuration options.
-		if len(config.Process.Args) > 0 && args.Config["oci.entrypoint"] == "" {
-			args.Config["oci.entrypoint"] = shellquote.Join(config.Process.Args...)
+		err = inst.Update(args, false)
+		if err != nil {
+			return err
 		}
+	}
 
-		if config.Process.Cwd != "" && args.Config["oci.cwd"] == "" {
-			args.Config["oci.cwd"] = config.Process.Cwd
-		}
+	err = inst.UpdateBackupFile()
+	if err != nil {
+		return err
+	}
 
-		if args.Config["oci.uid"] == "" {
-			args.Config["oci.uid"] = fmt.Sprintf("%d", config.Process.User.UID)
+	reverter.Success()
+	return nil
+}
+
+// instanceApplyOCIConfig sets the instance configuration matching an OCI runtime configuration.
+// Keys which are already set are left untouched.
+func instanceApplyOCIConfig(instConfig map[string]string, config *ociSpecs.Spec) error {
+	instConfig["volatile.container.oci"] = "true"
+	for _, env := range config.Process.Env {
+		fields := strings.SplitN(env, "=", 2)
+		if len(fields) != 2 {
+			return fmt.Errorf("Bad OCI environment variable: %s", env)
 		}
 
-		if args.Config["oci.gid"] == "" {
-			args.Config["oci.gid"] = fmt.Sprintf("%d", config.Process.User.GID)
+		key := fmt.Sprintf("environment.%s", fields[0])
+		value := fields[1]
+
+		_, ok := instConfig[key]
+		if !ok {
+			instConfig[key] = value
 		}
+	}
 
-		err = inst.Update(args, false)
-		if err != nil {
-			return err
+	// Set the entrypoint configuration options.
+	if len(config.Process.Args) > 0 && instConfig["oci.entrypoint"] == "" {
+		instConfig["oci.entrypoint"] = shellquote.Join(config.Process.Args...)
+	}
+
+	if config.Process.Cwd != "" && instConfig["oci.cwd"] == "" {
+		instConfig["oci.cwd"] = config.Process.Cwd
+	}
+
+	if instConfig["oci.uid"] == "" {
+		instConfig["oci.uid"] = fmt.Sprintf("%d", config.Process.User.UID)
+	}
+
+	if instConfig["oci.gid"] == "" {
+		instConfig["oci.gid"] = fmt.Sprintf("%d", config.Process.User.GID)
+	}
+
+	return nil
+}
+
+// instanceCreateFromLazyOCI creates a container whose root filesystem is lazily pulled from an OCI registry.
+// It returns false, without creating anything, when the image can't be pulled lazily.
+func instanceCreateFromLazyOCI(ctx context.Context, s *state.State, imgAlias string, source api.InstanceSource, args db.InstanceArgs, op *operations.Operation) (bool, error) {
+	// Lazy pulling relies on idmapped mounts as the layers can't be shifted.
+	if !s.OS.IdmappedMounts || !oci.LayerStoreAvailable() {
+		logger.Debug("Lazy pulling of OCI images isn't supported on this system", logger.Ctx{"image": imgAlias})
+		return false, nil
+	}
+
+	remote, err := incus.ConnectOCI(source.Server, &incus.ConnectionArgs{
+		UserAgent:     version.UserAgent,
+		Proxy:         s.Proxy,
+		CachePath:     s.OS.CacheDir,
+		CacheExpiry:   time.Hour,
+		SkipGetEvents: true,
+		SkipGetServer: true,
+		TempPath:      internalUtil.VarPath("images"),
+	})
+	if err != nil {
+		return false, fmt.Errorf("Failed to connect to oci server %q: %w", source.Server, err)
+	}
+
+	registry, ok := remote.(*incus.ProtocolOCI)
+	if !ok {
+		return false, errors.New("Unexpected OCI client type")
+	}
+
+	entry, _, err := registry.GetImageAliasType(string(api.InstanceTypeContainer), imgAlias)
+	if err != nil {
+		return false, fmt.Errorf("Failed getting remote image info: %w", err)
+	}
+
+	img, _, err := registry.GetImage(entry.Target)
+	if err != nil {
+		return false, fmt.Errorf("Failed getting remote image info: %w", err)
+	}
+
+	// Prefer images which were already pulled.
+	var exists bool
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		exists, err = tx.ImageExists(ctx, args.Project, img.Fingerprint)
+
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if exists {
+		return false, nil
+	}
+
+	reference, layers, err := registry.GetImageLayers(img.Fingerprint)
+	if err != nil {
+		return false, err
+	}
+
+	lazyImage := oci.LazyImage{Reference: reference}
+	for _, layer := range layers {
+		if !oci.IsLazyLayer(layer.Annotations) {
+			logger.Debug("OCI image can't be pulled lazily", logger.Ctx{"image": reference, "layer": layer.Digest, "mediaType": layer.MediaType})
+			return false, nil
 		}
+
+		lazyImage.Layers = append(lazyImage.Layers, layer.Digest)
+	}
+
+	imageConfig, err := registry.GetImageConfig(img.Fingerprint)
+	if err != nil {
+		return false, fmt.Errorf("Failed getting OCI image configuration: %w", err)
+	}
+
+	// The layer store fetches the layers on its own, pass it the registry credentials.
+	err = oci.SetLayerStoreCredentials(source.Server)
+	if err != nil {
+		return false, fmt.Errorf("Failed storing OCI registry credentials: %w", err)
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	// Set the "image.*" keys.
+	for k, v := range img.Properties {
+		args.Config[fmt.Sprintf("image.%s", k)] = v
+	}
+
+	// Create the instance on an empty volume.
+	inst, instOp, cleanup, err := instance.CreateInternal(s, args, op, true, true)
+	if err != nil {
+		return false, fmt.Errorf("Failed creating instance record: %w", err)
+	}
+
+	reverter.Add(cleanup)
+	defer instOp.Done(nil)
+
+	pool, err := storagePools.LoadByInstance(s, inst)
+	if err != nil {
+		return false, fmt.Errorf("Failed loading instance storage pool: %w", err)
+	}
+
+	err = pool.CreateInstance(inst, nil)
+	if err != nil {
+		return false, fmt.Errorf("Failed creating instance: %w", err)
+	}
+
+	reverter.Add(func() { _ = inst.Delete(true) })
+
+	// Set up the lazily pulled root filesystem.
+	_, err = pool.MountInstance(inst, nil)
+	if err != nil {
+		return false, err
+	}
+
+	err = oci.CreateLazyRootfs(inst.Path(), lazyImage, *imageConfig)
+	if err != nil {
+		_ = pool.UnmountInstance(inst, nil)
+		return false, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(inst.Path(), "config.json"))
+	if err != nil {
+		_ = pool.UnmountInstance(inst, nil)
+		return false, err
+	}
+
+	err = pool.UnmountInstance(inst, nil)
+	if err != nil {
+		return false, err
+	}
+
+	var config ociSpecs.Spec
+	err = json.Unmarshal(data, &config)
+	if err != nil {
+		return false, err
+	}
+
+	// Reset the config to the post-generation one.
+	args.Config = inst.LocalConfig()
+
+	err = instanceApplyOCIConfig(args.Config, &config)
+	if err != nil {
+		return false, err
+	}
+
+	args.Config["volatile.container.oci.lazy"] = "pulling"
+
+	err = inst.Update(args, false)
+	if err != nil {
+		return false, err
 	}
 
 	err = inst.UpdateBackupFile()
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	reverter.Success()
-	return nil
+	return true, nil
 }
 
 func instanceRebuildFromImage(ctx context.Context, s *state.State, r *http.Request, inst instance.Instance, img *api.Image, op *operations.Operation) error {
@@ -309,6 +309,15 @@ func instanceBackupsPost(d *Daemon, r *http.Request) response.Response {
 	fullName := name + internalInstance.SnapshotDelimiter + req.Name
 	instanceOnly := req.InstanceOnly
 
+	// Resolve the snapshot incremental backups are based on.
+	parent := ""
+	if req.Parent != "" {
+		parent, err = backupParentSnapshot(s, inst, req.Parent)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
 	backup := func(op *operations.Operation) error {
 		args := db.InstanceBackup{
 			Name:                 fullName,
@@ -318,6 +327,7 @@ func instanceBackupsPost(d *Daemon, r *http.Request) response.Response {
 			InstanceOnly:         instanceOnly,
 			OptimizedStorage:     req.OptimizedStorage,
 			CompressionAlgorithm: req.CompressionAlgorithm,
+			Parent:               parent,
 		}
 
 		// Create the backup.
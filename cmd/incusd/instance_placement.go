@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	internalInstance "github.com/lxc/incus/v6/internal/instance"
+	"github.com/lxc/incus/v6/internal/server/cluster"
+	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// placementInstance holds the location and expanded configuration of an instance for placement purposes.
+type placementInstance struct {
+	location string
+	config   map[string]string
+}
+
+// instancePlacementLoad returns the instances of the project, other than the named one.
+func instancePlacementLoad(ctx context.Context, s *state.State, projectName string, instanceName string) ([]placementInstance, error) {
+	var instances []placementInstance
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		dbInstances, err := dbCluster.GetInstances(ctx, tx.Tx(), dbCluster.InstanceFilter{Project: &projectName})
+		if err != nil {
+			return err
+		}
+
+		instanceDevices, err := dbCluster.GetAllInstanceDevices(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		profileConfigs, err := dbCluster.GetAllProfileConfigs(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		profileDevices, err := dbCluster.GetAllProfileDevices(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		for _, dbInst := range dbInstances {
+			if dbInst.Name == instanceName {
+				continue
+			}
+
+			apiInst, err := dbInst.ToAPI(ctx, tx.Tx(), instanceDevices, profileConfigs, profileDevices)
+			if err != nil {
+				return err
+			}
+
+			instances = append(instances, placementInstance{location: dbInst.Node, config: apiInst.ExpandedConfig})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading instances for placement: %w", err)
+	}
+
+	return instances, nil
+}
+
+// instancePlacementAllowed returns whether placing an instance with the given expanded configuration
+// on the member respects both its placement rules and those of the instances already on the member.
+func instancePlacementAllowed(config map[string]string, member string, others []placementInstance) bool {
+	// Values are validated when set, so parsing errors are ignored here.
+	affinity, _ := internalInstance.ParsePlacementSelector(config["placement.affinity"])
+	antiAffinity, _ := internalInstance.ParsePlacementSelector(config["placement.anti-affinity"])
+
+	affinityFound := false
+	affinityOnMember := false
+
+	for _, other := range others {
+		if affinity != nil && affinity.Matches(other.config) {
+			affinityFound = true
+
+			if other.location == member {
+				affinityOnMember = true
+			}
+		}
+
+		if other.location != member {
+			continue
+		}
+
+		// Anti-affinity applies in both directions.
+		if antiAffinity != nil && antiAffinity.Matches(other.config) {
+			return false
+		}
+
+		otherAntiAffinity, _ := internalInstance.ParsePlacementSelector(other.config["placement.anti-affinity"])
+		if otherAntiAffinity != nil && otherAntiAffinity.Matches(config) {
+			return false
+		}
+	}
+
+	// Affinity only restricts placement once a matching instance exists.
+	if affinityFound && !affinityOnMember {
+		return false
+	}
+
+	return true
+}
+
+// instancePlacementFilter returns the candidate members on which the instance can be placed without
+// violating any affinity or anti-affinity rule.
+// When evacuating a member, its instances are ignored as they're all being moved away, so that an affinity
+// partner still on it doesn't prevent placing the instance anywhere.
+func instancePlacementFilter(ctx context.Context, s *state.State, projectName string, instanceName string, config map[string]string, candidates []db.NodeInfo, evacuatingMember string) ([]db.NodeInfo, error) {
+	if len(candidates) == 0 {
+		return candidates, nil
+	}
+
+	others, err := instancePlacementLoad(ctx, s, projectName, instanceName)
+	if err != nil {
+		return nil, err
+	}
+
+	if evacuatingMember != "" {
+		others = slices.DeleteFunc(others, func(other placementInstance) bool {
+			return other.location == evacuatingMember
+		})
+	}
+
+	filtered := make([]db.NodeInfo, 0, len(candidates))
+	for _, candidate := range candidates {
+		if instancePlacementAllowed(config, candidate.Name, others) {
+			filtered = append(filtered, candidate)
+		}
+	}
+
+	return filtered, nil
+}
+
+// instancePlacementRanked returns the highest ranked member (as returned by the placement scriptlet) which can
+// currently be reached, falling through to the next ranked member when one isn't available.
+// Nil is returned if none of the members is available.
+func instancePlacementRanked(s *state.State, ranked []db.NodeInfo) *db.NodeInfo {
+	for i, member := range ranked {
+		if member.Name == s.ServerName || cluster.HasConnectivity(s.Endpoints.NetworkCert(), s.ServerCert(), member.Address, true) {
+			return &ranked[i]
+		}
+
+		logger.Warn("Skipping unavailable cluster member ranked by the instance placement scriptlet", logger.Ctx{"member": member.Name})
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstancePlacementAllowed(t *testing.T) {
+	others := []placementInstance{
+		{location: "member1", config: map[string]string{"user.app": "db"}},
+		{location: "member2", config: map[string]string{"user.app": "web", "placement.anti-affinity": "user.app=cache"}},
+		{location: "member3", config: map[string]string{"user.app": "db", "user.tier": "backend"}},
+	}
+
+	tests := []struct {
+		name   string
+		config map[string]string
+		member string
+		others []placementInstance
+		want   bool
+	}{
+		{
+			name:   "No rules",
+			config: map[string]string{},
+			member: "member1",
+			others: others,
+			want:   true,
+		},
+		{
+			name:   "Anti-affinity with a matching instance on the member",
+			config: map[string]string{"placement.anti-affinity": "user.app=db"},
+			member: "member1",
+			others: others,
+			want:   false,
+		},
+		{
+			name:   "Anti-affinity without a matching instance on the member",
+			config: map[string]string{"placement.anti-affinity": "user.app=db"},
+			member: "member2",
+			others: others,
+			want:   true,
+		},
+		{
+			name:   "Anti-affinity of an instance on the member",
+			config: map[string]string{"user.app": "cache"},
+			member: "member2",
+			others: others,
+			want:   false,
+		},
+		{
+			name:   "Anti-affinity requiring all the keys",
+			config: map[string]string{"placement.anti-affinity": "user.app=db,user.tier=backend"},
+			member: "member1",
+			others: others,
+			want:   true,
+		},
+		{
+			name:   "Affinity with a matching instance on the member",
+			config: map[string]string{"placement.affinity": "user.app=web"},
+			member: "member2",
+			others: others,
+			want:   true,
+		},
+		{
+			name:   "Affinity with a matching instance on another member",
+			config: map[string]string{"placement.affinity": "user.app=web"},
+			member: "member1",
+			others: others,
+			want:   false,
+		},
+		{
+			name:   "Affinity with matching instances on several members",
+			config: map[string]string{"placement.affinity": "user.app=db"},
+			member: "member3",
+			others: others,
+			want:   true,
+		},
+		{
+			name:   "Affinity without any matching instance",
+			config: map[string]string{"placement.affinity": "user.app=mail"},
+			member: "member1",
+			others: others,
+			want:   true,
+		},
+		{
+			name:   "Affinity and anti-affinity both satisfied",
+			config: map[string]string{"placement.affinity": "user.tier=backend", "placement.anti-affinity": "user.app=web"},
+			member: "member3",
+			others: others,
+			want:   true,
+		},
+		{
+			name:   "No other instances",
+			config: map[string]string{"placement.affinity": "user.app=db", "placement.anti-affinity": "user.app=db"},
+			member: "member1",
+			others: nil,
+			want:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, instancePlacementAllowed(tt.config, tt.member, tt.others))
+		})
+	}
+}
@@ -8,7 +8,9 @@ import (
 	"maps"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 
@@ -22,6 +24,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/db/operationtype"
 	deviceConfig "github.com/lxc/incus/v6/internal/server/device/config"
 	"github.com/lxc/incus/v6/internal/server/instance"
+	instanceDrivers "github.com/lxc/incus/v6/internal/server/instance/drivers"
 	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
 	"github.com/lxc/incus/v6/internal/server/operations"
 	"github.com/lxc/incus/v6/internal/server/project"
@@ -325,6 +328,29 @@ func instancePost(d *Daemon, r *http.Request) response.Response {
 			return response.SmartError(err)
 		}
 
+		// Only keep the candidates which respect the affinity rules.
+		if targetMemberInfo == nil || targetMemberInfo.Name != inst.Location() {
+			candidates := targetCandidates
+			if targetMemberInfo != nil {
+				candidates = []db.NodeInfo{*targetMemberInfo}
+			}
+
+			if len(candidates) > 0 {
+				candidates, err = instancePlacementFilter(r.Context(), s, instProject, name, inst.ExpandedConfig(), candidates, "")
+				if err != nil {
+					return response.SmartError(err)
+				}
+
+				if len(candidates) == 0 {
+					return response.BadRequest(errors.New("No cluster member satisfies the instance placement rules"))
+				}
+
+				if targetMemberInfo == nil {
+					targetCandidates = candidates
+				}
+			}
+		}
+
 		// Run instance placement scriptlet if enabled.
 		if s.GlobalConfig.InstancesPlacementScriptlet() != "" {
 			// If a target was specified, limit the list of candidates to that target.
@@ -412,10 +438,31 @@ func instancePost(d *Daemon, r *http.Request) response.Response {
 
 			if targetMemberInfo == nil {
 				// Get a new target.
-				targetMemberInfo, err = scriptlet.InstancePlacementRun(r.Context(), logger.Log, s, &req, targetCandidates, leaderAddress)
+				targetMembers, err := scriptlet.InstancePlacementRun(r.Context(), logger.Log, s, &req, targetCandidates, leaderAddress)
 				if err != nil {
 					return response.BadRequest(fmt.Errorf("Failed instance placement scriptlet: %w", err))
 				}
+
+				// Prefer the highest ranked members which aren't the current location.
+				rankedMembers := make([]db.NodeInfo, 0, len(targetMembers))
+				for _, member := range targetMembers {
+					if member.Name != inst.Location() {
+						rankedMembers = append(rankedMembers, member)
+					}
+				}
+
+				for _, member := range targetMembers {
+					if member.Name == inst.Location() {
+						rankedMembers = append(rankedMembers, member)
+					}
+				}
+
+				if len(rankedMembers) > 0 {
+					targetMemberInfo = instancePlacementRanked(s, rankedMembers)
+					if targetMemberInfo == nil {
+						return response.SmartError(api.StatusErrorf(http.StatusServiceUnavailable, "None of the cluster members selected by the instance placement scriptlet is available"))
+					}
+				}
 			} else {
 				// Validate the current target.
 				_, err = scriptlet.InstancePlacementRun(r.Context(), logger.Log, s, &req, targetCandidates, leaderAddress)
@@ -486,6 +533,14 @@ func instancePost(d *Daemon, r *http.Request) response.Response {
 			targetMemberInfo = nil
 		}
 
+		// Explicit live migrations of containers don't fall back to a stateless migration.
+		if req.Live && inst.Type() == instancetype.Container && targetMemberInfo != nil {
+			err = instanceDrivers.CheckCRIU()
+			if err != nil {
+				return response.BadRequest(fmt.Errorf("Unable to live-migrate container: %w", err))
+			}
+		}
+
 		// Setup the instance move operation.
 		run := func(op *operations.Operation) error {
 			inst.SetOperation(op)
@@ -1030,3 +1085,73 @@ func migrateInstance(ctx context.Context, s *state.State, inst instance.Instance
 
 	return nil
 }
+
+// migrateInstanceWithFallback migrates the instance like migrateInstance, but falls back to a stateless
+// migration for running containers which can't be live-migrated. In that case, the container is stopped,
+// migrated and started back up on the target. It's only used for evacuations, explicit live moves fail instead.
+func migrateInstanceWithFallback(ctx context.Context, s *state.State, inst instance.Instance, req api.InstancePost, sourceMemberInfo *db.NodeInfo, targetMemberInfo *db.NodeInfo, targetGroupName string, op *operations.Operation) error {
+	if !req.Live || inst.Type() != instancetype.Container || targetMemberInfo == nil {
+		return migrateInstance(ctx, s, inst, req, sourceMemberInfo, targetMemberInfo, targetGroupName, op)
+	}
+
+	l := logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "target": targetMemberInfo.Name})
+
+	err := instanceDrivers.CheckCRIU()
+	if err == nil {
+		err = migrateInstance(ctx, s, inst, req, sourceMemberInfo, targetMemberInfo, targetGroupName, op)
+		if err == nil {
+			return nil
+		}
+	}
+
+	l.Warn("Unable to live-migrate container, falling back to a stateless migration", logger.Ctx{"err": err})
+
+	// Reload the instance as the failed attempt may have changed its state.
+	inst, err = instance.LoadByProjectAndName(s, inst.Project().Name, inst.Name())
+	if err != nil {
+		return err
+	}
+
+	// Only migrate the container if it's still on the source.
+	if inst.Location() == targetMemberInfo.Name {
+		return nil
+	}
+
+	if inst.IsRunning() {
+		timeout, err := strconv.Atoi(inst.ExpandedConfig()["boot.host_shutdown_timeout"])
+		if err != nil {
+			timeout = evacuateHostShutdownDefaultTimeout
+		}
+
+		err = inst.Shutdown(time.Duration(timeout) * time.Second)
+		if err != nil {
+			l.Warn("Failed shutting down instance, forcing stop", logger.Ctx{"err": err})
+
+			err = inst.Stop(false)
+			if err != nil && !errors.Is(err, instanceDrivers.ErrInstanceIsStopped) {
+				return fmt.Errorf("Failed stopping instance: %w", err)
+			}
+		}
+	}
+
+	req.Live = false
+	err = migrateInstance(ctx, s, inst, req, sourceMemberInfo, targetMemberInfo, targetGroupName, op)
+	if err != nil {
+		return err
+	}
+
+	// Start it back up on target.
+	dest, err := cluster.Connect(targetMemberInfo.Address, s.Endpoints.NetworkCert(), s.ServerCert(), nil, true)
+	if err != nil {
+		return fmt.Errorf("Failed to connect to destination %q: %w", targetMemberInfo.Address, err)
+	}
+
+	dest = dest.UseProject(inst.Project().Name)
+
+	startOp, err := dest.UpdateInstanceState(inst.Name(), api.InstanceStatePut{Action: "start"}, "")
+	if err != nil {
+		return err
+	}
+
+	return startOp.Wait()
+}
@@ -14,6 +14,7 @@ import (
 	petname "github.com/dustinkirkland/golang-petname"
 	"github.com/gorilla/websocket"
 
+	incus "github.com/lxc/incus/v6/client"
 	internalInstance "github.com/lxc/incus/v6/internal/instance"
 	"github.com/lxc/incus/v6/internal/server/backup"
 	"github.com/lxc/incus/v6/internal/server/cluster"
@@ -32,6 +33,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/scriptlet"
 	"github.com/lxc/incus/v6/internal/server/state"
 	storagePools "github.com/lxc/incus/v6/internal/server/storage"
+	storageDrivers "github.com/lxc/incus/v6/internal/server/storage/drivers"
 	internalUtil "github.com/lxc/incus/v6/internal/util"
 	"github.com/lxc/incus/v6/internal/version"
 	"github.com/lxc/incus/v6/shared/api"
@@ -116,6 +118,20 @@ func createFromImage(s *state.State, r *http.Request, p api.Project, profiles []
 			Profiles:    profiles,
 		}
 
+		if req.Source.Server != "" && req.Source.Protocol == "oci" && dbType == instancetype.Container && s.GlobalConfig.ImagesOCILazyPull() {
+			// Start application containers without waiting for the whole image when possible.
+			var created bool
+
+			created, err = instanceCreateFromLazyOCI(context.TODO(), s, imgAlias, req.Source, args, op)
+			if err != nil {
+				return err
+			}
+
+			if created {
+				return instanceCreateFinish(s, req, args, op)
+			}
+		}
+
 		if req.Source.Server != "" {
 			img, err = ensureDownloadedImageFitWithinBudget(context.TODO(), s, r, op, p, imgAlias, req.Source, string(req.Type))
 			if err != nil {
@@ -713,6 +729,21 @@ func createFromBackup(s *state.State, r *http.Request, projectName string, data
 		return response.BadRequest(errors.New("Backup file is missing required information"))
 	}
 
+	// Incremental backups get applied on top of the existing instance.
+	if bInfo.Parent != "" {
+		bInfo.Project = projectName
+
+		// Override instance name.
+		if instanceName != "" {
+			bInfo.Name = instanceName
+		}
+
+		// The backup file is now handled by createFromIncrementalBackup.
+		reverter.Success()
+
+		return createFromIncrementalBackup(s, r, bInfo, backupFile, pool)
+	}
+
 	// Check project permissions.
 	var req api.InstancesPost
 	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
@@ -856,6 +887,277 @@ func createFromBackup(s *state.State, r *http.Request, projectName string, data
 	return operations.OperationResponse(op)
 }
 
+// backupIncrementalCheckChain checks that the incremental backup continues the chain of snapshots of the
+// instance: its parent must be the most recent snapshot of the instance (with the same creation date when
+// recorded), and the snapshots it includes must be new and follow the parent.
+func backupIncrementalCheckChain(bInfo *backup.Info, snaps []instance.Instance) error {
+	if len(snaps) == 0 {
+		return fmt.Errorf("Instance has no snapshot, the backup requires parent snapshot %q", bInfo.Parent)
+	}
+
+	lastSnap := snaps[len(snaps)-1]
+	_, lastSnapName, _ := api.GetParentAndSnapshotName(lastSnap.Name())
+	if lastSnapName != bInfo.Parent {
+		return fmt.Errorf("Backup requires parent snapshot %q but the most recent snapshot of the instance is %q", bInfo.Parent, lastSnapName)
+	}
+
+	if bInfo.ParentCreatedAt != nil && !bInfo.ParentCreatedAt.Equal(lastSnap.CreationDate()) {
+		return fmt.Errorf("Parent snapshot %q of the instance isn't the one the backup is based on (created at %s instead of %s)", bInfo.Parent, lastSnap.CreationDate().UTC(), bInfo.ParentCreatedAt.UTC())
+	}
+
+	for _, snap := range snaps {
+		_, snapName, _ := api.GetParentAndSnapshotName(snap.Name())
+		if slices.Contains(bInfo.Snapshots, snapName) {
+			return fmt.Errorf("Snapshot %q included in the backup already exists", snapName)
+		}
+	}
+
+	// Check that the backup describes the snapshots it includes, in order and after the parent.
+	if bInfo.Config == nil || len(bInfo.Config.Snapshots) != len(bInfo.Snapshots) {
+		return errors.New("Backup snapshot list doesn't match its configuration")
+	}
+
+	previous := lastSnap.CreationDate()
+	for i, snap := range bInfo.Config.Snapshots {
+		if snap.Name != bInfo.Snapshots[i] {
+			return fmt.Errorf("Backup snapshot %q doesn't match its configuration (%q)", bInfo.Snapshots[i], snap.Name)
+		}
+
+		if snap.CreatedAt.Before(previous) {
+			return fmt.Errorf("Backup snapshot %q predates the snapshot it follows", snap.Name)
+		}
+
+		previous = snap.CreatedAt
+	}
+
+	return nil
+}
+
+// createFromIncrementalBackup applies an incremental backup onto the existing instance it was taken from.
+// The instance must be stopped and its most recent snapshot must be the one the backup is based on.
+func createFromIncrementalBackup(s *state.State, r *http.Request, bInfo *backup.Info, backupFile *os.File, pool string) response.Response {
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	reverter.Add(func() { _ = backupFile.Close() })
+
+	// Forward the request to the cluster member hosting the instance.
+	client, err := cluster.ConnectIfInstanceIsRemote(s, bInfo.Project, bInfo.Name, r)
+	if err != nil && !response.IsNotFoundError(err) {
+		return response.SmartError(err)
+	}
+
+	if client != nil {
+		_, err = backupFile.Seek(0, io.SeekStart)
+		if err != nil {
+			return response.InternalError(err)
+		}
+
+		op, err := client.CreateInstanceFromBackup(incus.InstanceBackupArgs{
+			BackupFile: backupFile,
+			PoolName:   pool,
+			Name:       bInfo.Name,
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		opAPI := op.Get()
+		return operations.ForwardedOperationResponse(bInfo.Project, &opAPI)
+	}
+
+	inst, err := instance.LoadByProjectAndName(s, bInfo.Project, bInfo.Name)
+	if err != nil {
+		if response.IsNotFoundError(err) {
+			return response.BadRequest(fmt.Errorf("Incremental backups can only be applied to an existing instance: %w", err))
+		}
+
+		return response.SmartError(err)
+	}
+
+	if inst.IsRunning() {
+		return response.BadRequest(errors.New("Incremental backups can only be applied to stopped instances"))
+	}
+
+	if inst.Type().String() != string(bInfo.Type) {
+		return response.BadRequest(fmt.Errorf("Backup of type %q can't be applied to instance of type %q", bInfo.Type, inst.Type().String()))
+	}
+
+	// Check that the backup follows the most recent snapshot of the instance.
+	snaps, err := inst.Snapshots()
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if len(snaps) == 0 {
+		return response.BadRequest(fmt.Errorf("Instance has no snapshot, the backup requires parent snapshot %q", bInfo.Parent))
+	}
+
+	err = backupIncrementalCheckChain(bInfo, snaps)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	instPool, err := storagePools.LoadByInstance(s, inst)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if pool != "" && pool != instPool.Name() {
+		return response.BadRequest(fmt.Errorf("Instance storage pool %q differs from the requested storage pool %q", instPool.Name(), pool))
+	}
+
+	// Check if the backup is optimized that the source pool driver matches the target pool driver.
+	if *bInfo.OptimizedStorage && instPool.Driver().Info().Name != bInfo.Backend {
+		return response.BadRequest(fmt.Errorf("Optimized backup storage driver %q differs from the target storage pool driver %q", bInfo.Backend, instPool.Driver().Info().Name))
+	}
+
+	// Check project permissions.
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return project.AllowInstanceUpdate(tx, bInfo.Project, bInfo.Name, bInfo.Config.Container.InstancePut, inst.LocalConfig())
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	bInfo.Pool = instPool.Name()
+
+	logger.Debug("Incremental backup file info loaded", logger.Ctx{
+		"type":      bInfo.Type,
+		"name":      bInfo.Name,
+		"project":   bInfo.Project,
+		"backend":   bInfo.Backend,
+		"pool":      bInfo.Pool,
+		"optimized": *bInfo.OptimizedStorage,
+		"parent":    bInfo.Parent,
+		"snapshots": bInfo.Snapshots,
+	})
+
+	// Copy reverter so far so we can use it inside run after this function has finished.
+	runReverter := reverter.Clone()
+
+	run := func(op *operations.Operation) error {
+		defer func() { _ = backupFile.Close() }()
+		defer runReverter.Fail()
+
+		// Apply the tarball to the existing storage volume(s).
+		postHook, revertHook, err := instPool.UpdateInstanceFromBackup(inst, *bInfo, backupFile, nil)
+		if err != nil {
+			return fmt.Errorf("Update instance from backup: %w", err)
+		}
+
+		runReverter.Add(revertHook)
+
+		volType, err := storagePools.InstanceTypeToVolumeType(inst.Type())
+		if err != nil {
+			return err
+		}
+
+		// Create the database records for the new snapshots.
+		for _, snap := range bInfo.Config.Snapshots {
+			snapInstName := fmt.Sprintf("%s%s%s", inst.Name(), internalInstance.SnapshotDelimiter, snap.Name)
+
+			arch, err := osarch.ArchitectureID(snap.Architecture)
+			if err != nil {
+				return err
+			}
+
+			var profiles []api.Profile
+			err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+				profiles, err = tx.GetProfiles(ctx, inst.Project().Name, snap.Profiles)
+
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("Failed loading profiles (%v) for instance snapshot %q: %w", strings.Join(snap.Profiles, ", "), snapInstName, err)
+			}
+
+			// Add root device if needed.
+			if snap.Devices == nil {
+				snap.Devices = make(map[string]map[string]string)
+			}
+
+			if snap.ExpandedDevices == nil {
+				snap.ExpandedDevices = make(map[string]map[string]string)
+			}
+
+			internalImportRootDevicePopulate(instPool.Name(), snap.Devices, snap.ExpandedDevices, profiles)
+
+			_, snapInstOp, cleanup, err := instance.CreateInternal(s, db.InstanceArgs{
+				Project:      inst.Project().Name,
+				Architecture: arch,
+				BaseImage:    snap.Config["volatile.base_image"],
+				Config:       snap.Config,
+				Description:  snap.Description,
+				CreationDate: snap.CreatedAt,
+				Type:         inst.Type(),
+				Snapshot:     true,
+				Devices:      deviceConfig.NewDevices(snap.Devices),
+				Ephemeral:    snap.Ephemeral,
+				LastUsedDate: snap.LastUsedAt,
+				Name:         snapInstName,
+				Profiles:     profiles,
+				Stateful:     snap.Stateful,
+			}, nil, true, true)
+			if err != nil {
+				return fmt.Errorf("Failed creating instance snapshot record %q: %w", snap.Name, err)
+			}
+
+			runReverter.Add(cleanup)
+			snapInstOp.Done(nil)
+
+			// Recreate missing mountpoints and symlinks.
+			volStorageName := project.Instance(inst.Project().Name, snapInstName)
+			snapshotMountPoint := storageDrivers.GetVolumeMountPath(instPool.Name(), volType, volStorageName)
+			snapshotPath := storagePools.InstancePath(inst.Type(), inst.Project().Name, inst.Name(), true)
+			snapshotTargetPath := storageDrivers.GetVolumeSnapshotDir(instPool.Name(), volType, volStorageName)
+
+			err = storagePools.CreateSnapshotMountpoint(snapshotMountPoint, snapshotTargetPath, snapshotPath)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Apply the instance configuration from the backup.
+		instArgs, err := backup.ConfigToInstanceDBArgs(s, bInfo.Config, inst.Project().Name, true)
+		if err != nil {
+			return err
+		}
+
+		instArgs.Name = inst.Name()
+
+		err = inst.Update(*instArgs, false)
+		if err != nil {
+			return fmt.Errorf("Failed updating instance configuration: %w", err)
+		}
+
+		// Run the storage post hook now that the snapshots exist in the database.
+		if postHook != nil {
+			err = postHook(inst)
+			if err != nil {
+				return fmt.Errorf("Post hook failed: %w", err)
+			}
+		}
+
+		runReverter.Success()
+
+		s.Events.SendLifecycle(inst.Project().Name, lifecycle.InstanceUpdated.Event(inst, nil))
+
+		return nil
+	}
+
+	resources := map[string][]api.URL{}
+	resources["instances"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", bInfo.Name)}
+
+	op, err := operations.OperationCreate(s, bInfo.Project, operations.OperationClassTask, operationtype.BackupRestore, resources, nil, run, nil, nil, r)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	reverter.Success()
+	return operations.OperationResponse(op)
+}
+
 // swagger:operation POST /1.0/instances instances instances_post
 //
 //	Create a new instance
@@ -1203,6 +1505,18 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 			candidateMembers = []db.NodeInfo{*targetMemberInfo}
 		}
 
+		// Only keep the candidates which respect the affinity rules.
+		if len(candidateMembers) > 0 {
+			candidateMembers, err = instancePlacementFilter(r.Context(), s, targetProjectName, req.Name, db.ExpandInstanceConfig(req.Config, profiles), candidateMembers, "")
+			if err != nil {
+				return response.SmartError(err)
+			}
+
+			if len(candidateMembers) == 0 {
+				return response.BadRequest(errors.New("No cluster member satisfies the instance placement rules"))
+			}
+		}
+
 		// Run instance placement scriptlet if enabled.
 		if s.GlobalConfig.InstancesPlacementScriptlet() != "" {
 			leaderAddress, err := s.Cluster.LeaderAddress()
@@ -1220,10 +1534,18 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 			reqExpanded.Config = db.ExpandInstanceConfig(reqExpanded.Config, profiles)
 			reqExpanded.Devices = db.ExpandInstanceDevices(deviceConfig.NewDevices(reqExpanded.Devices), profiles).CloneNative()
 
-			targetMemberInfo, err = scriptlet.InstancePlacementRun(r.Context(), logger.Log, s, &reqExpanded, candidateMembers, leaderAddress)
+			targetMembers, err := scriptlet.InstancePlacementRun(r.Context(), logger.Log, s, &reqExpanded, candidateMembers, leaderAddress)
 			if err != nil {
 				return response.SmartError(fmt.Errorf("Failed instance placement scriptlet: %w", err))
 			}
+
+			// Use the highest ranked member which is available.
+			if len(targetMembers) > 0 {
+				targetMemberInfo = instancePlacementRanked(s, targetMembers)
+				if targetMemberInfo == nil {
+					return response.SmartError(api.StatusErrorf(http.StatusServiceUnavailable, "None of the cluster members selected by the instance placement scriptlet is available"))
+				}
+			}
 		}
 
 		// If no target member was selected yet, pick the member with the least number of instances.
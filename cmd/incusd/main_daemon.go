@@ -90,7 +90,23 @@ func (c *cmdDaemon) run(cmd *cobra.Command, args []string) error {
 			}
 
 		case err = <-d.shutdownDoneCh:
+			if err == nil && d.restartOnStop.Load() {
+				return daemonReexec()
+			}
+
 			return err
 		}
 	}
 }
+
+// daemonReexec replaces the current process with a new copy of the daemon, picking up any updated binary.
+func daemonReexec() error {
+	path, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("Failed to find the daemon executable: %w", err)
+	}
+
+	logger.Info("Restarting daemon", logger.Ctx{"path": path})
+
+	return unix.Exec(path, os.Args, os.Environ())
+}
@@ -135,12 +135,7 @@ func (s *migrationSourceWs) DoStorage(state *state.State, projectName string, po
 		}
 	}
 
-	// The refresh argument passed to MigrationTypes() is always set
-	// to false here. The migration source/sender doesn't need to care whether
-	// or not it's doing a refresh as the migration sink/receiver will know
-	// this, and adjust the migration types accordingly.
-	// The same applies for clusterMove and storageMove, which are set to the most optimized defaults.
-	poolMigrationTypes = pool.MigrationTypes(storageDrivers.ContentType(srcConfig.Volume.ContentType), false, !s.volumeOnly, true, false)
+	poolMigrationTypes = storagePools.VolumeMigrationTypes(pool, vol, !s.volumeOnly)
 	if len(poolMigrationTypes) == 0 {
 		return errors.New("No source migration types available")
 	}
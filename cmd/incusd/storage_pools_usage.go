@@ -0,0 +1,238 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/db/warningtype"
+	"github.com/lxc/incus/v6/internal/server/lifecycle"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/state"
+	storagePools "github.com/lxc/incus/v6/internal/server/storage"
+	"github.com/lxc/incus/v6/internal/server/task"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// storagePoolUsageLevel represents how far a storage pool's usage is above its configured thresholds.
+type storagePoolUsageLevel int
+
+const (
+	storagePoolUsageNormal storagePoolUsageLevel = iota
+	storagePoolUsageWarning
+	storagePoolUsageCritical
+)
+
+// storagePoolUsage represents the most used resource of a storage pool.
+type storagePoolUsage struct {
+	resource string
+	percent  float64
+}
+
+// storagePoolUsageCompute returns the most used resource (data or metadata) reported for a storage pool.
+// Returns false if the pool didn't report any usable data.
+func storagePoolUsageCompute(res *api.ResourcesStoragePool) (storagePoolUsage, bool) {
+	var usage storagePoolUsage
+	var found bool
+
+	check := func(resource string, space api.ResourcesStoragePoolSpace) {
+		if space.Total == 0 {
+			return
+		}
+
+		percent := float64(space.Used) * 100 / float64(space.Total)
+		if !found || percent > usage.percent {
+			usage = storagePoolUsage{resource: resource, percent: percent}
+			found = true
+		}
+	}
+
+	check("data", res.Space)
+	if res.Metadata != nil {
+		check("metadata", *res.Metadata)
+	}
+
+	return usage, found
+}
+
+// storagePoolUsageLevelGet returns the usage level and the threshold that was exceeded (if any).
+// A threshold of zero disables the matching level.
+func storagePoolUsageLevelGet(percent float64, warningThreshold int64, criticalThreshold int64) (storagePoolUsageLevel, int64) {
+	if criticalThreshold > 0 && percent >= float64(criticalThreshold) {
+		return storagePoolUsageCritical, criticalThreshold
+	}
+
+	if warningThreshold > 0 && percent >= float64(warningThreshold) {
+		return storagePoolUsageWarning, warningThreshold
+	}
+
+	return storagePoolUsageNormal, 0
+}
+
+// storagePoolUsageWarningsUpdate raises the warning matching the usage level of the pool and resolves the others.
+// Warnings of remote pools aren't tied to a cluster member as the usage is the same from all members.
+// Returns the usage level of the pool as recorded by the warnings before the update.
+func storagePoolUsageWarningsUpdate(ctx context.Context, s *state.State, poolID int64, remote bool, level storagePoolUsageLevel, message string) (storagePoolUsageLevel, error) {
+	levelTypes := map[storagePoolUsageLevel]warningtype.Type{
+		storagePoolUsageWarning:  warningtype.StoragePoolUsageWarning,
+		storagePoolUsageCritical: warningtype.StoragePoolUsageCritical,
+	}
+
+	previous := storagePoolUsageNormal
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		nodeName := ""
+		if !remote {
+			var err error
+
+			nodeName, err = tx.GetLocalNodeName(ctx)
+			if err != nil {
+				return err
+			}
+		}
+
+		projectName := ""
+		entityType := dbCluster.TypeStoragePool
+		entityID := int(poolID)
+
+		for typeLevel, warningType := range levelTypes {
+			filter := dbCluster.WarningFilter{
+				TypeCode:       &warningType,
+				Node:           &nodeName,
+				Project:        &projectName,
+				EntityTypeCode: &entityType,
+				EntityID:       &entityID,
+			}
+
+			dbWarnings, err := dbCluster.GetWarnings(ctx, tx.Tx(), filter)
+			if err != nil {
+				return err
+			}
+
+			for _, w := range dbWarnings {
+				if w.Status == warningtype.StatusResolved {
+					continue
+				}
+
+				previous = max(previous, typeLevel)
+
+				if typeLevel != level {
+					err = tx.UpdateWarningStatus(w.UUID, warningtype.StatusResolved)
+					if err != nil {
+						return err
+					}
+				}
+			}
+
+			if typeLevel == level {
+				err = tx.UpsertWarning(ctx, nodeName, projectName, entityType, entityID, warningType, message)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return storagePoolUsageNormal, err
+	}
+
+	return previous, nil
+}
+
+func autoCheckStoragePoolsUsageTask(d *Daemon) (task.Func, task.Schedule) {
+	// `f` compares the usage of the storage pools against the configured thresholds.
+	f := func(ctx context.Context) {
+		s := d.State()
+
+		var poolNames []string
+
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			var err error
+
+			poolNames, err = tx.GetCreatedStoragePoolNames(ctx)
+
+			return err
+		})
+		if err != nil {
+			if !response.IsNotFoundError(err) {
+				logger.Error("Failed getting storage pools for usage check", logger.Ctx{"err": err})
+			}
+
+			return
+		}
+
+		// Remote pools report the same usage from every member, so only check them on the leader.
+		isLeader := true
+		if s.ServerClustered {
+			leader, err := s.Cluster.LeaderAddress()
+			if err != nil {
+				logger.Error("Failed getting cluster leader for storage pool usage check", logger.Ctx{"err": err})
+				return
+			}
+
+			isLeader = s.LocalConfig.ClusterAddress() == leader
+		}
+
+		warningThreshold := s.GlobalConfig.StorageWarningThreshold()
+		criticalThreshold := s.GlobalConfig.StorageCriticalThreshold()
+
+		for _, poolName := range poolNames {
+			pool, err := storagePools.LoadByName(s, poolName)
+			if err != nil {
+				logger.Error("Failed loading storage pool for usage check", logger.Ctx{"pool": poolName, "err": err})
+				continue
+			}
+
+			remote := pool.Driver().Info().Remote
+			if remote && !isLeader {
+				continue
+			}
+
+			res, err := pool.GetResources()
+			if err != nil {
+				logger.Debug("Failed getting storage pool usage", logger.Ctx{"pool": poolName, "err": err})
+				continue
+			}
+
+			usage, ok := storagePoolUsageCompute(res)
+			if !ok {
+				continue
+			}
+
+			level, threshold := storagePoolUsageLevelGet(usage.percent, warningThreshold, criticalThreshold)
+
+			message := ""
+			if level != storagePoolUsageNormal {
+				message = fmt.Sprintf("Pool %s usage at %.1f%% exceeds the %d%% threshold", usage.resource, usage.percent, threshold)
+			}
+
+			previous, err := storagePoolUsageWarningsUpdate(ctx, s, pool.ID(), remote, level, message)
+			if err != nil {
+				logger.Error("Failed updating storage pool usage warnings", logger.Ctx{"pool": poolName, "err": err})
+				continue
+			}
+
+			if level == previous {
+				continue
+			}
+
+			switch level {
+			case storagePoolUsageWarning:
+				logger.Warn("Storage pool usage above warning threshold", logger.Ctx{"pool": poolName, "resource": usage.resource, "usage": usage.percent, "threshold": threshold})
+				s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.StoragePoolUsageWarning.Event(poolName, nil, map[string]any{"resource": usage.resource, "usage": usage.percent, "threshold": threshold}))
+			case storagePoolUsageCritical:
+				logger.Error("Storage pool usage above critical threshold", logger.Ctx{"pool": poolName, "resource": usage.resource, "usage": usage.percent, "threshold": threshold})
+				s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.StoragePoolUsageCritical.Event(poolName, nil, map[string]any{"resource": usage.resource, "usage": usage.percent, "threshold": threshold}))
+			default:
+				logger.Info("Storage pool usage back to normal", logger.Ctx{"pool": poolName, "usage": usage.percent})
+				s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.StoragePoolUsageResolved.Event(poolName, nil, map[string]any{"usage": usage.percent}))
+			}
+		}
+	}
+
+	return f, task.Every(5 * time.Minute)
+}
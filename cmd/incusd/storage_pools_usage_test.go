@@ -0,0 +1,90 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+func TestStoragePoolUsageCompute(t *testing.T) {
+	tests := []struct {
+		name      string
+		res       api.ResourcesStoragePool
+		wantUsage storagePoolUsage
+		wantOK    bool
+	}{
+		{
+			name: "no data",
+			res:  api.ResourcesStoragePool{},
+		},
+		{
+			name:      "data only",
+			res:       api.ResourcesStoragePool{Space: api.ResourcesStoragePoolSpace{Used: 25, Total: 100}},
+			wantUsage: storagePoolUsage{resource: "data", percent: 25},
+			wantOK:    true,
+		},
+		{
+			name: "metadata more used than data",
+			res: api.ResourcesStoragePool{
+				Space:    api.ResourcesStoragePoolSpace{Used: 25, Total: 100},
+				Metadata: &api.ResourcesStoragePoolSpace{Used: 9, Total: 10},
+			},
+			wantUsage: storagePoolUsage{resource: "metadata", percent: 90},
+			wantOK:    true,
+		},
+		{
+			name: "data more used than metadata",
+			res: api.ResourcesStoragePool{
+				Space:    api.ResourcesStoragePoolSpace{Used: 50, Total: 100},
+				Metadata: &api.ResourcesStoragePoolSpace{Used: 1, Total: 10},
+			},
+			wantUsage: storagePoolUsage{resource: "data", percent: 50},
+			wantOK:    true,
+		},
+		{
+			name: "metadata only",
+			res: api.ResourcesStoragePool{
+				Metadata: &api.ResourcesStoragePoolSpace{Used: 3, Total: 4},
+			},
+			wantUsage: storagePoolUsage{resource: "metadata", percent: 75},
+			wantOK:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usage, ok := storagePoolUsageCompute(&tt.res)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantUsage, usage)
+		})
+	}
+}
+
+func TestStoragePoolUsageLevelGet(t *testing.T) {
+	tests := []struct {
+		name          string
+		percent       float64
+		warning       int64
+		critical      int64
+		wantLevel     storagePoolUsageLevel
+		wantThreshold int64
+	}{
+		{name: "below thresholds", percent: 50, warning: 80, critical: 95, wantLevel: storagePoolUsageNormal},
+		{name: "at warning threshold", percent: 80, warning: 80, critical: 95, wantLevel: storagePoolUsageWarning, wantThreshold: 80},
+		{name: "between thresholds", percent: 90.5, warning: 80, critical: 95, wantLevel: storagePoolUsageWarning, wantThreshold: 80},
+		{name: "at critical threshold", percent: 95, warning: 80, critical: 95, wantLevel: storagePoolUsageCritical, wantThreshold: 95},
+		{name: "warnings disabled", percent: 90, warning: 0, critical: 95, wantLevel: storagePoolUsageNormal},
+		{name: "critical disabled", percent: 99, warning: 80, critical: 0, wantLevel: storagePoolUsageWarning, wantThreshold: 80},
+		{name: "all disabled", percent: 100, wantLevel: storagePoolUsageNormal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			level, threshold := storagePoolUsageLevelGet(tt.percent, tt.warning, tt.critical)
+			assert.Equal(t, tt.wantLevel, level)
+			assert.Equal(t, tt.wantThreshold, threshold)
+		})
+	}
+}
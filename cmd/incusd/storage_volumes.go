@@ -697,6 +697,11 @@ func storagePoolVolumesPost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(fmt.Errorf("Currently not allowed to create storage volumes of type %q", req.Type))
 	}
 
+	// Replicas are only marked as such when pushed by the server replicating the source volume.
+	if req.Config["volatile.replication.source"] != "" && req.Source.Type != "migration" {
+		return response.BadRequest(errors.New(`The "volatile.replication.source" property can't be set`))
+	}
+
 	var poolID int64
 	var dbVolume *db.StorageVolume
 
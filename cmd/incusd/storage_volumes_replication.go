@@ -0,0 +1,634 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	incus "github.com/lxc/incus/v6/client"
+	internalInstance "github.com/lxc/incus/v6/internal/instance"
+	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/db/operationtype"
+	"github.com/lxc/incus/v6/internal/server/db/warningtype"
+	"github.com/lxc/incus/v6/internal/server/lifecycle"
+	"github.com/lxc/incus/v6/internal/server/operations"
+	"github.com/lxc/incus/v6/internal/server/project"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/state"
+	storagePools "github.com/lxc/incus/v6/internal/server/storage"
+	storageDrivers "github.com/lxc/incus/v6/internal/server/storage/drivers"
+	"github.com/lxc/incus/v6/internal/server/task"
+	"github.com/lxc/incus/v6/internal/server/warnings"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+	localtls "github.com/lxc/incus/v6/shared/tls"
+)
+
+// storageVolumeReplicationSnapshotPrefix is the name prefix of the snapshots created for incremental replication.
+const storageVolumeReplicationSnapshotPrefix = "replication-"
+
+var storagePoolVolumeTypeReplicationCmd = APIEndpoint{
+	Path: "storage-pools/{poolName}/volumes/{type}/{volumeName}/replication",
+
+	Post: APIEndpointAction{Handler: storagePoolVolumeTypeReplicationPost, AccessHandler: allowPermission(auth.ObjectTypeStorageVolume, auth.EntitlementCanEdit, "poolName", "type", "volumeName", "location")},
+}
+
+// swagger:operation POST /1.0/storage-pools/{poolName}/volumes/{type}/{volumeName}/replication storage storage_pool_volume_type_replication_post
+//
+//	Act on the replication of a storage volume
+//
+//	Either synchronizes the storage volume to its replication target right away (`sync`)
+//	or turns a replica into a regular storage volume (`promote`).
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: target
+//	    description: Cluster member name
+//	    type: string
+//	    example: server01
+//	  - in: body
+//	    name: replication
+//	    description: Replication action
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/StorageVolumeReplicationPost"
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func storagePoolVolumeTypeReplicationPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	// Get the name of the storage pool the volume is supposed to be attached to.
+	poolName, err := url.PathUnescape(mux.Vars(r)["poolName"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Get the volume type.
+	volumeTypeName, err := url.PathUnescape(mux.Vars(r)["type"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Get the name of the storage volume.
+	volumeName, err := url.PathUnescape(mux.Vars(r)["volumeName"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Convert the volume type name to our internal integer representation.
+	volumeType, err := storagePools.VolumeTypeNameToDBType(volumeTypeName)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Check that the storage volume type is valid.
+	if volumeType != db.StoragePoolVolumeTypeCustom {
+		return response.BadRequest(fmt.Errorf("Invalid storage volume type %q", volumeTypeName))
+	}
+
+	projectName, err := project.StorageVolumeProject(s.DB.Cluster, request.ProjectParam(r), db.StoragePoolVolumeTypeCustom)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	resp := forwardedResponseIfTargetIsRemote(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	resp = forwardedResponseIfVolumeIsRemote(s, r, poolName, projectName, volumeName, db.StoragePoolVolumeTypeCustom)
+	if resp != nil {
+		return resp
+	}
+
+	req := api.StorageVolumeReplicationPost{}
+
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	pool, err := storagePools.LoadByName(s, poolName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	dbVol, err := storagePools.VolumeDBGet(pool, projectName, volumeName, storageDrivers.VolumeTypeCustom)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var run func(op *operations.Operation) error
+
+	switch req.Action {
+	case "sync":
+		if dbVol.Config["replication.target"] == "" {
+			return response.BadRequest(errors.New("Storage volume has no replication target"))
+		}
+
+		run = func(op *operations.Operation) error {
+			return storageVolumeReplicate(s, projectName, poolName, volumeName, op)
+		}
+
+	case "promote":
+		if dbVol.Config["volatile.replication.source"] == "" {
+			return response.BadRequest(errors.New("Storage volume isn't a replica"))
+		}
+
+		run = func(op *operations.Operation) error {
+			return storageVolumeReplicaPromote(s, pool, projectName, volumeName, op)
+		}
+
+	default:
+		return response.BadRequest(fmt.Errorf("Invalid replication action %q", req.Action))
+	}
+
+	resources := map[string][]api.URL{}
+	resources["storage_volumes"] = []api.URL{*api.NewURL().Path(version.APIVersion, "storage-pools", poolName, "volumes", "custom", volumeName)}
+
+	op, err := operations.OperationCreate(s, request.ProjectParam(r), operations.OperationClassTask, operationtype.VolumeReplicate, resources, nil, run, nil, nil, r)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
+
+func autoReplicateStorageVolumesTask(d *Daemon) (task.Func, task.Schedule) {
+	// `f` replicates the custom volumes whose replication schedule is due to their replication target.
+	f := func(ctx context.Context) {
+		s := d.State()
+		var volumes []db.StorageVolumeArgs
+
+		// Get list of custom volumes that are due to be replicated.
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			allVolumes, err := tx.GetStoragePoolVolumesWithType(ctx, db.StoragePoolVolumeTypeCustom, true)
+			if err != nil {
+				return fmt.Errorf("Failed getting volumes for auto custom volume replication task: %w", err)
+			}
+
+			var dueVolumes []db.StorageVolumeArgs
+			for _, v := range allVolumes {
+				schedule := v.Config["replication.schedule"]
+				if schedule == "" || v.Config["replication.target"] == "" {
+					continue
+				}
+
+				// Check if replication is scheduled.
+				if !snapshotIsScheduledNow(schedule, v.ID) {
+					continue
+				}
+
+				dueVolumes = append(dueVolumes, v)
+			}
+
+			volumes, err = storageVolumesForLocalMember(ctx, tx, s, dueVolumes, "replication")
+			return err
+		})
+		if err != nil {
+			logger.Error("Failed getting custom volume replication schedule info", logger.Ctx{"err": err})
+			return
+		}
+
+		if len(volumes) == 0 {
+			return
+		}
+
+		opRun := func(op *operations.Operation) error {
+			return autoReplicateStorageVolumes(ctx, s, volumes, op)
+		}
+
+		op, err := operations.OperationCreate(s, "", operations.OperationClassTask, operationtype.VolumesReplicationSchedule, nil, nil, opRun, nil, nil, nil)
+		if err != nil {
+			logger.Error("Failed creating scheduled volume replication operation", logger.Ctx{"err": err})
+			return
+		}
+
+		logger.Info("Replicating scheduled custom volumes")
+		err = op.Start()
+		if err != nil {
+			logger.Error("Failed starting scheduled volume replication operation", logger.Ctx{"err": err})
+			return
+		}
+
+		err = op.Wait(ctx)
+		if err != nil {
+			logger.Error("Failed scheduled volume replication", logger.Ctx{"err": err})
+			return
+		}
+
+		logger.Info("Done replicating scheduled custom volumes")
+	}
+
+	first := true
+	schedule := func() (time.Duration, error) {
+		interval := time.Minute
+
+		if first {
+			first = false
+			return interval, task.ErrSkip
+		}
+
+		return interval, nil
+	}
+
+	return f, schedule
+}
+
+// autoReplicateStorageVolumes replicates the custom volumes sequentially.
+// Failures are recorded as warnings against the volume rather than stopping the other replications.
+func autoReplicateStorageVolumes(ctx context.Context, s *state.State, volumes []db.StorageVolumeArgs, op *operations.Operation) error {
+	var failed int
+
+	for _, v := range volumes {
+		err := ctx.Err()
+		if err != nil {
+			return err // Stop if context is cancelled.
+		}
+
+		err = storageVolumeReplicate(s, v.ProjectName, v.PoolName, v.Name, op)
+		if err == nil {
+			err = warnings.ResolveWarningsByLocalNodeAndProjectAndTypeAndEntity(s.DB.Cluster, v.ProjectName, warningtype.VolumeReplicationFailure, dbCluster.TypeStorageVolume, int(v.ID))
+			if err != nil {
+				logger.Warn("Failed to resolve volume replication failure warning", logger.Ctx{"err": err})
+			}
+
+			continue
+		}
+
+		failed++
+		logger.Error("Failed scheduled volume replication", logger.Ctx{"volName": v.Name, "project": v.ProjectName, "pool": v.PoolName, "err": err})
+
+		warnErr := s.DB.Cluster.Transaction(s.ShutdownCtx, func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.UpsertWarningLocalNode(ctx, v.ProjectName, dbCluster.TypeStorageVolume, int(v.ID), warningtype.VolumeReplicationFailure, err.Error())
+		})
+		if warnErr != nil {
+			logger.Warn("Failed to create volume replication failure warning", logger.Ctx{"err": warnErr})
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d scheduled volume replications failed", failed)
+	}
+
+	return nil
+}
+
+// storageVolumeReplicationMarker returns the value of volatile.replication.source identifying the replicas
+// of a custom volume.
+func storageVolumeReplicationMarker(s *state.State, projectName string, poolName string, volName string) string {
+	return fmt.Sprintf("%s/%s/%s/%s", s.ServerCert().Fingerprint(), projectName, poolName, volName)
+}
+
+// storageVolumeReplicate synchronizes a custom volume to its replication target and records the outcome
+// in the volume's volatile.replication.* keys.
+func storageVolumeReplicate(s *state.State, projectName string, poolName string, volName string, op *operations.Operation) error {
+	pool, err := storagePools.LoadByName(s, poolName)
+	if err != nil {
+		return err
+	}
+
+	err = storageVolumeReplicateSync(s, pool, projectName, volName, op)
+
+	statusErr := storageVolumeReplicationStatus(s, pool, projectName, volName, err)
+	if statusErr != nil {
+		logger.Warn("Failed recording volume replication status", logger.Ctx{"volName": volName, "project": projectName, "pool": poolName, "err": statusErr})
+	}
+
+	if err != nil {
+		return fmt.Errorf("Failed replicating volume %q (project %q, pool %q): %w", volName, projectName, poolName, err)
+	}
+
+	return nil
+}
+
+// storageVolumeReplicateSync copies or refreshes the replica of a custom volume.
+// In incremental mode a new replication snapshot is taken first and transferred along with the other snapshots,
+// allowing optimized drivers to only send the changes since the previous replication snapshot.
+func storageVolumeReplicateSync(s *state.State, pool storagePools.Pool, projectName string, volName string, op *operations.Operation) error {
+	dbVol, err := storagePools.VolumeDBGet(pool, projectName, volName, storageDrivers.VolumeTypeCustom)
+	if err != nil {
+		return err
+	}
+
+	config := dbVol.Config
+	if config["volatile.replication.source"] != "" {
+		return errors.New("Replicas can't be replicated, promote the volume first")
+	}
+
+	remoteName, targetPoolName, err := storagePools.ParseReplicationTarget(config["replication.target"])
+	if err != nil {
+		return err
+	}
+
+	// Remote servers can only be reached through the remotes defined in the server configuration, as the
+	// connection is authenticated with the server's own certificate.
+	var remoteURL, remoteFingerprint string
+	if remoteName != "" {
+		remoteURL, remoteFingerprint = s.GlobalConfig.ReplicationRemote(remoteName)
+		if remoteURL == "" {
+			return fmt.Errorf("Replication remote %q isn't configured on the server", remoteName)
+		}
+	}
+
+	snapshots := config["replication.mode"] != "full"
+
+	// The replica gets the volume's configuration without its replication settings.
+	replicaConfig := make(map[string]string, len(config))
+	for k, v := range config {
+		if strings.HasPrefix(k, "replication.") || strings.HasPrefix(k, "volatile.replication.") {
+			continue
+		}
+
+		replicaConfig[k] = v
+	}
+
+	replicaConfig["volatile.replication.source"] = storageVolumeReplicationMarker(s, projectName, pool.Name(), volName)
+
+	var snapName string
+	if snapshots {
+		snapName = storageVolumeReplicationSnapshotPrefix + time.Now().UTC().Format("20060102-150405")
+
+		err = pool.CreateCustomVolumeSnapshot(projectName, volName, snapName, time.Time{}, op)
+		if err != nil {
+			return fmt.Errorf("Failed creating replication snapshot: %w", err)
+		}
+	}
+
+	if remoteURL == "" {
+		err = storageVolumeReplicateLocal(s, pool, projectName, dbVol, targetPoolName, replicaConfig, snapshots, op)
+	} else {
+		err = storageVolumeReplicateRemote(s, pool, projectName, dbVol, remoteURL, remoteFingerprint, targetPoolName, replicaConfig, snapshots, op)
+	}
+
+	if err != nil {
+		if snapName != "" {
+			_ = pool.DeleteCustomVolumeSnapshot(projectName, volName+internalInstance.SnapshotDelimiter+snapName, op)
+		}
+
+		return err
+	}
+
+	if !snapshots {
+		return nil
+	}
+
+	// Only keep the latest replication snapshot which serves as the base for the next incremental transfer.
+	snaps, err := storagePools.VolumeDBSnapshotsGet(pool, projectName, volName, storageDrivers.VolumeTypeCustom)
+	if err != nil {
+		return err
+	}
+
+	for _, snap := range snaps {
+		_, name, _ := api.GetParentAndSnapshotName(snap.Name)
+		if !strings.HasPrefix(name, storageVolumeReplicationSnapshotPrefix) || name == snapName {
+			continue
+		}
+
+		err = pool.DeleteCustomVolumeSnapshot(projectName, snap.Name, op)
+		if err != nil {
+			return fmt.Errorf("Failed deleting replication snapshot %q: %w", snap.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// storageVolumeReplicateLocal creates or refreshes the replica of a custom volume on another storage pool of the server.
+func storageVolumeReplicateLocal(s *state.State, pool storagePools.Pool, projectName string, dbVol *db.StorageVolume, targetPoolName string, replicaConfig map[string]string, snapshots bool, op *operations.Operation) error {
+	if targetPoolName == pool.Name() {
+		return errors.New("The replication target must be a different storage pool")
+	}
+
+	targetPool, err := storagePools.LoadByName(s, targetPoolName)
+	if err != nil {
+		return fmt.Errorf("Failed loading replication target pool: %w", err)
+	}
+
+	// A replica on a local pool must stay on the same cluster member.
+	if s.ServerClustered && pool.Driver().Info().Remote && !targetPool.Driver().Info().Remote {
+		return errors.New("Volumes on remote storage pools can't be replicated to local storage pools in a cluster")
+	}
+
+	// Apply the same project restrictions and limits as when creating the volume through the API.
+	err = s.DB.Cluster.Transaction(s.ShutdownCtx, func(ctx context.Context, tx *db.ClusterTx) error {
+		req := api.StorageVolumesPost{
+			Name:        dbVol.Name,
+			Type:        db.StoragePoolVolumeTypeNameCustom,
+			ContentType: dbVol.ContentType,
+			StorageVolumePut: api.StorageVolumePut{
+				Config: replicaConfig,
+			},
+		}
+
+		return project.AllowVolumeCreation(tx, projectName, targetPoolName, req)
+	})
+	if err != nil {
+		return err
+	}
+
+	replica, err := storagePools.VolumeDBGet(targetPool, projectName, dbVol.Name, storageDrivers.VolumeTypeCustom)
+	if err != nil && !response.IsNotFoundError(err) {
+		return err
+	}
+
+	if replica == nil {
+		return targetPool.CreateCustomVolumeFromCopy(projectName, projectName, dbVol.Name, dbVol.Description, replicaConfig, pool.Name(), dbVol.Name, snapshots, op)
+	}
+
+	if replica.Config["volatile.replication.source"] != replicaConfig["volatile.replication.source"] {
+		return fmt.Errorf("Storage volume %q on pool %q isn't a replica of this volume", dbVol.Name, targetPoolName)
+	}
+
+	return targetPool.RefreshCustomVolume(projectName, projectName, dbVol.Name, dbVol.Description, replicaConfig, pool.Name(), dbVol.Name, snapshots, false, op)
+}
+
+// storageVolumeReplicateRemote creates or refreshes the replica of a custom volume on a remote server.
+// The volume is pushed to the remote server which must trust the server's certificate.
+func storageVolumeReplicateRemote(s *state.State, pool storagePools.Pool, projectName string, dbVol *db.StorageVolume, remoteURL string, remoteFingerprint string, targetPoolName string, replicaConfig map[string]string, snapshots bool, op *operations.Operation) error {
+	serverCert := s.ServerCert()
+
+	args := &incus.ConnectionArgs{
+		TLSClientCert: string(serverCert.PublicKey()),
+		TLSClientKey:  string(serverCert.PrivateKey()),
+		UserAgent:     version.UserAgent,
+		Proxy:         s.Proxy,
+		SkipGetEvents: true,
+	}
+
+	// Pin the remote server certificate if a fingerprint is configured.
+	fingerprint := strings.ToLower(remoteFingerprint)
+	if fingerprint != "" {
+		cert, err := localtls.GetRemoteCertificate(remoteURL, version.UserAgent)
+		if err != nil {
+			return fmt.Errorf("Failed getting certificate of replication target %q: %w", remoteURL, err)
+		}
+
+		if localtls.CertFingerprint(cert) != fingerprint {
+			return fmt.Errorf("Certificate fingerprint mismatch for replication target %q", remoteURL)
+		}
+
+		args.TLSServerCert = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+	}
+
+	target, err := incus.ConnectIncus(remoteURL, args)
+	if err != nil {
+		return fmt.Errorf("Failed connecting to replication target %q: %w", remoteURL, err)
+	}
+
+	defer target.Disconnect()
+
+	target = target.UseProject(projectName)
+
+	if !target.HasExtension("storage_volume_replication") {
+		return fmt.Errorf("Replication target %q is missing the required \"storage_volume_replication\" API extension", remoteURL)
+	}
+
+	replica, _, err := target.GetStoragePoolVolume(targetPoolName, "custom", dbVol.Name)
+	if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+		return fmt.Errorf("Failed getting replica from replication target %q: %w", remoteURL, err)
+	}
+
+	if replica != nil && replica.Config["volatile.replication.source"] != replicaConfig["volatile.replication.source"] {
+		return fmt.Errorf("Storage volume %q on replication target %q isn't a replica of this volume", dbVol.Name, remoteURL)
+	}
+
+	info, err := target.GetConnectionInfo()
+	if err != nil {
+		return err
+	}
+
+	// Request push mode migration on the remote server.
+	req := api.StorageVolumesPost{
+		Name:        dbVol.Name,
+		Type:        db.StoragePoolVolumeTypeNameCustom,
+		ContentType: dbVol.ContentType,
+		StorageVolumePut: api.StorageVolumePut{
+			Config:      replicaConfig,
+			Description: dbVol.Description,
+		},
+		Source: api.StorageVolumeSource{
+			Type:       "migration",
+			Mode:       "push",
+			Name:       dbVol.Name,
+			VolumeOnly: !snapshots,
+			Refresh:    replica != nil,
+		},
+	}
+
+	targetOp, _, err := target.RawOperation("POST", fmt.Sprintf("/storage-pools/%s/volumes/%s", url.PathEscape(targetPoolName), db.StoragePoolVolumeTypeNameCustom), req, "")
+	if err != nil {
+		return fmt.Errorf("Failed requesting volume creation on replication target %q: %w", remoteURL, err)
+	}
+
+	opAPI := targetOp.Get()
+
+	targetSecrets := map[string]string{}
+	for k, v := range opAPI.Metadata {
+		val, ok := v.(string)
+		if ok {
+			targetSecrets[k] = val
+		}
+	}
+
+	srcMigration, err := newStorageMigrationSource(!snapshots, &api.StorageVolumePostTarget{
+		Certificate: info.Certificate,
+		Operation:   fmt.Sprintf("%s/1.0/operations/%s", remoteURL, url.PathEscape(opAPI.ID)),
+		Websockets:  targetSecrets,
+	})
+	if err != nil {
+		_ = targetOp.Cancel()
+		return fmt.Errorf("Failed setting up storage volume migration on source: %w", err)
+	}
+
+	err = srcMigration.DoStorage(s, projectName, pool.Name(), dbVol.Name, op)
+	if err != nil {
+		_ = targetOp.Cancel()
+		return err
+	}
+
+	err = targetOp.Wait()
+	if err != nil {
+		return fmt.Errorf("Failed transferring volume to replication target %q: %w", remoteURL, err)
+	}
+
+	return nil
+}
+
+// storageVolumeReplicationStatus records the outcome of a replication in the volume's volatile keys.
+func storageVolumeReplicationStatus(s *state.State, pool storagePools.Pool, projectName string, volName string, syncErr error) error {
+	return s.DB.Cluster.Transaction(s.ShutdownCtx, func(ctx context.Context, tx *db.ClusterTx) error {
+		dbVol, err := tx.GetStoragePoolVolume(ctx, pool.ID(), projectName, db.StoragePoolVolumeTypeCustom, volName, true)
+		if err != nil {
+			return err
+		}
+
+		config := maps.Clone(dbVol.Config)
+		if syncErr != nil {
+			config["volatile.replication.status"] = "failed"
+		} else {
+			config["volatile.replication.status"] = "synced"
+			config["volatile.replication.last_sync"] = time.Now().UTC().Format(time.RFC3339)
+		}
+
+		return tx.UpdateStoragePoolVolume(ctx, projectName, volName, db.StoragePoolVolumeTypeCustom, pool.ID(), dbVol.Description, config)
+	})
+}
+
+// storageVolumeReplicaPromote turns a replica into a regular custom volume which is no longer updated by
+// its source volume.
+func storageVolumeReplicaPromote(s *state.State, pool storagePools.Pool, projectName string, volName string, op *operations.Operation) error {
+	var config map[string]string
+	var contentType string
+
+	err := s.DB.Cluster.Transaction(s.ShutdownCtx, func(ctx context.Context, tx *db.ClusterTx) error {
+		dbVol, err := tx.GetStoragePoolVolume(ctx, pool.ID(), projectName, db.StoragePoolVolumeTypeCustom, volName, true)
+		if err != nil {
+			return err
+		}
+
+		config = maps.Clone(dbVol.Config)
+		contentType = dbVol.ContentType
+		delete(config, "volatile.replication.source")
+
+		return tx.UpdateStoragePoolVolume(ctx, projectName, volName, db.StoragePoolVolumeTypeCustom, pool.ID(), dbVol.Description, config)
+	})
+	if err != nil {
+		return err
+	}
+
+	vol := pool.GetVolume(storageDrivers.VolumeTypeCustom, storageDrivers.ContentType(contentType), volName, config)
+	s.Events.SendLifecycle(projectName, lifecycle.StorageVolumeUpdated.Event(vol, db.StoragePoolVolumeTypeNameCustom, projectName, op, nil))
+
+	return nil
+}
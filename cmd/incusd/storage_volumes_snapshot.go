@@ -1195,9 +1195,7 @@ func storagePoolVolumeSnapshotTypeDelete(d *Daemon, r *http.Request) response.Re
 func pruneExpiredAndAutoCreateCustomVolumeSnapshotsTask(d *Daemon) (task.Func, task.Schedule) {
 	f := func(ctx context.Context) {
 		s := d.State()
-		var volumes, remoteVolumes, expiredSnapshots, expiredRemoteSnapshots []db.StorageVolumeArgs
-		var memberCount int
-		var onlineMemberIDs []int64
+		var volumes, expiredSnapshots []db.StorageVolumeArgs
 
 		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
 			// Get the list of expired custom volume snapshots for this member (or remote).
@@ -1206,15 +1204,9 @@ func pruneExpiredAndAutoCreateCustomVolumeSnapshotsTask(d *Daemon) (task.Func, t
 				return fmt.Errorf("Failed getting expired custom volume snapshots: %w", err)
 			}
 
-			for _, v := range allExpiredSnapshots {
-				if v.NodeID < 0 {
-					// Keep a separate list of remote volumes in order to select a member to
-					// perform the snapshot expiry on later.
-					expiredRemoteSnapshots = append(expiredRemoteSnapshots, v)
-				} else {
-					logger.Debug("Scheduling local custom volume snapshot expiry", logger.Ctx{"volName": v.Name, "project": v.ProjectName, "pool": v.PoolName})
-					expiredSnapshots = append(expiredSnapshots, v) // Always include local volumes.
-				}
+			expiredSnapshots, err = storageVolumesForLocalMember(ctx, tx, s, allExpiredSnapshots, "snapshot expiry")
+			if err != nil {
+				return err
 			}
 
 			projs, err := dbCluster.GetProjects(ctx, tx.Tx())
@@ -1236,6 +1228,7 @@ func pruneExpiredAndAutoCreateCustomVolumeSnapshotsTask(d *Daemon) (task.Func, t
 				return fmt.Errorf("Failed getting volumes for auto custom volume snapshot task: %w", err)
 			}
 
+			var dueVolumes []db.StorageVolumeArgs
 			for _, v := range allVolumes {
 				err = project.AllowSnapshotCreation(projects[v.ProjectName])
 				if err != nil {
@@ -1252,107 +1245,17 @@ func pruneExpiredAndAutoCreateCustomVolumeSnapshotsTask(d *Daemon) (task.Func, t
 					continue
 				}
 
-				if v.NodeID < 0 {
-					// Keep a separate list of remote volumes in order to select a member to
-					// perform the snapshot later.
-					remoteVolumes = append(remoteVolumes, v)
-				} else {
-					logger.Debug("Scheduling local auto custom volume snapshot", logger.Ctx{"volName": v.Name, "project": v.ProjectName, "pool": v.PoolName})
-					volumes = append(volumes, v) // Always include local volumes.
-				}
+				dueVolumes = append(dueVolumes, v)
 			}
 
-			if len(remoteVolumes) > 0 || len(expiredRemoteSnapshots) > 0 {
-				// Get list of cluster members.
-				members, err := tx.GetNodes(ctx)
-				if err != nil {
-					return fmt.Errorf("Failed getting cluster members: %w", err)
-				}
-
-				memberCount = len(members)
-
-				// Filter to online members.
-				for _, member := range members {
-					if member.IsOffline(s.GlobalConfig.OfflineThreshold()) {
-						continue
-					}
-
-					onlineMemberIDs = append(onlineMemberIDs, member.ID)
-				}
-
-				return nil
-			}
-
-			return nil
+			volumes, err = storageVolumesForLocalMember(ctx, tx, s, dueVolumes, "snapshot")
+			return err
 		})
 		if err != nil {
 			logger.Error("Failed getting custom volume info", logger.Ctx{"err": err})
 			return
 		}
 
-		localMemberID := s.DB.Cluster.GetNodeID()
-
-		if len(expiredRemoteSnapshots) > 0 {
-			// Skip expiring remote custom volume snapshots if there are no online members, as we can't
-			// be sure that the cluster isn't partitioned and we may end up attempting to expire
-			// snapshot on multiple members.
-			if memberCount > 1 && len(onlineMemberIDs) <= 0 {
-				logger.Error("Skipping remote volumes for expire custom volume snapshot task due to no online members")
-			} else {
-				for _, v := range expiredRemoteSnapshots {
-					// If there are multiple cluster members, a stable random member is chosen
-					// to perform the snapshot expiry. This avoids expiring the snapshot on
-					// every member and spreads the load across the online cluster members.
-					if memberCount > 1 {
-						selectedMemberID, err := localUtil.GetStableRandomInt64FromList(int64(v.ID), onlineMemberIDs)
-						if err != nil {
-							logger.Error("Failed scheduling remote expire custom volume snapshot task", logger.Ctx{"volName": v.Name, "project": v.ProjectName, "pool": v.PoolName, "err": err})
-							continue
-						}
-
-						// Don't snapshot, if we're not the chosen one.
-						if localMemberID != selectedMemberID {
-							continue
-						}
-					}
-
-					logger.Debug("Scheduling remote custom volume snapshot expiry", logger.Ctx{"volName": v.Name, "project": v.ProjectName, "pool": v.PoolName})
-					expiredSnapshots = append(expiredSnapshots, v)
-				}
-			}
-		}
-
-		if len(remoteVolumes) > 0 {
-			// Skip snapshotting remote custom volumes if there are no online members, as we can't be
-			// sure that the cluster isn't partitioned and we may end up attempting the snapshot on
-			// multiple members.
-			if memberCount > 1 && len(onlineMemberIDs) <= 0 {
-				logger.Error("Skipping remote volumes for auto custom volume snapshot task due to no online members")
-			} else {
-				for _, v := range remoteVolumes {
-					// If there are multiple cluster members, a stable random member is chosen
-					// to perform the snapshot from. This avoids taking the snapshot on every
-					// member and spreads the load taking the snapshots across the online
-					// cluster members.
-					if memberCount > 1 {
-						selectedNodeID, err := localUtil.GetStableRandomInt64FromList(int64(v.ID), onlineMemberIDs)
-						if err != nil {
-							logger.Error("Failed scheduling remote auto custom volume snapshot task", logger.Ctx{"volName": v.Name, "project": v.ProjectName, "pool": v.PoolName, "err": err})
-							continue
-						}
-
-						// Don't snapshot, if we're not the chosen one.
-						if localMemberID != selectedNodeID {
-							continue
-						}
-					}
-
-					logger.Debug("Scheduling remote auto custom volume snapshot", logger.Ctx{"volName": v.Name, "project": v.ProjectName, "pool": v.PoolName})
-					volumes = append(volumes, v)
-				}
-			}
-		}
-
 		// Handle snapshot expiry first before creating new ones to reduce the chances of running out of
 		// disk space.
 		if len(expiredSnapshots) > 0 {
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"slices"
 	"strings"
 
@@ -10,12 +11,80 @@ import (
 	"github.com/lxc/incus/v6/internal/server/instance"
 	"github.com/lxc/incus/v6/internal/server/state"
 	storagePools "github.com/lxc/incus/v6/internal/server/storage"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
 	"github.com/lxc/incus/v6/internal/version"
 	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
 )
 
 var supportedVolumeTypes = []int{db.StoragePoolVolumeTypeContainer, db.StoragePoolVolumeTypeVM, db.StoragePoolVolumeTypeCustom, db.StoragePoolVolumeTypeImage}
 
+// storageVolumesForLocalMember returns the volumes a scheduled task should handle on the local member.
+// Local volumes are always included. Volumes on remote pools are handled by a single online cluster member,
+// chosen as a stable random member for each volume, which spreads the load across the cluster.
+func storageVolumesForLocalMember(ctx context.Context, tx *db.ClusterTx, s *state.State, volumes []db.StorageVolumeArgs, taskName string) ([]db.StorageVolumeArgs, error) {
+	var selected, remoteVolumes []db.StorageVolumeArgs
+
+	for _, v := range volumes {
+		if v.NodeID < 0 {
+			remoteVolumes = append(remoteVolumes, v)
+			continue
+		}
+
+		logger.Debug("Scheduling local custom volume task", logger.Ctx{"task": taskName, "volName": v.Name, "project": v.ProjectName, "pool": v.PoolName})
+		selected = append(selected, v)
+	}
+
+	if len(remoteVolumes) == 0 {
+		return selected, nil
+	}
+
+	// Get list of cluster members.
+	members, err := tx.GetNodes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting cluster members: %w", err)
+	}
+
+	// Filter to online members.
+	var onlineMemberIDs []int64
+	for _, member := range members {
+		if member.IsOffline(s.GlobalConfig.OfflineThreshold()) {
+			continue
+		}
+
+		onlineMemberIDs = append(onlineMemberIDs, member.ID)
+	}
+
+	// Skip remote volumes if there are no online members, as we can't be sure that the cluster isn't
+	// partitioned and we may end up running the task on multiple members.
+	if len(members) > 1 && len(onlineMemberIDs) == 0 {
+		logger.Error("Skipping remote volumes for custom volume task due to no online members", logger.Ctx{"task": taskName})
+		return selected, nil
+	}
+
+	localMemberID := s.DB.Cluster.GetNodeID()
+
+	for _, v := range remoteVolumes {
+		if len(members) > 1 {
+			selectedMemberID, err := localUtil.GetStableRandomInt64FromList(v.ID, onlineMemberIDs)
+			if err != nil {
+				logger.Error("Failed scheduling remote custom volume task", logger.Ctx{"task": taskName, "volName": v.Name, "project": v.ProjectName, "pool": v.PoolName, "err": err})
+				continue
+			}
+
+			// Skip the volume if we're not the chosen one.
+			if localMemberID != selectedMemberID {
+				continue
+			}
+		}
+
+		logger.Debug("Scheduling remote custom volume task", logger.Ctx{"task": taskName, "volName": v.Name, "project": v.ProjectName, "pool": v.PoolName})
+		selected = append(selected, v)
+	}
+
+	return selected, nil
+}
+
 func storagePoolVolumeUpdateUsers(ctx context.Context, s *state.State, projectName string, oldPoolName string, oldVol *api.StorageVolume, newPoolName string, newVol *api.StorageVolume) error {
 	// Update all instances that are using the volume with a local (non-expanded) device.
 	err := storagePools.VolumeUsedByInstanceDevices(s, oldPoolName, projectName, oldVol, false, func(dbInst db.InstanceArgs, project api.Project, usedByDevices []string) error {
@@ -0,0 +1,85 @@
+package chunkstore
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// Chunk size boundaries. Cut points are looked for once the minimum size is reached and
+// the mask is tuned so that the average chunk ends up close to 64KiB.
+const (
+	chunkMinSize = 16 * 1024
+	chunkMaxSize = 256 * 1024
+	chunkMask    = (1 << 16) - 1
+)
+
+// gear is the table of random values used by the rolling hash.
+// It is derived from SHA256 so that chunk boundaries are stable across builds.
+var gear = func() [256]uint64 {
+	var table [256]uint64
+	for i := range table {
+		sum := sha256.Sum256([]byte{byte(i)})
+		table[i] = binary.LittleEndian.Uint64(sum[:8])
+	}
+
+	return table
+}()
+
+// chunker splits a stream into content-defined chunks using a gear rolling hash.
+type chunker struct {
+	r   io.Reader
+	buf []byte
+	n   int
+	eof bool
+}
+
+func newChunker(r io.Reader) *chunker {
+	return &chunker{r: r, buf: make([]byte, chunkMaxSize)}
+}
+
+// next returns the next chunk or io.EOF once the stream is exhausted.
+func (c *chunker) next() ([]byte, error) {
+	if !c.eof && c.n < len(c.buf) {
+		n, err := io.ReadFull(c.r, c.buf[c.n:])
+		c.n += n
+
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			c.eof = true
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	if c.n == 0 {
+		return nil, io.EOF
+	}
+
+	cut := cutPoint(c.buf[:c.n])
+	chunk := make([]byte, cut)
+	copy(chunk, c.buf[:cut])
+
+	c.n = copy(c.buf, c.buf[cut:c.n])
+
+	return chunk, nil
+}
+
+// cutPoint returns the length of the first chunk of data.
+func cutPoint(data []byte) int {
+	if len(data) <= chunkMinSize {
+		return len(data)
+	}
+
+	end := min(len(data), chunkMaxSize)
+
+	var hash uint64
+	for i := chunkMinSize; i < end; i++ {
+		hash = (hash << 1) + gear[data[i]]
+		if hash&chunkMask == 0 {
+			return i + 1
+		}
+	}
+
+	return end
+}
@@ -0,0 +1,243 @@
+package chunkstore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Chunk represents a single chunk of a file.
+type Chunk struct {
+	// SHA256 of the uncompressed chunk content.
+	Hash string `json:"hash"`
+
+	// Size of the uncompressed chunk content.
+	Size int64 `json:"size"`
+}
+
+// Index represents a file stored in the chunk store as an ordered list of chunks.
+type Index struct {
+	// SHA256 of the whole file.
+	Hash string `json:"hash"`
+
+	// Size of the whole file.
+	Size int64 `json:"size"`
+
+	// Chunks making up the file, in order.
+	Chunks []Chunk `json:"chunks"`
+}
+
+// ReadIndex reads a chunk index from the given path.
+func ReadIndex(path string) (*Index, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	idx := &Index{}
+	err = json.Unmarshal(content, idx)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing chunk index %q: %w", path, err)
+	}
+
+	return idx, nil
+}
+
+// Write atomically writes the chunk index to the given path.
+func (idx *Index) Write(path string) error {
+	content, err := json.Marshal(idx)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(path, content)
+}
+
+// writeFileAtomic writes the data to a uniquely named temporary file next to the path and renames it into
+// place, so that concurrent writers of the same path don't interfere with each other.
+func writeFileAtomic(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp_")
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = os.Remove(f.Name()) }()
+
+	_, err = f.Write(data)
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	err = f.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+// Store is a content-addressed store of zstd compressed chunks.
+// Files are split at content-defined boundaries so that files sharing content share most of their chunks.
+type Store struct {
+	path string
+}
+
+var encoder, _ = zstd.NewWriter(nil)
+var decoder, _ = zstd.NewReader(nil)
+
+// New returns a chunk store rooted at the given path.
+func New(path string) *Store {
+	return &Store{path: path}
+}
+
+// chunkPath returns the path of the chunk with the given hash.
+func (s *Store) chunkPath(hash string) string {
+	return filepath.Join(s.path, hash[:4], hash)
+}
+
+// Has returns whether a chunk is present in the store.
+func (s *Store) Has(hash string) bool {
+	_, err := os.Lstat(s.chunkPath(hash))
+	return err == nil
+}
+
+// Import splits the content of the reader into chunks, adds the missing ones to the store and
+// returns the resulting index.
+func (s *Store) Import(r io.Reader) (*Index, error) {
+	idx := &Index{Chunks: []Chunk{}}
+	fileHash := sha256.New()
+	c := newChunker(r)
+
+	for {
+		data, err := c.next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return nil, err
+		}
+
+		_, _ = fileHash.Write(data)
+
+		sum := sha256.Sum256(data)
+		chunk := Chunk{Hash: hex.EncodeToString(sum[:]), Size: int64(len(data))}
+
+		err = s.writeChunk(chunk.Hash, data)
+		if err != nil {
+			return nil, err
+		}
+
+		idx.Chunks = append(idx.Chunks, chunk)
+		idx.Size += chunk.Size
+	}
+
+	idx.Hash = hex.EncodeToString(fileHash.Sum(nil))
+
+	return idx, nil
+}
+
+// writeChunk adds a chunk to the store unless it's already there.
+func (s *Store) writeChunk(hash string, data []byte) error {
+	if s.Has(hash) {
+		return nil
+	}
+
+	path := s.chunkPath(hash)
+
+	err := os.MkdirAll(filepath.Dir(path), 0o700)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(path, encoder.EncodeAll(data, nil))
+}
+
+// Export writes the content of the file described by the index to the writer.
+// Every chunk is checked against its hash, as is the whole file.
+func (s *Store) Export(idx *Index, w io.Writer) error {
+	fileHash := sha256.New()
+	mw := io.MultiWriter(w, fileHash)
+
+	for _, chunk := range idx.Chunks {
+		compressed, err := os.ReadFile(s.chunkPath(chunk.Hash))
+		if err != nil {
+			return fmt.Errorf("Failed reading chunk %q: %w", chunk.Hash, err)
+		}
+
+		data, err := decoder.DecodeAll(compressed, nil)
+		if err != nil {
+			return fmt.Errorf("Failed decompressing chunk %q: %w", chunk.Hash, err)
+		}
+
+		sum := sha256.Sum256(data)
+		if hex.EncodeToString(sum[:]) != chunk.Hash {
+			return fmt.Errorf("Chunk %q is corrupted", chunk.Hash)
+		}
+
+		_, err = mw.Write(data)
+		if err != nil {
+			return err
+		}
+	}
+
+	if hex.EncodeToString(fileHash.Sum(nil)) != idx.Hash {
+		return errors.New("Exported file doesn't match the index hash")
+	}
+
+	return nil
+}
+
+// Prune removes all the chunks which aren't referenced by any of the given indexes and returns how many
+// were removed. It must not run concurrently with Import.
+func (s *Store) Prune(indexes []*Index) (int, error) {
+	used := map[string]struct{}{}
+	for _, idx := range indexes {
+		for _, chunk := range idx.Chunks {
+			used[chunk.Hash] = struct{}{}
+		}
+	}
+
+	removed := 0
+	err := filepath.WalkDir(s.path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		_, ok := used[d.Name()]
+		if ok {
+			return nil
+		}
+
+		err = os.Remove(path)
+		if err != nil {
+			return err
+		}
+
+		removed++
+
+		return nil
+	})
+	if err != nil {
+		return removed, err
+	}
+
+	return removed, nil
+}
@@ -0,0 +1,161 @@
+package chunkstore_test
+
+import (
+	"bytes"
+	"io/fs"
+	"math/rand"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/internal/chunkstore"
+)
+
+// randomData returns deterministic pseudo-random content.
+func randomData(seed int64, size int) []byte {
+	data := make([]byte, size)
+	_, _ = rand.New(rand.NewSource(seed)).Read(data)
+
+	return data
+}
+
+func TestStore_ImportExport(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "empty", data: []byte{}},
+		{name: "smaller than a chunk", data: randomData(1, 1000)},
+		{name: "several chunks", data: randomData(2, 3*1024*1024)},
+		{name: "repetitive content", data: bytes.Repeat([]byte("incus"), 500*1024)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := chunkstore.New(t.TempDir())
+
+			idx, err := store.Import(bytes.NewReader(tt.data))
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(tt.data)), idx.Size)
+
+			var out bytes.Buffer
+			err = store.Export(idx, &out)
+			require.NoError(t, err)
+			assert.True(t, bytes.Equal(tt.data, out.Bytes()))
+		})
+	}
+}
+
+func TestStore_Deduplication(t *testing.T) {
+	store := chunkstore.New(t.TempDir())
+
+	// Insert some data in the middle of the original content to shift everything after it.
+	original := randomData(3, 4*1024*1024)
+	modified := append(append(append([]byte{}, original[:1024*1024]...), []byte("some new content")...), original[1024*1024:]...)
+
+	idxOriginal, err := store.Import(bytes.NewReader(original))
+	require.NoError(t, err)
+
+	idxModified, err := store.Import(bytes.NewReader(modified))
+	require.NoError(t, err)
+
+	known := map[string]bool{}
+	for _, chunk := range idxOriginal.Chunks {
+		known[chunk.Hash] = true
+	}
+
+	shared := 0
+	for _, chunk := range idxModified.Chunks {
+		if known[chunk.Hash] {
+			shared++
+		}
+	}
+
+	// Only the chunk(s) around the insertion point should differ.
+	assert.GreaterOrEqual(t, shared, len(idxModified.Chunks)-2)
+
+	// Pruning with only the modified index keeps it intact.
+	removed, err := store.Prune([]*chunkstore.Index{idxModified})
+	require.NoError(t, err)
+	assert.LessOrEqual(t, removed, 2)
+
+	var out bytes.Buffer
+	err = store.Export(idxModified, &out)
+	require.NoError(t, err)
+	assert.True(t, bytes.Equal(modified, out.Bytes()))
+
+	// The original can't be rebuilt anymore.
+	err = store.Export(idxOriginal, &bytes.Buffer{})
+	assert.Error(t, err)
+}
+
+func TestIndex_WriteRead(t *testing.T) {
+	store := chunkstore.New(t.TempDir())
+	path := filepath.Join(t.TempDir(), "test.idx")
+
+	idx, err := store.Import(bytes.NewReader(randomData(4, 512*1024)))
+	require.NoError(t, err)
+
+	err = idx.Write(path)
+	require.NoError(t, err)
+
+	loaded, err := chunkstore.ReadIndex(path)
+	require.NoError(t, err)
+	assert.Equal(t, idx, loaded)
+}
+
+func TestStore_ConcurrentImport(t *testing.T) {
+	storePath := t.TempDir()
+	store := chunkstore.New(storePath)
+	indexPath := filepath.Join(t.TempDir(), "test.idx")
+	data := randomData(5, 512*1024)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			idx, err := store.Import(bytes.NewReader(data))
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			errs <- idx.Write(indexPath)
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	idx, err := chunkstore.ReadIndex(indexPath)
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	err = store.Export(idx, &out)
+	require.NoError(t, err)
+	assert.True(t, bytes.Equal(data, out.Bytes()))
+
+	// No temporary file is left behind.
+	for _, dir := range []string{storePath, filepath.Dir(indexPath)} {
+		err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			assert.NotContains(t, d.Name(), ".tmp_")
+			return nil
+		})
+		require.NoError(t, err)
+	}
+}
@@ -0,0 +1,91 @@
+package instance
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// backupTargetS3Prefix is the prefix of backups.target values referring to an S3 target of the server configuration.
+const backupTargetS3Prefix = "s3:"
+
+// BackupTargetFromURL converts the URL of an S3 backup target into a backup target using the given credentials.
+// The URL format is "http(s)://<host>/<bucket>[/<path>]", credentials being stored separately.
+func BackupTargetFromURL(value string, accessKey string, secretKey string) (*api.BackupTarget, error) {
+	u, err := url.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("Unsupported backup target URL scheme %q", u.Scheme)
+	}
+
+	if u.User != nil {
+		return nil, errors.New("Backup target URL must not include credentials")
+	}
+
+	if u.Host == "" {
+		return nil, errors.New("Backup target URL is missing the host")
+	}
+
+	bucketName, path, _ := strings.Cut(strings.Trim(u.Path, "/"), "/")
+	if bucketName == "" {
+		return nil, errors.New("Backup target URL is missing the bucket name")
+	}
+
+	target := &api.BackupTarget{
+		Protocol:   "s3",
+		URL:        (&url.URL{Scheme: u.Scheme, Host: u.Host}).String(),
+		BucketName: bucketName,
+		Path:       path,
+		AccessKey:  accessKey,
+		SecretKey:  secretKey,
+	}
+
+	return target, nil
+}
+
+// ParseBackupTargetS3 returns the name of the server S3 target referred to by a backups.target value of the
+// form "s3:<name>".
+func ParseBackupTargetS3(value string) (string, error) {
+	name, ok := strings.CutPrefix(value, backupTargetS3Prefix)
+	if !ok || name == "" || strings.ContainsAny(name, "./") {
+		return "", fmt.Errorf("Invalid backup target %q, expected s3:<name>", value)
+	}
+
+	return name, nil
+}
+
+// ParseBackupTargetBucket splits a backups.target storage bucket reference of the form "<pool>/<bucket>".
+func ParseBackupTargetBucket(value string) (string, string, error) {
+	poolName, bucketName, ok := strings.Cut(value, "/")
+	if !ok || poolName == "" || bucketName == "" || strings.Contains(bucketName, "/") {
+		return "", "", fmt.Errorf("Invalid backup target storage bucket %q, expected <pool>/<bucket>", value)
+	}
+
+	return poolName, bucketName, nil
+}
+
+// IsBackupTargetS3 returns whether a backups.target value refers to a server S3 target rather than a storage bucket.
+func IsBackupTargetS3(value string) bool {
+	return strings.HasPrefix(value, backupTargetS3Prefix)
+}
+
+// ValidBackupTarget validates a backups.target value.
+func ValidBackupTarget(value string) error {
+	if strings.Contains(value, "://") {
+		return errors.New("S3 URLs can't be used directly, configure the target on the server as backups.s3.<name>.* and use s3:<name>")
+	}
+
+	if IsBackupTargetS3(value) {
+		_, err := ParseBackupTargetS3(value)
+		return err
+	}
+
+	_, _, err := ParseBackupTargetBucket(value)
+	return err
+}
@@ -157,8 +157,10 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	//   - `auto` *(default)*: The system will automatically decide the best evacuation method based on the
 	//      instance's type and configured devices:
 	//     + If any device is not suitable for migration, the instance will not be migrated (only stopped).
-	//     + Live migration will be used only for virtual machines with the `migration.stateful` setting
+	//     + Live migration will be used only for instances with the `migration.stateful` setting
 	//       enabled and for which all its devices can be migrated as well.
+	//       Containers additionally need CRIU to be usable on the cluster member, and are stopped,
+	//       migrated and started again on the target if their live migration fails.
 	//   - `live-migrate`: Instances are live-migrated to another server. This means the instance remains running
 	//      and operational during the migration process, ensuring minimal disruption.
 	//   - `migrate`: In this mode, instances are migrated to another server in the cluster. The migration
@@ -177,6 +179,31 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	//  shortdesc: What to do when evacuating the instance
 	"cluster.evacuate": validate.Optional(validate.IsOneOf("auto", "migrate", "live-migrate", "stop", "stateful-stop", "force-stop")),
 
+	// gendoc:generate(entity=instance, group=miscellaneous, key=placement.affinity)
+	// A comma-separated list of `key=value` pairs, for example `user.app=web`.
+	// The instance is only placed on cluster members that already run an instance of the same project
+	// whose expanded configuration has all those values.
+	// If no such instance exists yet, any cluster member can be used.
+	//
+	// See {ref}`cluster-placement-rules` for more information.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: Instances to place this instance with
+	"placement.affinity": validate.Optional(IsPlacementSelector),
+
+	// gendoc:generate(entity=instance, group=miscellaneous, key=placement.anti-affinity)
+	// A comma-separated list of `key=value` pairs, for example `user.app=db`.
+	// The instance is never placed on a cluster member that runs an instance of the same project
+	// whose expanded configuration has all those values.
+	//
+	// See {ref}`cluster-placement-rules` for more information.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: Instances to keep this instance away from
+	"placement.anti-affinity": validate.Optional(IsPlacementSelector),
+
 	// gendoc:generate(entity=instance, group=resource-limits, key=limits.cpu)
 	// A number or a specific range of CPUs to expose to the instance.
 	//
@@ -252,6 +279,7 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 
 	// gendoc:generate(entity=instance, group=migration, key=migration.stateful)
 	// Enabling this option prevents the use of some features that are incompatible with it.
+	// For containers, it makes cluster evacuations live-migrate the container using CRIU.
 	// ---
 	//  type: bool
 	//  defaultdesc: `false`
@@ -353,6 +381,46 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 		return err
 	},
 
+	// gendoc:generate(entity=instance, group=backups, key=backups.schedule)
+	// Specify either a cron expression (`<minute> <hour> <dom> <month> <dow>`), a comma-and-space-separated list of schedule aliases (`@hourly`, `@daily`, `@midnight`, `@weekly`, `@monthly`, `@annually`, `@yearly`), or `@never` or leave empty to disable automatic backups.
+	// ---
+	//  type: string
+	//  defaultdesc: empty
+	//  liveupdate: no
+	//  shortdesc: Schedule for automatic instance backups
+	"backups.schedule": validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly", "@never"})),
+
+	// gendoc:generate(entity=instance, group=backups, key=backups.expiry)
+	// Specify an expression like `1M 2H 3d 4w 5m 6y`.
+	// ---
+	//  type: string
+	//  liveupdate: no
+	//  shortdesc: When scheduled backups are to be deleted
+	"backups.expiry": func(value string) error {
+		// Validate expression
+		_, err := GetExpiry(time.Time{}, value)
+		return err
+	},
+
+	// gendoc:generate(entity=instance, group=backups, key=backups.retention)
+	// Only the given number of most recent scheduled backups are kept, older ones get deleted.
+	// ---
+	//  type: integer
+	//  defaultdesc: `0` (no limit)
+	//  liveupdate: no
+	//  shortdesc: Number of scheduled backups to keep
+	"backups.retention": validate.Optional(validate.IsUint32),
+
+	// gendoc:generate(entity=instance, group=backups, key=backups.target)
+	// Specify either an S3 target of the server configuration (`s3:<name>`, see `backups.s3.NAME.url`) or a storage bucket (`<pool>/<bucket>`) in the instance's project.
+	// Scheduled backups are uploaded to the target and then removed from the server.
+	// When not set, scheduled backups are kept on the server.
+	// ---
+	//  type: string
+	//  liveupdate: no
+	//  shortdesc: Where to store scheduled backups
+	"backups.target": validate.Optional(ValidBackupTarget),
+
 	// Volatile keys.
 
 	// gendoc:generate(entity=instance, group=volatile, key=volatile.apply_template)
@@ -942,6 +1010,14 @@ var InstanceConfigKeysContainer = map[string]func(value string) error{
 	//  shortdesc: Whether the container is an OCI application container
 	"volatile.container.oci": validate.IsBool,
 
+	// gendoc:generate(entity=instance, group=volatile, key=volatile.container.oci.lazy)
+	// Set to `pulling` while the root file system is fetched from the registry on demand and to `pulled` once
+	// all of it is available locally. The root file system is turned into a regular one on the next start.
+	// ---
+	//  type: string
+	//  shortdesc: State of the lazily pulled root file system of an OCI application container
+	"volatile.container.oci.lazy": validate.Optional(validate.IsOneOf("pulling", "pulled")),
+
 	// gendoc:generate(entity=instance, group=volatile, key=volatile.last_state.idmap)
 	//
 	// ---
@@ -0,0 +1,51 @@
+package instance
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PlacementSelector is a set of configuration keys and values which an instance must all have to match.
+type PlacementSelector map[string]string
+
+// ParsePlacementSelector parses a comma-separated list of key=value pairs.
+func ParsePlacementSelector(value string) (PlacementSelector, error) {
+	selector := PlacementSelector{}
+
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		key, val, ok := strings.Cut(entry, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("Invalid placement selector entry %q, expected key=value", entry)
+		}
+
+		selector[strings.TrimSpace(key)] = strings.TrimSpace(val)
+	}
+
+	if len(selector) == 0 {
+		return nil, fmt.Errorf("Empty placement selector %q", value)
+	}
+
+	return selector, nil
+}
+
+// IsPlacementSelector validates a placement selector.
+func IsPlacementSelector(value string) error {
+	_, err := ParsePlacementSelector(value)
+	return err
+}
+
+// Matches returns whether the configuration has all the keys and values of the selector.
+func (p PlacementSelector) Matches(config map[string]string) bool {
+	for key, value := range p {
+		if config[key] != value {
+			return false
+		}
+	}
+
+	return true
+}
@@ -7,11 +7,14 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
 
+	"github.com/lxc/incus/v6/internal/instance"
 	"github.com/lxc/incus/v6/internal/server/state"
 	"github.com/lxc/incus/v6/shared/api"
 )
@@ -53,14 +56,95 @@ func (b *CommonBackup) OptimizedStorage() bool {
 
 // upload handles backup uploads.
 func (b *CommonBackup) upload(filePath string, req *api.BackupTarget) error {
+	client, err := newS3Client(req)
+	if err != nil {
+		return err
+	}
+
+	// Upload the object.
+	tr, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+
+	defer tr.Close()
+
+	_, err = client.PutObject(context.Background(), req.BucketName, req.Path, tr, -1, minio.PutObjectOptions{})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// PruneTarget deletes the backups stored directly under the target path which are either beyond the
+// retention count (most recent ones being kept) or past the expiry expression.
+// Only the objects whose name is accepted by match are considered, anything else stored under the path
+// is left untouched.
+func PruneTarget(req *api.BackupTarget, match func(name string) bool, retention int, expiry string) error {
+	client, err := newS3Client(req)
+	if err != nil {
+		return err
+	}
+
+	prefix := req.Path
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	var objects []minio.ObjectInfo
+	for object := range client.ListObjects(context.Background(), req.BucketName, minio.ListObjectsOptions{Prefix: prefix}) {
+		if object.Err != nil {
+			return object.Err
+		}
+
+		// Skip sub-directories and objects which weren't created as backups.
+		name := strings.TrimPrefix(object.Key, prefix)
+		if strings.Contains(name, "/") || !match(name) {
+			continue
+		}
+
+		objects = append(objects, object)
+	}
+
+	// Sort the most recent backups first.
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].LastModified.After(objects[j].LastModified)
+	})
+
+	for i, object := range objects {
+		expired := retention > 0 && i >= retention
+		if !expired {
+			expiryDate, err := instance.GetExpiry(object.LastModified, expiry)
+			if err != nil {
+				return err
+			}
+
+			expired = !expiryDate.IsZero() && expiryDate.Before(time.Now())
+		}
+
+		if !expired {
+			continue
+		}
+
+		err = client.RemoveObject(context.Background(), req.BucketName, object.Key, minio.RemoveObjectOptions{})
+		if err != nil {
+			return fmt.Errorf("Failed deleting backup %q from target: %w", object.Key, err)
+		}
+	}
+
+	return nil
+}
+
+// newS3Client returns an S3 client for the backup target.
+func newS3Client(req *api.BackupTarget) (*minio.Client, error) {
 	if req.Protocol != "s3" {
-		return fmt.Errorf("Unsupported backup target protocol %q", req.Protocol)
+		return nil, fmt.Errorf("Unsupported backup target protocol %q", req.Protocol)
 	}
 
-	// Set up an S3 client.
 	uri, err := url.Parse(req.URL)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	creds := credentials.NewStaticV4(req.AccessKey, req.SecretKey, "")
@@ -75,28 +159,10 @@ func (b *CommonBackup) upload(filePath string, req *api.BackupTarget) error {
 		},
 	}
 
-	client, err := minio.New(uri.Host, &minio.Options{
+	return minio.New(uri.Host, &minio.Options{
 		BucketLookup: minio.BucketLookupPath,
 		Creds:        creds,
 		Secure:       uri.Scheme == "https",
 		Transport:    ts,
 	})
-	if err != nil {
-		return err
-	}
-
-	// Upload the object.
-	tr, err := os.Open(filePath)
-	if err != nil {
-		return err
-	}
-
-	defer tr.Close()
-
-	_, err = client.PutObject(context.Background(), req.BucketName, req.Path, tr, -1, minio.PutObjectOptions{})
-	if err != nil {
-		return err
-	}
-
-	return nil
 }
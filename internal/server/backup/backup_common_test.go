@@ -0,0 +1,185 @@
+package backup
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// fakeS3 is a minimal S3 server supporting object listing and deletion.
+type fakeS3 struct {
+	mu      sync.Mutex
+	bucket  string
+	objects map[string]time.Time
+}
+
+type fakeS3Object struct {
+	Key          string
+	LastModified string
+	Size         int64
+}
+
+type fakeS3Prefix struct {
+	Prefix string
+}
+
+type fakeS3ListResult struct {
+	XMLName        xml.Name `xml:"ListBucketResult"`
+	Name           string
+	Prefix         string
+	KeyCount       int
+	MaxKeys        int
+	IsTruncated    bool
+	Contents       []fakeS3Object
+	CommonPrefixes []fakeS3Prefix
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket != f.bucket {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Query().Has("location"):
+		_, _ = w.Write([]byte(`<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/">us-east-1</LocationConstraint>`))
+	case r.Method == http.MethodGet && key == "":
+		prefix := r.URL.Query().Get("prefix")
+		delimiter := r.URL.Query().Get("delimiter")
+
+		result := fakeS3ListResult{Name: f.bucket, Prefix: prefix, MaxKeys: 1000}
+		keys := make([]string, 0, len(f.objects))
+		for k := range f.objects {
+			keys = append(keys, k)
+		}
+
+		slices.Sort(keys)
+
+		for _, k := range keys {
+			name, ok := strings.CutPrefix(k, prefix)
+			if !ok {
+				continue
+			}
+
+			dir, _, isDir := strings.Cut(name, delimiter)
+			if delimiter != "" && isDir {
+				commonPrefix := fakeS3Prefix{Prefix: prefix + dir + delimiter}
+				if !slices.Contains(result.CommonPrefixes, commonPrefix) {
+					result.CommonPrefixes = append(result.CommonPrefixes, commonPrefix)
+				}
+
+				continue
+			}
+
+			result.Contents = append(result.Contents, fakeS3Object{Key: k, LastModified: f.objects[k].UTC().Format(time.RFC3339), Size: 1})
+		}
+
+		result.KeyCount = len(result.Contents) + len(result.CommonPrefixes)
+
+		w.Header().Set("Content-Type", "application/xml")
+		_ = xml.NewEncoder(w).Encode(result)
+	case r.Method == http.MethodDelete && key != "":
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotImplemented)
+	}
+}
+
+func (f *fakeS3) keys() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	keys := make([]string, 0, len(f.objects))
+	for k := range f.objects {
+		keys = append(keys, k)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
+
+func TestPruneTarget(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	isBackup := func(name string) bool { return strings.HasPrefix(name, "scheduled-") }
+
+	tests := []struct {
+		name      string
+		retention int
+		expiry    string
+		want      []string
+	}{
+		{
+			name: "nothing to prune",
+			want: []string{"proj/c1/notes.txt", "proj/c1/old/scheduled-0", "proj/c1/scheduled-1", "proj/c1/scheduled-2", "proj/c1/scheduled-3", "proj/c2/scheduled-1"},
+		},
+		{
+			name:      "retention keeps the most recent backups",
+			retention: 2,
+			want:      []string{"proj/c1/notes.txt", "proj/c1/old/scheduled-0", "proj/c1/scheduled-2", "proj/c1/scheduled-3", "proj/c2/scheduled-1"},
+		},
+		{
+			name:   "expiry deletes the old backups",
+			expiry: "2d",
+			want:   []string{"proj/c1/notes.txt", "proj/c1/old/scheduled-0", "proj/c1/scheduled-3", "proj/c2/scheduled-1"},
+		},
+		{
+			name:      "retention and expiry",
+			retention: 1,
+			expiry:    "1w",
+			want:      []string{"proj/c1/notes.txt", "proj/c1/old/scheduled-0", "proj/c1/scheduled-3", "proj/c2/scheduled-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s3 := &fakeS3{
+				bucket: "backups",
+				objects: map[string]time.Time{
+					"proj/c1/scheduled-1":     now.AddDate(0, 0, -5),
+					"proj/c1/scheduled-2":     now.AddDate(0, 0, -3),
+					"proj/c1/scheduled-3":     now.AddDate(0, 0, -1),
+					"proj/c1/notes.txt":       now.AddDate(0, 0, -10),
+					"proj/c1/old/scheduled-0": now.AddDate(0, 0, -10),
+					"proj/c2/scheduled-1":     now.AddDate(0, 0, -10),
+				},
+			}
+
+			server := httptest.NewServer(s3)
+			defer server.Close()
+
+			target := &api.BackupTarget{
+				Protocol:   "s3",
+				URL:        server.URL,
+				BucketName: "backups",
+				Path:       "proj/c1",
+				AccessKey:  "access",
+				SecretKey:  "secret",
+			}
+
+			err := PruneTarget(target, isBackup, tt.retention, tt.expiry)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, s3.keys())
+		})
+	}
+}
+
+func TestPruneTargetUnsupportedProtocol(t *testing.T) {
+	err := PruneTarget(&api.BackupTarget{Protocol: "sftp"}, func(string) bool { return true }, 1, "")
+	assert.EqualError(t, err, `Unsupported backup target protocol "sftp"`)
+}
@@ -3,6 +3,7 @@ package backup
 import (
 	"fmt"
 	"io"
+	"time"
 
 	"gopkg.in/yaml.v2"
 
@@ -50,10 +51,12 @@ type Info struct {
 	Backend          string         `json:"backend" yaml:"backend"`
 	Pool             string         `json:"pool" yaml:"pool"`
 	Snapshots        []string       `json:"snapshots,omitempty" yaml:"snapshots,omitempty"`
-	OptimizedStorage *bool          `json:"optimized,omitempty" yaml:"optimized,omitempty"`               // Optional field to handle older optimized backups that don't have this field.
-	OptimizedHeader  *bool          `json:"optimized_header,omitempty" yaml:"optimized_header,omitempty"` // Optional field to handle older optimized backups that don't have this field.
-	Type             Type           `json:"type,omitempty" yaml:"type,omitempty"`                         // Type of backup.
-	Config           *config.Config `json:"config,omitempty" yaml:"config,omitempty"`                     // Equivalent of backup.yaml but embedded in index for quick retrieval.
+	Parent           string         `json:"parent,omitempty" yaml:"parent,omitempty"`                       // Snapshot an incremental backup is based on.
+	ParentCreatedAt  *time.Time     `json:"parent_created_at,omitempty" yaml:"parent_created_at,omitempty"` // Creation date of the parent snapshot, to tell it apart from another snapshot with the same name.
+	OptimizedStorage *bool          `json:"optimized,omitempty" yaml:"optimized,omitempty"`                 // Optional field to handle older optimized backups that don't have this field.
+	OptimizedHeader  *bool          `json:"optimized_header,omitempty" yaml:"optimized_header,omitempty"`   // Optional field to handle older optimized backups that don't have this field.
+	Type             Type           `json:"type,omitempty" yaml:"type,omitempty"`                           // Type of backup.
+	Config           *config.Config `json:"config,omitempty" yaml:"config,omitempty"`                       // Equivalent of backup.yaml but embedded in index for quick retrieval.
 }
 
 // GetInfo extracts backup information from a given ReadSeeker.
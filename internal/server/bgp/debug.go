@@ -2,9 +2,10 @@ package bgp
 
 // DebugInfo represents the internal debug state of the BGP server.
 type DebugInfo struct {
-	Server   DebugInfoServer   `json:"server" yaml:"server"`
-	Prefixes []DebugInfoPrefix `json:"prefixes" yaml:"prefixes"`
-	Peers    []DebugInfoPeer   `json:"peers" yaml:"peers"`
+	Server     DebugInfoServer      `json:"server" yaml:"server"`
+	Prefixes   []DebugInfoPrefix    `json:"prefixes" yaml:"prefixes"`
+	Peers      []DebugInfoPeer      `json:"peers" yaml:"peers"`
+	EVPNRoutes []DebugInfoEVPNRoute `json:"evpn_routes" yaml:"evpn_routes"`
 }
 
 // DebugInfoServer exposes the shared listener configuration.
@@ -24,11 +25,20 @@ type DebugInfoPrefix struct {
 
 // DebugInfoPeer exposes details on a single BGP peer.
 type DebugInfoPeer struct {
-	Address  string `json:"address" yaml:"address"`
-	ASN      uint32 `json:"asn" yaml:"asn"`
-	Password string `json:"password" yaml:"password"`
-	Count    int    `json:"count" yaml:"count"`
-	HoldTime uint64 `json:"holdtime" yaml:"holdtime"`
+	Address   string `json:"address" yaml:"address"`
+	ASN       uint32 `json:"asn" yaml:"asn"`
+	Password  string `json:"password" yaml:"password"`
+	Count     int    `json:"count" yaml:"count"`
+	EVPNCount int    `json:"evpn_count" yaml:"evpn_count"`
+	HoldTime  uint64 `json:"holdtime" yaml:"holdtime"`
+}
+
+// DebugInfoEVPNRoute exposes details on a single advertised EVPN route.
+type DebugInfoEVPNRoute struct {
+	Owner string `json:"owner" yaml:"owner"`
+	VNI   uint32 `json:"vni" yaml:"vni"`
+	VTEP  string `json:"vtep" yaml:"vtep"`
+	MAC   string `json:"mac" yaml:"mac"`
 }
 
 // Debug returns a dump of the current configuration.
@@ -57,6 +67,7 @@ func (s *Server) debug() DebugInfo {
 		entry.ASN = peer.asn
 		entry.Password = peer.password
 		entry.Count = peer.count
+		entry.EVPNCount = peer.evpnCount
 		entry.HoldTime = peer.holdtime
 
 		debug.Peers = append(debug.Peers, entry)
@@ -73,5 +84,17 @@ func (s *Server) debug() DebugInfo {
 		debug.Prefixes = append(debug.Prefixes, entry)
 	}
 
+	// Fill in the EVPN routes.
+	debug.EVPNRoutes = []DebugInfoEVPNRoute{}
+	for _, path := range s.evpnPaths {
+		entry := DebugInfoEVPNRoute{}
+		entry.Owner = path.owner
+		entry.VNI = path.route.VNI
+		entry.VTEP = path.route.VTEP.String()
+		entry.MAC = path.route.MAC.String()
+
+		debug.EVPNRoutes = append(debug.EVPNRoutes, entry)
+	}
+
 	return debug
 }
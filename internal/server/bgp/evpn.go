@@ -0,0 +1,394 @@
+package bgp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+
+	"github.com/google/uuid"
+	bgpAPI "github.com/osrg/gobgp/v3/api"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// EVPN related constants.
+const (
+	// evpnRouteTargetSubType is the sub-type of route target extended communities.
+	evpnRouteTargetSubType = 0x02
+
+	// evpnTunnelTypeVXLAN is the VXLAN tunnel type of the encapsulation extended community (RFC 8365).
+	evpnTunnelTypeVXLAN = 8
+
+	// evpnPMSITunnelTypeIngressReplication is the ingress replication type of the PMSI tunnel attribute.
+	evpnPMSITunnelTypeIngressReplication = 6
+)
+
+// evpnFamily is the L2VPN EVPN address family.
+var evpnFamily = &bgpAPI.Family{Afi: bgpAPI.Family_AFI_L2VPN, Safi: bgpAPI.Family_SAFI_EVPN}
+
+// EVPNRoute represents an EVPN route of a VXLAN network identifier (VNI).
+// Routes with a MAC address are MAC/IP advertisement routes (type 2) telling which VTEP the MAC address is
+// reachable through. Those without are inclusive multicast Ethernet tag routes (type 3) telling that a VTEP
+// takes part in the VNI, so that broadcast and unknown traffic gets replicated to it.
+type EVPNRoute struct {
+	VNI  uint32
+	VTEP net.IP
+	MAC  net.HardwareAddr
+}
+
+// String returns a representation of the route which is unique for the VNI, VTEP and MAC address.
+func (r EVPNRoute) String() string {
+	return fmt.Sprintf("%d/%s/%s", r.VNI, r.VTEP.String(), r.MAC.String())
+}
+
+type evpnPath struct {
+	owner string
+	route EVPNRoute
+}
+
+// AddEVPNRoute advertises a new EVPN route.
+func (s *Server) AddEVPNRoute(route EVPNRoute, owner string) error {
+	// Locking.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.addEVPNRoute(route, owner)
+}
+
+func (s *Server) addEVPNRoute(route EVPNRoute, owner string) error {
+	// Check for an existing entry.
+	for _, path := range s.evpnPaths {
+		if path.owner == owner && path.route.String() == route.String() {
+			return nil
+		}
+	}
+
+	// Add the route to the server.
+	var pathUUID string
+	if s.bgp != nil {
+		bgpPath, err := evpnRoutePath(route, s.asn, s.routerID)
+		if err != nil {
+			return err
+		}
+
+		resp, err := s.bgp.AddPath(context.Background(), &bgpAPI.AddPathRequest{Path: bgpPath})
+		if err != nil {
+			return err
+		}
+
+		pathUUID = string(resp.Uuid)
+	} else {
+		// Generate a dummy UUID.
+		pathUUID = uuid.New().String()
+	}
+
+	// Add path to the map.
+	s.evpnPaths[pathUUID] = evpnPath{
+		owner: owner,
+		route: route,
+	}
+
+	return nil
+}
+
+// RemoveEVPNRoute withdraws an EVPN route.
+func (s *Server) RemoveEVPNRoute(route EVPNRoute, owner string) error {
+	// Locking.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	found := false
+	for pathUUID, path := range s.evpnPaths {
+		if path.owner != owner || path.route.String() != route.String() {
+			continue
+		}
+
+		found = true
+
+		err := s.removeEVPNRouteByUUID(pathUUID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !found {
+		return ErrPrefixNotFound
+	}
+
+	return nil
+}
+
+// RemoveEVPNRoutesByOwner withdraws all EVPN routes for the provided owner.
+func (s *Server) RemoveEVPNRoutesByOwner(owner string) error {
+	// Locking.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Make a copy of the paths dict to safely iterate (path removal mutates it).
+	paths := map[string]evpnPath{}
+	maps.Copy(paths, s.evpnPaths)
+
+	for pathUUID, path := range paths {
+		if path.owner != owner {
+			continue
+		}
+
+		err := s.removeEVPNRouteByUUID(pathUUID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) removeEVPNRouteByUUID(pathUUID string) error {
+	// Remove it from the BGP server.
+	if s.bgp != nil {
+		err := s.bgp.DeletePath(context.Background(), &bgpAPI.DeletePathRequest{Uuid: []byte(pathUUID)})
+		if err != nil && err.Error() != "can't find a specified path" {
+			return err
+		}
+	}
+
+	// Remove the path from the map.
+	delete(s.evpnPaths, pathUUID)
+
+	return nil
+}
+
+// EVPNRoutes returns the EVPN routes of the VNI known to the server, whether advertised or received from peers.
+func (s *Server) EVPNRoutes(vni uint32) ([]EVPNRoute, error) {
+	// Locking.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.bgp == nil {
+		return nil, nil
+	}
+
+	routes := []EVPNRoute{}
+	seen := map[string]struct{}{}
+
+	err := s.bgp.ListPath(context.Background(), &bgpAPI.ListPathRequest{TableType: bgpAPI.TableType_GLOBAL, Family: evpnFamily}, func(d *bgpAPI.Destination) {
+		for _, bgpPath := range d.Paths {
+			if bgpPath.IsWithdraw {
+				continue
+			}
+
+			route, err := evpnPathRoute(bgpPath)
+			if err != nil || route == nil || route.VNI != vni {
+				continue
+			}
+
+			_, found := seen[route.String()]
+			if found {
+				continue
+			}
+
+			seen[route.String()] = struct{}{}
+			routes = append(routes, *route)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return routes, nil
+}
+
+// evpnRoutePath returns the BGP path advertising the EVPN route.
+// The route distinguisher is made of the router ID and the VNI, and the route target of the ASN and the VNI.
+// Both only have room for a 16-bit VNI, which is why EVPN tunnels are limited to those.
+func evpnRoutePath(route EVPNRoute, asn uint32, routerID net.IP) (*bgpAPI.Path, error) {
+	if route.VTEP == nil {
+		return nil, errors.New("Missing VTEP address for EVPN route")
+	}
+
+	rd, err := anypb.New(&bgpAPI.RouteDistinguisherIPAddress{
+		Admin:    routerID.String(),
+		Assigned: route.VNI,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pattrs := []*anypb.Any{}
+
+	var nlri *anypb.Any
+	if route.MAC != nil {
+		nlri, err = anypb.New(&bgpAPI.EVPNMACIPAdvertisementRoute{
+			Rd:         rd,
+			Esi:        &bgpAPI.EthernetSegmentIdentifier{},
+			MacAddress: route.MAC.String(),
+			Labels:     []uint32{route.VNI},
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		nlri, err = anypb.New(&bgpAPI.EVPNInclusiveMulticastEthernetTagRoute{
+			Rd:        rd,
+			IpAddress: route.VTEP.String(),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		vtep := route.VTEP.To4()
+		if vtep == nil {
+			vtep = route.VTEP.To16()
+		}
+
+		// Ask for broadcast and unknown traffic to be replicated to the VTEP.
+		aPMSI, err := anypb.New(&bgpAPI.PmsiTunnelAttribute{
+			Type:  evpnPMSITunnelTypeIngressReplication,
+			Label: route.VNI,
+			Id:    vtep,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		pattrs = append(pattrs, aPMSI)
+	}
+
+	aOrigin, err := anypb.New(&bgpAPI.OriginAttribute{Origin: 0})
+	if err != nil {
+		return nil, err
+	}
+
+	aNextHop, err := anypb.New(&bgpAPI.MpReachNLRIAttribute{
+		Family:   evpnFamily,
+		NextHops: []string{route.VTEP.String()},
+		Nlris:    []*anypb.Any{nlri},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var routeTarget *anypb.Any
+	if asn > 65535 {
+		routeTarget, err = anypb.New(&bgpAPI.FourOctetAsSpecificExtended{
+			IsTransitive: true,
+			SubType:      evpnRouteTargetSubType,
+			Asn:          asn,
+			LocalAdmin:   route.VNI,
+		})
+	} else {
+		routeTarget, err = anypb.New(&bgpAPI.TwoOctetAsSpecificExtended{
+			IsTransitive: true,
+			SubType:      evpnRouteTargetSubType,
+			Asn:          asn,
+			LocalAdmin:   route.VNI,
+		})
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	encap, err := anypb.New(&bgpAPI.EncapExtended{TunnelType: evpnTunnelTypeVXLAN})
+	if err != nil {
+		return nil, err
+	}
+
+	aCommunities, err := anypb.New(&bgpAPI.ExtendedCommunitiesAttribute{
+		Communities: []*anypb.Any{routeTarget, encap},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pattrs = append([]*anypb.Any{aOrigin, aNextHop, aCommunities}, pattrs...)
+
+	return &bgpAPI.Path{
+		Family: evpnFamily,
+		Nlri:   nlri,
+		Pattrs: pattrs,
+	}, nil
+}
+
+// evpnPathRoute returns the EVPN route of a BGP path, or nil for the route types which aren't used.
+// The VNI is taken from the route target when present, otherwise from the label.
+func evpnPathRoute(bgpPath *bgpAPI.Path) (*EVPNRoute, error) {
+	nlri, err := bgpPath.Nlri.UnmarshalNew()
+	if err != nil {
+		return nil, err
+	}
+
+	route := &EVPNRoute{}
+	var label uint32
+
+	switch r := nlri.(type) {
+	case *bgpAPI.EVPNMACIPAdvertisementRoute:
+		route.MAC, err = net.ParseMAC(r.MacAddress)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(r.Labels) > 0 {
+			label = r.Labels[0]
+		}
+
+	case *bgpAPI.EVPNInclusiveMulticastEthernetTagRoute:
+		route.VTEP = net.ParseIP(r.IpAddress)
+	default:
+		return nil, nil
+	}
+
+	for _, pattr := range bgpPath.Pattrs {
+		attr, err := pattr.UnmarshalNew()
+		if err != nil {
+			return nil, err
+		}
+
+		switch a := attr.(type) {
+		case *bgpAPI.MpReachNLRIAttribute:
+			if route.VTEP == nil && len(a.NextHops) > 0 {
+				route.VTEP = net.ParseIP(a.NextHops[0])
+			}
+
+		case *bgpAPI.NextHopAttribute:
+			if route.VTEP == nil {
+				route.VTEP = net.ParseIP(a.NextHop)
+			}
+
+		case *bgpAPI.PmsiTunnelAttribute:
+			if label == 0 {
+				label = a.Label
+			}
+
+		case *bgpAPI.ExtendedCommunitiesAttribute:
+			for _, community := range a.Communities {
+				value, err := community.UnmarshalNew()
+				if err != nil {
+					return nil, err
+				}
+
+				switch c := value.(type) {
+				case *bgpAPI.TwoOctetAsSpecificExtended:
+					if c.SubType == evpnRouteTargetSubType {
+						route.VNI = c.LocalAdmin
+					}
+
+				case *bgpAPI.FourOctetAsSpecificExtended:
+					if c.SubType == evpnRouteTargetSubType {
+						route.VNI = c.LocalAdmin
+					}
+				}
+			}
+		}
+	}
+
+	if route.VNI == 0 {
+		route.VNI = label
+	}
+
+	if route.VTEP == nil || route.VTEP.IsUnspecified() {
+		return nil, errors.New("Missing VTEP address in EVPN route")
+	}
+
+	return route, nil
+}
@@ -25,11 +25,12 @@ type Server struct {
 	bgp *bgpServer.BgpServer
 
 	// Internal state (to handle reconfiguration)
-	address  string
-	asn      uint32
-	routerID net.IP
-	paths    map[string]path
-	peers    map[string]peer
+	address   string
+	asn       uint32
+	routerID  net.IP
+	paths     map[string]path
+	peers     map[string]peer
+	evpnPaths map[string]evpnPath
 
 	mu sync.Mutex
 }
@@ -45,15 +46,19 @@ type peer struct {
 	asn      uint32
 	password string
 	holdtime uint64
-	count    int
+
+	// Number of users of the unicast and EVPN address families of the peer.
+	count     int
+	evpnCount int
 }
 
 // NewServer returns a new server instance.
 func NewServer() *Server {
 	// Setup new struct.
 	s := &Server{
-		paths: map[string]path{},
-		peers: map[string]peer{},
+		paths:     map[string]path{},
+		peers:     map[string]peer{},
+		evpnPaths: map[string]evpnPath{},
 	}
 
 	return s
@@ -99,8 +104,8 @@ func (s *Server) start(address string, asn uint32, routerID net.IP) error {
 		RouterId: routerID.String(),
 		Asn:      asn,
 
-		// Always setup for IPv4 and IPv6.
-		Families: []uint32{0, 1},
+		// Always setup for IPv4, IPv6 and EVPN.
+		Families: []uint32{0, 1, 9},
 
 		// Listen address.
 		ListenAddresses: []string{addrHost},
@@ -126,14 +131,14 @@ func (s *Server) start(address string, asn uint32, routerID net.IP) error {
 		}
 	}
 
-	// Copy the peer list.
-	oldPeers := map[string]peer{}
-	maps.Copy(oldPeers, s.peers)
-
 	// Add existing peers.
-	s.peers = map[string]peer{}
-	for _, peer := range oldPeers {
-		err := s.addPeer(peer.address, peer.asn, peer.password, peer.holdtime)
+	for _, peer := range s.peers {
+		conf, err := peer.config()
+		if err != nil {
+			return err
+		}
+
+		err = s.bgp.AddPeer(context.Background(), &bgpAPI.AddPeerRequest{Peer: conf})
 		if err != nil {
 			return err
 		}
@@ -144,6 +149,19 @@ func (s *Server) start(address string, asn uint32, routerID net.IP) error {
 	s.asn = asn
 	s.routerID = routerID
 
+	// Copy the EVPN path list.
+	oldEVPNPaths := map[string]evpnPath{}
+	maps.Copy(oldEVPNPaths, s.evpnPaths)
+
+	// Add existing EVPN paths (those need the ASN and router ID).
+	s.evpnPaths = map[string]evpnPath{}
+	for _, path := range oldEVPNPaths {
+		err := s.addEVPNRoute(path.route, path.owner)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -154,21 +172,14 @@ func (s *Server) stop() error {
 		return nil
 	}
 
-	// Save the peer list.
-	oldPeers := map[string]peer{}
-	maps.Copy(oldPeers, s.peers)
-
-	// Remove all the peers.
+	// Remove all the peers (keeping them in the list for the next start).
 	for _, peer := range s.peers {
-		err := s.removePeer(peer.address)
+		err := s.bgp.DeletePeer(context.Background(), &bgpAPI.DeletePeerRequest{Address: peer.address.String()})
 		if err != nil {
 			return err
 		}
 	}
 
-	// Restore peer list.
-	s.peers = oldPeers
-
 	// Stop the listener.
 	err := s.bgp.StopBgp(context.Background(), &bgpAPI.StopBgpRequest{})
 	if err != nil {
@@ -396,10 +407,20 @@ func (s *Server) AddPeer(address net.IP, asn uint32, password string, holdTime u
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	return s.addPeer(address, asn, password, holdTime)
+	return s.addPeer(address, asn, password, holdTime, false)
 }
 
-func (s *Server) addPeer(address net.IP, asn uint32, password string, holdTime uint64) error {
+// AddEVPNPeer adds a new BGP peer with which EVPN routes are exchanged.
+// The peer is shared with AddPeer, only adding the EVPN address family to an existing peer.
+func (s *Server) AddEVPNPeer(address net.IP, asn uint32, password string, holdTime uint64) error {
+	// Locking.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.addPeer(address, asn, password, holdTime, true)
+}
+
+func (s *Server) addPeer(address net.IP, asn uint32, password string, holdTime uint64, evpn bool) error {
 	// Look for an existing peer.
 	bgpPeer, bgpPeerExists := s.peers[address.String()]
 	if bgpPeerExists {
@@ -412,18 +433,85 @@ func (s *Server) addPeer(address net.IP, asn uint32, password string, holdTime u
 		}
 
 		// Reuse the existing entry.
-		bgpPeer.count++
-		s.peers[address.String()] = bgpPeer
+		newPeer := bgpPeer
+		if evpn {
+			newPeer.evpnCount++
+		} else {
+			newPeer.count++
+		}
+
+		// Update the address families of the peer if needed.
+		if (newPeer.count > 0) != (bgpPeer.count > 0) || (newPeer.evpnCount > 0) != (bgpPeer.evpnCount > 0) {
+			err := s.updatePeer(newPeer)
+			if err != nil {
+				return err
+			}
+		}
+
+		s.peers[address.String()] = newPeer
+		return nil
+	}
+
+	bgpPeer = peer{
+		address:  address,
+		asn:      asn,
+		password: password,
+		holdtime: holdTime,
+	}
+
+	if evpn {
+		bgpPeer.evpnCount = 1
+	} else {
+		bgpPeer.count = 1
+	}
+
+	// Add the peer.
+	if s.bgp != nil {
+		conf, err := bgpPeer.config()
+		if err != nil {
+			return err
+		}
+
+		err = s.bgp.AddPeer(context.Background(), &bgpAPI.AddPeerRequest{Peer: conf})
+		if err != nil {
+			return err
+		}
+	}
+
+	// Add the peer to the list.
+	s.peers[address.String()] = bgpPeer
+
+	return nil
+}
+
+// updatePeer applies the configuration of an existing peer to the BGP server.
+func (s *Server) updatePeer(bgpPeer peer) error {
+	if s.bgp == nil {
 		return nil
 	}
 
+	conf, err := bgpPeer.config()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.bgp.UpdatePeer(context.Background(), &bgpAPI.UpdatePeerRequest{Peer: conf})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// config returns the BGP server configuration of the peer.
+func (p peer) config() (*bgpAPI.Peer, error) {
 	// Setup the configuration.
 	n := &bgpAPI.Peer{
 		// Peer information.
 		Conf: &bgpAPI.PeerConf{
-			NeighborAddress: address.String(),
-			PeerAsn:         uint32(asn),
-			AuthPassword:    password,
+			NeighborAddress: p.address.String(),
+			PeerAsn:         p.asn,
+			AuthPassword:    p.password,
 		},
 
 		// Allow for 120s offline before route removal.
@@ -440,20 +528,29 @@ func (s *Server) addPeer(address net.IP, asn uint32, password string, holdTime u
 	}
 
 	// Add hold time if configured.
-	if holdTime > 0 {
+	if p.holdtime > 0 {
 		n.Timers = &bgpAPI.Timers{
 			Config: &bgpAPI.TimersConfig{
-				HoldTime: holdTime,
+				HoldTime: p.holdtime,
 			},
 		}
 	}
 
-	// Setup peer for dual-stack.
-	n.AfiSafis = make([]*bgpAPI.AfiSafi, 0)
-	for _, f := range []string{"ipv4-unicast", "ipv6-unicast"} {
+	// Setup peer for dual-stack and EVPN as needed.
+	families := []string{}
+	if p.count > 0 {
+		families = append(families, "ipv4-unicast", "ipv6-unicast")
+	}
+
+	if p.evpnCount > 0 {
+		families = append(families, "l2vpn-evpn")
+	}
+
+	n.AfiSafis = make([]*bgpAPI.AfiSafi, 0, len(families))
+	for _, f := range families {
 		rf, err := bgpPacket.GetRouteFamily(f)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		afi, safi := bgpPacket.RouteFamilyToAfiSafi(rf)
@@ -472,29 +569,7 @@ func (s *Server) addPeer(address net.IP, asn uint32, password string, holdTime u
 		})
 	}
 
-	// Add the peer.
-	if s.bgp != nil {
-		err := s.bgp.AddPeer(context.Background(), &bgpAPI.AddPeerRequest{Peer: n})
-		if err != nil {
-			return err
-		}
-	}
-
-	// Add the peer to the list.
-	if bgpPeerExists {
-		bgpPeer.count++
-		s.peers[address.String()] = bgpPeer
-	} else {
-		s.peers[address.String()] = peer{
-			address:  address,
-			asn:      asn,
-			password: password,
-			holdtime: holdTime,
-			count:    1,
-		}
-	}
-
-	return nil
+	return n, nil
 }
 
 // RemovePeer removes a prefix from the BGP server.
@@ -503,33 +578,55 @@ func (s *Server) RemovePeer(address net.IP) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	return s.removePeer(address)
+	return s.removePeer(address, false)
 }
 
-func (s *Server) removePeer(address net.IP) error {
+// RemoveEVPNPeer removes a BGP peer added by AddEVPNPeer.
+func (s *Server) RemoveEVPNPeer(address net.IP) error {
+	// Locking.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.removePeer(address, true)
+}
+
+func (s *Server) removePeer(address net.IP, evpn bool) error {
 	// Find the peer.
 	bgpPeer, bgpPeerExists := s.peers[address.String()]
-	if !bgpPeerExists {
+	if !bgpPeerExists || (evpn && bgpPeer.evpnCount == 0) || (!evpn && bgpPeer.count == 0) {
 		return ErrPeerNotFound
 	}
 
-	// Remove the peer from the BGP server.
-	if s.bgp != nil && bgpPeer.count == 1 {
-		err := s.bgp.DeletePeer(context.Background(), &bgpAPI.DeletePeerRequest{Address: address.String()})
+	// Decrease refcount.
+	newPeer := bgpPeer
+	if evpn {
+		newPeer.evpnCount--
+	} else {
+		newPeer.count--
+	}
+
+	// Delete the peer once unused.
+	if newPeer.count == 0 && newPeer.evpnCount == 0 {
+		if s.bgp != nil {
+			err := s.bgp.DeletePeer(context.Background(), &bgpAPI.DeletePeerRequest{Address: address.String()})
+			if err != nil {
+				return err
+			}
+		}
+
+		delete(s.peers, address.String())
+		return nil
+	}
+
+	// Update the address families of the peer if needed.
+	if (newPeer.count > 0) != (bgpPeer.count > 0) || (newPeer.evpnCount > 0) != (bgpPeer.evpnCount > 0) {
+		err := s.updatePeer(newPeer)
 		if err != nil {
 			return err
 		}
 	}
 
-	// Update peer list.
-	if bgpPeer.count == 1 {
-		// Delete the peer.
-		delete(s.peers, address.String())
-	} else {
-		// Decrease refcount.
-		bgpPeer.count--
-		s.peers[address.String()] = bgpPeer
-	}
+	s.peers[address.String()] = newPeer
 
 	return nil
 }
@@ -51,6 +51,16 @@ func (c *Config) MetricsAuthentication() bool {
 	return c.m.GetBool("core.metrics_authentication")
 }
 
+// MetricsPushConfig returns the settings needed to push metrics to a remote endpoint.
+func (c *Config) MetricsPushConfig() (address string, format string, username string, password string, caCert string, projects string) {
+	return c.m.GetString("metrics.push.url"), c.m.GetString("metrics.push.format"), c.m.GetString("metrics.push.username"), c.m.GetString("metrics.push.password"), c.m.GetString("metrics.push.ca_cert"), c.m.GetString("metrics.push.projects")
+}
+
+// MetricsPushInterval returns the interval at which metrics are pushed.
+func (c *Config) MetricsPushInterval() time.Duration {
+	return time.Duration(c.m.GetInt64("metrics.push.interval")) * time.Second
+}
+
 // BGPASN returns the BGP ASN setting.
 func (c *Config) BGPASN() int64 {
 	return c.m.GetInt64("core.bgp_asn")
@@ -163,6 +173,16 @@ func (c *Config) NetworkOVNSSL() (string, string, string) {
 	return c.m.GetString("network.ovn.ca_cert"), c.m.GetString("network.ovn.client_cert"), c.m.GetString("network.ovn.client_key")
 }
 
+// StorageWarningThreshold returns the storage pool usage percentage at which a warning is raised.
+func (c *Config) StorageWarningThreshold() int64 {
+	return c.m.GetInt64("storage.warning_threshold")
+}
+
+// StorageCriticalThreshold returns the storage pool usage percentage at which a critical warning is raised.
+func (c *Config) StorageCriticalThreshold() int64 {
+	return c.m.GetInt64("storage.critical_threshold")
+}
+
 // LinstorControllerConnection returns the Linstor controller connection string.
 func (c *Config) LinstorControllerConnection() string {
 	return c.m.GetString("storage.linstor.controller_connection")
@@ -185,6 +205,16 @@ func (c *Config) ImagesDefaultArchitecture() string {
 	return c.m.GetString("images.default_architecture")
 }
 
+// ImagesChunkStore returns whether image files should be stored in the deduplicated chunk store.
+func (c *Config) ImagesChunkStore() bool {
+	return c.m.GetBool("images.chunk_store")
+}
+
+// ImagesOCILazyPull returns whether OCI images should be pulled lazily when possible.
+func (c *Config) ImagesOCILazyPull() bool {
+	return c.m.GetBool("images.oci_lazy_pull")
+}
+
 // ImagesCompressionAlgorithm returns the compression algorithm to use for images.
 func (c *Config) ImagesCompressionAlgorithm() string {
 	return c.m.GetString("images.compression_algorithm")
@@ -324,6 +354,20 @@ func (c *Config) OpenFGA() (apiURL string, apiToken string, storeID string) {
 	return c.m.GetString("openfga.api.url"), c.m.GetString("openfga.api.token"), c.m.GetString("openfga.store.id")
 }
 
+// BackupsS3Target returns the URL, access key and secret key of the named S3 backup target.
+func (c *Config) BackupsS3Target(name string) (string, string, string) {
+	prefix := fmt.Sprintf("backups.s3.%s", name)
+
+	return c.m.GetString(prefix + ".url"), c.m.GetString(prefix + ".access_key"), c.m.GetString(prefix + ".secret_key")
+}
+
+// ReplicationRemote returns the URL and certificate fingerprint of the named replication remote.
+func (c *Config) ReplicationRemote(name string) (string, string) {
+	prefix := fmt.Sprintf("replication.remotes.%s", name)
+
+	return c.m.GetString(prefix + ".url"), c.m.GetString(prefix + ".fingerprint")
+}
+
 // Loggers returns a map where the key is the logger name and the value is its type.
 func (c *Config) Loggers() (map[string]string, error) {
 	result := make(map[string]string)
@@ -414,6 +458,33 @@ func (c *Config) LoggingConfigForWebhook(loggerName string) (string, string, str
 	return c.m.GetString(addressKey), c.m.GetString(usernameKey), c.m.GetString(passwordKey), c.m.GetString(caCertKey), int(c.m.GetInt64(retryKey))
 }
 
+// LoggingConfigForOTLP returns the logging configuration for the OTLP logger type.
+func (c *Config) LoggingConfigForOTLP(loggerName string) (string, string, string, string, string, int) {
+	prefix := fmt.Sprintf("logging.%s", loggerName)
+	addressKey := fmt.Sprintf("%s.%s", prefix, "target.address")
+	usernameKey := fmt.Sprintf("%s.%s", prefix, "target.username")
+	passwordKey := fmt.Sprintf("%s.%s", prefix, "target.password")
+	caCertKey := fmt.Sprintf("%s.%s", prefix, "target.ca_cert")
+	protocolKey := fmt.Sprintf("%s.%s", prefix, "target.protocol")
+	retryKey := fmt.Sprintf("%s.%s", prefix, "target.retry")
+
+	return c.m.GetString(addressKey), c.m.GetString(usernameKey), c.m.GetString(passwordKey), c.m.GetString(caCertKey), c.m.GetString(protocolKey), int(c.m.GetInt64(retryKey))
+}
+
+// LoggingConfigForNATS returns the logging configuration for the NATS logger type.
+func (c *Config) LoggingConfigForNATS(loggerName string) (string, string, string, string, string, bool, int) {
+	prefix := fmt.Sprintf("logging.%s", loggerName)
+	addressKey := fmt.Sprintf("%s.%s", prefix, "target.address")
+	usernameKey := fmt.Sprintf("%s.%s", prefix, "target.username")
+	passwordKey := fmt.Sprintf("%s.%s", prefix, "target.password")
+	caCertKey := fmt.Sprintf("%s.%s", prefix, "target.ca_cert")
+	subjectPrefixKey := fmt.Sprintf("%s.%s", prefix, "target.subject_prefix")
+	jetStreamKey := fmt.Sprintf("%s.%s", prefix, "target.jetstream")
+	spoolSizeKey := fmt.Sprintf("%s.%s", prefix, "target.spool_size")
+
+	return c.m.GetString(addressKey), c.m.GetString(usernameKey), c.m.GetString(passwordKey), c.m.GetString(caCertKey), c.m.GetString(subjectPrefixKey), c.m.GetBool(jetStreamKey), int(c.m.GetInt64(spoolSizeKey))
+}
+
 // Dump current configuration keys and their values. Keys with values matching
 // their defaults are omitted.
 func (c *Config) Dump() map[string]string {
@@ -443,6 +514,13 @@ func (c *Config) update(values map[string]string) (map[string]string, error) {
 		return nil, err
 	}
 
+	// A threshold of zero disables the matching alert.
+	warningThreshold := c.StorageWarningThreshold()
+	criticalThreshold := c.StorageCriticalThreshold()
+	if warningThreshold > 0 && criticalThreshold > 0 && criticalThreshold < warningThreshold {
+		return nil, fmt.Errorf("cannot set 'storage.critical_threshold' to '%d': Value must be greater than or equal to 'storage.warning_threshold' (%d)", criticalThreshold, warningThreshold)
+	}
+
 	err = c.tx.UpdateClusterConfig(changed)
 	if err != nil {
 		return nil, fmt.Errorf("cannot persist configuration changes: %w", err)
@@ -773,6 +851,16 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: Interval at which to look for updates to cached images
 	"images.auto_update_interval": {Type: config.Int64, Default: "6"},
 
+	// gendoc:generate(entity=server, group=images, key=images.chunk_store)
+	// When enabled, the image files are split into content-defined chunks, which are stored only once
+	// and shared between images. The files are rebuilt on demand, for example to create image volumes on storage pools.
+	// ---
+	//  type: bool
+	//  scope: global
+	//  defaultdesc: `false`
+	//  shortdesc: Whether to store image files in a deduplicated chunk store
+	"images.chunk_store": {Type: config.Bool, Default: "false"},
+
 	// gendoc:generate(entity=server, group=images, key=images.compression_algorithm)
 	// Possible values are `bzip2`, `gzip`, `lz4`, `lzma`, `xz`, `zstd` or `none`.
 	// ---
@@ -789,6 +877,16 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: Default architecture to use in a mixed-architecture cluster
 	"images.default_architecture": {Validator: validate.Optional(validate.IsArchitecture)},
 
+	// gendoc:generate(entity=server, group=images, key=images.oci_lazy_pull)
+	// When enabled, application containers created from eStargz or zstd:chunked OCI images start without waiting
+	// for the image to be downloaded. The image content is fetched on demand and completed in the background.
+	// ---
+	//  type: bool
+	//  scope: global
+	//  defaultdesc: `false`
+	//  shortdesc: Whether to lazily pull OCI images which support it
+	"images.oci_lazy_pull": {Type: config.Bool, Default: "false"},
+
 	// gendoc:generate(entity=server, group=images, key=images.remote_cache_expiry)
 	// Specify the number of days after which the unused cached image expires.
 	// ---
@@ -904,6 +1002,67 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: Events to send to the Loki server
 	"loki.types": {Validator: validate.Optional(validate.IsListOf(validate.IsOneOf("lifecycle", "logging", "network-acl"))), Default: "lifecycle,logging", Deprecated: "Use 'logging.*.types' instead"},
 
+	// gendoc:generate(entity=server, group=metrics, key=metrics.push.url)
+	// Specify the full URL of the endpoint, for example `https://prometheus.example.com/api/v1/write` or `https://otel.example.com:4318/v1/metrics`.
+	// Every cluster member pushes its own metrics.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: URL to push server and instance metrics to
+	"metrics.push.url": {Validator: validate.Optional(validate.IsRequestURL)},
+
+	// gendoc:generate(entity=server, group=metrics, key=metrics.push.format)
+	// Possible values are `remote_write` (Prometheus remote-write) and `otlp` (OpenTelemetry over HTTP with JSON encoding).
+	// ---
+	//  type: string
+	//  scope: global
+	//  defaultdesc: `remote_write`
+	//  shortdesc: Protocol used to push metrics
+	"metrics.push.format": {Default: "remote_write", Validator: validate.IsOneOf("remote_write", "otlp")},
+
+	// gendoc:generate(entity=server, group=metrics, key=metrics.push.interval)
+	// Specify the interval in seconds.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `60`
+	//  shortdesc: Interval at which metrics are pushed
+	"metrics.push.interval": {Type: config.Int64, Default: "60", Validator: validate.IsInRange(10, 86400)},
+
+	// gendoc:generate(entity=server, group=metrics, key=metrics.push.username)
+	//
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: User name used for authentication
+	"metrics.push.username": {},
+
+	// gendoc:generate(entity=server, group=metrics, key=metrics.push.password)
+	//
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Password used for authentication
+	"metrics.push.password": {},
+
+	// gendoc:generate(entity=server, group=metrics, key=metrics.push.ca_cert)
+	//
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: CA certificate for the server
+	"metrics.push.ca_cert": {},
+
+	// gendoc:generate(entity=server, group=metrics, key=metrics.push.projects)
+	// Specify a comma-separated list of projects whose instance metrics are pushed.
+	// Server metrics are always included.
+	// ---
+	//  type: string
+	//  scope: global
+	//  defaultdesc: all projects
+	//  shortdesc: Projects to push instance metrics for
+	"metrics.push.projects": {},
+
 	// gendoc:generate(entity=server, group=openfga, key=openfga.api.token)
 	//
 	// ---
@@ -1015,6 +1174,15 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: OVN SSL client key
 	"network.ovn.client_key": {Default: ""},
 
+	// gendoc:generate(entity=server, group=miscellaneous, key=storage.critical_threshold)
+	// Set to `0` to disable critical usage alerts.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `95`
+	//  shortdesc: Storage pool usage percentage at which a critical warning is raised
+	"storage.critical_threshold": {Type: config.Int64, Default: "95", Validator: validate.Optional(validate.IsInRange(0, 100))},
+
 	// gendoc:generate(entity=server, group=miscellaneous, key=storage.linstor.controller_connection)
 	//
 	// ---
@@ -1046,6 +1214,15 @@ var ConfigSchema = config.Schema{
 	//  scope: global
 	//  shortdesc: LINSTOR SSL client key
 	"storage.linstor.client_key": {Default: ""},
+
+	// gendoc:generate(entity=server, group=miscellaneous, key=storage.warning_threshold)
+	// Set to `0` to disable usage warnings.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `80`
+	//  shortdesc: Storage pool usage percentage at which a warning is raised
+	"storage.warning_threshold": {Type: config.Int64, Default: "80", Validator: validate.Optional(validate.IsInRange(0, 100))},
 }
 
 func expiryValidator(value string) error {
@@ -77,6 +77,21 @@ func TestConfigLoad_MaxVotersValidator(t *testing.T) {
 	require.EqualError(t, err, "cannot set 'cluster.max_voters' to '4': Value must be an odd number equal to or higher than 3")
 }
 
+// The critical storage usage threshold can't be below the warning one.
+func TestConfigLoad_StorageThresholdsValidator(t *testing.T) {
+	tx, cleanup := db.NewTestClusterTx(t)
+	defer cleanup()
+
+	config, err := clusterConfig.Load(context.Background(), tx)
+	require.NoError(t, err)
+
+	_, err = config.Patch(map[string]string{"storage.critical_threshold": "70"})
+	require.EqualError(t, err, "cannot set 'storage.critical_threshold' to '70': Value must be greater than or equal to 'storage.warning_threshold' (80)")
+
+	_, err = config.Patch(map[string]string{"storage.warning_threshold": "0", "storage.critical_threshold": "70"})
+	require.NoError(t, err)
+}
+
 // If some previously set values are missing from the ones passed to Replace(),
 // they are deleted from the configuration.
 func TestConfig_ReplaceDeleteValues(t *testing.T) {
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	internalInstance "github.com/lxc/incus/v6/internal/instance"
+)
+
+// IsBackupsS3Config reports whether the config key is for an S3 backup target.
+func IsBackupsS3Config(key string) bool {
+	return strings.HasPrefix(key, "backups.s3.")
+}
+
+// GetBackupsS3RuleForKey returns the rule for the specified S3 backup target config key.
+func GetBackupsS3RuleForKey(key string) (Key, error) {
+	fields := strings.Split(key, ".")
+	if len(fields) != 4 || fields[2] == "" {
+		return Key{}, fmt.Errorf("%s is not a valid S3 backup target config key", key)
+	}
+
+	switch fields[3] {
+	case "url":
+		// gendoc:generate(entity=server, group=miscellaneous, key=backups.s3.NAME.url)
+		// Specify the S3 endpoint, bucket and optional path, for example `https://s3.example.net/backups`.
+		// Instances and custom volumes refer to the target as `s3:NAME` in their `backups.target` option.
+		// ---
+		//  type: string
+		//  scope: global
+		//  shortdesc: URL of the S3 backup target
+		return Key{Validator: func(value string) error {
+			if value == "" {
+				return nil
+			}
+
+			_, err := internalInstance.BackupTargetFromURL(value, "", "")
+			return err
+		}}, nil
+	case "access_key":
+		// gendoc:generate(entity=server, group=miscellaneous, key=backups.s3.NAME.access_key)
+		//
+		// ---
+		//  type: string
+		//  scope: global
+		//  shortdesc: S3 access key of the backup target
+		return Key{}, nil
+	case "secret_key":
+		// gendoc:generate(entity=server, group=miscellaneous, key=backups.s3.NAME.secret_key)
+		//
+		// ---
+		//  type: string
+		//  scope: global
+		//  shortdesc: S3 secret key of the backup target
+		return Key{}, nil
+	}
+
+	return Key{}, fmt.Errorf("%s is not a valid S3 backup target config key", key)
+}
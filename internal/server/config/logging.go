@@ -87,8 +87,48 @@ func GetLoggingRuleForKey(key string) (Key, error) {
 		// ---
 		//  type: string
 		//  scope: global
-		//  shortdesc: The type of the logger. One of `loki`, `syslog` or `webhook`.
-		return Key{Validator: validate.Optional(validate.IsListOf(validate.IsOneOf("syslog", "loki", "webhook")))}, nil
+		//  shortdesc: The type of the logger. One of `loki`, `nats`, `otlp`, `syslog` or `webhook`.
+		return Key{Validator: validate.Optional(validate.IsListOf(validate.IsOneOf("syslog", "loki", "webhook", "otlp", "nats")))}, nil
+	case "target.protocol":
+		// gendoc:generate(entity=server, group=logging, key=logging.NAME.target.protocol)
+		// Possible values are `http` and `grpc`.
+		// Only used by the `otlp` logger type.
+		// ---
+		//  type: string
+		//  scope: global
+		//  defaultdesc: `http`
+		//  shortdesc: Protocol used to send OpenTelemetry log records
+		return Key{Validator: validate.Optional(validate.IsOneOf("http", "grpc")), Default: "http"}, nil
+	case "target.subject_prefix":
+		// gendoc:generate(entity=server, group=logging, key=logging.NAME.target.subject_prefix)
+		// Events are published to `<prefix>.<event type>.<project>`.
+		// Only used by the `nats` logger type.
+		// ---
+		//  type: string
+		//  scope: global
+		//  defaultdesc: `incus`
+		//  shortdesc: Prefix of the NATS subjects events are published to
+		return Key{Default: "incus"}, nil
+	case "target.jetstream":
+		// gendoc:generate(entity=server, group=logging, key=logging.NAME.target.jetstream)
+		// When enabled, each event is only considered delivered once a JetStream stream acknowledged it.
+		// Only used by the `nats` logger type.
+		// ---
+		//  type: bool
+		//  scope: global
+		//  defaultdesc: `false`
+		//  shortdesc: Whether to wait for JetStream acknowledgements
+		return Key{Validator: validate.Optional(validate.IsBool), Default: "false"}, nil
+	case "target.spool_size":
+		// gendoc:generate(entity=server, group=logging, key=logging.NAME.target.spool_size)
+		// Once reached, the oldest undelivered events are dropped.
+		// Only used by the `nats` logger type.
+		// ---
+		//  type: integer
+		//  scope: global
+		//  defaultdesc: `10000`
+		//  shortdesc: Maximum number of undelivered events kept on disk
+		return Key{Validator: validate.Optional(validate.IsUint32), Default: "10000"}, nil
 	case "target.retry":
 		// gendoc:generate(entity=server, group=logging, key=logging.NAME.target.retry)
 		//
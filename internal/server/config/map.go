@@ -109,9 +109,9 @@ func (m *Map) GetRaw(name string) string {
 		return value
 	}
 
-	if IsLoggingConfig(name) {
+	if isDynamicConfig(name) {
 		if !ok {
-			key, err := GetLoggingRuleForKey(name)
+			key, err := getDynamicRuleForKey(name)
 			if err != nil {
 				panic(err)
 			}
@@ -133,7 +133,7 @@ func (m *Map) GetRaw(name string) string {
 
 // GetString returns the value of the given key, which must be of type String.
 func (m *Map) GetString(name string) string {
-	if !internalInstance.IsUserConfig(name) && !IsLoggingConfig(name) {
+	if !internalInstance.IsUserConfig(name) && !isDynamicConfig(name) {
 		m.schema.assertKeyType(name, String)
 	}
 
@@ -142,13 +142,16 @@ func (m *Map) GetString(name string) string {
 
 // GetBool returns the value of the given key, which must be of type Bool.
 func (m *Map) GetBool(name string) bool {
-	m.schema.assertKeyType(name, Bool)
+	if !isDynamicConfig(name) {
+		m.schema.assertKeyType(name, Bool)
+	}
+
 	return util.IsTrue(m.GetRaw(name))
 }
 
 // GetInt64 returns the value of the given key, which must be of type Int64.
 func (m *Map) GetInt64(name string) int64 {
-	if !IsLoggingConfig(name) {
+	if !isDynamicConfig(name) {
 		m.schema.assertKeyType(name, Int64)
 	}
 
@@ -226,8 +229,8 @@ func (m *Map) set(name string, value string, initial bool) (bool, error) {
 		return true, nil
 	}
 
-	if IsLoggingConfig(name) {
-		rule, err := GetLoggingRuleForKey(name)
+	if isDynamicConfig(name) {
+		rule, err := getDynamicRuleForKey(name)
 		if err != nil {
 			return false, err
 		}
@@ -290,3 +293,22 @@ func normalizeBool(value string) string {
 
 	return "false"
 }
+
+// isDynamicConfig reports whether the config key belongs to a user-named group of keys (loggers, backup targets,
+// replication remotes).
+func isDynamicConfig(name string) bool {
+	return IsLoggingConfig(name) || IsBackupsS3Config(name) || IsReplicationRemotesConfig(name)
+}
+
+// getDynamicRuleForKey returns the rule for a config key belonging to a user-named group of keys.
+func getDynamicRuleForKey(name string) (Key, error) {
+	if IsBackupsS3Config(name) {
+		return GetBackupsS3RuleForKey(name)
+	}
+
+	if IsReplicationRemotesConfig(name) {
+		return GetReplicationRemotesRuleForKey(name)
+	}
+
+	return GetLoggingRuleForKey(name)
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/lxc/incus/v6/shared/validate"
+)
+
+// IsReplicationRemotesConfig reports whether the config key is for a storage volume replication remote.
+func IsReplicationRemotesConfig(key string) bool {
+	return strings.HasPrefix(key, "replication.remotes.")
+}
+
+// GetReplicationRemotesRuleForKey returns the rule for the specified replication remote config key.
+func GetReplicationRemotesRuleForKey(key string) (Key, error) {
+	fields := strings.Split(key, ".")
+	if len(fields) != 4 || fields[2] == "" {
+		return Key{}, fmt.Errorf("%s is not a valid replication remote config key", key)
+	}
+
+	switch fields[3] {
+	case "url":
+		// gendoc:generate(entity=server, group=miscellaneous, key=replication.remotes.NAME.url)
+		// Specify the address of the remote server, for example `https://backup.example.net:8443`.
+		// Custom volumes refer to the remote as `NAME:<pool>` in their `replication.target` option.
+		// The remote server must trust the certificate of this server.
+		// ---
+		//  type: string
+		//  scope: global
+		//  shortdesc: URL of the replication remote
+		return Key{Validator: validReplicationRemoteURL}, nil
+	case "fingerprint":
+		// gendoc:generate(entity=server, group=miscellaneous, key=replication.remotes.NAME.fingerprint)
+		// Required unless the certificate of the remote server is signed by a trusted CA.
+		// ---
+		//  type: string
+		//  scope: global
+		//  shortdesc: SHA-256 fingerprint of the replication remote's certificate
+		return Key{Validator: validate.Optional(validate.IsCertificateFingerprint)}, nil
+	}
+
+	return Key{}, fmt.Errorf("%s is not a valid replication remote config key", key)
+}
+
+// validReplicationRemoteURL validates the address of a replication remote.
+func validReplicationRemoteURL(value string) error {
+	if value == "" {
+		return nil
+	}
+
+	u, err := url.Parse(value)
+	if err != nil {
+		return err
+	}
+
+	if u.Scheme != "https" || u.Host == "" {
+		return errors.New("Replication remote URL must be of the form https://<host>[:<port>]")
+	}
+
+	if u.User != nil || strings.Trim(u.Path, "/") != "" || u.RawQuery != "" {
+		return errors.New("Replication remote URL must only contain the address of the server")
+	}
+
+	return nil
+}
@@ -25,6 +25,7 @@ type InstanceBackup struct {
 	InstanceOnly         bool
 	OptimizedStorage     bool
 	CompressionAlgorithm string
+	Parent               string
 }
 
 // StoragePoolVolumeBackup is a value object holding all db-related details about a storage volume backup.
@@ -44,18 +44,23 @@ const (
 
 	// NetworkPeerTypeRemote represents a remote peer connection.
 	NetworkPeerTypeRemote
+
+	// NetworkPeerTypeWireguard represents a WireGuard peer connection.
+	NetworkPeerTypeWireguard
 )
 
 // NetworkPeerTypeNames maps peer types (integers) to their API representation (string).
 var NetworkPeerTypeNames = map[int]string{
-	NetworkPeerTypeLocal:  "local",
-	NetworkPeerTypeRemote: "remote",
+	NetworkPeerTypeLocal:     "local",
+	NetworkPeerTypeRemote:    "remote",
+	NetworkPeerTypeWireguard: "wireguard",
 }
 
 // NetworkPeerTypes maps peer strings to their internal representation (integers).
 var NetworkPeerTypes = map[string]int{
-	NetworkPeerTypeNames[NetworkPeerTypeLocal]:  NetworkPeerTypeLocal,
-	NetworkPeerTypeNames[NetworkPeerTypeRemote]: NetworkPeerTypeRemote,
+	NetworkPeerTypeNames[NetworkPeerTypeLocal]:     NetworkPeerTypeLocal,
+	NetworkPeerTypeNames[NetworkPeerTypeRemote]:    NetworkPeerTypeRemote,
+	NetworkPeerTypeNames[NetworkPeerTypeWireguard]: NetworkPeerTypeWireguard,
 }
 
 // NetworkPeer is a value object holding db-related details about a network peer.
@@ -142,6 +147,9 @@ func (n *NetworkPeer) ToAPI(ctx context.Context, tx *sql.Tx) (*api.NetworkPeer,
 
 		resp.TargetIntegration = integrations[0].Name
 		resp.Status = api.NetworkStatusCreated
+	} else if n.Type == NetworkPeerTypeWireguard {
+		// WireGuard peers are defined by their configuration alone.
+		resp.Status = api.NetworkStatusCreated
 	} else {
 		// Peer has mutual peering from target network.
 		if n.TargetNetworkName.String != "" && n.TargetNetworkProject.String != "" {
@@ -79,6 +79,10 @@ const (
 	BucketBackupRemove
 	BucketBackupRename
 	BucketBackupRestore
+	ClusterRollingRestart
+	BackupsSchedule
+	VolumeReplicate
+	VolumesReplicationSchedule
 )
 
 // Description return a human-readable description of the operation type.
@@ -210,6 +214,14 @@ func (t Type) Description() string {
 		return "Renaming bucket backup"
 	case BucketBackupRestore:
 		return "Restoring bucket backup"
+	case ClusterRollingRestart:
+		return "Restarting cluster members"
+	case BackupsSchedule:
+		return "Creating scheduled backups"
+	case VolumeReplicate:
+		return "Replicating storage volume"
+	case VolumesReplicationSchedule:
+		return "Replicating scheduled storage volumes"
 	default:
 		return "Executing operation"
 	}
@@ -291,6 +303,8 @@ func (t Type) Permission() (auth.ObjectType, auth.Entitlement) {
 		return auth.ObjectTypeStorageVolume, auth.EntitlementCanManageBackups
 	case CustomVolumeBackupRestore:
 		return auth.ObjectTypeStorageVolume, auth.EntitlementCanEdit
+	case VolumeReplicate:
+		return auth.ObjectTypeStorageVolume, auth.EntitlementCanEdit
 
 	case BucketBackupCreate:
 		return auth.ObjectTypeStorageVolume, auth.EntitlementCanManageBackups
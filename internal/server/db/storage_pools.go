@@ -881,6 +881,7 @@ var NodeSpecificStorageConfig = []string{
 	"source",
 	"source.wipe",
 	"volatile.initial_source",
+	"volatile.dir.reflink",
 	"zfs.pool_name",
 	"lvm.thinpool_name",
 	"lvm.vg_name",
@@ -56,6 +56,14 @@ const (
 	StoragePoolUnvailable
 	// UnableToUpdateClusterCertificate represents the unable to update cluster certificate warning.
 	UnableToUpdateClusterCertificate
+	// ScheduledBackupFailure represents the failure of a scheduled instance or volume backup.
+	ScheduledBackupFailure
+	// VolumeReplicationFailure represents the failure of a scheduled storage volume replication.
+	VolumeReplicationFailure
+	// StoragePoolUsageWarning represents a storage pool whose usage exceeds the warning threshold.
+	StoragePoolUsageWarning
+	// StoragePoolUsageCritical represents a storage pool whose usage exceeds the critical threshold.
+	StoragePoolUsageCritical
 )
 
 // TypeNames associates a warning code to its name.
@@ -84,6 +92,10 @@ var TypeNames = map[Type]string{
 	InstanceTypeNotOperational:        "Instance type not operational",
 	StoragePoolUnvailable:             "Storage pool unavailable",
 	UnableToUpdateClusterCertificate:  "Unable to update cluster certificate",
+	ScheduledBackupFailure:            "Failed to create scheduled backup",
+	VolumeReplicationFailure:          "Failed to replicate storage volume",
+	StoragePoolUsageWarning:           "Storage pool usage above warning threshold",
+	StoragePoolUsageCritical:          "Storage pool usage above critical threshold",
 }
 
 // Severity returns the severity of the warning type.
@@ -137,6 +149,14 @@ func (t Type) Severity() Severity {
 		return SeverityHigh
 	case UnableToUpdateClusterCertificate:
 		return SeverityLow
+	case ScheduledBackupFailure:
+		return SeverityModerate
+	case VolumeReplicationFailure:
+		return SeverityModerate
+	case StoragePoolUsageWarning:
+		return SeverityModerate
+	case StoragePoolUsageCritical:
+		return SeverityHigh
 	}
 
 	return SeverityLow
@@ -52,6 +52,7 @@ type DiskLimits struct {
 	ReadIOps   int64
 	WriteBytes int64
 	WriteIOps  int64
+	Group      string // QEMU throttle group shared by disks backed by the same volume.
 }
 
 // RunConfig represents run-time config used for device setup/cleanup.
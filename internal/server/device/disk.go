@@ -78,6 +78,9 @@ type diskBlockLimit struct {
 	readIops  int64
 	writeBps  int64
 	writeIops int64
+
+	// Set when the limits come from the custom volume rather than from the disk device.
+	volumeDevName string
 }
 
 // diskSourceNotFoundError error used to indicate source not found.
@@ -1244,20 +1247,9 @@ func (d *disk) startVM() (*deviceConfig.RunConfig, error) {
 	attached := util.IsTrueOrEmpty(d.config["attached"])
 
 	// Add I/O limits if set.
-	var diskLimits *deviceConfig.DiskLimits
-	if d.config["limits.read"] != "" || d.config["limits.write"] != "" || d.config["limits.max"] != "" {
-		// Parse the limits into usable values.
-		readBps, readIops, writeBps, writeIops, err := d.parseLimit(d.config)
-		if err != nil {
-			return nil, err
-		}
-
-		diskLimits = &deviceConfig.DiskLimits{
-			ReadBytes:  readBps,
-			ReadIOps:   readIops,
-			WriteBytes: writeBps,
-			WriteIOps:  writeIops,
-		}
+	diskLimits, err := d.vmDiskLimits()
+	if err != nil {
+		return nil, err
 	}
 
 	if internalInstance.IsRootDiskDevice(d.config) {
@@ -1751,23 +1743,19 @@ func (d *disk) Update(oldDevices deviceConfig.Devices, isRunning bool) error {
 		}
 
 		if d.inst.Type() == instancetype.VM {
-			var diskLimits *deviceConfig.DiskLimits
 			runConf.Mounts = []deviceConfig.MountEntryItem{}
-			if d.config["limits.read"] != "" || d.config["limits.write"] != "" || d.config["limits.max"] != "" {
-				// Parse the limits into usable values.
-				readBps, readIops, writeBps, writeIops, err := d.parseLimit(d.config)
-				if err != nil {
-					return err
-				}
+			diskLimits, err := d.vmDiskLimits()
+			if err != nil {
+				return err
+			}
 
-				// Apply the limits to a minimal mount entry.
-				diskLimits = &deviceConfig.DiskLimits{
-					ReadBytes:  readBps,
-					ReadIOps:   readIops,
-					WriteBytes: writeBps,
-					WriteIOps:  writeIops,
-				}
+			// Clear any limits previously inherited from the custom volume.
+			if diskLimits == nil && d.config["pool"] != "" && d.config["source"] != "" && d.config["path"] != "/" {
+				diskLimits = &deviceConfig.DiskLimits{}
+			}
 
+			if diskLimits != nil {
+				// Apply the limits to a minimal mount entry.
 				runConf.Mounts = append(runConf.Mounts, deviceConfig.MountEntryItem{
 					DevName: d.name,
 					Limits:  diskLimits,
@@ -1875,6 +1863,17 @@ func (d *disk) generateLimits(runConf *deviceConfig.RunConfig) error {
 
 		if dev["limits.read"] != "" || dev["limits.write"] != "" || dev["limits.max"] != "" {
 			hasDiskLimits = true
+			break
+		}
+
+		bps, iops, _, err := d.volumeLimits(dev)
+		if err != nil {
+			return err
+		}
+
+		if bps > 0 || iops > 0 {
+			hasDiskLimits = true
+			break
 		}
 	}
 
@@ -2587,6 +2586,10 @@ func (d *disk) getDiskLimits() (map[string]diskBlockLimit, error) {
 		}
 
 		device := diskBlockLimit{readBps: readBps, readIops: readIops, writeBps: writeBps, writeIops: writeIops}
+		if dev["limits.read"] == "" && dev["limits.write"] == "" && dev["limits.max"] == "" && (readBps > 0 || readIops > 0) {
+			device.volumeDevName = devName
+		}
+
 		for _, block := range blocks {
 			blockStr := ""
 
@@ -2619,6 +2622,21 @@ func (d *disk) getDiskLimits() (map[string]diskBlockLimit, error) {
 
 	// Average duplicate limits
 	for block, limits := range blockLimits {
+		// Custom volume limits can only be enforced on a block device backing that volume alone.
+		if len(limits) > 1 {
+			deviceLimits := make([]diskBlockLimit, 0, len(limits))
+			for _, limit := range limits {
+				if limit.volumeDevName != "" {
+					d.logger.Warn("Ignoring custom volume I/O limits as its block device is shared with other disks", logger.Ctx{"device": limit.volumeDevName, "block": block})
+					continue
+				}
+
+				deviceLimits = append(deviceLimits, limit)
+			}
+
+			limits = deviceLimits
+		}
+
 		var readBpsCount, readBpsTotal, readIopsCount, readIopsTotal, writeBpsCount, writeBpsTotal, writeIopsCount, writeIopsTotal int64
 
 		for _, limit := range limits {
@@ -2667,8 +2685,119 @@ func (d *disk) getDiskLimits() (map[string]diskBlockLimit, error) {
 	return result, nil
 }
 
+// vmDiskLimits returns the I/O limits to apply to the VM disk, or nil if there are none.
+// Limits only coming from a custom volume are placed in a QEMU throttle group specific to that volume.
+func (d *disk) vmDiskLimits() (*deviceConfig.DiskLimits, error) {
+	readBps, readIops, writeBps, writeIops, err := d.parseDeviceLimit(d.config)
+	if err != nil {
+		return nil, err
+	}
+
+	bps, iops, group, err := d.volumeLimits(d.config)
+	if err != nil {
+		return nil, err
+	}
+
+	// The disk's own limits can't exceed the ones of the volume.
+	if readBps != 0 || readIops != 0 || writeBps != 0 || writeIops != 0 {
+		group = ""
+	}
+
+	limits := &deviceConfig.DiskLimits{
+		ReadBytes:  minLimit(readBps, bps),
+		ReadIOps:   minLimit(readIops, iops),
+		WriteBytes: minLimit(writeBps, bps),
+		WriteIOps:  minLimit(writeIops, iops),
+		Group:      group,
+	}
+
+	if limits.ReadBytes == 0 && limits.ReadIOps == 0 && limits.WriteBytes == 0 && limits.WriteIOps == 0 {
+		return nil, nil
+	}
+
+	return limits, nil
+}
+
+// minLimit returns the lowest of two I/O limits, zero meaning no limit.
+func minLimit(a int64, b int64) int64 {
+	if a == 0 {
+		return b
+	}
+
+	if b == 0 {
+		return a
+	}
+
+	return min(a, b)
+}
+
+// volumeLimits returns the I/O bytes/iops limits configured on the custom volume used by the disk
+// device along with a name identifying the volume. Zero limits are returned for other disk devices.
+func (d *disk) volumeLimits(dev deviceConfig.Device) (int64, int64, string, error) {
+	// Only custom volumes carry their own limits.
+	if dev["pool"] == "" || dev["source"] == "" || dev["path"] == "/" {
+		return 0, 0, "", nil
+	}
+
+	pool, err := storagePools.LoadByName(d.state, dev["pool"])
+	if err != nil {
+		return -1, -1, "", err
+	}
+
+	storageProjectName, err := project.StorageVolumeProject(d.state.DB.Cluster, d.inst.Project().Name, db.StoragePoolVolumeTypeCustom)
+	if err != nil {
+		return -1, -1, "", err
+	}
+
+	// Parse the volume name and path.
+	volName, _ := internalInstance.SplitVolumeSource(dev["source"])
+
+	var dbVolume *db.StorageVolume
+	err = d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		dbVolume, err = tx.GetStoragePoolVolume(ctx, pool.ID(), storageProjectName, db.StoragePoolVolumeTypeCustom, volName, true)
+		return err
+	})
+	if err != nil {
+		return -1, -1, "", err
+	}
+
+	bps := int64(0)
+	if dbVolume.Config["limits.bandwidth"] != "" {
+		bps, err = units.ParseByteSizeString(dbVolume.Config["limits.bandwidth"])
+		if err != nil {
+			return -1, -1, "", err
+		}
+	}
+
+	iops := int64(0)
+	if dbVolume.Config["limits.iops"] != "" {
+		iops, err = strconv.ParseInt(dbVolume.Config["limits.iops"], 10, 64)
+		if err != nil {
+			return -1, -1, "", err
+		}
+	}
+
+	return bps, iops, fmt.Sprintf("incus_volume_%d", dbVolume.ID), nil
+}
+
 // parseLimit parses the disk configuration for its I/O limits and returns the I/O bytes/iops limits.
+// If the custom volume used by the device has limits too, the lowest of both is returned.
 func (d *disk) parseLimit(dev deviceConfig.Device) (int64, int64, int64, int64, error) {
+	readBps, readIops, writeBps, writeIops, err := d.parseDeviceLimit(dev)
+	if err != nil {
+		return -1, -1, -1, -1, err
+	}
+
+	bps, iops, _, err := d.volumeLimits(dev)
+	if err != nil {
+		return -1, -1, -1, -1, err
+	}
+
+	return minLimit(readBps, bps), minLimit(readIops, iops), minLimit(writeBps, bps), minLimit(writeIops, iops), nil
+}
+
+// parseDeviceLimit parses the I/O limits set on the disk device itself and returns the I/O bytes/iops limits.
+func (d *disk) parseDeviceLimit(dev deviceConfig.Device) (int64, int64, int64, int64, error) {
 	readSpeed := dev["limits.read"]
 	writeSpeed := dev["limits.write"]
 
@@ -324,8 +324,8 @@ func (d *nicBridged) validateConfig(instConf instance.ConfigReader) error {
 			return errors.New("Specified network is not fully created")
 		}
 
-		if n.Type() != "bridge" {
-			return errors.New("Specified network must be of type bridge")
+		if !slices.Contains([]string{"bridge", "wireguard"}, n.Type()) {
+			return errors.New("Specified network must be of type bridge or wireguard")
 		}
 
 		netConfig := n.Config()
@@ -863,6 +863,7 @@ func (d *nicBridged) Start() (*deviceConfig.RunConfig, error) {
 
 		if brNetfilterEnabled {
 			var listenAddresses map[int64]string
+			var loadBalancers int
 
 			err = d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
 				networkID := d.network.ID()
@@ -880,17 +881,27 @@ func (d *nicBridged) Start() (*deviceConfig.RunConfig, error) {
 					}
 				}
 
+				// Load balancers apply to all members.
+				dbLoadBalancers, err := cluster.GetNetworkLoadBalancers(ctx, tx.Tx(), cluster.NetworkLoadBalancerFilter{
+					NetworkID: &networkID,
+				})
+				if err != nil {
+					return err
+				}
+
+				loadBalancers = len(dbLoadBalancers)
+
 				return nil
 			})
 			if err != nil {
-				return nil, fmt.Errorf("Failed loading network forwards: %w", err)
+				return nil, fmt.Errorf("Failed loading network forwards and load balancers: %w", err)
 			}
 
-			// If br_netfilter is enabled and bridge has forwards, we enable hairpin mode on NIC's
-			// bridge port in case any of the forwards target this NIC and the instance attempts to
-			// connect to the forward's listener. Without hairpin mode on the target of the forward
+			// If br_netfilter is enabled and bridge has forwards or load balancers, we enable hairpin mode
+			// on NIC's bridge port in case any of them target this NIC and the instance attempts to
+			// connect to their listener. Without hairpin mode on the target of the forward
 			// will not be able to connect to the listener.
-			if len(listenAddresses) > 0 {
+			if len(listenAddresses) > 0 || loadBalancers > 0 {
 				link := &ip.Link{Name: saveData["host_name"]}
 				err = link.BridgeLinkSetHairpin(true)
 				if err != nil {
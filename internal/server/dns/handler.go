@@ -1,6 +1,7 @@
 package dns
 
 import (
+	"errors"
 	"fmt"
 	"net"
 	"strings"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/util"
 )
 
 type dnsHandler struct {
@@ -45,10 +47,12 @@ func (d dnsHandler) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
 		return
 	}
 
-	// Check that it's a supported request type.
-	if r.Question[0].Qtype != dns.TypeAXFR && r.Question[0].Qtype != dns.TypeIXFR && r.Question[0].Qtype != dns.TypeSOA {
+	// Extract the request information.
+	name := strings.TrimSuffix(r.Question[0].Name, ".")
+	ip, _, err := net.SplitHostPort(w.RemoteAddr().String())
+	if err != nil {
 		m := &dns.Msg{}
-		m.SetRcode(r, dns.RcodeNotImplemented)
+		m.SetRcode(r, dns.RcodeServerFailure)
 		err := w.WriteMsg(m)
 		if err != nil {
 			logger.Error("Unable to write message", logger.Ctx{"err": err})
@@ -57,12 +61,21 @@ func (d dnsHandler) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
 		return
 	}
 
-	// Extract the request information.
-	name := strings.TrimSuffix(r.Question[0].Name, ".")
-	ip, _, err := net.SplitHostPort(w.RemoteAddr().String())
-	if err != nil {
+	// Handle dynamic updates.
+	if r.Opcode == dns.OpcodeUpdate {
+		d.serveUpdate(w, r, ip)
+		return
+	}
+
+	// Answer regular queries for the zones allowing them.
+	if r.Question[0].Qtype != dns.TypeAXFR && r.Question[0].Qtype != dns.TypeIXFR && d.serveQuery(w, r, ip) {
+		return
+	}
+
+	// Check that it's a supported request type.
+	if r.Question[0].Qtype != dns.TypeAXFR && r.Question[0].Qtype != dns.TypeIXFR && r.Question[0].Qtype != dns.TypeSOA {
 		m := &dns.Msg{}
-		m.SetRcode(r, dns.RcodeServerFailure)
+		m.SetRcode(r, dns.RcodeNotImplemented)
 		err := w.WriteMsg(m)
 		if err != nil {
 			logger.Error("Unable to write message", logger.Ctx{"err": err})
@@ -138,6 +151,173 @@ func (d dnsHandler) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
 	}
 }
 
+// serveQuery answers a regular query for a name within a zone which allows them.
+// It returns false when the query should instead go through the zone transfer handling.
+func (d dnsHandler) serveQuery(w dns.ResponseWriter, r *dns.Msg, ip string) bool {
+	q := r.Question[0]
+
+	zone, err := d.server.lookupZone(q.Name)
+	if err != nil {
+		logger.Error("Failed to load DNS zone", logger.Ctx{"name": q.Name, "err": err})
+
+		m := &dns.Msg{}
+		m.SetRcode(r, dns.RcodeServerFailure)
+		err := w.WriteMsg(m)
+		if err != nil {
+			logger.Error("Unable to write message", logger.Ctx{"err": err})
+		}
+
+		return true
+	}
+
+	if zone == nil {
+		// SOA requests keep being handled as before.
+		if q.Qtype == dns.TypeSOA {
+			return false
+		}
+
+		// Not authoritative for the name and recursion isn't supported, refuse the query.
+		m := &dns.Msg{}
+		m.SetRcode(r, dns.RcodeRefused)
+		err := w.WriteMsg(m)
+		if err != nil {
+			logger.Error("Unable to write message", logger.Ctx{"err": err})
+		}
+
+		return true
+	}
+
+	if !zone.queryEnabled() {
+		return false
+	}
+
+	// Check access.
+	tsig := r.IsTsig()
+	if zone.queryRestricted() && !isAllowed(zone.info, ip, tsig, w.TsigStatus() == nil) {
+		m := &dns.Msg{}
+		m.SetRcode(r, dns.RcodeRefused)
+		err := w.WriteMsg(m)
+		if err != nil {
+			logger.Error("Unable to write message", logger.Ctx{"err": err})
+		}
+
+		return true
+	}
+
+	// Prepare the response.
+	m := &dns.Msg{}
+	m.SetReply(r)
+	m.Authoritative = true
+	answerQuery(m, q, zone.records)
+
+	// Fit the response in the datagram size supported by the client.
+	if w.LocalAddr().Network() == "udp" {
+		size := dns.MinMsgSize
+		opt := r.IsEdns0()
+		if opt != nil {
+			size = int(opt.UDPSize())
+		}
+
+		m.Truncate(size)
+	}
+
+	if tsig != nil && w.TsigStatus() == nil {
+		m.SetTsig(tsig.Hdr.Name, tsig.Algorithm, 300, time.Now().Unix())
+	}
+
+	err = w.WriteMsg(m)
+	if err != nil {
+		logger.Error("Unable to write message", logger.Ctx{"err": err})
+	}
+
+	return true
+}
+
+// serveUpdate applies a dynamic update (RFC 2136) to the records of a zone which allows them.
+func (d dnsHandler) serveUpdate(w dns.ResponseWriter, r *dns.Msg, ip string) {
+	q := r.Question[0]
+	tsig := r.IsTsig()
+
+	reply := func(rcode int) {
+		m := &dns.Msg{}
+		m.SetRcode(r, rcode)
+
+		if tsig != nil && w.TsigStatus() == nil {
+			m.SetTsig(tsig.Hdr.Name, tsig.Algorithm, 300, time.Now().Unix())
+		}
+
+		err := w.WriteMsg(m)
+		if err != nil {
+			logger.Error("Unable to write message", logger.Ctx{"err": err})
+		}
+	}
+
+	// The zone section holds a single SOA entry for the zone to update.
+	if q.Qtype != dns.TypeSOA || d.server.zoneUpdater == nil {
+		reply(dns.RcodeFormatError)
+		return
+	}
+
+	// Load the zone.
+	name := strings.TrimSuffix(strings.ToLower(q.Name), ".")
+	zone, err := d.server.zoneRetriever(name, false)
+	if err != nil {
+		reply(dns.RcodeNotAuth)
+		return
+	}
+
+	// Check access.
+	peer := updatePeer(zone.Info, ip, tsig, w.TsigStatus() == nil)
+	if !util.IsTrue(zone.Info.Config["dns.update.enabled"]) || peer == "" {
+		reply(dns.RcodeRefused)
+		return
+	}
+
+	err = checkUpdates(name, r.Ns)
+	if err != nil {
+		logger.Debug("Rejected DNS update", logger.Ctx{"zone": name, "peer": peer, "err": err})
+		reply(updateRcode(err))
+		return
+	}
+
+	// Apply the update, checking the prerequisites against the same records the changes are made to.
+	requestor := &api.EventLifecycleRequestor{
+		Username: peer,
+		Protocol: "tsig",
+		Address:  w.RemoteAddr().String(),
+	}
+
+	err = d.server.zoneUpdater(name, requestor, func(content string, records []api.NetworkZoneRecord) ([]api.NetworkZoneRecord, error) {
+		zoneRecords, err := parseZone(name, content)
+		if err != nil {
+			return nil, err
+		}
+
+		err = checkUpdatePrerequisites(name, zoneRecords, r.Answer)
+		if err != nil {
+			return nil, err
+		}
+
+		return applyUpdates(name, records, r.Ns)
+	})
+	if err != nil {
+		var updateErr updateError
+		if errors.As(err, &updateErr) {
+			logger.Debug("Rejected DNS update", logger.Ctx{"zone": name, "peer": peer, "err": err})
+		} else {
+			logger.Error("Failed to apply DNS update", logger.Ctx{"zone": name, "peer": peer, "err": err})
+		}
+
+		reply(updateRcode(err))
+		return
+	}
+
+	// Serve the updated records.
+	d.server.flushZone(name)
+
+	reply(dns.RcodeSuccess)
+}
+
 func isAllowed(zone api.NetworkZone, ip string, tsig *dns.TSIG, tsigStatus bool) bool {
 	type peer struct {
 		address string
@@ -0,0 +1,284 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+// zoneCacheExpiry is how long zone records are served from memory before being rendered again.
+// Rendering a zone may require fetching the network leases from all cluster members, so this
+// can't be done on every query. It matches the negative caching TTL of the zones.
+const zoneCacheExpiry = 30 * time.Second
+
+// maxCNAMEChain is the maximum number of CNAME records followed when answering a query.
+const maxCNAMEChain = 8
+
+// cachedZone holds a zone and, when it answers regular queries, its parsed records.
+type cachedZone struct {
+	info    api.NetworkZone
+	records []dns.RR
+	expiry  time.Time
+}
+
+// queryEnabled returns whether regular queries should be answered for the zone.
+func (z *cachedZone) queryEnabled() bool {
+	return util.IsTrue(z.info.Config["dns.query.enabled"])
+}
+
+// queryRestricted returns whether regular queries are limited to the zone peers.
+func (z *cachedZone) queryRestricted() bool {
+	return util.IsTrueOrEmpty(z.info.Config["dns.query.restricted"])
+}
+
+// flushZones drops the cached zone names and records.
+func (s *Server) flushZones() {
+	s.zonesMu.Lock()
+	defer s.zonesMu.Unlock()
+
+	s.zones = nil
+	s.zoneNames = nil
+}
+
+// flushZone drops the cached records of the zone.
+func (s *Server) flushZone(name string) {
+	s.zonesMu.Lock()
+	defer s.zonesMu.Unlock()
+
+	delete(s.zones, name)
+}
+
+// loadZoneNames returns the names of all the network zones.
+// The returned map must not be modified.
+func (s *Server) loadZoneNames() (map[string]struct{}, error) {
+	s.zonesMu.Lock()
+	names := s.zoneNames
+	expiry := s.zoneNamesExpiry
+	s.zonesMu.Unlock()
+
+	if names != nil && time.Now().Before(expiry) {
+		return names, nil
+	}
+
+	names = map[string]struct{}{}
+	if s.db != nil {
+		err := s.db.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			zones, err := dbCluster.GetNetworkZones(ctx, tx.Tx())
+			if err != nil {
+				return err
+			}
+
+			for _, zone := range zones {
+				names[strings.ToLower(zone.Name)] = struct{}{}
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	s.zonesMu.Lock()
+	s.zoneNames = names
+	s.zoneNamesExpiry = time.Now().Add(zoneCacheExpiry)
+	s.zonesMu.Unlock()
+
+	return names, nil
+}
+
+// lookupZone returns the zone holding the provided name, or nil if the name doesn't belong to any zone.
+// The most specific zone wins, so a name within a zone delegated to a sub-zone gets the sub-zone.
+func (s *Server) lookupZone(name string) (*cachedZone, error) {
+	names, err := s.loadZoneNames()
+	if err != nil {
+		return nil, err
+	}
+
+	labels := dns.SplitDomainName(strings.ToLower(name))
+	for i := range labels {
+		zoneName := strings.Join(labels[i:], ".")
+
+		_, found := names[zoneName]
+		if !found {
+			continue
+		}
+
+		return s.loadZone(zoneName)
+	}
+
+	return nil, nil
+}
+
+// loadZone returns the zone, along with its parsed records if it answers regular queries.
+// Zones are loaded again once expired, without holding the lock so that queries for other zones
+// aren't blocked while the records are rendered.
+func (s *Server) loadZone(name string) (*cachedZone, error) {
+	s.zonesMu.Lock()
+	zone := s.zones[name]
+	s.zonesMu.Unlock()
+
+	if zone != nil && time.Now().Before(zone.expiry) {
+		return zone, nil
+	}
+
+	// Start with the zone configuration, only rendering the records when needed.
+	content, err := s.zoneRetriever(name, false)
+	if err != nil {
+		return nil, err
+	}
+
+	zone = &cachedZone{
+		info:   content.Info,
+		expiry: time.Now().Add(zoneCacheExpiry),
+	}
+
+	if !zone.queryEnabled() {
+		s.storeZone(name, zone)
+		return zone, nil
+	}
+
+	content, err = s.zoneRetriever(name, true)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := parseZone(name, content.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	zone.info = content.Info
+	zone.records = records
+	s.storeZone(name, zone)
+
+	return zone, nil
+}
+
+// storeZone adds the loaded zone to the cache.
+func (s *Server) storeZone(name string, zone *cachedZone) {
+	s.zonesMu.Lock()
+	defer s.zonesMu.Unlock()
+
+	if s.zones == nil {
+		s.zones = map[string]*cachedZone{}
+	}
+
+	s.zones[name] = zone
+}
+
+// parseZone returns the records of the zone content.
+func parseZone(name string, content string) ([]dns.RR, error) {
+	records := []dns.RR{}
+	zoneRR := dns.NewZoneParser(strings.NewReader(content), "", "")
+	for {
+		rr, ok := zoneRR.Next()
+		if !ok {
+			err := zoneRR.Err()
+			if err != nil {
+				return nil, fmt.Errorf("Bad DNS record in zone %q: %w", name, err)
+			}
+
+			break
+		}
+
+		// The zone content is meant for transfers and so ends with a copy of the SOA record.
+		if rr.Header().Rrtype == dns.TypeSOA && len(records) > 0 {
+			continue
+		}
+
+		records = append(records, rr)
+	}
+
+	return records, nil
+}
+
+// answerQuery fills the response to a regular query from the records of the zone holding the name.
+// Names without any record get NXDOMAIN and names without records of the requested type get an empty
+// answer, both carrying the zone SOA record so that resolvers can cache the negative response.
+func answerQuery(m *dns.Msg, q dns.Question, records []dns.RR) {
+	var soa *dns.SOA
+	for _, rr := range records {
+		var ok bool
+		soa, ok = rr.(*dns.SOA)
+		if ok {
+			break
+		}
+	}
+
+	if soa == nil {
+		m.Rcode = dns.RcodeServerFailure
+		return
+	}
+
+	name := dns.CanonicalName(q.Name)
+	for range maxCNAMEChain {
+		found, answers, cname := lookupRecords(records, name, q.Qtype)
+		if !found {
+			m.Rcode = dns.RcodeNameError
+			break
+		}
+
+		if len(answers) > 0 {
+			m.Answer = append(m.Answer, answers...)
+			return
+		}
+
+		if cname == nil {
+			break
+		}
+
+		// Follow the alias, leaving it to the resolver if it points outside of the zone.
+		m.Answer = append(m.Answer, cname)
+		name = dns.CanonicalName(cname.Target)
+		if !dns.IsSubDomain(dns.CanonicalName(soa.Hdr.Name), name) {
+			return
+		}
+	}
+
+	// Negative answer, include the SOA record with its minimum TTL (RFC 2308).
+	negative, _ := dns.Copy(soa).(*dns.SOA)
+	negative.Hdr.Ttl = min(soa.Hdr.Ttl, soa.Minttl)
+	m.Ns = append(m.Ns, negative)
+}
+
+// lookupRecords returns whether the name exists in the records, its records of the requested type
+// and its CNAME record if any.
+func lookupRecords(records []dns.RR, name string, qtype uint16) (bool, []dns.RR, *dns.CNAME) {
+	found := false
+	answers := []dns.RR{}
+	var cname *dns.CNAME
+
+	for _, rr := range records {
+		owner := dns.CanonicalName(rr.Header().Name)
+		if owner != name {
+			// Names with records below them exist even without records of their own.
+			if dns.IsSubDomain(name, owner) {
+				found = true
+			}
+
+			continue
+		}
+
+		found = true
+
+		if qtype == dns.TypeANY || rr.Header().Rrtype == qtype {
+			answers = append(answers, rr)
+			continue
+		}
+
+		if rr.Header().Rrtype == dns.TypeCNAME {
+			cname, _ = rr.(*dns.CNAME)
+		}
+	}
+
+	return found, answers, cname
+}
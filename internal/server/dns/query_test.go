@@ -0,0 +1,122 @@
+package dns
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+const testZone = `example.net. 3600 IN SOA example.net. ns1.example.net. 1 120 60 86400 30
+example.net. 300 IN NS ns1.example.net.
+c1.example.net. 300 IN A 10.0.0.2
+c1.example.net. 300 IN AAAA fd42::2
+www.example.net. 300 IN CNAME c1.example.net.
+ext.example.net. 300 IN CNAME www.example.org.
+_http._tcp.svc.example.net. 300 IN SRV 0 0 80 c1.example.net.
+`
+
+func testRecords(t *testing.T) []dns.RR {
+	records := []dns.RR{}
+	zoneRR := dns.NewZoneParser(strings.NewReader(testZone), "", "")
+	for {
+		rr, ok := zoneRR.Next()
+		if !ok {
+			require.NoError(t, zoneRR.Err())
+			break
+		}
+
+		records = append(records, rr)
+	}
+
+	return records
+}
+
+func TestAnswerQuery(t *testing.T) {
+	records := testRecords(t)
+
+	query := func(name string, qtype uint16) *dns.Msg {
+		m := &dns.Msg{}
+		answerQuery(m, dns.Question{Name: name, Qtype: qtype, Qclass: dns.ClassINET}, records)
+		return m
+	}
+
+	// Direct match, names are case insensitive.
+	m := query("C1.example.net.", dns.TypeA)
+	assert.Equal(t, dns.RcodeSuccess, m.Rcode)
+	require.Len(t, m.Answer, 1)
+	assert.Equal(t, "10.0.0.2", m.Answer[0].(*dns.A).A.String())
+	assert.Empty(t, m.Ns)
+
+	// Aliases are followed within the zone.
+	m = query("www.example.net.", dns.TypeAAAA)
+	require.Len(t, m.Answer, 2)
+	assert.Equal(t, dns.TypeCNAME, m.Answer[0].Header().Rrtype)
+	assert.Equal(t, dns.TypeAAAA, m.Answer[1].Header().Rrtype)
+
+	// But not outside of it.
+	m = query("ext.example.net.", dns.TypeA)
+	require.Len(t, m.Answer, 1)
+	assert.Empty(t, m.Ns)
+
+	// Missing names get NXDOMAIN along with the SOA for negative caching.
+	m = query("missing.example.net.", dns.TypeA)
+	assert.Equal(t, dns.RcodeNameError, m.Rcode)
+	assert.Empty(t, m.Answer)
+	require.Len(t, m.Ns, 1)
+	assert.Equal(t, uint32(30), m.Ns[0].Header().Ttl)
+
+	// Names without records of the requested type get an empty answer.
+	m = query("c1.example.net.", dns.TypeTXT)
+	assert.Equal(t, dns.RcodeSuccess, m.Rcode)
+	assert.Empty(t, m.Answer)
+	require.Len(t, m.Ns, 1)
+
+	// Names with records below them exist.
+	m = query("_tcp.svc.example.net.", dns.TypeA)
+	assert.Equal(t, dns.RcodeSuccess, m.Rcode)
+	assert.Empty(t, m.Answer)
+
+	m = query("_http._tcp.svc.example.net.", dns.TypeSRV)
+	require.Len(t, m.Answer, 1)
+}
+
+func TestLoadZoneParallel(t *testing.T) {
+	retriever := func(name string, full bool) (*Zone, error) {
+		return &Zone{
+			Info:    api.NetworkZone{Name: name, NetworkZonePut: api.NetworkZonePut{Config: map[string]string{"dns.query.enabled": "true"}}},
+			Content: testZone,
+		}, nil
+	}
+
+	s := NewServer(nil, retriever, nil)
+
+	// Queries are answered concurrently while the zones get flushed, run with -race to catch unlocked accesses.
+	wg := sync.WaitGroup{}
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for range 50 {
+				zone, err := s.loadZone("example.net")
+				assert.NoError(t, err)
+				assert.Len(t, zone.records, 7)
+
+				_, err = s.lookupZone("c1.example.net.")
+				assert.NoError(t, err)
+
+				if i%5 == 0 {
+					s.flushZones()
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+}
@@ -27,10 +27,19 @@ type Server struct {
 	// External dependencies.
 	db            *db.Cluster
 	zoneRetriever ZoneRetriever
+	zoneUpdater   ZoneUpdater
 
 	// Internal state (to handle reconfiguration).
 	address string
 
+	// Zones answering regular queries.
+	// Guarded by zonesMu rather than mu as queries are answered concurrently and mu is held
+	// while reloading the TSIG keys, which flushes them.
+	zones           map[string]*cachedZone
+	zoneNames       map[string]struct{}
+	zoneNamesExpiry time.Time
+	zonesMu         sync.Mutex
+
 	cmd chan serverCmdInfo
 
 	mu sync.Mutex
@@ -53,9 +62,9 @@ type serverCmdInfo struct {
 }
 
 // NewServer returns a new server instance.
-func NewServer(db *db.Cluster, retriever ZoneRetriever) *Server {
+func NewServer(db *db.Cluster, retriever ZoneRetriever, updater ZoneUpdater) *Server {
 	// Setup new struct.
-	s := &Server{db: db, zoneRetriever: retriever}
+	s := &Server{db: db, zoneRetriever: retriever, zoneUpdater: updater}
 	return s
 }
 
@@ -296,5 +305,8 @@ func (s *Server) updateTSIG() error {
 	s.tcpDNS.TsigSecret = secrets
 	s.udpDNS.TsigSecret = secrets
 
+	// The zones may have changed, drop them from the query cache.
+	s.flushZones()
+
 	return nil
 }
@@ -0,0 +1,308 @@
+package dns
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/miekg/dns"
+
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+// ZoneUpdater is a function which applies a dynamic update to the records of a DNS zone.
+// The update function is passed the rendered zone content and the current zone records, and returns
+// the records it changed, the ones left without entries being meant for deletion. The changes must be
+// applied atomically and only if the records didn't change since being passed to the update function.
+type ZoneUpdater func(name string, requestor *api.EventLifecycleRequestor, update func(content string, records []api.NetworkZoneRecord) ([]api.NetworkZoneRecord, error)) error
+
+// updateError is an error carrying the DNS response code to return for a failed update.
+type updateError struct {
+	rcode int
+	msg   string
+}
+
+func (e updateError) Error() string {
+	return e.msg
+}
+
+// updateErrorf returns an update error with the provided response code.
+func updateErrorf(rcode int, format string, args ...any) error {
+	return updateError{rcode: rcode, msg: fmt.Sprintf(format, args...)}
+}
+
+// updateRcode returns the DNS response code matching the error.
+func updateRcode(err error) int {
+	var updateErr updateError
+	if errors.As(err, &updateErr) {
+		return updateErr.rcode
+	}
+
+	return dns.RcodeServerFailure
+}
+
+// updatePeer returns the name of the zone peer allowed to update the zone, or an empty string if none.
+// Updates must be signed with the TSIG key of a peer, listed in dns.update.peers when set.
+func updatePeer(zone api.NetworkZone, ip string, tsig *dns.TSIG, tsigStatus bool) string {
+	if tsig == nil || !tsigStatus {
+		return ""
+	}
+
+	allowedPeers := util.SplitNTrimSpace(zone.Config["dns.update.peers"], ",", -1, true)
+
+	for k, v := range zone.Config {
+		if !strings.HasPrefix(k, "peers.") || !strings.HasSuffix(k, ".key") || v == "" {
+			continue
+		}
+
+		fields := strings.SplitN(k, ".", 3)
+		if len(fields) != 3 {
+			continue
+		}
+
+		peerName := fields[1]
+
+		if tsig.Hdr.Name != fmt.Sprintf("%s_%s.", zone.Name, peerName) {
+			continue
+		}
+
+		if len(allowedPeers) > 0 && !slices.Contains(allowedPeers, peerName) {
+			continue
+		}
+
+		address := zone.Config[fmt.Sprintf("peers.%s.address", peerName)]
+		if address != "" && address != ip {
+			continue
+		}
+
+		return peerName
+	}
+
+	return ""
+}
+
+// checkUpdatePrerequisites checks the prerequisite section of a dynamic update against the zone records (RFC 2136 section 3.2).
+func checkUpdatePrerequisites(zoneName string, records []dns.RR, prereqs []dns.RR) error {
+	zoneName = dns.CanonicalName(zoneName)
+
+	// Value dependent prerequisites, grouped by RRset.
+	rrsets := map[string][]dns.RR{}
+	rrsetKey := func(name string, rrtype uint16) string {
+		return fmt.Sprintf("%s/%d", name, rrtype)
+	}
+
+	for _, rr := range prereqs {
+		hdr := rr.Header()
+		name := dns.CanonicalName(hdr.Name)
+
+		if hdr.Ttl != 0 {
+			return updateErrorf(dns.RcodeFormatError, "Prerequisite for %q has a non-zero TTL", name)
+		}
+
+		if !dns.IsSubDomain(zoneName, name) {
+			return updateErrorf(dns.RcodeNotZone, "Prerequisite for %q is outside of the zone", name)
+		}
+
+		found, rrset, _ := lookupRecords(records, name, hdr.Rrtype)
+		if hdr.Rrtype == dns.TypeANY {
+			// Names only exist when having records of their own.
+			found = slices.ContainsFunc(records, func(zoneRR dns.RR) bool { return dns.CanonicalName(zoneRR.Header().Name) == name })
+		}
+
+		switch hdr.Class {
+		case dns.ClassANY:
+			if hdr.Rrtype == dns.TypeANY && !found {
+				return updateErrorf(dns.RcodeNameError, "Name %q isn't in use", name)
+			}
+
+			if hdr.Rrtype != dns.TypeANY && len(rrset) == 0 {
+				return updateErrorf(dns.RcodeNXRrset, "No %s records for %q", dns.TypeToString[hdr.Rrtype], name)
+			}
+
+		case dns.ClassNONE:
+			if hdr.Rrtype == dns.TypeANY && found {
+				return updateErrorf(dns.RcodeYXDomain, "Name %q is in use", name)
+			}
+
+			if hdr.Rrtype != dns.TypeANY && len(rrset) > 0 {
+				return updateErrorf(dns.RcodeYXRrset, "Found %s records for %q", dns.TypeToString[hdr.Rrtype], name)
+			}
+
+		case dns.ClassINET:
+			key := rrsetKey(name, hdr.Rrtype)
+			rrsets[key] = append(rrsets[key], rr)
+
+		default:
+			return updateErrorf(dns.RcodeFormatError, "Prerequisite for %q has an invalid class", name)
+		}
+	}
+
+	for _, expected := range rrsets {
+		hdr := expected[0].Header()
+		_, rrset, _ := lookupRecords(records, dns.CanonicalName(hdr.Name), hdr.Rrtype)
+
+		if len(rrset) != len(expected) {
+			return updateErrorf(dns.RcodeNXRrset, "Different %s records for %q", dns.TypeToString[hdr.Rrtype], hdr.Name)
+		}
+
+		for _, rr := range expected {
+			if !slices.ContainsFunc(rrset, func(zoneRR dns.RR) bool { return dns.IsDuplicate(rr, zoneRR) }) {
+				return updateErrorf(dns.RcodeNXRrset, "Different %s records for %q", dns.TypeToString[hdr.Rrtype], hdr.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkUpdates checks the update section of a dynamic update (RFC 2136 section 3.4.1).
+// Only names below the zone apex can be updated, as they map onto the zone records.
+func checkUpdates(zoneName string, updates []dns.RR) error {
+	zoneName = dns.CanonicalName(zoneName)
+
+	for _, rr := range updates {
+		hdr := rr.Header()
+		name := dns.CanonicalName(hdr.Name)
+
+		if !dns.IsSubDomain(zoneName, name) {
+			return updateErrorf(dns.RcodeNotZone, "Update for %q is outside of the zone", name)
+		}
+
+		if name == zoneName {
+			return updateErrorf(dns.RcodeRefused, "Records at the zone apex can't be updated")
+		}
+
+		switch hdr.Class {
+		case dns.ClassINET:
+			if hdr.Rrtype == dns.TypeANY || hdr.Rrtype == dns.TypeAXFR || hdr.Rrtype == dns.TypeIXFR || hdr.Rrtype == dns.TypeSOA {
+				return updateErrorf(dns.RcodeFormatError, "Can't add %s records", dns.TypeToString[hdr.Rrtype])
+			}
+
+		case dns.ClassANY:
+			if hdr.Ttl != 0 {
+				return updateErrorf(dns.RcodeFormatError, "Deletion for %q has a non-zero TTL", name)
+			}
+
+		case dns.ClassNONE:
+			if hdr.Ttl != 0 || hdr.Rrtype == dns.TypeANY {
+				return updateErrorf(dns.RcodeFormatError, "Invalid deletion for %q", name)
+			}
+
+		default:
+			return updateErrorf(dns.RcodeFormatError, "Update for %q has an invalid class", name)
+		}
+	}
+
+	return nil
+}
+
+// applyUpdates applies the update section of a dynamic update to the zone records (RFC 2136 section 3.4.2).
+// It returns the changed records, those left without entries being meant for deletion.
+func applyUpdates(zoneName string, records []api.NetworkZoneRecord, updates []dns.RR) ([]api.NetworkZoneRecord, error) {
+	zoneName = dns.CanonicalName(zoneName)
+
+	changed := []api.NetworkZoneRecord{}
+	changedIndex := map[string]int{}
+
+	// getRecord returns the changed record with the provided name, starting from the existing one if any.
+	getRecord := func(name string) *api.NetworkZoneRecord {
+		i, found := changedIndex[strings.ToLower(name)]
+		if found {
+			return &changed[i]
+		}
+
+		record := api.NetworkZoneRecord{Name: name}
+		for _, existing := range records {
+			if strings.EqualFold(existing.Name, name) {
+				record = existing
+				record.Entries = slices.Clone(existing.Entries)
+				break
+			}
+		}
+
+		changed = append(changed, record)
+		changedIndex[strings.ToLower(name)] = len(changed) - 1
+
+		return &changed[len(changed)-1]
+	}
+
+	for _, rr := range updates {
+		hdr := rr.Header()
+		name := strings.TrimSuffix(dns.CanonicalName(hdr.Name), "."+zoneName)
+		record := getRecord(name)
+
+		switch hdr.Class {
+		case dns.ClassINET:
+			entry := api.NetworkZoneRecordEntry{
+				Type:  dns.TypeToString[hdr.Rrtype],
+				TTL:   uint64(hdr.Ttl),
+				Value: strings.TrimPrefix(rr.String(), hdr.String()),
+			}
+
+			// Adding an existing entry only updates its TTL.
+			i, err := findEntry(record.Entries, rr)
+			if err != nil {
+				return nil, err
+			}
+
+			if i >= 0 {
+				record.Entries[i].TTL = entry.TTL
+				continue
+			}
+
+			record.Entries = append(record.Entries, entry)
+
+		case dns.ClassANY:
+			record.Entries = slices.DeleteFunc(record.Entries, func(entry api.NetworkZoneRecordEntry) bool {
+				return hdr.Rrtype == dns.TypeANY || strings.EqualFold(entry.Type, dns.TypeToString[hdr.Rrtype])
+			})
+
+		case dns.ClassNONE:
+			// Compare the entry with the record to delete, as they're part of the same RRset.
+			deleteRR := dns.Copy(rr)
+			deleteRR.Header().Class = dns.ClassINET
+
+			i, err := findEntry(record.Entries, deleteRR)
+			if err != nil {
+				return nil, err
+			}
+
+			if i >= 0 {
+				record.Entries = slices.Delete(record.Entries, i, i+1)
+			}
+		}
+	}
+
+	// Skip the records which didn't change.
+	return slices.DeleteFunc(changed, func(record api.NetworkZoneRecord) bool {
+		for _, existing := range records {
+			if strings.EqualFold(existing.Name, record.Name) {
+				return slices.Equal(existing.Entries, record.Entries)
+			}
+		}
+
+		return len(record.Entries) == 0
+	}), nil
+}
+
+// findEntry returns the index of the record entry matching the resource record, or -1 if not found.
+func findEntry(entries []api.NetworkZoneRecordEntry, rr dns.RR) (int, error) {
+	for i, entry := range entries {
+		if !strings.EqualFold(entry.Type, dns.TypeToString[rr.Header().Rrtype]) {
+			continue
+		}
+
+		entryRR, err := dns.NewRR(fmt.Sprintf("%s 0 IN %s %s", rr.Header().Name, entry.Type, entry.Value))
+		if err != nil {
+			return -1, fmt.Errorf("Bad zone record entry: %w", err)
+		}
+
+		if dns.IsDuplicate(entryRR, rr) {
+			return i, nil
+		}
+	}
+
+	return -1, nil
+}
@@ -0,0 +1,140 @@
+package dns
+
+import (
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+func mustRR(t *testing.T, s string) dns.RR {
+	rr, err := dns.NewRR(s)
+	require.NoError(t, err)
+
+	return rr
+}
+
+func TestUpdatePeer(t *testing.T) {
+	zone := api.NetworkZone{
+		Name: "example.net",
+		NetworkZonePut: api.NetworkZonePut{Config: map[string]string{
+			"peers.dhcp.key":      "c2VjcmV0",
+			"peers.dhcp.address":  "192.0.2.1",
+			"peers.other.key":     "c2VjcmV0",
+			"peers.nokey.address": "192.0.2.2",
+		}},
+	}
+
+	tsig := func(name string) *dns.TSIG {
+		return &dns.TSIG{Hdr: dns.RR_Header{Name: name}}
+	}
+
+	assert.Equal(t, "dhcp", updatePeer(zone, "192.0.2.1", tsig("example.net_dhcp."), true))
+	assert.Empty(t, updatePeer(zone, "192.0.2.3", tsig("example.net_dhcp."), true))
+	assert.Empty(t, updatePeer(zone, "192.0.2.1", tsig("example.net_dhcp."), false))
+	assert.Empty(t, updatePeer(zone, "192.0.2.2", nil, false))
+	assert.Equal(t, "other", updatePeer(zone, "192.0.2.3", tsig("example.net_other."), true))
+
+	zone.Config["dns.update.peers"] = "dhcp"
+	assert.Empty(t, updatePeer(zone, "192.0.2.3", tsig("example.net_other."), true))
+}
+
+func TestCheckUpdatePrerequisites(t *testing.T) {
+	records := testRecords(t)
+
+	check := func(prereqs ...dns.RR) int {
+		err := checkUpdatePrerequisites("example.net", records, prereqs)
+		if err == nil {
+			return dns.RcodeSuccess
+		}
+
+		return updateRcode(err)
+	}
+
+	nameInUse := func(name string) dns.RR {
+		return &dns.ANY{Hdr: dns.RR_Header{Name: name, Rrtype: dns.TypeANY, Class: dns.ClassANY}}
+	}
+
+	nameNotInUse := func(name string) dns.RR {
+		return &dns.ANY{Hdr: dns.RR_Header{Name: name, Rrtype: dns.TypeANY, Class: dns.ClassNONE}}
+	}
+
+	assert.Equal(t, dns.RcodeSuccess, check(nameInUse("c1.example.net.")))
+	assert.Equal(t, dns.RcodeNameError, check(nameInUse("missing.example.net.")))
+	assert.Equal(t, dns.RcodeSuccess, check(nameNotInUse("missing.example.net.")))
+	assert.Equal(t, dns.RcodeYXDomain, check(nameNotInUse("c1.example.net.")))
+	assert.Equal(t, dns.RcodeNotZone, check(nameInUse("c1.example.org.")))
+
+	assert.Equal(t, dns.RcodeSuccess, check(&dns.ANY{Hdr: dns.RR_Header{Name: "c1.example.net.", Rrtype: dns.TypeA, Class: dns.ClassANY}}))
+	assert.Equal(t, dns.RcodeNXRrset, check(&dns.ANY{Hdr: dns.RR_Header{Name: "c1.example.net.", Rrtype: dns.TypeTXT, Class: dns.ClassANY}}))
+	assert.Equal(t, dns.RcodeYXRrset, check(&dns.ANY{Hdr: dns.RR_Header{Name: "c1.example.net.", Rrtype: dns.TypeA, Class: dns.ClassNONE}}))
+
+	// Value dependent prerequisites must match the whole RRset.
+	assert.Equal(t, dns.RcodeSuccess, check(mustRR(t, "c1.example.net. 0 IN A 10.0.0.2")))
+	assert.Equal(t, dns.RcodeNXRrset, check(mustRR(t, "c1.example.net. 0 IN A 10.0.0.3")))
+	assert.Equal(t, dns.RcodeFormatError, check(mustRR(t, "c1.example.net. 300 IN A 10.0.0.2")))
+}
+
+func TestApplyUpdates(t *testing.T) {
+	records := []api.NetworkZoneRecord{
+		{
+			Name: "web",
+			NetworkZoneRecordPut: api.NetworkZoneRecordPut{
+				Description: "Web server",
+				Entries: []api.NetworkZoneRecordEntry{
+					{Type: "A", Value: "10.0.0.10"},
+					{Type: "TXT", Value: `"hello"`},
+				},
+			},
+		},
+		{
+			Name: "old",
+			NetworkZoneRecordPut: api.NetworkZoneRecordPut{
+				Entries: []api.NetworkZoneRecordEntry{{Type: "A", Value: "10.0.0.20"}},
+			},
+		},
+	}
+
+	updates := []dns.RR{
+		// Add a new record.
+		mustRR(t, "host.example.net. 600 IN A 10.0.0.30"),
+
+		// Add an existing entry, only changing its TTL.
+		mustRR(t, "web.example.net. 60 IN A 10.0.0.10"),
+
+		// Delete a specific entry.
+		&dns.TXT{Hdr: dns.RR_Header{Name: "web.example.net.", Rrtype: dns.TypeTXT, Class: dns.ClassNONE}, Txt: []string{"hello"}},
+
+		// Delete all entries of a name.
+		&dns.ANY{Hdr: dns.RR_Header{Name: "old.example.net.", Rrtype: dns.TypeANY, Class: dns.ClassANY}},
+
+		// Delete entries of a name without any.
+		&dns.ANY{Hdr: dns.RR_Header{Name: "missing.example.net.", Rrtype: dns.TypeANY, Class: dns.ClassANY}},
+	}
+
+	require.NoError(t, checkUpdates("example.net", updates))
+
+	changed, err := applyUpdates("example.net", records, updates)
+	require.NoError(t, err)
+	require.Len(t, changed, 3)
+
+	assert.Equal(t, "host", changed[0].Name)
+	assert.Equal(t, []api.NetworkZoneRecordEntry{{Type: "A", TTL: 600, Value: "10.0.0.30"}}, changed[0].Entries)
+
+	assert.Equal(t, "web", changed[1].Name)
+	assert.Equal(t, "Web server", changed[1].Description)
+	assert.Equal(t, []api.NetworkZoneRecordEntry{{Type: "A", TTL: 60, Value: "10.0.0.10"}}, changed[1].Entries)
+
+	assert.Equal(t, "old", changed[2].Name)
+	assert.Empty(t, changed[2].Entries)
+
+	// The existing records are left untouched.
+	assert.Len(t, records[0].Entries, 2)
+
+	// Records at the apex or outside the zone can't be updated.
+	assert.Equal(t, dns.RcodeRefused, updateRcode(checkUpdates("example.net", []dns.RR{mustRR(t, "example.net. 300 IN TXT foo")})))
+	assert.Equal(t, dns.RcodeNotZone, updateRcode(checkUpdates("example.net", []dns.RR{mustRR(t, "host.example.org. 300 IN A 10.0.0.1")})))
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"slices"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -41,6 +42,7 @@ type Server struct {
 	listeners map[string]*Listener
 	notify    NotifyFunc
 	location  string
+	journal   *Journal
 }
 
 // NewServer returns a new event server.
@@ -66,8 +68,26 @@ func (s *Server) SetLocalLocation(location string) {
 	s.location = location
 }
 
+// SetJournal sets the journal used to record events and replay them to listeners.
+func (s *Server) SetJournal(journal *Journal) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.journal = journal
+}
+
 // AddListener creates and returns a new event listener.
 func (s *Server) AddListener(projectName string, allProjects bool, projectPermissionFunc auth.PermissionChecker, connection EventListenerConnection, messageTypes []string, excludeSources []EventSource, recvFunc EventHandler, excludeLocations []string) (*Listener, error) {
+	return s.addListener(projectName, allProjects, projectPermissionFunc, connection, messageTypes, excludeSources, recvFunc, excludeLocations, false, 0)
+}
+
+// AddListenerSince creates and returns a new event listener which first receives the journaled events
+// with an ID greater than since, before receiving live events.
+func (s *Server) AddListenerSince(since uint64, projectName string, allProjects bool, projectPermissionFunc auth.PermissionChecker, connection EventListenerConnection, messageTypes []string, excludeSources []EventSource, recvFunc EventHandler, excludeLocations []string) (*Listener, error) {
+	return s.addListener(projectName, allProjects, projectPermissionFunc, connection, messageTypes, excludeSources, recvFunc, excludeLocations, true, since)
+}
+
+func (s *Server) addListener(projectName string, allProjects bool, projectPermissionFunc auth.PermissionChecker, connection EventListenerConnection, messageTypes []string, excludeSources []EventSource, recvFunc EventHandler, excludeLocations []string, replay bool, since uint64) (*Listener, error) {
 	if allProjects && projectName != "" {
 		return nil, errors.New("Cannot specify project name when listening for events on all projects")
 	}
@@ -92,6 +112,7 @@ func (s *Server) AddListener(projectName string, allProjects bool, projectPermis
 		projectPermissionFunc: projectPermissionFunc,
 		excludeSources:        excludeSources,
 		excludeLocations:      excludeLocations,
+		replaying:             replay,
 	}
 
 	s.lock.Lock()
@@ -101,13 +122,45 @@ func (s *Server) AddListener(projectName string, allProjects bool, projectPermis
 		return nil, fmt.Errorf("A listener with ID %q already exists", listener.id)
 	}
 
+	// Retrieve the missed events while holding the lock so that none get lost or duplicated.
+	var missed []JournalEntry
+	if replay {
+		if s.journal == nil {
+			return nil, errors.New("Event journal isn't available")
+		}
+
+		var err error
+		missed, err = s.journal.Since(since)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	s.listeners[listener.id] = listener
 
 	go listener.start()
 
+	if replay {
+		go listener.replay(missed)
+	}
+
 	return listener, nil
 }
 
+// CheckSince checks that the events following the given ID can be replayed.
+func (s *Server) CheckSince(since uint64) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.journal == nil {
+		return errors.New("Event journal isn't available")
+	}
+
+	_, err := s.journal.Since(since)
+
+	return err
+}
+
 // SendLifecycle broadcasts a lifecycle event.
 func (s *Server) SendLifecycle(projectName string, event api.EventLifecycle) {
 	_ = s.Send(projectName, api.EventTypeLifecycle, event)
@@ -157,10 +210,6 @@ func (s *Server) Inject(event api.Event, eventSource EventSource) {
 }
 
 func (s *Server) broadcast(event api.Event, eventSource EventSource) error {
-	sourceInSlice := func(source EventSource, sources []EventSource) bool {
-		return slices.Contains(sources, source)
-	}
-
 	s.lock.Lock()
 
 	// Set the Location for local events to the local serverName if not already populated (do it here rather
@@ -169,6 +218,15 @@ func (s *Server) broadcast(event api.Event, eventSource EventSource) error {
 		event.Location = s.location
 	}
 
+	// Record the event in the journal (if any), which also sets its ID.
+	// It's written to disk once the lock is released.
+	journal := s.journal
+	if journal != nil && journaled(event) {
+		journal.Append(&event, eventSource)
+	} else {
+		journal = nil
+	}
+
 	// If a notification hook is present, then call it for locally produced events.
 	// This can be used to send local events to another target (such as an event-hub member).
 	if s.notify != nil && eventSource == EventSourceLocal {
@@ -177,26 +235,7 @@ func (s *Server) broadcast(event api.Event, eventSource EventSource) error {
 
 	listeners := s.listeners
 	for _, listener := range listeners {
-		// If the event is project specific, check if the listener is requesting events from that project.
-		if event.Project != "" && !listener.allProjects && event.Project != listener.projectName {
-			continue
-		}
-
-		// If the event is project specific, ensure we have permission to view it.
-		if event.Project != "" && !listener.projectPermissionFunc(auth.ObjectProject(event.Project)) {
-			continue
-		}
-
-		if sourceInSlice(eventSource, listener.excludeSources) {
-			continue
-		}
-
-		if !slices.Contains(listener.messageTypes, event.Type) {
-			continue
-		}
-
-		// If the event doesn't come from this member and has been excluded by listener, don't deliver it.
-		if eventSource != EventSourceLocal && slices.Contains(listener.excludeLocations, event.Location) {
+		if !listener.matches(event, eventSource) {
 			continue
 		}
 
@@ -215,7 +254,7 @@ func (s *Server) broadcast(event api.Event, eventSource EventSource) error {
 				return
 			}
 
-			err := listener.WriteJSON(event)
+			err := listener.send(event)
 			if err != nil {
 				// Remove the listener from the list
 				s.lock.Lock()
@@ -229,6 +268,11 @@ func (s *Server) broadcast(event api.Event, eventSource EventSource) error {
 
 	s.lock.Unlock()
 
+	// Errors are ignored as failing to persist an event shouldn't prevent its delivery.
+	if journal != nil {
+		_ = journal.Flush()
+	}
+
 	return nil
 }
 
@@ -241,4 +285,100 @@ type Listener struct {
 	projectPermissionFunc auth.PermissionChecker
 	excludeSources        []EventSource
 	excludeLocations      []string
+
+	replayLock sync.Mutex
+	replaying  bool
+	pending    []api.Event
+}
+
+// matches returns whether the event should be delivered to the listener.
+func (l *Listener) matches(event api.Event, eventSource EventSource) bool {
+	// If the event is project specific, check if the listener is requesting events from that project.
+	if event.Project != "" && !l.allProjects && event.Project != l.projectName {
+		return false
+	}
+
+	// If the event is project specific, ensure we have permission to view it.
+	if event.Project != "" && !l.projectPermissionFunc(auth.ObjectProject(event.Project)) {
+		return false
+	}
+
+	if slices.Contains(l.excludeSources, eventSource) {
+		return false
+	}
+
+	if !slices.Contains(l.messageTypes, event.Type) {
+		return false
+	}
+
+	// If the event doesn't come from this member and has been excluded by listener, don't deliver it.
+	if eventSource != EventSourceLocal && slices.Contains(l.excludeLocations, event.Location) {
+		return false
+	}
+
+	return true
+}
+
+// send delivers a live event, holding it back while missed events are being replayed.
+func (l *Listener) send(event api.Event) error {
+	l.replayLock.Lock()
+	if l.replaying {
+		l.pending = append(l.pending, event)
+		l.replayLock.Unlock()
+		return nil
+	}
+
+	l.replayLock.Unlock()
+
+	return l.WriteJSON(event)
+}
+
+// replay delivers the missed events followed by the live events received in the meantime.
+func (l *Listener) replay(missed []JournalEntry) {
+	var err error
+
+	for _, entry := range missed {
+		if !l.matches(entry.Event, entry.Source) {
+			continue
+		}
+
+		err = l.WriteJSON(entry.Event)
+		if err != nil {
+			break
+		}
+	}
+
+	l.replayLock.Lock()
+	defer l.replayLock.Unlock()
+
+	for _, event := range l.pending {
+		if err != nil {
+			break
+		}
+
+		err = l.WriteJSON(event)
+	}
+
+	l.pending = nil
+	l.replaying = false
+
+	if err != nil {
+		l.Close()
+	}
+}
+
+// journaled returns whether the event should be recorded in the journal.
+// Debug and trace log messages are skipped to avoid them pushing out the other events.
+func journaled(event api.Event) bool {
+	if event.Type != api.EventTypeLogging {
+		return true
+	}
+
+	logEntry := api.EventLogging{}
+	err := json.Unmarshal(event.Metadata, &logEntry)
+	if err != nil {
+		return false
+	}
+
+	return logEntry.Level != "debug" && logEntry.Level != "trace"
 }
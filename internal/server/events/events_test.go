@@ -0,0 +1,110 @@
+package events
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// testConnection is an event listener connection recording the events written to it.
+type testConnection struct {
+	lock   sync.Mutex
+	events []api.Event
+}
+
+func (c *testConnection) Reader(ctx context.Context, recvFunc EventHandler) {
+	<-ctx.Done()
+}
+
+func (c *testConnection) WriteJSON(event any) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.events = append(c.events, event.(api.Event))
+
+	return nil
+}
+
+func (c *testConnection) Close() error {
+	return nil
+}
+
+func (c *testConnection) LocalAddr() net.Addr {
+	return nil
+}
+
+func (c *testConnection) RemoteAddr() net.Addr {
+	return nil
+}
+
+func (c *testConnection) received() []api.Event {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return append([]api.Event(nil), c.events...)
+}
+
+func TestServerReplaySources(t *testing.T) {
+	journal, err := NewJournal(filepath.Join(t.TempDir(), "events.journal"), 10)
+	require.NoError(t, err)
+
+	defer func() { _ = journal.Close() }()
+
+	s := NewServer(false, false, nil)
+	s.SetLocalLocation("member1")
+	s.SetJournal(journal)
+
+	require.NoError(t, s.Send("", api.EventTypeLifecycle, api.EventLifecycle{Action: "local"}))
+	s.Inject(api.Event{Type: api.EventTypeLifecycle, Location: "member2", Metadata: []byte(`{"action":"pulled"}`)}, EventSourcePull)
+	s.Inject(api.Event{Type: api.EventTypeLifecycle, Location: "member3", Metadata: []byte(`{"action":"pushed"}`)}, EventSourcePush)
+
+	// A cluster member listener excludes the events pulled from other members and its own.
+	conn := &testConnection{}
+	listener, err := s.AddListenerSince(0, "", true, nil, conn, []string{api.EventTypeLifecycle}, []EventSource{EventSourcePull}, nil, []string{"member3"})
+	require.NoError(t, err)
+
+	defer listener.Close()
+
+	assert.Eventually(t, func() bool { return len(conn.received()) == 1 }, time.Second, 10*time.Millisecond)
+
+	events := conn.received()
+	assert.Equal(t, uint64(1), events[0].ID)
+	assert.Equal(t, "member1", events[0].Location)
+
+	// A regular listener gets all of them.
+	conn = &testConnection{}
+	listener, err = s.AddListenerSince(1, "", true, nil, conn, []string{api.EventTypeLifecycle}, nil, nil, nil)
+	require.NoError(t, err)
+
+	defer listener.Close()
+
+	assert.Eventually(t, func() bool { return len(conn.received()) == 2 }, time.Second, 10*time.Millisecond)
+}
+
+func TestServerReplayGap(t *testing.T) {
+	journal, err := NewJournal(filepath.Join(t.TempDir(), "events.journal"), 2)
+	require.NoError(t, err)
+
+	defer func() { _ = journal.Close() }()
+
+	s := NewServer(false, false, nil)
+	s.SetJournal(journal)
+
+	for range 3 {
+		require.NoError(t, s.Send("", api.EventTypeLifecycle, api.EventLifecycle{}))
+	}
+
+	assert.NoError(t, s.CheckSince(1))
+	assert.ErrorIs(t, s.CheckSince(0), ErrJournalGap)
+
+	_, err = s.AddListenerSince(0, "", true, nil, &testConnection{}, []string{api.EventTypeLifecycle}, nil, nil, nil)
+	assert.ErrorIs(t, err, ErrJournalGap)
+}
@@ -0,0 +1,261 @@
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// ErrJournalGap is returned when events following the requested ID were already dropped from the journal.
+var ErrJournalGap = errors.New("Events following the requested ID are no longer in the journal")
+
+// ErrJournalAhead is returned when the requested ID is past the last event of the journal, as happens
+// after the journal was reset or when resuming from another member.
+var ErrJournalAhead = errors.New("The requested ID is ahead of the journal")
+
+// JournalEntry is an event recorded in the journal, along with where it was received from.
+type JournalEntry struct {
+	api.Event
+
+	Source EventSource `json:"source,omitempty"`
+}
+
+// Journal is a bounded, persistent record of the most recent events.
+//
+// Every recorded event is given a sequence ID, allowing clients to replay the events they missed.
+// The events are kept in memory and appended to a file so that they survive daemon restarts.
+//
+// Recording an event (Append) only updates the in-memory state, writing it to the file is done separately
+// (Flush) so that callers can avoid doing disk I/O while holding their own locks.
+type Journal struct {
+	lock    sync.Mutex
+	path    string
+	size    int
+	entries []JournalEntry
+	lastID  uint64
+	pending []JournalEntry
+
+	// Protects the file, held while writing to it.
+	fileLock sync.Mutex
+	file     *os.File
+	written  int
+}
+
+// NewJournal opens (or creates) the journal at the given path, keeping at most size events.
+func NewJournal(path string, size int) (*Journal, error) {
+	if size <= 0 {
+		return nil, errors.New("Journal size must be positive")
+	}
+
+	j := &Journal{
+		path: path,
+		size: size,
+	}
+
+	// Load the existing events.
+	f, err := os.Open(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("Failed opening event journal %q: %w", path, err)
+	}
+
+	if f != nil {
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(nil, 16*1024*1024)
+
+		for scanner.Scan() {
+			entry := JournalEntry{}
+
+			// Skip entries which were only partially written.
+			err := json.Unmarshal(scanner.Bytes(), &entry)
+			if err != nil || entry.ID <= j.lastID {
+				continue
+			}
+
+			j.add(entry)
+		}
+
+		_ = f.Close()
+	}
+
+	// Rewrite the file so that it only contains the retained events.
+	err = j.compact(j.entries)
+	if err != nil {
+		return nil, err
+	}
+
+	return j, nil
+}
+
+// add appends the entry to the in-memory list, dropping the oldest entry if needed.
+func (j *Journal) add(entry JournalEntry) {
+	j.lastID = entry.ID
+	j.entries = append(j.entries, entry)
+
+	if len(j.entries) > j.size {
+		j.entries = j.entries[len(j.entries)-j.size:]
+	}
+}
+
+// compact replaces the journal file with one containing only the given entries.
+// Must be called with fileLock held. On failure, the file is left closed and the next flush tries again.
+func (j *Journal) compact(entries []JournalEntry) error {
+	if j.file != nil {
+		_ = j.file.Close()
+		j.file = nil
+	}
+
+	tmpPath := j.path + ".tmp"
+
+	f, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("Failed creating event journal %q: %w", tmpPath, err)
+	}
+
+	writer := bufio.NewWriter(f)
+	encoder := json.NewEncoder(writer)
+
+	for _, entry := range entries {
+		err = encoder.Encode(entry)
+		if err != nil {
+			_ = f.Close()
+			return err
+		}
+	}
+
+	err = writer.Flush()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	err = f.Close()
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(tmpPath, j.path)
+	if err != nil {
+		return err
+	}
+
+	j.file, err = os.OpenFile(j.path, os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	j.written = len(entries)
+
+	return nil
+}
+
+// Append records the event received from the given source in memory, setting its ID.
+// The event is written to the file on the next call to Flush.
+func (j *Journal) Append(event *api.Event, source EventSource) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	event.ID = j.lastID + 1
+
+	entry := JournalEntry{Event: *event, Source: source}
+	j.add(entry)
+	j.pending = append(j.pending, entry)
+
+	// Don't let the pending entries grow forever if they can't be written.
+	if len(j.pending) > j.size {
+		j.pending = j.pending[len(j.pending)-j.size:]
+	}
+}
+
+// Flush writes the events recorded since the last flush to the file.
+// The events are always kept in memory, even if they couldn't be written to disk.
+func (j *Journal) Flush() error {
+	j.fileLock.Lock()
+	defer j.fileLock.Unlock()
+
+	j.lock.Lock()
+	pending := j.pending
+	j.pending = nil
+
+	// Avoid the file growing forever by regularly dropping the events which aren't retained anymore.
+	// This also reopens the file if a previous write or compaction failed.
+	var entries []JournalEntry
+	if j.file == nil || j.written+len(pending) >= 2*j.size {
+		entries = append([]JournalEntry(nil), j.entries...)
+	}
+
+	j.lock.Unlock()
+
+	if entries != nil {
+		return j.compact(entries)
+	}
+
+	for _, entry := range pending {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+
+		_, err = j.file.Write(append(data, '\n'))
+		if err != nil {
+			// Reopen the file on the next flush, as it may now end with a partially written entry.
+			_ = j.file.Close()
+			j.file = nil
+
+			return err
+		}
+
+		j.written++
+	}
+
+	return nil
+}
+
+// Since returns the recorded entries with an ID greater than the given one, oldest first.
+// ErrJournalGap is returned if some of those were already dropped from the journal and
+// ErrJournalAhead if the ID is greater than the one of the last recorded event.
+func (j *Journal) Since(id uint64) ([]JournalEntry, error) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	if id > j.lastID {
+		return nil, ErrJournalAhead
+	}
+
+	if len(j.entries) > 0 && j.entries[0].ID > id+1 {
+		return nil, ErrJournalGap
+	}
+
+	for i, entry := range j.entries {
+		if entry.ID > id {
+			return append([]JournalEntry(nil), j.entries[i:]...), nil
+		}
+	}
+
+	return nil, nil
+}
+
+// Close closes the journal file, after writing the pending events.
+func (j *Journal) Close() error {
+	err := j.Flush()
+
+	j.fileLock.Lock()
+	defer j.fileLock.Unlock()
+
+	if j.file == nil {
+		return err
+	}
+
+	closeErr := j.file.Close()
+	j.file = nil
+
+	if err != nil {
+		return err
+	}
+
+	return closeErr
+}
@@ -0,0 +1,127 @@
+package events
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+func TestJournalAppendSince(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.journal")
+
+	j, err := NewJournal(path, 3)
+	require.NoError(t, err)
+
+	for i := range 5 {
+		event := api.Event{Type: api.EventTypeLifecycle, Project: "p"}
+		j.Append(&event, EventSource(i%3))
+		assert.Equal(t, uint64(i+1), event.ID)
+	}
+
+	require.NoError(t, j.Flush())
+
+	// Only the last three events are retained, along with their source.
+	entries, err := j.Since(2)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, uint64(3), entries[0].ID)
+	assert.Equal(t, EventSource(EventSourcePush), entries[0].Source)
+	assert.Equal(t, EventSource(EventSourceLocal), entries[1].Source)
+
+	entries, err = j.Since(4)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, uint64(5), entries[0].ID)
+
+	entries, err = j.Since(5)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	// The events following 1 were dropped.
+	_, err = j.Since(1)
+	assert.ErrorIs(t, err, ErrJournalGap)
+
+	// There are no events past 5 yet.
+	_, err = j.Since(6)
+	assert.ErrorIs(t, err, ErrJournalAhead)
+
+	require.NoError(t, j.Close())
+
+	// The events and their IDs survive a restart.
+	j, err = NewJournal(path, 3)
+	require.NoError(t, err)
+
+	entries, err = j.Since(2)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, EventSource(EventSourcePull), entries[2].Source)
+
+	event := api.Event{Type: api.EventTypeLifecycle}
+	j.Append(&event, EventSourceLocal)
+	assert.Equal(t, uint64(6), event.ID)
+
+	require.NoError(t, j.Close())
+}
+
+func TestJournalCompact(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.journal")
+
+	j, err := NewJournal(path, 2)
+	require.NoError(t, err)
+
+	for range 10 {
+		event := api.Event{Type: api.EventTypeLifecycle}
+		j.Append(&event, EventSourceLocal)
+		require.NoError(t, j.Flush())
+	}
+
+	require.NoError(t, j.Close())
+
+	// The file doesn't keep more than twice the retained events.
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, bytes.Count(data, []byte("\n")), 4)
+}
+
+func TestJournalRecover(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "events.journal")
+
+	j, err := NewJournal(path, 10)
+	require.NoError(t, err)
+
+	// Make the compaction fail by putting a directory where the temporary file is created.
+	require.NoError(t, os.Mkdir(path+".tmp", 0o700))
+
+	j.fileLock.Lock()
+	err = j.compact(nil)
+	j.fileLock.Unlock()
+	require.Error(t, err)
+
+	// Events are kept in memory while the file can't be written.
+	event := api.Event{Type: api.EventTypeLifecycle}
+	j.Append(&event, EventSourceLocal)
+	assert.Error(t, j.Flush())
+
+	entries, err := j.Since(0)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	// The file gets rewritten once possible.
+	require.NoError(t, os.Remove(path+".tmp"))
+
+	event = api.Event{Type: api.EventTypeLifecycle}
+	j.Append(&event, EventSourceLocal)
+	require.NoError(t, j.Flush())
+	require.NoError(t, j.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, bytes.Count(data, []byte("\n")))
+}
@@ -12,9 +12,10 @@ type FeatureOpts struct {
 
 // SNATOpts specify how SNAT rules are setup.
 type SNATOpts struct {
-	Append      bool       // Append rules (has no effect if driver doesn't support it).
-	Subnet      *net.IPNet // Subnet of source network used to identify candidate traffic.
-	SNATAddress net.IP     // SNAT IP address to use. If nil then MASQUERADE is used.
+	Append      bool         // Append rules (has no effect if driver doesn't support it).
+	Subnet      *net.IPNet   // Subnet of source network used to identify candidate traffic.
+	SNATAddress net.IP       // SNAT IP address to use. If nil then MASQUERADE is used.
+	Exclude     []*net.IPNet // Destination subnets for which the source address is left as is.
 }
 
 // Opts for setting up the firewall.
@@ -52,6 +53,21 @@ type AddressForward struct {
 	SNAT          bool
 }
 
+// LoadBalancer represents a NAT load balancer, spreading the connections to a listen port over several targets.
+type LoadBalancer struct {
+	ListenAddress net.IP
+	Protocol      string
+	ListenPort    uint64
+	Targets       []LoadBalancerTarget
+	Hashed        bool // Pick the target from a hash of the source address rather than in turn.
+}
+
+// LoadBalancerTarget represents a load balancer target.
+type LoadBalancerTarget struct {
+	Address net.IP
+	Port    uint64
+}
+
 // AddressSet represent an address set.
 type AddressSet struct {
 	Name      string
@@ -378,6 +378,7 @@ func (d Nftables) NetworkClear(networkName string, _ bool, _ []uint) error {
 		"fwd", "pstrt", "in", "out", // Chains used for network operation rules.
 		"aclin", "aclout", "aclfwd", "acl", // Chains used by ACL rules.
 		"fwdprert", "fwdout", "fwdpstrt", // Chains used by Address Forward rules.
+		"lbprert", "lbout", "lbpstrt", // Chains used by Load Balancer rules.
 		"egress", // Chains added for limits.priority option
 	}
 
@@ -1459,6 +1460,91 @@ func (d Nftables) NetworkApplyForwards(networkName string, rules []AddressForwar
 	return nil
 }
 
+// NetworkApplyLoadBalancers apply network load balancer rules to firewall.
+func (d Nftables) NetworkApplyLoadBalancers(networkName string, loadBalancers []LoadBalancer) error {
+	var dnatRules []map[string]any
+	var snatRules []map[string]any
+
+	// Only add a single masquerade rule per target, even if it's used by several listen ports.
+	snatTargets := map[string]struct{}{}
+
+	for i, lb := range loadBalancers {
+		err := validateLoadBalancer(&lb)
+		if err != nil {
+			return fmt.Errorf("Invalid load balancer %d: %w", i, err)
+		}
+
+		ipFamily := "ip"
+		if lb.ListenAddress.To4() == nil {
+			ipFamily = "ip6"
+		}
+
+		// Round-robin over the targets unless hashing the source address was requested.
+		selector := "numgen inc"
+		if lb.Hashed {
+			selector = fmt.Sprintf("jhash %s saddr", ipFamily)
+		}
+
+		dnatRules = append(dnatRules, map[string]any{
+			"ipFamily":      ipFamily,
+			"protocol":      lb.Protocol,
+			"listenAddress": lb.ListenAddress.String(),
+			"listenPort":    lb.ListenPort,
+			"selector":      selector,
+			"targetCount":   len(lb.Targets),
+			"targets":       loadBalancerTargetMap(&lb),
+		})
+
+		// Apply MASQUERADE rule for each target so that instances can reach the load balancer
+		// even when they are picked as the target.
+		for _, target := range lb.Targets {
+			key := fmt.Sprintf("%s/%s/%d", lb.Protocol, target.Address.String(), target.Port)
+			_, found := snatTargets[key]
+			if found {
+				continue
+			}
+
+			snatTargets[key] = struct{}{}
+			snatRules = append(snatRules, map[string]any{
+				"ipFamily":   ipFamily,
+				"protocol":   lb.Protocol,
+				"targetHost": target.Address.String(),
+				"targetPort": target.Port,
+			})
+		}
+	}
+
+	// Apply rules or remove chains if no rules generated.
+	if len(dnatRules) > 0 {
+		tplFields := map[string]any{
+			"namespace":      nftablesNamespace,
+			"chainSeparator": nftablesChainSeparator,
+			"family":         "inet",
+			"label":          networkName,
+			"dnatRules":      dnatRules,
+			"snatRules":      snatRules,
+		}
+
+		config := &strings.Builder{}
+		err := nftablesNetLoadBalancerNAT.Execute(config, tplFields)
+		if err != nil {
+			return fmt.Errorf("Failed running %q template: %w", nftablesNetLoadBalancerNAT.Name(), err)
+		}
+
+		err = subprocess.RunCommandWithFds(context.TODO(), strings.NewReader(config.String()), nil, "nft", "-f", "-")
+		if err != nil {
+			return err
+		}
+	} else {
+		err := d.removeChains([]string{"inet"}, networkName, "lbprert", "lbout", "lbpstrt")
+		if err != nil {
+			return fmt.Errorf("Failed clearing nftables load balancer rules for network %q: %w", networkName, err)
+		}
+	}
+
+	return nil
+}
+
 // NetworkApplyAddressSets creates or updates named nft sets for all address sets.
 func (d Nftables) NetworkApplyAddressSets(sets []AddressSet, nftTable string) error {
 	_, err := subprocess.RunCommand("nft", "create", "table", nftTable, nftablesNamespace)
@@ -31,6 +31,9 @@ chain pstrt{{.chainSeparator}}{{.networkName}} {
 	type nat hook postrouting priority 100; policy accept;
 
 	{{ range $ipFamily, $config := .rules }}
+	{{ range $config.Exclude }}
+	{{$ipFamily}} saddr {{$config.Subnet}} {{$ipFamily}} daddr {{.}} return
+	{{ end }}
 	{{ if $config.SNATAddress }}
 	{{$ipFamily}} saddr {{$config.Subnet}} {{$ipFamily}} daddr != {{$config.Subnet}} snat {{$config.SNATAddress}}
 	{{ else }}
@@ -115,6 +118,39 @@ table {{.family}} {{.namespace}} {
 }
 `))
 
+var nftablesNetLoadBalancerNAT = template.Must(template.New("nftablesNetLoadBalancerNAT").Parse(`
+add table {{.family}} {{.namespace}}
+add chain {{.family}} {{.namespace}} lbprert{{.chainSeparator}}{{.label}} {type nat hook prerouting priority -100; policy accept;}
+add chain {{.family}} {{.namespace}} lbout{{.chainSeparator}}{{.label}} {type nat hook output priority -100; policy accept;}
+add chain {{.family}} {{.namespace}} lbpstrt{{.chainSeparator}}{{.label}} {type nat hook postrouting priority 100; policy accept;}
+flush chain {{.family}} {{.namespace}} lbprert{{.chainSeparator}}{{.label}}
+flush chain {{.family}} {{.namespace}} lbout{{.chainSeparator}}{{.label}}
+flush chain {{.family}} {{.namespace}} lbpstrt{{.chainSeparator}}{{.label}}
+
+table {{.family}} {{.namespace}} {
+	chain lbprert{{.chainSeparator}}{{.label}} {
+		type nat hook prerouting priority -100; policy accept;
+		{{ range .dnatRules }}
+		{{.ipFamily}} daddr {{.listenAddress}} {{.protocol}} dport {{.listenPort}} dnat {{.ipFamily}} addr . port to {{.selector}} mod {{.targetCount}} map { {{.targets}} }
+		{{ end }}
+	}
+
+	chain lbout{{.chainSeparator}}{{.label}} {
+		type nat hook output priority -100; policy accept;
+		{{ range .dnatRules }}
+		{{.ipFamily}} daddr {{.listenAddress}} {{.protocol}} dport {{.listenPort}} dnat {{.ipFamily}} addr . port to {{.selector}} mod {{.targetCount}} map { {{.targets}} }
+		{{ end }}
+	}
+
+	chain lbpstrt{{.chainSeparator}}{{.label}} {
+		type nat hook postrouting priority 100; policy accept;
+		{{ range .snatRules }}
+		{{.ipFamily}} saddr {{.targetHost}} {{.ipFamily}} daddr {{.targetHost}} {{.protocol}} dport {{.targetPort}} masquerade
+		{{ end }}
+	}
+}
+`))
+
 var nftablesNetACLSetup = template.Must(template.New("nftablesNetACLSetup").Parse(`
 add table {{.family}} {{.namespace}}
 add chain {{.family}} {{.namespace}} acl{{.chainSeparator}}{{.networkName}}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"strings"
 )
 
 // portRangesFromSlice checks if adjacent indices in the given slice contain consecutive
@@ -112,6 +113,44 @@ func getOptimisedDNATRanges(forward *AddressForward) map[[2]uint64][2]uint64 {
 	return snatRules
 }
 
+// validateLoadBalancer checks that the load balancer is complete and that its targets match the listen address family.
+func validateLoadBalancer(lb *LoadBalancer) error {
+	if lb.ListenAddress == nil {
+		return errors.New("Listen address is required")
+	}
+
+	if lb.Protocol == "" || lb.ListenPort == 0 {
+		return errors.New("Protocol and listen port are required")
+	}
+
+	if len(lb.Targets) == 0 {
+		return errors.New("At least one target is required")
+	}
+
+	listenIsIP4 := lb.ListenAddress.To4() != nil
+	for i, target := range lb.Targets {
+		if target.Address == nil || target.Port == 0 {
+			return fmt.Errorf("Target %d requires an address and a port", i)
+		}
+
+		if (target.Address.To4() != nil) != listenIsIP4 {
+			return fmt.Errorf("Target %d doesn't match the IP version of the listen address", i)
+		}
+	}
+
+	return nil
+}
+
+// loadBalancerTargetMap returns the nftables map elements associating each target index with its address and port.
+func loadBalancerTargetMap(lb *LoadBalancer) string {
+	elements := make([]string, 0, len(lb.Targets))
+	for i, target := range lb.Targets {
+		elements = append(elements, fmt.Sprintf("%d : %s . %d", i, target.Address.String(), target.Port))
+	}
+
+	return strings.Join(elements, ", ")
+}
+
 // subnetMask returns the subnet mask of the given network as a string. Both IPv4 and IPv6 are handled.
 func subnetMask(ipNet *net.IPNet) string {
 	if ipNet.IP.To4() != nil {
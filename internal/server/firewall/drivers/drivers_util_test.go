@@ -2,6 +2,7 @@ package drivers
 
 import (
 	"log"
+	"net"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -145,3 +146,66 @@ func Test_getOptimisedSNATRanges(t *testing.T) {
 		assert.Equal(t, tt.expected, actual)
 	}
 }
+
+func Test_validateLoadBalancer(t *testing.T) {
+	tests := []struct {
+		name    string
+		lb      *LoadBalancer
+		wantErr bool
+	}{
+		{
+			name: "Valid",
+			lb: &LoadBalancer{
+				ListenAddress: net.ParseIP("192.0.2.1"),
+				Protocol:      "tcp",
+				ListenPort:    80,
+				Targets:       []LoadBalancerTarget{{Address: net.ParseIP("10.0.0.2"), Port: 8080}},
+			},
+		},
+		{
+			name: "No targets",
+			lb: &LoadBalancer{
+				ListenAddress: net.ParseIP("192.0.2.1"),
+				Protocol:      "tcp",
+				ListenPort:    80,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Mixed IP versions",
+			lb: &LoadBalancer{
+				ListenAddress: net.ParseIP("192.0.2.1"),
+				Protocol:      "udp",
+				ListenPort:    53,
+				Targets:       []LoadBalancerTarget{{Address: net.ParseIP("fd42::2"), Port: 53}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		err := validateLoadBalancer(tt.lb)
+		if tt.wantErr {
+			assert.Error(t, err, tt.name)
+		} else {
+			assert.NoError(t, err, tt.name)
+		}
+	}
+}
+
+func Test_loadBalancerTargetMap(t *testing.T) {
+	lb := &LoadBalancer{
+		Targets: []LoadBalancerTarget{
+			{Address: net.ParseIP("10.0.0.2"), Port: 80},
+			{Address: net.ParseIP("10.0.0.3"), Port: 8080},
+		},
+	}
+
+	assert.Equal(t, "0 : 10.0.0.2 . 80, 1 : 10.0.0.3 . 8080", loadBalancerTargetMap(lb))
+
+	lb = &LoadBalancer{
+		Targets: []LoadBalancerTarget{{Address: net.ParseIP("fd42::2"), Port: 443}},
+	}
+
+	assert.Equal(t, "0 : fd42::2 . 443", loadBalancerTargetMap(lb))
+}
@@ -164,6 +164,11 @@ func (d Xtables) networkForwardIPTablesComment(networkName string) string {
 	return fmt.Sprintf("Incus network-forward %s", networkName)
 }
 
+// networkLoadBalancerIPTablesComment returns the iptables comment that is added to each network load balancer related rule.
+func (d Xtables) networkLoadBalancerIPTablesComment(networkName string) string {
+	return fmt.Sprintf("Incus network-load-balancer %s", networkName)
+}
+
 // networkSetupNICFilteringChain creates the NIC filtering chain if it doesn't exist, and adds the jump rules to
 // the INPUT and FORWARD filter chains. Must be called after networkSetupForwardingPolicy so that the rules are
 // prepended before the default forwarding policy rules.
@@ -339,7 +344,8 @@ func (d Xtables) networkSetupForwardingPolicy(networkName string, ipVersion uint
 
 // networkSetupOutboundNAT configures outbound NAT.
 // If srcIP is non-nil then SNAT is used with the specified address, otherwise MASQUERADE mode is used.
-func (d Xtables) networkSetupOutboundNAT(networkName string, subnet *net.IPNet, srcIP net.IP, appendRule bool) error {
+// Traffic towards the excluded subnets keeps its source address.
+func (d Xtables) networkSetupOutboundNAT(networkName string, subnet *net.IPNet, srcIP net.IP, exclude []*net.IPNet, appendRule bool) error {
 	family := uint(4)
 	if subnet.IP.To4() == nil {
 		family = 6
@@ -359,13 +365,40 @@ func (d Xtables) networkSetupOutboundNAT(networkName string, subnet *net.IPNet,
 
 	comment := d.networkIPTablesComment(networkName)
 
+	addRule := func(ruleArgs ...string) error {
+		if appendRule {
+			return d.iptablesAppend(family, comment, "nat", "POSTROUTING", ruleArgs...)
+		}
+
+		return d.iptablesPrepend(family, comment, "nat", "POSTROUTING", ruleArgs...)
+	}
+
+	// The exclusions must come before the NAT rule, so are added first when appending and last when prepending.
+	addExclusions := func() error {
+		for _, excludeSubnet := range exclude {
+			err := addRule("-s", subnet.String(), "-d", excludeSubnet.String(), "-j", "RETURN")
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
 	if appendRule {
-		err := d.iptablesAppend(family, comment, "nat", "POSTROUTING", args...)
+		err := addExclusions()
 		if err != nil {
 			return err
 		}
-	} else {
-		err := d.iptablesPrepend(family, comment, "nat", "POSTROUTING", args...)
+	}
+
+	err := addRule(args...)
+	if err != nil {
+		return err
+	}
+
+	if !appendRule {
+		err = addExclusions()
 		if err != nil {
 			return err
 		}
@@ -441,14 +474,14 @@ func (d Xtables) networkSetupDHCPv4Checksum(networkName string) error {
 // NetworkSetup configure network firewall.
 func (d Xtables) NetworkSetup(networkName string, opts Opts) error {
 	if opts.SNATV4 != nil {
-		err := d.networkSetupOutboundNAT(networkName, opts.SNATV4.Subnet, opts.SNATV4.SNATAddress, opts.SNATV4.Append)
+		err := d.networkSetupOutboundNAT(networkName, opts.SNATV4.Subnet, opts.SNATV4.SNATAddress, opts.SNATV4.Exclude, opts.SNATV4.Append)
 		if err != nil {
 			return err
 		}
 	}
 
 	if opts.SNATV6 != nil {
-		err := d.networkSetupOutboundNAT(networkName, opts.SNATV6.Subnet, opts.SNATV6.SNATAddress, opts.SNATV6.Append)
+		err := d.networkSetupOutboundNAT(networkName, opts.SNATV6.Subnet, opts.SNATV6.SNATAddress, opts.SNATV6.Exclude, opts.SNATV6.Append)
 		if err != nil {
 			return err
 		}
@@ -753,10 +786,11 @@ func (d Xtables) NetworkClear(networkName string, delete bool, ipVersions []uint
 	comments := []string{
 		d.networkIPTablesComment(networkName),
 		d.networkForwardIPTablesComment(networkName),
+		d.networkLoadBalancerIPTablesComment(networkName),
 	}
 
 	for _, ipVersion := range ipVersions {
-		// Clear any rules associated to the network and network address forwards.
+		// Clear any rules associated to the network, network address forwards and load balancers.
 		err := d.iptablesClear(ipVersion, comments, "filter", "mangle", "nat")
 		if err != nil {
 			return err
@@ -1659,6 +1693,116 @@ func (d Xtables) NetworkApplyForwards(networkName string, rules []AddressForward
 	return nil
 }
 
+// NetworkApplyLoadBalancers apply network load balancer rules to firewall.
+func (d Xtables) NetworkApplyLoadBalancers(networkName string, loadBalancers []LoadBalancer) error {
+	// Validate all load balancers first.
+	for i, lb := range loadBalancers {
+		err := validateLoadBalancer(&lb)
+		if err != nil {
+			return fmt.Errorf("Invalid load balancer %d: %w", i, err)
+		}
+
+		if lb.Hashed {
+			return errors.New("Hashed load balancing is not supported under xtables")
+		}
+	}
+
+	comment := d.networkLoadBalancerIPTablesComment(networkName)
+
+	clearNetworkLoadBalancers := func() error {
+		for _, ipVersion := range []uint{4, 6} {
+			err := d.iptablesClear(ipVersion, []string{comment}, "nat")
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	// Clear any load balancer rules associated to the network.
+	err := clearNetworkLoadBalancers()
+	if err != nil {
+		return err
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	// Clear all network load balancers if we fail, otherwise the load balancers are only partially applied.
+	reverter.Add(func() {
+		err := clearNetworkLoadBalancers()
+		if err != nil {
+			logger.Error("Failed to clear firewall rules after failing to apply network load balancers", logger.Ctx{"network_name": networkName, "error": err})
+		}
+	})
+
+	// Only add a single MASQUERADE rule per target, even if it's used by several listen ports.
+	masqueradeTargets := map[string]struct{}{}
+
+	for _, lb := range loadBalancers {
+		ipVersion := uint(4)
+		if lb.ListenAddress.To4() == nil {
+			ipVersion = 6
+		}
+
+		listenAddressStr := lb.ListenAddress.String()
+		listenPortStr := fmt.Sprintf("%d", lb.ListenPort)
+		targetsLen := len(lb.Targets)
+
+		// Rules are prepended, so go through the targets backwards for the first target to be matched first.
+		// Each target but the last one only picks every nth connection among those left by the previous
+		// ones, which spreads the connections evenly over all the targets.
+		for i := targetsLen - 1; i >= 0; i-- {
+			target := lb.Targets[i]
+			targetAddressStr := target.Address.String()
+
+			targetDest := fmt.Sprintf("%s:%d", targetAddressStr, target.Port)
+			if ipVersion == 6 {
+				targetDest = fmt.Sprintf("[%s]:%d", targetAddressStr, target.Port)
+			}
+
+			args := []string{"-p", lb.Protocol, "--destination", listenAddressStr, "--dport", listenPortStr}
+			if i < targetsLen-1 {
+				args = append(args, "-m", "statistic", "--mode", "nth", "--every", fmt.Sprintf("%d", targetsLen-i), "--packet", "0")
+			}
+
+			args = append(args, "-j", "DNAT", "--to-destination", targetDest)
+
+			// outbound <-> instance.
+			err := d.iptablesPrepend(ipVersion, comment, "nat", "PREROUTING", args...)
+			if err != nil {
+				return err
+			}
+
+			// host <-> instance.
+			err = d.iptablesPrepend(ipVersion, comment, "nat", "OUTPUT", args...)
+			if err != nil {
+				return err
+			}
+
+			key := fmt.Sprintf("%s/%s/%d", lb.Protocol, targetAddressStr, target.Port)
+			_, found := masqueradeTargets[key]
+			if found {
+				continue
+			}
+
+			masqueradeTargets[key] = struct{}{}
+
+			// instance <-> instance.
+			// Requires instance's bridge port has hairpin mode enabled when br_netfilter is loaded.
+			err = d.iptablesPrepend(ipVersion, comment, "nat", "POSTROUTING", "-p", lb.Protocol, "--source", targetAddressStr, "--destination", targetAddressStr, "--dport", fmt.Sprintf("%d", target.Port), "-j", "MASQUERADE")
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	reverter.Success()
+
+	return nil
+}
+
 // NetworkApplyAddressSets isn't supported under xtables.
 func (d Xtables) NetworkApplyAddressSets(sets []AddressSet, nftTable string) error {
 	return errors.New("Address sets aren't supported by xtables firewalling")
@@ -1044,9 +1044,20 @@ func (d *common) canMigrate(inst instance.Instance) string {
 	}
 
 	// Check if set up for live migration.
-	// Limit automatic live-migration to virtual machines for now.
-	if inst.Type() == instancetype.VM && util.IsTrue(config["migration.stateful"]) {
-		return "live-migrate"
+	// Containers additionally need CRIU to be usable on this system.
+	if util.IsTrue(config["migration.stateful"]) {
+		if inst.Type() == instancetype.VM {
+			return "live-migrate"
+		}
+
+		if inst.Type() == instancetype.Container {
+			err := CheckCRIU()
+			if err == nil {
+				return "live-migrate"
+			}
+
+			logger.Warn("Instance will not be live-migrated", logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "err": err})
+		}
 	}
 
 	return "migrate"
@@ -23,6 +23,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -64,6 +65,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/metrics"
 	localMigration "github.com/lxc/incus/v6/internal/server/migration"
 	"github.com/lxc/incus/v6/internal/server/network"
+	"github.com/lxc/incus/v6/internal/server/oci"
 	"github.com/lxc/incus/v6/internal/server/operations"
 	"github.com/lxc/incus/v6/internal/server/project"
 	"github.com/lxc/incus/v6/internal/server/response"
@@ -1869,6 +1871,11 @@ func (d *lxc) handleIdmappedStorage() (idmap.StorageType, *idmap.Set, error) {
 		return idmap.StorageTypeNone, nil, errors.New("Container is protected against filesystem shifting")
 	}
 
+	// Lazily pulled root filesystems can't be shifted without fetching all of them.
+	if oci.IsLazy(d.Path()) {
+		return idmap.StorageTypeNone, nil, errors.New("Lazily pulled containers require support for idmapped mounts")
+	}
+
 	d.logger.Debug("Container idmap changed, remapping")
 	d.updateProgress("Remapping container filesystem")
 
@@ -2050,6 +2057,40 @@ func (d *lxc) startCommon() (string, []func() error, error) {
 
 	reverter.Add(func() { _ = d.unmount() })
 
+	// Handle lazily pulled root filesystems.
+	if oci.IsLazy(d.Path()) {
+		if d.localConfig["volatile.container.oci.lazy"] == "pulled" {
+			// All the layers are available locally, turn the root filesystem into a regular one.
+			d.updateProgress("Flattening container filesystem")
+
+			err = oci.FlattenLazyRootfs(d.Path())
+			if err != nil {
+				return "", nil, fmt.Errorf("Failed flattening lazily pulled root filesystem: %w", err)
+			}
+
+			err = d.VolatileSet(map[string]string{"volatile.container.oci.lazy": ""})
+			if err != nil {
+				return "", nil, err
+			}
+
+			err = oci.StopLayerStore()
+			if err != nil {
+				d.logger.Warn("Failed stopping the OCI layer store", logger.Ctx{"err": err})
+			}
+		} else {
+			postStartHooks = append(postStartHooks, func() error {
+				go d.prefetchLazyRootfs()
+
+				return nil
+			})
+		}
+	} else if d.localConfig["volatile.container.oci.lazy"] != "" {
+		err = d.VolatileSet(map[string]string{"volatile.container.oci.lazy": ""})
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
 	idmapType, nextIdmap, err := d.handleIdmappedStorage()
 	if err != nil {
 		return "", nil, fmt.Errorf("Failed to handle idmapped storage: %w", err)
@@ -5328,10 +5369,15 @@ func (d *lxc) Export(metaWriter io.Writer, rootfsWriter io.Writer, properties ma
 		return nil, errors.New("Cannot export a running instance as an image")
 	}
 
+	err := storagePools.InstanceLazyRootfsCheck(d)
+	if err != nil {
+		return nil, err
+	}
+
 	d.logger.Info("Exporting instance", ctxMap)
 
 	// Start the storage.
-	_, err := d.mount()
+	_, err = d.mount()
 	if err != nil {
 		d.logger.Error("Failed exporting instance", ctxMap)
 		return nil, err
@@ -5596,6 +5642,31 @@ func getCRIULogErrors(imagesDir string, method string) (string, error) {
 	return strings.Join(ret, "\n"), nil
 }
 
+// criuSupported records that CRIU can checkpoint and restore containers on this system.
+// Only successful checks are cached, so that installing or fixing CRIU is picked up without a restart.
+var criuSupported atomic.Bool
+
+// CheckCRIU returns an error if containers can't be checkpointed and restored (and so live-migrated) on this system.
+func CheckCRIU() error {
+	if criuSupported.Load() {
+		return nil
+	}
+
+	_, err := exec.LookPath("criu")
+	if err != nil {
+		return errors.New("CRIU isn't installed")
+	}
+
+	_, err = subprocess.RunCommand("criu", "check")
+	if err != nil {
+		return fmt.Errorf("CRIU isn't supported on this system: %w", err)
+	}
+
+	criuSupported.Store(true)
+
+	return nil
+}
+
 // Check if CRIU supports pre-dumping and number of pre-dump iterations.
 func (d *lxc) migrationSendCheckForPreDumpSupport() (bool, int) {
 	// Check if this architecture/kernel/criu combination supports pre-copy dirty memory tracking feature.
@@ -5690,6 +5761,13 @@ func (d *lxc) MigrateSend(args instance.MigrateSendArgs) error {
 
 	var stateConn io.ReadWriteCloser
 	if args.Live {
+		err = CheckCRIU()
+		if err != nil {
+			err = fmt.Errorf("Unable to live-migrate the container: %w", err)
+			op.Done(err)
+			return err
+		}
+
 		stateConn, err = args.StateConn(connectionsCtx)
 		if err != nil {
 			op.Done(err)
@@ -5707,11 +5785,13 @@ func (d *lxc) MigrateSend(args instance.MigrateSendArgs) error {
 	clusterMove := args.ClusterMoveSourceName != ""
 	storageMove := args.StoragePool != ""
 
-	// The refresh argument passed to MigrationTypes() is always set to false here.
-	// The migration source/sender doesn't need to care whether or not it's doing a refresh as the migration
-	// sink/receiver will know this, and adjust the migration types accordingly.
-	// The same applies for clusterMove and storageMove, which are set to the most optimized defaults.
-	poolMigrationTypes := pool.MigrationTypes(storagePools.InstanceContentType(d), false, args.Snapshots, true, false)
+	poolMigrationTypes, err := storagePools.InstanceMigrationTypes(pool, d, args.Snapshots)
+	if err != nil {
+		err := fmt.Errorf("Failed getting source migration types: %w", err)
+		op.Done(err)
+		return err
+	}
+
 	if len(poolMigrationTypes) == 0 {
 		err := errors.New("No source migration types available")
 		op.Done(err)
@@ -6329,6 +6409,14 @@ func (d *lxc) MigrateReceive(args instance.MigrateReceiveArgs) error {
 		}
 	}
 
+	// Fail early if the container state can't be restored here.
+	if criuType != nil && *criuType == migration.CRIUType_CRIU_RSYNC {
+		err = CheckCRIU()
+		if err != nil {
+			return fmt.Errorf("Unable to receive the live-migrated container: %w", err)
+		}
+	}
+
 	// When doing a cluster same-name move we cannot load the storage pool using the instance's volume DB
 	// record because it may be associated to the wrong cluster member. Instead we ascertain the pool to load
 	// using the instance's root disk device.
@@ -8019,6 +8107,24 @@ func (d *lxc) getStorageType() (string, error) {
 	return pool.Driver().Info().Name, nil
 }
 
+// prefetchLazyRootfs fetches whatever wasn't accessed yet of a lazily pulled root filesystem.
+// Once done, the root filesystem is flattened on the next start.
+func (d *lxc) prefetchLazyRootfs() {
+	err := oci.PrefetchLazyRootfs(d.state.ShutdownCtx, d.Path())
+	if err != nil {
+		if !errors.Is(err, oci.ErrPrefetchRunning) {
+			d.logger.Warn("Failed fetching lazily pulled root filesystem", logger.Ctx{"err": err})
+		}
+
+		return
+	}
+
+	err = d.VolatileSet(map[string]string{"volatile.container.oci.lazy": "pulled"})
+	if err != nil {
+		d.logger.Warn("Failed recording lazily pulled root filesystem as fetched", logger.Ctx{"err": err})
+	}
+}
+
 // mount the instance's rootfs volume if needed.
 func (d *lxc) mount() (*storagePools.MountInfo, error) {
 	pool, err := d.getStoragePool()
@@ -8040,6 +8146,15 @@ func (d *lxc) mount() (*storagePools.MountInfo, error) {
 		return nil, err
 	}
 
+	// Lazily pulled root filesystems are assembled on top of the volume.
+	if oci.IsLazy(d.Path()) {
+		err = oci.MountLazyRootfs(d.Path())
+		if err != nil {
+			_ = pool.UnmountInstance(d, nil)
+			return nil, err
+		}
+	}
+
 	return mountInfo, nil
 }
 
@@ -8059,11 +8174,32 @@ func (d *lxc) unmount() error {
 		return nil
 	}
 
+	lazy := oci.IsLazy(d.Path())
+	if lazy {
+		err = oci.UnmountLazyRootfs(d.Path())
+		if err != nil {
+			return err
+		}
+	}
+
 	err = pool.UnmountInstance(d, nil)
 	if err != nil {
+		// Restore the root filesystem for the remaining users of the volume.
+		if lazy && errors.Is(err, storageDrivers.ErrInUse) {
+			_ = oci.MountLazyRootfs(d.Path())
+		}
+
 		return err
 	}
 
+	// Stop the layer store once no lazily pulled root filesystem uses it anymore.
+	if lazy {
+		err = oci.StopLayerStore()
+		if err != nil {
+			d.logger.Warn("Failed stopping the OCI layer store", logger.Ctx{"err": err})
+		}
+	}
+
 	return nil
 }
 
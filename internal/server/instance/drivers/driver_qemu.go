@@ -4742,7 +4742,7 @@ func (d *qemu) addDriveConfig(qemuDev map[string]any, bootIndexes map[string]int
 		}
 
 		if driveConf.Limits != nil {
-			err = m.SetBlockThrottle(qemuDev["id"].(string), int(driveConf.Limits.ReadBytes), int(driveConf.Limits.WriteBytes), int(driveConf.Limits.ReadIOps), int(driveConf.Limits.WriteIOps))
+			err = m.SetBlockThrottle(qemuDev["id"].(string), int(driveConf.Limits.ReadBytes), int(driveConf.Limits.WriteBytes), int(driveConf.Limits.ReadIOps), int(driveConf.Limits.WriteIOps), driveConf.Limits.Group)
 			if err != nil {
 				return fmt.Errorf("Failed applying limits for disk device %q: %w", driveConf.DevName, err)
 			}
@@ -7188,12 +7188,13 @@ func (d *qemu) MigrateSend(args instance.MigrateSendArgs) error {
 	remoteClusterMove := clusterMove && pool.Driver().Info().Remote
 	storageMove := args.StoragePool != ""
 
-	// The refresh argument passed to MigrationTypes() is always set
-	// to false here. The migration source/sender doesn't need to care whether
-	// or not it's doing a refresh as the migration sink/receiver will know
-	// this, and adjust the migration types accordingly.
-	// The same applies for clusterMove and storageMove, which are set to the most optimized defaults.
-	poolMigrationTypes := pool.MigrationTypes(storagePools.InstanceContentType(d), false, args.Snapshots, true, false)
+	poolMigrationTypes, err := storagePools.InstanceMigrationTypes(pool, d, args.Snapshots)
+	if err != nil {
+		err := fmt.Errorf("Failed getting source migration types: %w", err)
+		op.Done(err)
+		return err
+	}
+
 	if len(poolMigrationTypes) == 0 {
 		err := errors.New("No source migration types available")
 		op.Done(err)
@@ -8959,7 +8960,7 @@ func (d *qemu) DeviceEventHandler(runConf *deviceConfig.RunConfig) error {
 
 		if mount.Limits != nil {
 			// Apply the limits.
-			err = m.SetBlockThrottle(devID, int(mount.Limits.ReadBytes), int(mount.Limits.WriteBytes), int(mount.Limits.ReadIOps), int(mount.Limits.WriteIOps))
+			err = m.SetBlockThrottle(devID, int(mount.Limits.ReadBytes), int(mount.Limits.WriteBytes), int(mount.Limits.ReadIOps), int(mount.Limits.WriteIOps), mount.Limits.Group)
 			if err != nil {
 				return fmt.Errorf("Failed applying limits for disk device %q: %w", mount.DevName, err)
 			}
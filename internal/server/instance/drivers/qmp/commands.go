@@ -1306,9 +1306,11 @@ func (m *Monitor) UpdateBlockSize(id string) error {
 }
 
 // SetBlockThrottle applies an I/O limit on a disk.
-func (m *Monitor) SetBlockThrottle(id string, bytesRead int, bytesWrite int, iopsRead int, iopsWrite int) error {
+// If a group is provided, the limits are shared with all other disks in the same throttle group.
+func (m *Monitor) SetBlockThrottle(id string, bytesRead int, bytesWrite int, iopsRead int, iopsWrite int, group string) error {
 	var args struct {
-		ID string `json:"id"`
+		ID    string `json:"id"`
+		Group string `json:"group,omitempty"`
 
 		Bytes      int `json:"bps"`
 		BytesRead  int `json:"bps_rd"`
@@ -1319,6 +1321,7 @@ func (m *Monitor) SetBlockThrottle(id string, bytesRead int, bytesWrite int, iop
 	}
 
 	args.ID = id
+	args.Group = group
 	args.BytesRead = bytesRead
 	args.BytesWrite = bytesWrite
 	args.IOPsRead = iopsRead
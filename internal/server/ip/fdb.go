@@ -0,0 +1,111 @@
+package ip
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+// FDB represents arguments for bridge forwarding database manipulation.
+type FDB struct {
+	DevName   string
+	MAC       net.HardwareAddr
+	DstIP     net.IP
+	Permanent bool
+}
+
+// netlinkNeigh returns the netlink neighbour of a forwarding database entry of the device itself (not of its
+// bridge), such as the remote VTEP entries of a VXLAN device.
+func (f *FDB) netlinkNeigh() (*netlink.Neigh, error) {
+	link, err := linkByName(f.DevName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &netlink.Neigh{
+		LinkIndex:    link.Attrs().Index,
+		Family:       unix.AF_BRIDGE,
+		Flags:        netlink.NTF_SELF,
+		State:        netlink.NUD_NOARP | netlink.NUD_PERMANENT,
+		HardwareAddr: f.MAC,
+		IP:           f.DstIP,
+	}, nil
+}
+
+// Add appends a forwarding database entry to the device.
+// Multiple entries can be added for the all-zero MAC address, traffic then being replicated to each destination.
+func (f *FDB) Add() error {
+	neigh, err := f.netlinkNeigh()
+	if err != nil {
+		return err
+	}
+
+	err = netlink.NeighAppend(neigh)
+	if err != nil {
+		return fmt.Errorf("Failed to add forwarding database entry %q (destination %q) to %q: %w", f.MAC, f.DstIP, f.DevName, err)
+	}
+
+	return nil
+}
+
+// Delete removes a forwarding database entry from the device.
+func (f *FDB) Delete() error {
+	neigh, err := f.netlinkNeigh()
+	if err != nil {
+		return err
+	}
+
+	err = netlink.NeighDel(neigh)
+	if err != nil {
+		return fmt.Errorf("Failed to delete forwarding database entry %q (destination %q) from %q: %w", f.MAC, f.DstIP, f.DevName, err)
+	}
+
+	return nil
+}
+
+// Show lists the forwarding database entries of DevName. When DevName is a bridge, the entries of its ports are
+// included too, with DevName set to the port.
+func (f *FDB) Show() ([]FDB, error) {
+	link, err := linkByName(f.DevName)
+	if err != nil {
+		return nil, err
+	}
+
+	index := link.Attrs().Index
+
+	netlinkNeighbours, err := netlink.NeighList(0, unix.AF_BRIDGE)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get forwarding database entries of %q: %w", f.DevName, err)
+	}
+
+	names := map[int]string{index: f.DevName}
+	entries := []FDB{}
+
+	for _, neighbour := range netlinkNeighbours {
+		if neighbour.LinkIndex != index && neighbour.MasterIndex != index {
+			continue
+		}
+
+		name, found := names[neighbour.LinkIndex]
+		if !found {
+			port, err := netlink.LinkByIndex(neighbour.LinkIndex)
+			if err != nil {
+				continue
+			}
+
+			name = port.Attrs().Name
+			names[neighbour.LinkIndex] = name
+		}
+
+		entries = append(entries, FDB{
+			DevName:   name,
+			MAC:       neighbour.HardwareAddr,
+			DstIP:     neighbour.IP,
+			Permanent: neighbour.State&netlink.NUD_PERMANENT != 0,
+		})
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,22 @@
+package ip
+
+import (
+	"github.com/vishvananda/netlink"
+)
+
+// Wireguard represents arguments for link device of type wireguard.
+type Wireguard struct {
+	Link
+}
+
+// Add adds new virtual link.
+func (w *Wireguard) Add() error {
+	attrs, err := w.netlinkAttrs()
+	if err != nil {
+		return err
+	}
+
+	return w.addLink(&netlink.Wireguard{
+		LinkAttrs: attrs,
+	})
+}
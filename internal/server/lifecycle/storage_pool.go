@@ -10,9 +10,12 @@ type StoragePoolAction string
 
 // All supported lifecycle events for storage pools.
 const (
-	StoragePoolCreated = StoragePoolAction(api.EventLifecycleStoragePoolCreated)
-	StoragePoolDeleted = StoragePoolAction(api.EventLifecycleStoragePoolDeleted)
-	StoragePoolUpdated = StoragePoolAction(api.EventLifecycleStoragePoolUpdated)
+	StoragePoolCreated       = StoragePoolAction(api.EventLifecycleStoragePoolCreated)
+	StoragePoolDeleted       = StoragePoolAction(api.EventLifecycleStoragePoolDeleted)
+	StoragePoolUpdated       = StoragePoolAction(api.EventLifecycleStoragePoolUpdated)
+	StoragePoolUsageCritical = StoragePoolAction(api.EventLifecycleStoragePoolUsageCritical)
+	StoragePoolUsageResolved = StoragePoolAction(api.EventLifecycleStoragePoolUsageResolved)
+	StoragePoolUsageWarning  = StoragePoolAction(api.EventLifecycleStoragePoolUsageWarning)
 )
 
 // Event creates the lifecycle event for an action on an storage pool.
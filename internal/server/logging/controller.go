@@ -109,6 +109,10 @@ func LoggerFromType(s *state.State, loggerName string, loggerType string) (Logge
 		loggerClient, err = NewLokiLogger(s, loggerName)
 	case "webhook":
 		loggerClient, err = NewWebhookLogger(s, loggerName)
+	case "otlp":
+		loggerClient, err = NewOTLPLogger(s, loggerName)
+	case "nats":
+		loggerClient, err = NewNATSLogger(s, loggerName)
 	default:
 		return nil, fmt.Errorf("%s is not supported logger type", loggerType)
 	}
@@ -0,0 +1,351 @@
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+
+	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/internal/util"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+	localtls "github.com/lxc/incus/v6/shared/tls"
+)
+
+// natsGlobalToken is the subject token used for events which don't belong to a project.
+const natsGlobalToken = "_global"
+
+// NATSLogger represents a NATS logger.
+//
+// Events are first written to an on-disk spool and only removed from it once the server
+// confirmed their reception, providing at-least-once delivery across server outages and restarts.
+// Reception is confirmed through a flush of the connection (the server processed the message) or,
+// when using JetStream, through the publish acknowledgement (the message was persisted).
+type NATSLogger struct {
+	common
+
+	address       string
+	username      string
+	password      string
+	caCert        string
+	subjectPrefix string
+	jetStream     bool
+	spoolSize     int
+
+	timeout    time.Duration
+	retryDelay time.Duration
+
+	conn   *nats.Conn
+	js     jetstream.JetStream
+	ctx    context.Context
+	spool  *spool
+	notify chan struct{}
+	quit   chan struct{}
+	once   sync.Once
+	wg     sync.WaitGroup
+}
+
+// NewNATSLogger returns a logger of nats type.
+func NewNATSLogger(s *state.State, name string) (*NATSLogger, error) {
+	address, username, password, caCert, subjectPrefix, jetStream, spoolSize := s.GlobalConfig.LoggingConfigForNATS(name)
+
+	// Set defaults.
+	if subjectPrefix == "" {
+		subjectPrefix = "incus"
+	}
+
+	if spoolSize == 0 {
+		spoolSize = 10000
+	}
+
+	return &NATSLogger{
+		common:        newCommonLogger(name, s.GlobalConfig),
+		address:       address,
+		username:      username,
+		password:      password,
+		caCert:        caCert,
+		subjectPrefix: subjectPrefix,
+		jetStream:     jetStream,
+		spoolSize:     spoolSize,
+		timeout:       10 * time.Second,
+		retryDelay:    10 * time.Second,
+		ctx:           s.ShutdownCtx,
+		notify:        make(chan struct{}, 1),
+		quit:          make(chan struct{}),
+	}, nil
+}
+
+// Start opens the spool and starts delivering events.
+func (l *NATSLogger) Start() error {
+	var err error
+
+	l.spool, err = newSpool(util.VarPath("logging", l.name), l.spoolSize)
+	if err != nil {
+		return err
+	}
+
+	l.wg.Add(1)
+	go l.run()
+
+	return nil
+}
+
+// Stop stops the logger, leaving undelivered events in the spool.
+func (l *NATSLogger) Stop() {
+	l.once.Do(func() { close(l.quit) })
+	l.wg.Wait()
+}
+
+// Validate checks whether the logger configuration is correct.
+func (l *NATSLogger) Validate() error {
+	if l.address == "" {
+		return fmt.Errorf("%s: Address cannot be empty", l.name)
+	}
+
+	u, err := url.Parse(l.address)
+	if err != nil {
+		return fmt.Errorf("%s: Invalid address: %w", l.name, err)
+	}
+
+	if u.Scheme != "nats" && u.Scheme != "tls" {
+		return fmt.Errorf("%s: Address must use the nats or tls scheme", l.name)
+	}
+
+	if strings.ContainsAny(l.subjectPrefix, " \t*>") {
+		return fmt.Errorf("%s: Invalid subject prefix %q", l.name, l.subjectPrefix)
+	}
+
+	return nil
+}
+
+// HandleEvent handles the event received from the internal event listener.
+func (l *NATSLogger) HandleEvent(event api.Event) {
+	if !l.processEvent(event) {
+		return
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	err = l.spool.push(data)
+	if err != nil {
+		logger.Warn("Failed spooling event", logger.Ctx{"logger": l.name, "err": err})
+		return
+	}
+
+	// Wake up the sender.
+	select {
+	case l.notify <- struct{}{}:
+	default:
+	}
+}
+
+// subject returns the subject to publish the event to, in the form <prefix>.<type>.<project>.
+func (l *NATSLogger) subject(event api.Event) string {
+	project := event.Project
+
+	if project == "" && event.Type == api.EventTypeLifecycle {
+		lifecycleEvent := api.EventLifecycle{}
+
+		err := json.Unmarshal(event.Metadata, &lifecycleEvent)
+		if err == nil {
+			project = lifecycleEvent.Project
+		}
+	}
+
+	if project == "" {
+		project = natsGlobalToken
+	}
+
+	return strings.Join([]string{l.subjectPrefix, natsSubjectToken(event.Type), natsSubjectToken(project)}, ".")
+}
+
+// natsSubjectToken replaces the characters which aren't allowed in a NATS subject token.
+func natsSubjectToken(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '.', '*', '>', ' ', '\t', '\r', '\n':
+			return '_'
+		}
+
+		return r
+	}, s)
+}
+
+func (l *NATSLogger) run() {
+	defer func() {
+		l.disconnect()
+		l.wg.Done()
+	}()
+
+	for {
+		err := l.deliver()
+		if err != nil {
+			logger.Warn("Failed delivering events, will retry", logger.Ctx{"logger": l.name, "address": l.address, "err": err})
+
+			l.disconnect()
+
+			select {
+			case <-l.ctx.Done():
+				return
+			case <-l.quit:
+				return
+			case <-time.After(l.retryDelay):
+				continue
+			}
+		}
+
+		select {
+		case <-l.ctx.Done():
+			return
+		case <-l.quit:
+			return
+		case <-l.notify:
+		}
+	}
+}
+
+// deliver publishes all the spooled events in order, removing each of them once confirmed.
+func (l *NATSLogger) deliver() error {
+	for {
+		select {
+		case <-l.ctx.Done():
+			return nil
+		case <-l.quit:
+			return nil
+		default:
+		}
+
+		seq, data, ok, err := l.spool.peek()
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			return nil
+		}
+
+		event := api.Event{}
+		err = json.Unmarshal(data, &event)
+		if err != nil {
+			// Drop corrupted entries rather than blocking the queue.
+			logger.Warn("Dropping invalid spooled event", logger.Ctx{"logger": l.name, "err": err})
+
+			err = l.spool.remove(seq)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if l.conn == nil {
+			err = l.connect()
+			if err != nil {
+				return err
+			}
+		}
+
+		err = l.publish(l.subject(event), data)
+		if err != nil {
+			return err
+		}
+
+		err = l.spool.remove(seq)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// connect establishes a new connection to the NATS server.
+func (l *NATSLogger) connect() error {
+	opts := []nats.Option{
+		nats.Name(fmt.Sprintf("incus/%s", version.Version)),
+		nats.Timeout(l.timeout),
+
+		// Reconnections are handled by the delivery loop, so that no event is ever buffered outside of the spool.
+		nats.NoReconnect(),
+	}
+
+	if l.username != "" {
+		opts = append(opts, nats.UserInfo(l.username, l.password))
+	}
+
+	if l.caCert != "" {
+		tlsConfig, err := localtls.GetTLSConfigMem("", "", l.caCert, "", false)
+		if err != nil {
+			return err
+		}
+
+		opts = append(opts, nats.Secure(tlsConfig))
+	}
+
+	conn, err := nats.Connect(l.address, opts...)
+	if err != nil {
+		return err
+	}
+
+	if l.jetStream {
+		js, err := jetstream.New(conn)
+		if err != nil {
+			conn.Close()
+			return err
+		}
+
+		l.js = js
+	}
+
+	l.conn = conn
+
+	return nil
+}
+
+// disconnect closes the connection to the NATS server, if any.
+func (l *NATSLogger) disconnect() {
+	if l.conn == nil {
+		return
+	}
+
+	l.conn.Close()
+	l.conn = nil
+	l.js = nil
+}
+
+// publish sends the payload to the subject and waits for the server to confirm it.
+func (l *NATSLogger) publish(subject string, payload []byte) error {
+	if l.js != nil {
+		ctx, cancel := context.WithTimeout(l.ctx, l.timeout)
+		defer cancel()
+
+		_, err := l.js.Publish(ctx, subject, payload)
+		if err != nil {
+			return fmt.Errorf("Failed publishing to JetStream: %w", err)
+		}
+
+		return nil
+	}
+
+	err := l.conn.Publish(subject, payload)
+	if err != nil {
+		return err
+	}
+
+	// Wait for the server to have processed the message.
+	err = l.conn.FlushTimeout(l.timeout)
+	if err != nil {
+		return fmt.Errorf("Failed waiting for server confirmation: %w", err)
+	}
+
+	return nil
+}
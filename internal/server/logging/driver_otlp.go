@@ -0,0 +1,439 @@
+package logging
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/shared/api"
+	localtls "github.com/lxc/incus/v6/shared/tls"
+)
+
+const (
+	otlpProtocolHTTP = "http"
+	otlpProtocolGRPC = "grpc"
+
+	otlpGRPCExportMethod = "/opentelemetry.proto.collector.logs.v1.LogsService/Export"
+)
+
+// otlpRawCodec passes already encoded protobuf messages through gRPC.
+type otlpRawCodec struct{}
+
+// Marshal returns the raw message.
+func (otlpRawCodec) Marshal(v any) ([]byte, error) {
+	b, ok := v.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("Unexpected message type %T", v)
+	}
+
+	return b, nil
+}
+
+// Unmarshal stores the raw message.
+func (otlpRawCodec) Unmarshal(data []byte, v any) error {
+	b, ok := v.(*[]byte)
+	if !ok {
+		return fmt.Errorf("Unexpected message type %T", v)
+	}
+
+	*b = data
+
+	return nil
+}
+
+// Name returns the codec name.
+func (otlpRawCodec) Name() string {
+	return "proto"
+}
+
+// OTLPLogger represents an OpenTelemetry (OTLP) logs client.
+type OTLPLogger struct {
+	common
+
+	batchSize int
+	batchWait time.Duration
+	timeout   time.Duration
+
+	address  string
+	protocol string
+	username string
+	password string
+	caCert   string
+	member   string
+	retry    int
+
+	client  *http.Client
+	conn    *grpc.ClientConn
+	ctx     context.Context
+	quit    chan struct{}
+	once    sync.Once
+	records chan otlpRecord
+	wg      sync.WaitGroup
+}
+
+// NewOTLPLogger returns a logger of otlp type.
+func NewOTLPLogger(s *state.State, name string) (*OTLPLogger, error) {
+	address, username, password, caCert, protocol, retry := s.GlobalConfig.LoggingConfigForOTLP(name)
+
+	// Set defaults.
+	if retry == 0 {
+		retry = 3
+	}
+
+	if protocol == "" {
+		protocol = otlpProtocolHTTP
+	}
+
+	// Identify the cluster member (or standalone server).
+	member := s.ServerName
+	if !s.ServerClustered {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, err
+		}
+
+		member = hostname
+	}
+
+	return &OTLPLogger{
+		common:    newCommonLogger(name, s.GlobalConfig),
+		batchSize: 10 * 1024,
+		batchWait: 1 * time.Second,
+		timeout:   10 * time.Second,
+		address:   address,
+		protocol:  protocol,
+		username:  username,
+		password:  password,
+		caCert:    caCert,
+		member:    member,
+		retry:     retry,
+		ctx:       s.ShutdownCtx,
+		records:   make(chan otlpRecord),
+		quit:      make(chan struct{}),
+	}, nil
+}
+
+// Start starts the OTLP logger.
+func (l *OTLPLogger) Start() error {
+	u, err := url.Parse(l.address)
+	if err != nil {
+		return err
+	}
+
+	switch l.protocol {
+	case otlpProtocolHTTP:
+		l.client = http.DefaultClient
+
+		if l.caCert != "" {
+			tlsConfig, err := localtls.GetTLSConfigMem("", "", l.caCert, "", false)
+			if err != nil {
+				return err
+			}
+
+			l.client = &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+		}
+
+		// Use the standard path if none was provided.
+		if u.Path == "" || u.Path == "/" {
+			u.Path = "/v1/logs"
+			l.address = u.String()
+		}
+
+	case otlpProtocolGRPC:
+		creds := insecure.NewCredentials()
+		if u.Scheme == "https" {
+			tlsConfig, err := localtls.GetTLSConfigMem("", "", l.caCert, "", false)
+			if err != nil {
+				return err
+			}
+
+			creds = credentials.NewTLS(tlsConfig)
+		}
+
+		l.conn, err = grpc.NewClient(u.Host, grpc.WithTransportCredentials(creds))
+		if err != nil {
+			return fmt.Errorf("Failed setting up gRPC client: %w", err)
+		}
+	}
+
+	l.wg.Add(1)
+	go l.run()
+
+	return nil
+}
+
+// Stop stops the client.
+func (l *OTLPLogger) Stop() {
+	l.once.Do(func() { close(l.quit) })
+	l.wg.Wait()
+
+	if l.conn != nil {
+		_ = l.conn.Close()
+	}
+}
+
+// Validate checks whether the logger configuration is correct.
+func (l *OTLPLogger) Validate() error {
+	if l.address == "" {
+		return fmt.Errorf("%s: Address cannot be empty", l.name)
+	}
+
+	u, err := url.Parse(l.address)
+	if err != nil {
+		return fmt.Errorf("%s: Invalid address: %w", l.name, err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%s: Address must use the http or https scheme", l.name)
+	}
+
+	if l.protocol != otlpProtocolHTTP && l.protocol != otlpProtocolGRPC {
+		return fmt.Errorf("%s: Unsupported protocol %q", l.name, l.protocol)
+	}
+
+	return nil
+}
+
+// HandleEvent handles the event received from the internal event listener.
+func (l *OTLPLogger) HandleEvent(event api.Event) {
+	if !l.processEvent(event) {
+		return
+	}
+
+	record := otlpRecord{
+		resource: LabelSet{
+			"service.name":   "incus",
+			"host.name":      l.member,
+			"incus.member":   l.member,
+			"incus.location": event.Location,
+		},
+		timestamp:  event.Timestamp,
+		attributes: LabelSet{"incus.event.type": event.Type},
+	}
+
+	// Fill in the location on standalone systems.
+	if record.resource["incus.location"] == "" {
+		record.resource["incus.location"] = l.member
+	}
+
+	if event.Project != "" {
+		record.resource["incus.project"] = event.Project
+	}
+
+	switch event.Type {
+	case api.EventTypeLifecycle:
+		lifecycleEvent := api.EventLifecycle{}
+
+		err := json.Unmarshal(event.Metadata, &lifecycleEvent)
+		if err != nil {
+			return
+		}
+
+		if lifecycleEvent.Project != "" {
+			record.resource["incus.project"] = lifecycleEvent.Project
+		}
+
+		if lifecycleEvent.Name != "" && strings.HasPrefix(lifecycleEvent.Action, "instance-") {
+			record.resource["incus.instance"] = lifecycleEvent.Name
+		}
+
+		record.eventName = lifecycleEvent.Action
+		record.severityNumber = otlpSeverityInfo
+		record.severityText = "info"
+		record.body = lifecycleEvent.Action
+
+		record.attributes["incus.action"] = lifecycleEvent.Action
+		record.attributes["incus.source"] = lifecycleEvent.Source
+
+		for k, v := range buildNestedContext("context", lifecycleEvent.Context) {
+			record.attributes["incus."+k] = v
+		}
+
+		if lifecycleEvent.Requestor != nil {
+			record.attributes["incus.requester.address"] = lifecycleEvent.Requestor.Address
+			record.attributes["incus.requester.protocol"] = lifecycleEvent.Requestor.Protocol
+			record.attributes["incus.requester.username"] = lifecycleEvent.Requestor.Username
+		}
+
+	case api.EventTypeLogging, api.EventTypeNetworkACL:
+		logEvent := api.EventLogging{}
+
+		err := json.Unmarshal(event.Metadata, &logEvent)
+		if err != nil {
+			return
+		}
+
+		if logEvent.Context["project"] != "" {
+			record.resource["incus.project"] = logEvent.Context["project"]
+		}
+
+		if logEvent.Context["instance"] != "" {
+			record.resource["incus.instance"] = logEvent.Context["instance"]
+		}
+
+		record.severityNumber = otlpSeverity(logEvent.Level)
+		record.severityText = logEvent.Level
+		record.body = logEvent.Message
+
+		for k, v := range logEvent.Context {
+			record.attributes["incus.context."+k] = v
+		}
+	}
+
+	l.records <- record
+}
+
+func (l *OTLPLogger) run() {
+	batch := newOTLPBatch()
+
+	minWaitCheckFrequency := 10 * time.Millisecond
+	maxWaitCheckFrequency := max(l.batchWait/10, minWaitCheckFrequency)
+
+	maxWaitCheck := time.NewTicker(maxWaitCheckFrequency)
+
+	defer func() {
+		// Send all pending batches
+		l.sendBatch(batch)
+		l.wg.Done()
+	}()
+
+	for {
+		select {
+		case <-l.ctx.Done():
+			return
+
+		case <-l.quit:
+			return
+
+		case r := <-l.records:
+			// If adding the record to the batch will increase the size over the max
+			// size allowed, we do send the current batch and then create a new one
+			if batch.sizeBytesAfter(r) > l.batchSize {
+				l.sendBatch(batch)
+
+				batch = newOTLPBatch(r)
+				break
+			}
+
+			// The max size of the batch isn't reached, so we can add the record
+			batch.add(r)
+
+		case <-maxWaitCheck.C:
+			// Send batch if max wait time has been reached
+			if batch.age() < l.batchWait {
+				break
+			}
+
+			l.sendBatch(batch)
+			batch = newOTLPBatch()
+		}
+	}
+}
+
+func (l *OTLPLogger) sendBatch(batch *otlpBatch) {
+	if batch.empty() {
+		return
+	}
+
+	buf, _ := batch.encode()
+
+	for range l.retry {
+		select {
+		case <-l.quit:
+			return
+		default:
+			// Try to send the message.
+			retryable, err := l.send(l.ctx, buf)
+			if err == nil || !retryable {
+				return
+			}
+
+			// Retry every 10s.
+			time.Sleep(10 * time.Second)
+		}
+	}
+}
+
+// send exports the encoded records and returns whether a failure can be retried.
+func (l *OTLPLogger) send(ctx context.Context, buf []byte) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, l.timeout)
+	defer cancel()
+
+	if l.protocol == otlpProtocolGRPC {
+		if l.username != "" && l.password != "" {
+			auth := base64.StdEncoding.EncodeToString([]byte(l.username + ":" + l.password))
+			ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Basic "+auth)
+		}
+
+		var resp []byte
+		err := l.conn.Invoke(ctx, otlpGRPCExportMethod, buf, &resp, grpc.ForceCodec(otlpRawCodec{}))
+		if err != nil {
+			// Only retry codes which the OTLP specification considers transient.
+			switch status.Code(err) {
+			case codes.Canceled, codes.DeadlineExceeded, codes.Aborted, codes.OutOfRange, codes.Unavailable, codes.DataLoss, codes.ResourceExhausted:
+				return true, err
+			}
+
+			return false, err
+		}
+
+		return false, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, l.address, bytes.NewReader(buf))
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Set("Content-Type", "application/x-protobuf")
+
+	if l.username != "" && l.password != "" {
+		req.SetBasicAuth(l.username, l.password)
+	}
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		scanner := bufio.NewScanner(io.LimitReader(resp.Body, maxErrMsgLen))
+		line := ""
+
+		if scanner.Scan() {
+			line = scanner.Text()
+		}
+
+		err = fmt.Errorf("server returned HTTP status %s (%d): %s", resp.Status, resp.StatusCode, line)
+
+		// Only retry 429s, 502s, 503s and 504s as per the OTLP specification.
+		switch resp.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true, err
+		}
+
+		return false, err
+	}
+
+	return false, nil
+}
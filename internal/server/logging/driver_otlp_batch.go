@@ -0,0 +1,105 @@
+package logging
+
+import (
+	"time"
+
+	"github.com/lxc/incus/v6/internal/version"
+)
+
+// otlpResourceLogs holds the pending log records of a single resource.
+type otlpResourceLogs struct {
+	resource LabelSet
+	records  []otlpRecord
+}
+
+// otlpBatch holds pending log records waiting to be sent to an OpenTelemetry collector, and it's used
+// to reduce the number of export requests by aggregating multiple records in a single request.
+type otlpBatch struct {
+	resources map[string]*otlpResourceLogs
+	bytes     int
+	createdAt time.Time
+}
+
+func newOTLPBatch(records ...otlpRecord) *otlpBatch {
+	b := &otlpBatch{
+		resources: map[string]*otlpResourceLogs{},
+		bytes:     0,
+		createdAt: time.Now(),
+	}
+
+	// Add records to the batch
+	for _, record := range records {
+		b.add(record)
+	}
+
+	return b
+}
+
+// add a record to the batch.
+func (b *otlpBatch) add(record otlpRecord) {
+	b.bytes += len(record.body)
+
+	// Append the record to an already existing resource (if any)
+	resource := record.resource.String()
+
+	logs, ok := b.resources[resource]
+	if ok {
+		logs.records = append(logs.records, record)
+		return
+	}
+
+	// Add the record as a new resource
+	b.resources[resource] = &otlpResourceLogs{
+		resource: record.resource,
+		records:  []otlpRecord{record},
+	}
+}
+
+// sizeBytesAfter returns the size of the batch after the input record
+// will be added to the batch itself.
+func (b *otlpBatch) sizeBytesAfter(record otlpRecord) int {
+	return b.bytes + len(record.body)
+}
+
+// age of the batch since its creation.
+func (b *otlpBatch) age() time.Duration {
+	return time.Since(b.createdAt)
+}
+
+// encode the batch as a protobuf export request, and returns the encoded bytes and the number of
+// encoded records.
+func (b *otlpBatch) encode() ([]byte, int) {
+	now := time.Now()
+	recordsCount := 0
+
+	var scope []byte
+	scope = otlpAppendString(scope, otlpScopeName, "incus")
+	scope = otlpAppendString(scope, otlpScopeVersion, version.Version)
+
+	var req []byte
+	for _, logs := range b.resources {
+		var resource []byte
+		resource = otlpAppendAttributes(resource, otlpResourceAttributes, logs.resource)
+
+		var scopeLogs []byte
+		scopeLogs = otlpAppendMessage(scopeLogs, otlpScopeLogsScope, scope)
+
+		for _, record := range logs.records {
+			scopeLogs = otlpAppendMessage(scopeLogs, otlpScopeLogsLogRecords, record.encode(now))
+			recordsCount++
+		}
+
+		var resourceLogs []byte
+		resourceLogs = otlpAppendMessage(resourceLogs, otlpResourceLogsResource, resource)
+		resourceLogs = otlpAppendMessage(resourceLogs, otlpResourceLogsScopeLogs, scopeLogs)
+
+		req = otlpAppendMessage(req, otlpExportLogsRequestResourceLogs, resourceLogs)
+	}
+
+	return req, recordsCount
+}
+
+// empty returns true if resources is empty.
+func (b *otlpBatch) empty() bool {
+	return len(b.resources) == 0
+}
@@ -0,0 +1,143 @@
+package logging
+
+import (
+	"maps"
+	"slices"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Field numbers of the OpenTelemetry logs protocol (opentelemetry.proto.logs.v1).
+const (
+	otlpExportLogsRequestResourceLogs = 1
+
+	otlpResourceLogsResource  = 1
+	otlpResourceLogsScopeLogs = 2
+
+	otlpResourceAttributes = 1
+
+	otlpScopeLogsScope      = 1
+	otlpScopeLogsLogRecords = 2
+
+	otlpScopeName    = 1
+	otlpScopeVersion = 2
+
+	otlpLogRecordTimeUnixNano         = 1
+	otlpLogRecordSeverityNumber       = 2
+	otlpLogRecordSeverityText         = 3
+	otlpLogRecordBody                 = 5
+	otlpLogRecordAttributes           = 6
+	otlpLogRecordObservedTimeUnixNano = 11
+	otlpLogRecordEventName            = 12
+
+	otlpKeyValueKey   = 1
+	otlpKeyValueValue = 2
+
+	otlpAnyValueString = 1
+)
+
+// OpenTelemetry severity numbers.
+const (
+	otlpSeverityUnspecified = 0
+	otlpSeverityTrace       = 1
+	otlpSeverityDebug       = 5
+	otlpSeverityInfo        = 9
+	otlpSeverityWarn        = 13
+	otlpSeverityError       = 17
+	otlpSeverityFatal       = 21
+)
+
+// otlpRecord represents a single OpenTelemetry log record along with the resource it belongs to.
+type otlpRecord struct {
+	resource LabelSet
+
+	timestamp      time.Time
+	eventName      string
+	severityNumber int
+	severityText   string
+	body           string
+	attributes     LabelSet
+}
+
+// otlpSeverity converts an Incus log level into an OpenTelemetry severity number.
+func otlpSeverity(level string) int {
+	switch level {
+	case "trace":
+		return otlpSeverityTrace
+	case "debug":
+		return otlpSeverityDebug
+	case "info":
+		return otlpSeverityInfo
+	case "warn", "warning":
+		return otlpSeverityWarn
+	case "error":
+		return otlpSeverityError
+	case "fatal", "panic":
+		return otlpSeverityFatal
+	}
+
+	return otlpSeverityUnspecified
+}
+
+// otlpAppendString appends a string field if it isn't empty.
+func otlpAppendString(b []byte, num protowire.Number, value string) []byte {
+	if value == "" {
+		return b
+	}
+
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, value)
+}
+
+// otlpAppendMessage appends an embedded message field.
+func otlpAppendMessage(b []byte, num protowire.Number, msg []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, msg)
+}
+
+// otlpAppendAttributes appends the given labels as sorted key/value attributes.
+func otlpAppendAttributes(b []byte, num protowire.Number, labels LabelSet) []byte {
+	for _, k := range slices.Sorted(maps.Keys(labels)) {
+		var value []byte
+		value = protowire.AppendTag(value, otlpAnyValueString, protowire.BytesType)
+		value = protowire.AppendString(value, labels[k])
+
+		var kv []byte
+		kv = otlpAppendString(kv, otlpKeyValueKey, k)
+		kv = otlpAppendMessage(kv, otlpKeyValueValue, value)
+
+		b = otlpAppendMessage(b, num, kv)
+	}
+
+	return b
+}
+
+// encode returns the protobuf encoding of the log record.
+func (r *otlpRecord) encode(observed time.Time) []byte {
+	var b []byte
+
+	b = protowire.AppendTag(b, otlpLogRecordTimeUnixNano, protowire.Fixed64Type)
+	b = protowire.AppendFixed64(b, uint64(r.timestamp.UnixNano()))
+
+	if r.severityNumber != otlpSeverityUnspecified {
+		b = protowire.AppendTag(b, otlpLogRecordSeverityNumber, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(r.severityNumber))
+	}
+
+	b = otlpAppendString(b, otlpLogRecordSeverityText, r.severityText)
+
+	var body []byte
+	body = protowire.AppendTag(body, otlpAnyValueString, protowire.BytesType)
+	body = protowire.AppendString(body, r.body)
+	b = otlpAppendMessage(b, otlpLogRecordBody, body)
+
+	b = otlpAppendAttributes(b, otlpLogRecordAttributes, r.attributes)
+
+	b = protowire.AppendTag(b, otlpLogRecordObservedTimeUnixNano, protowire.Fixed64Type)
+	b = protowire.AppendFixed64(b, uint64(observed.UnixNano()))
+
+	b = otlpAppendString(b, otlpLogRecordEventName, r.eventName)
+
+	return b
+}
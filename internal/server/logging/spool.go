@@ -0,0 +1,150 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// spool is an on-disk queue of pending messages.
+//
+// Each message is stored in its own file, named after its sequence number, so that messages
+// survive daemon restarts and can be removed individually once delivered.
+type spool struct {
+	mu         sync.Mutex
+	path       string
+	maxEntries int
+	seqs       []uint64
+	next       uint64
+}
+
+// newSpool opens (or creates) the spool at the given path and loads any pending messages.
+func newSpool(path string, maxEntries int) (*spool, error) {
+	err := os.MkdirAll(path, 0o700)
+	if err != nil {
+		return nil, fmt.Errorf("Failed creating spool directory %q: %w", path, err)
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed reading spool directory %q: %w", path, err)
+	}
+
+	s := &spool{
+		path:       path,
+		maxEntries: maxEntries,
+	}
+
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok {
+			// Clear leftover temporary files.
+			if strings.HasSuffix(entry.Name(), ".tmp") {
+				_ = os.Remove(filepath.Join(path, entry.Name()))
+			}
+
+			continue
+		}
+
+		seq, err := strconv.ParseUint(name, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		s.seqs = append(s.seqs, seq)
+	}
+
+	slices.Sort(s.seqs)
+
+	if len(s.seqs) > 0 {
+		s.next = s.seqs[len(s.seqs)-1] + 1
+	}
+
+	return s, nil
+}
+
+// entryPath returns the path of the message with the given sequence number.
+func (s *spool) entryPath(seq uint64) string {
+	return filepath.Join(s.path, fmt.Sprintf("%020d.json", seq))
+}
+
+// push adds a message to the end of the spool, dropping the oldest message if the spool is full.
+func (s *spool) push(data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seq := s.next
+	path := s.entryPath(seq)
+
+	// Write to a temporary file first so that a crash can't leave a partial message behind.
+	err := os.WriteFile(path+".tmp", data, 0o600)
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(path+".tmp", path)
+	if err != nil {
+		_ = os.Remove(path + ".tmp")
+		return err
+	}
+
+	s.next++
+	s.seqs = append(s.seqs, seq)
+
+	for s.maxEntries > 0 && len(s.seqs) > s.maxEntries {
+		err = os.Remove(s.entryPath(s.seqs[0]))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+
+		s.seqs = s.seqs[1:]
+	}
+
+	return nil
+}
+
+// peek returns the oldest message in the spool along with its sequence number.
+// It returns false if the spool is empty.
+func (s *spool) peek() (uint64, []byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for len(s.seqs) > 0 {
+		seq := s.seqs[0]
+
+		data, err := os.ReadFile(s.entryPath(seq))
+		if err != nil {
+			// Skip messages removed from underneath us.
+			if errors.Is(err, os.ErrNotExist) {
+				s.seqs = s.seqs[1:]
+				continue
+			}
+
+			return 0, nil, false, err
+		}
+
+		return seq, data, true, nil
+	}
+
+	return 0, nil, false, nil
+}
+
+// remove deletes the message with the given sequence number once it has been delivered.
+func (s *spool) remove(seq uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := os.Remove(s.entryPath(seq))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	s.seqs = slices.DeleteFunc(s.seqs, func(v uint64) bool { return v == seq })
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpoolPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spool")
+
+	s, err := newSpool(path, 0)
+	require.NoError(t, err)
+
+	// Empty spool.
+	_, _, ok, err := s.peek()
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, s.push([]byte("first")))
+	require.NoError(t, s.push([]byte("second")))
+
+	// Messages are returned in order until removed.
+	seq, data, ok, err := s.peek()
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, []byte("first"), data)
+
+	_, data, _, err = s.peek()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("first"), data)
+
+	require.NoError(t, s.remove(seq))
+
+	_, data, ok, err = s.peek()
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, []byte("second"), data)
+
+	// Removing a message twice is fine.
+	require.NoError(t, s.remove(seq))
+}
+
+func TestSpoolMaxEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spool")
+
+	s, err := newSpool(path, 2)
+	require.NoError(t, err)
+
+	for _, msg := range []string{"a", "b", "c"} {
+		require.NoError(t, s.push([]byte(msg)))
+	}
+
+	// The oldest message was dropped, both from memory and disk.
+	entries, err := os.ReadDir(path)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	seq, data, ok, err := s.peek()
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, []byte("b"), data)
+
+	require.NoError(t, s.remove(seq))
+
+	_, data, _, err = s.peek()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("c"), data)
+}
+
+func TestSpoolReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spool")
+
+	s, err := newSpool(path, 0)
+	require.NoError(t, err)
+
+	for _, msg := range []string{"a", "b", "c"} {
+		require.NoError(t, s.push([]byte(msg)))
+	}
+
+	seq, _, _, err := s.peek()
+	require.NoError(t, err)
+	require.NoError(t, s.remove(seq))
+
+	// Leftovers from an interrupted write and unrelated files.
+	require.NoError(t, os.WriteFile(filepath.Join(path, "00000000000000000009.json.tmp"), []byte("partial"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(path, "invalid.json"), []byte("invalid"), 0o600))
+
+	// Reopening the spool replays the pending messages in order.
+	s, err = newSpool(path, 0)
+	require.NoError(t, err)
+
+	var replayed []string
+	for {
+		seq, data, ok, err := s.peek()
+		require.NoError(t, err)
+		if !ok {
+			break
+		}
+
+		replayed = append(replayed, string(data))
+		require.NoError(t, s.remove(seq))
+	}
+
+	assert.Equal(t, []string{"b", "c"}, replayed)
+	assert.NoFileExists(t, filepath.Join(path, "00000000000000000009.json.tmp"))
+
+	// New messages keep going after the replayed ones.
+	require.NoError(t, s.push([]byte("d")))
+	assert.FileExists(t, s.entryPath(3))
+}
@@ -95,11 +95,23 @@ func (m *MetricSet) Merge(metricSet *MetricSet) {
 	}
 }
 
-func (m *MetricSet) String() string {
-	var out strings.Builder
-	metricTypes := []MetricType{}
+// metricTypeName returns the OpenMetrics type of the given metric.
+func metricTypeName(metricType MetricType) string {
+	// ProcsTotal is a gauge according to the OpenMetrics spec as its value can decrease.
+	if metricType == ProcsTotal || metricType == CPUs || metricType == GoGoroutines || metricType == GoHeapObjects {
+		return "gauge"
+	} else if strings.HasSuffix(MetricNames[metricType], "_total") || strings.HasSuffix(MetricNames[metricType], "_seconds") {
+		return "counter"
+	} else if strings.HasSuffix(MetricNames[metricType], "_bytes") {
+		return "gauge"
+	}
 
-	// Sort output by metric type name
+	return ""
+}
+
+// metricTypes returns the metric types present in the set, sorted by their numeric code.
+func (m *MetricSet) metricTypes() []MetricType {
+	metricTypes := make([]MetricType, 0, len(m.set))
 	for metricType := range m.set {
 		metricTypes = append(metricTypes, metricType)
 	}
@@ -108,26 +120,22 @@ func (m *MetricSet) String() string {
 		return int(metricTypes[i]) < int(metricTypes[j])
 	})
 
-	for _, metricType := range metricTypes {
+	return metricTypes
+}
+
+func (m *MetricSet) String() string {
+	var out strings.Builder
+
+	// Sort output by metric type name
+	for _, metricType := range m.metricTypes() {
 		// Add HELP message as specified by OpenMetrics
 		_, err := out.WriteString(MetricHeaders[metricType] + "\n")
 		if err != nil {
 			return ""
 		}
 
-		metricTypeName := ""
-
-		// ProcsTotal is a gauge according to the OpenMetrics spec as its value can decrease.
-		if metricType == ProcsTotal || metricType == CPUs || metricType == GoGoroutines || metricType == GoHeapObjects {
-			metricTypeName = "gauge"
-		} else if strings.HasSuffix(MetricNames[metricType], "_total") || strings.HasSuffix(MetricNames[metricType], "_seconds") {
-			metricTypeName = "counter"
-		} else if strings.HasSuffix(MetricNames[metricType], "_bytes") {
-			metricTypeName = "gauge"
-		}
-
 		// Add TYPE message as specified by OpenMetrics
-		_, err = out.WriteString(fmt.Sprintf("# TYPE %s %s\n", MetricNames[metricType], metricTypeName))
+		_, err = out.WriteString(fmt.Sprintf("# TYPE %s %s\n", MetricNames[metricType], metricTypeName(metricType)))
 		if err != nil {
 			return ""
 		}
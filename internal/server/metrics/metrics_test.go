@@ -1,9 +1,14 @@
 package metrics
 
 import (
+	"encoding/json"
+	"math"
 	"testing"
+	"time"
 
+	"github.com/klauspost/compress/s2"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
 
 	"github.com/lxc/incus/v6/internal/server/auth"
 )
@@ -56,3 +61,118 @@ func TestMetricSet_FilterSamples(t *testing.T) {
 		require.Contains(t, hasKeys, "project")
 	}
 }
+
+// consumeMessages returns the embedded messages with the given field number.
+func consumeMessages(t *testing.T, b []byte, field protowire.Number) [][]byte {
+	var out [][]byte
+
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		require.GreaterOrEqual(t, n, 0)
+		b = b[n:]
+
+		if num == field && typ == protowire.BytesType {
+			v, n := protowire.ConsumeBytes(b)
+			require.GreaterOrEqual(t, n, 0)
+			out = append(out, v)
+			b = b[n:]
+
+			continue
+		}
+
+		n = protowire.ConsumeFieldValue(num, typ, b)
+		require.GreaterOrEqual(t, n, 0)
+		b = b[n:]
+	}
+
+	return out
+}
+
+func TestMetricSet_RemoteWrite(t *testing.T) {
+	m := NewMetricSet(map[string]string{"project": "default", "name": "jammy"})
+	m.AddSamples(CPUSecondsTotal, Sample{Value: 1.5, Labels: map[string]string{"mode": "user"}})
+
+	timestamp := time.Unix(1700000000, 0)
+	data, err := s2.Decode(nil, m.RemoteWrite(map[string]string{"location": "server01"}, timestamp))
+	require.NoError(t, err)
+
+	series := consumeMessages(t, data, remoteWriteRequestTimeseries)
+	require.Len(t, series, 1)
+	require.Len(t, consumeMessages(t, data, remoteWriteRequestMetadata), 1)
+
+	// Labels are sorted by name and include the metric name.
+	var names []string
+	values := map[string]string{}
+	for _, label := range consumeMessages(t, series[0], remoteWriteTimeseriesLabels) {
+		var name string
+		var value string
+		for len(label) > 0 {
+			num, _, n := protowire.ConsumeTag(label)
+			label = label[n:]
+			v, n := protowire.ConsumeString(label)
+			label = label[n:]
+
+			if num == remoteWriteLabelName {
+				name = v
+			} else {
+				value = v
+			}
+		}
+
+		names = append(names, name)
+		values[name] = value
+	}
+
+	require.Equal(t, []string{"__name__", "location", "mode", "name", "project"}, names)
+	require.Equal(t, "incus_cpu_seconds_total", values["__name__"])
+	require.Equal(t, "server01", values["location"])
+
+	// The extra labels must not leak into the MetricSet.
+	require.NotContains(t, m.set[CPUSecondsTotal][0].Labels, "location")
+
+	samples := consumeMessages(t, series[0], remoteWriteTimeseriesSamples)
+	require.Len(t, samples, 1)
+
+	b := samples[0]
+	_, _, n := protowire.ConsumeTag(b)
+	b = b[n:]
+	value, n := protowire.ConsumeFixed64(b)
+	b = b[n:]
+	require.Equal(t, 1.5, math.Float64frombits(value))
+
+	_, _, n = protowire.ConsumeTag(b)
+	b = b[n:]
+	ts, _ := protowire.ConsumeVarint(b)
+	require.Equal(t, uint64(timestamp.UnixMilli()), ts)
+}
+
+func TestMetricSet_OTLP(t *testing.T) {
+	m := NewMetricSet(nil)
+	m.AddSamples(CPUSecondsTotal, Sample{Value: 1.5, Labels: map[string]string{"mode": "user"}})
+	m.AddSamples(MemoryMemFreeBytes, Sample{Value: 1024})
+
+	data, err := m.OTLP(map[string]string{"service.name": "incus"}, time.Unix(1, 0))
+	require.NoError(t, err)
+
+	req := otlpMetricsRequest{}
+	err = json.Unmarshal(data, &req)
+	require.NoError(t, err)
+
+	require.Len(t, req.ResourceMetrics, 1)
+	require.Equal(t, []otlpAttribute{{Key: "service.name", Value: otlpAttributeValue{StringValue: "incus"}}}, req.ResourceMetrics[0].Resource.Attributes)
+
+	metrics := req.ResourceMetrics[0].ScopeMetrics[0].Metrics
+	require.Len(t, metrics, 2)
+
+	// Counters are exported as monotonic cumulative sums.
+	require.Equal(t, "incus_cpu_seconds_total", metrics[0].Name)
+	require.NotNil(t, metrics[0].Sum)
+	require.True(t, metrics[0].Sum.IsMonotonic)
+	require.Equal(t, "1000000000", metrics[0].Sum.DataPoints[0].TimeUnixNano)
+	require.Equal(t, 1.5, metrics[0].Sum.DataPoints[0].AsDouble)
+
+	// Everything else is a gauge.
+	require.Equal(t, "incus_memory_MemFree_bytes", metrics[1].Name)
+	require.NotNil(t, metrics[1].Gauge)
+	require.Equal(t, 1024.0, metrics[1].Gauge.DataPoints[0].AsDouble)
+}
@@ -0,0 +1,136 @@
+package metrics
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// OTLP aggregation temporality for cumulative sums.
+const otlpAggregationTemporalityCumulative = 2
+
+// otlpAttribute represents an OTLP key/value attribute.
+type otlpAttribute struct {
+	Key   string             `json:"key"`
+	Value otlpAttributeValue `json:"value"`
+}
+
+// otlpAttributeValue represents the value of an OTLP attribute.
+type otlpAttributeValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+// otlpDataPoint represents an OTLP number data point.
+type otlpDataPoint struct {
+	Attributes   []otlpAttribute `json:"attributes,omitempty"`
+	TimeUnixNano string          `json:"timeUnixNano"`
+	AsDouble     float64         `json:"asDouble"`
+}
+
+// otlpGauge represents an OTLP gauge.
+type otlpGauge struct {
+	DataPoints []otlpDataPoint `json:"dataPoints"`
+}
+
+// otlpSum represents an OTLP sum.
+type otlpSum struct {
+	DataPoints             []otlpDataPoint `json:"dataPoints"`
+	AggregationTemporality int             `json:"aggregationTemporality"`
+	IsMonotonic            bool            `json:"isMonotonic"`
+}
+
+// otlpMetric represents a single OTLP metric.
+type otlpMetric struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Gauge       *otlpGauge `json:"gauge,omitempty"`
+	Sum         *otlpSum   `json:"sum,omitempty"`
+}
+
+// otlpScope represents an OTLP instrumentation scope.
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+// otlpScopeMetrics represents the metrics of an OTLP instrumentation scope.
+type otlpScopeMetrics struct {
+	Scope   otlpScope    `json:"scope"`
+	Metrics []otlpMetric `json:"metrics"`
+}
+
+// otlpResource represents an OTLP resource.
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+// otlpResourceMetrics represents the metrics of an OTLP resource.
+type otlpResourceMetrics struct {
+	Resource     otlpResource       `json:"resource"`
+	ScopeMetrics []otlpScopeMetrics `json:"scopeMetrics"`
+}
+
+// otlpMetricsRequest represents an OTLP metrics export request.
+type otlpMetricsRequest struct {
+	ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+}
+
+// otlpAttributes converts a map into a sorted list of OTLP attributes.
+func otlpAttributes(values map[string]string) []otlpAttribute {
+	attributes := make([]otlpAttribute, 0, len(values))
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		attributes = append(attributes, otlpAttribute{Key: key, Value: otlpAttributeValue{StringValue: values[key]}})
+	}
+
+	return attributes
+}
+
+// OTLP encodes the MetricSet as an OTLP/HTTP JSON metrics export request.
+// The provided resource attributes describe the server the metrics originate from.
+// Raw data added through AddRaw isn't included.
+func (m *MetricSet) OTLP(resource map[string]string, timestamp time.Time) ([]byte, error) {
+	timeUnixNano := strconv.FormatInt(timestamp.UnixNano(), 10)
+
+	scopeMetrics := otlpScopeMetrics{
+		Scope:   otlpScope{Name: "incus"},
+		Metrics: make([]otlpMetric, 0, len(m.set)),
+	}
+
+	for _, metricType := range m.metricTypes() {
+		dataPoints := make([]otlpDataPoint, 0, len(m.set[metricType]))
+		for _, sample := range m.set[metricType] {
+			dataPoints = append(dataPoints, otlpDataPoint{
+				Attributes:   otlpAttributes(sample.Labels),
+				TimeUnixNano: timeUnixNano,
+				AsDouble:     sample.Value,
+			})
+		}
+
+		metric := otlpMetric{
+			Name:        MetricNames[metricType],
+			Description: metricHelp(metricType),
+		}
+
+		if metricTypeName(metricType) == "counter" {
+			metric.Sum = &otlpSum{
+				DataPoints:             dataPoints,
+				AggregationTemporality: otlpAggregationTemporalityCumulative,
+				IsMonotonic:            true,
+			}
+		} else {
+			metric.Gauge = &otlpGauge{DataPoints: dataPoints}
+		}
+
+		scopeMetrics.Metrics = append(scopeMetrics.Metrics, metric)
+	}
+
+	req := otlpMetricsRequest{
+		ResourceMetrics: []otlpResourceMetrics{{
+			Resource:     otlpResource{Attributes: otlpAttributes(resource)},
+			ScopeMetrics: []otlpScopeMetrics{scopeMetrics},
+		}},
+	}
+
+	return json.Marshal(req)
+}
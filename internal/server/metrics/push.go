@@ -0,0 +1,133 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"time"
+
+	incustls "github.com/lxc/incus/v6/shared/tls"
+)
+
+const (
+	// PushFormatRemoteWrite is the Prometheus remote-write push format.
+	PushFormatRemoteWrite = "remote_write"
+
+	// PushFormatOTLP is the OpenTelemetry (OTLP/HTTP JSON) push format.
+	PushFormatOTLP = "otlp"
+)
+
+// Pusher sends metric sets to a remote endpoint.
+type Pusher struct {
+	client   *http.Client
+	address  string
+	format   string
+	username string
+	password string
+}
+
+// NewPusher returns a new Pusher for the given endpoint and format.
+func NewPusher(address string, format string, username string, password string, caCertificate string) (*Pusher, error) {
+	if address == "" {
+		return nil, errors.New("Address cannot be empty")
+	}
+
+	if format != PushFormatRemoteWrite && format != PushFormatOTLP {
+		return nil, fmt.Errorf("Unsupported metrics push format %q", format)
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+
+	// Setup the server for self-signed certificates.
+	if caCertificate != "" {
+		tlsConfig := &tls.Config{
+			MinVersion: tls.VersionTLS12,
+		}
+
+		certBlock, _ := pem.Decode([]byte(caCertificate))
+		if certBlock == nil {
+			return nil, errors.New("Invalid remote certificate")
+		}
+
+		serverCert, err := x509.ParseCertificate(certBlock.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid remote certificate: %w", err)
+		}
+
+		incustls.TLSConfigWithTrustedCert(tlsConfig, serverCert)
+		client.Transport = &http.Transport{TLSClientConfig: tlsConfig}
+	}
+
+	return &Pusher{
+		client:   client,
+		address:  address,
+		format:   format,
+		username: username,
+		password: password,
+	}, nil
+}
+
+// Push sends the MetricSet to the remote endpoint.
+// The labels are added to every series (remote-write) or used as resource attributes (OTLP).
+func (p *Pusher) Push(ctx context.Context, metricSet *MetricSet, labels map[string]string) error {
+	now := time.Now()
+
+	var body []byte
+	var err error
+
+	headers := map[string]string{}
+
+	switch p.format {
+	case PushFormatRemoteWrite:
+		body = metricSet.RemoteWrite(labels, now)
+		headers["Content-Type"] = "application/x-protobuf"
+		headers["Content-Encoding"] = "snappy"
+		headers["X-Prometheus-Remote-Write-Version"] = "0.1.0"
+	case PushFormatOTLP:
+		resource := map[string]string{"service.name": "incus"}
+		maps.Copy(resource, labels)
+
+		body, err = metricSet.OTLP(resource, now)
+		if err != nil {
+			return fmt.Errorf("Failed encoding metrics: %w", err)
+		}
+
+		headers["Content-Type"] = "application/json"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.address, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	req.Header.Set("User-Agent", "incus")
+
+	if p.username != "" {
+		req.SetBasicAuth(p.username, p.password)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("Metrics push failed with status %q: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/s2"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Field numbers and enums of the Prometheus remote-write protocol (prompb).
+const (
+	remoteWriteRequestTimeseries = 1
+	remoteWriteRequestMetadata   = 3
+
+	remoteWriteTimeseriesLabels  = 1
+	remoteWriteTimeseriesSamples = 2
+
+	remoteWriteLabelName  = 1
+	remoteWriteLabelValue = 2
+
+	remoteWriteSampleValue     = 1
+	remoteWriteSampleTimestamp = 2
+
+	remoteWriteMetadataType       = 1
+	remoteWriteMetadataFamilyName = 2
+	remoteWriteMetadataHelp       = 4
+
+	remoteWriteMetadataTypeUnknown = 0
+	remoteWriteMetadataTypeCounter = 1
+	remoteWriteMetadataTypeGauge   = 2
+)
+
+// metricHelp returns the help text of the given metric without the OpenMetrics prefix.
+func metricHelp(metricType MetricType) string {
+	return strings.TrimPrefix(MetricHeaders[metricType], "# HELP "+MetricNames[metricType]+" ")
+}
+
+// RemoteWrite encodes the MetricSet as a snappy compressed Prometheus remote-write request.
+// The provided labels are added to every series without altering the samples of the MetricSet.
+// Raw data added through AddRaw isn't included.
+func (m *MetricSet) RemoteWrite(labels map[string]string, timestamp time.Time) []byte {
+	var req []byte
+
+	for _, metricType := range m.metricTypes() {
+		for _, sample := range m.set[metricType] {
+			sampleLabels := make(map[string]string, len(sample.Labels)+len(labels)+1)
+			maps.Copy(sampleLabels, labels)
+			maps.Copy(sampleLabels, sample.Labels)
+			sampleLabels["__name__"] = MetricNames[metricType]
+
+			// Labels must be sorted by name.
+			var series []byte
+			for _, name := range slices.Sorted(maps.Keys(sampleLabels)) {
+				var label []byte
+				label = protowire.AppendTag(label, remoteWriteLabelName, protowire.BytesType)
+				label = protowire.AppendString(label, name)
+				label = protowire.AppendTag(label, remoteWriteLabelValue, protowire.BytesType)
+				label = protowire.AppendString(label, sampleLabels[name])
+
+				series = protowire.AppendTag(series, remoteWriteTimeseriesLabels, protowire.BytesType)
+				series = protowire.AppendBytes(series, label)
+			}
+
+			var value []byte
+			value = protowire.AppendTag(value, remoteWriteSampleValue, protowire.Fixed64Type)
+			value = protowire.AppendFixed64(value, math.Float64bits(sample.Value))
+			value = protowire.AppendTag(value, remoteWriteSampleTimestamp, protowire.VarintType)
+			value = protowire.AppendVarint(value, uint64(timestamp.UnixMilli()))
+
+			series = protowire.AppendTag(series, remoteWriteTimeseriesSamples, protowire.BytesType)
+			series = protowire.AppendBytes(series, value)
+
+			req = protowire.AppendTag(req, remoteWriteRequestTimeseries, protowire.BytesType)
+			req = protowire.AppendBytes(req, series)
+		}
+
+		metadataType := remoteWriteMetadataTypeUnknown
+		switch metricTypeName(metricType) {
+		case "counter":
+			metadataType = remoteWriteMetadataTypeCounter
+		case "gauge":
+			metadataType = remoteWriteMetadataTypeGauge
+		}
+
+		var metadata []byte
+		metadata = protowire.AppendTag(metadata, remoteWriteMetadataType, protowire.VarintType)
+		metadata = protowire.AppendVarint(metadata, uint64(metadataType))
+		metadata = protowire.AppendTag(metadata, remoteWriteMetadataFamilyName, protowire.BytesType)
+		metadata = protowire.AppendString(metadata, MetricNames[metricType])
+		metadata = protowire.AppendTag(metadata, remoteWriteMetadataHelp, protowire.BytesType)
+		metadata = protowire.AppendString(metadata, metricHelp(metricType))
+
+		req = protowire.AppendTag(req, remoteWriteRequestMetadata, protowire.BytesType)
+		req = protowire.AppendBytes(req, metadata)
+	}
+
+	return s2.EncodeSnappy(nil, req)
+}
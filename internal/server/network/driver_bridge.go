@@ -20,6 +20,7 @@ import (
 
 	incus "github.com/lxc/incus/v6/client"
 	"github.com/lxc/incus/v6/internal/server/apparmor"
+	"github.com/lxc/incus/v6/internal/server/bgp"
 	"github.com/lxc/incus/v6/internal/server/cluster"
 	"github.com/lxc/incus/v6/internal/server/cluster/request"
 	"github.com/lxc/incus/v6/internal/server/daemon"
@@ -62,6 +63,7 @@ func (n *bridge) DBType() db.NetworkType {
 func (n *bridge) Info() Info {
 	info := n.common.Info()
 	info.AddressForwards = true
+	info.LoadBalancers = true
 
 	return info
 }
@@ -673,6 +675,15 @@ func (n *bridge) Validate(config map[string]string, clientType request.ClientTyp
 				//  default: `1`
 				//  shortdesc: Specific TTL to use for multicast routing topologies
 				rules[k] = validate.Optional(validate.IsUint8)
+			case "evpn":
+				// gendoc:generate(entity=network_bridge, group=common, key=tunnel.NAME.evpn)
+				// Remote VTEPs and MAC addresses are then exchanged with the BGP peers of the network and the other cluster members.
+				// ---
+				//  type: bool
+				//  condition: `vxlan`
+				//  default: `false`
+				//  shortdesc: Whether to use BGP EVPN as the control plane of the `vxlan` tunnel
+				rules[k] = validate.Optional(validate.IsBool)
 			}
 		}
 	}
@@ -717,6 +728,11 @@ func (n *bridge) Validate(config map[string]string, clientType request.ClientTyp
 
 	maps.Copy(rules, bgpRules)
 
+	// Add the WireGuard validation rules.
+	if n.netType == "wireguard" {
+		n.wireguardValidationRules(rules)
+	}
+
 	// gendoc:generate(entity=network_bridge, group=common, key=user.*)
 	//
 	// ---
@@ -760,6 +776,43 @@ func (n *bridge) Validate(config map[string]string, clientType request.ClientTyp
 		}
 	}
 
+	// Check EVPN tunnels.
+	for k, v := range config {
+		if !strings.HasPrefix(k, "tunnel.") || !strings.HasSuffix(k, ".evpn") || !util.IsTrue(v) {
+			continue
+		}
+
+		tunnel := strings.Split(k, ".")[1]
+		getConfig := func(key string) string {
+			return config[fmt.Sprintf("tunnel.%s.%s", tunnel, key)]
+		}
+
+		if getConfig("protocol") != "vxlan" {
+			return fmt.Errorf("EVPN can only be used with %q tunnels", "vxlan")
+		}
+
+		if getConfig("remote") != "" || getConfig("group") != "" {
+			return fmt.Errorf("EVPN tunnel %q can't have a remote or group address", tunnel)
+		}
+
+		// The VNI is part of the route distinguisher and route target, which only have room for 16 bits.
+		if getConfig("id") != "" {
+			vni, err := strconv.ParseUint(getConfig("id"), 10, 32)
+			if err != nil || vni < 1 || vni > 65535 {
+				return fmt.Errorf("Invalid VNI %q for EVPN tunnel %q (must be between 1 and 65535)", getConfig("id"), tunnel)
+			}
+		}
+
+		if n.state.GlobalConfig.BGPASN() == 0 {
+			return fmt.Errorf("EVPN tunnels require %q to be set", "core.bgp_asn")
+		}
+
+		// The other cluster members peer with this one on its cluster address.
+		if n.state.ServerClustered && n.state.LocalConfig != nil && n.state.LocalConfig.BGPAddress() == "" {
+			return fmt.Errorf("EVPN tunnels in a cluster require %q to be set", "core.bgp_address")
+		}
+	}
+
 	// Check using same MAC address on every cluster node is safe.
 	if config["bridge.hwaddr"] != "" {
 		err = n.checkClusterWideMACSafe(config)
@@ -1260,6 +1313,15 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 		}
 	}
 
+	// Load the WireGuard peers, whose allowed IPs are reached through the WireGuard interface.
+	var wgPeers []*api.NetworkPeer
+	if n.netType == "wireguard" {
+		wgPeers, err = n.wireguardPeers()
+		if err != nil {
+			return err
+		}
+	}
+
 	// Initialize a new firewall option set.
 	fwOpts := firewallDrivers.Opts{}
 
@@ -1406,8 +1468,19 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 				dnsmasqCmd = append(dnsmasqCmd, fmt.Sprintf("--dhcp-option-force=119,%s", strings.Trim(dnsSearch, " ")))
 			}
 
-			if n.config["ipv4.dhcp.routes"] != "" {
-				dnsmasqCmd = append(dnsmasqCmd, fmt.Sprintf("--dhcp-option-force=121,%s", strings.ReplaceAll(n.config["ipv4.dhcp.routes"], " ", "")))
+			dhcpRoutes := n.config["ipv4.dhcp.routes"]
+			if n.netType == "wireguard" {
+				// Announce the routes to the WireGuard peers.
+				gateway := ipAddress
+				if n.config["ipv4.dhcp.gateway"] != "" {
+					gateway = net.ParseIP(n.config["ipv4.dhcp.gateway"])
+				}
+
+				dhcpRoutes = wireguardDHCPRoutes(dhcpRoutes, ipAddress, gateway, wireguardPeerSubnets(wgPeers, 4))
+			}
+
+			if dhcpRoutes != "" {
+				dnsmasqCmd = append(dnsmasqCmd, fmt.Sprintf("--dhcp-option-force=121,%s", strings.ReplaceAll(dhcpRoutes, " ", "")))
 			}
 
 			expiry := "1h"
@@ -1451,6 +1524,7 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 			fwOpts.SNATV4 = &firewallDrivers.SNATOpts{
 				SNATAddress: srcIP,
 				Subnet:      subnet,
+				Exclude:     wireguardPeerSubnets(wgPeers, 4),
 			}
 
 			if n.config["ipv4.nat.order"] == "after" {
@@ -1669,6 +1743,7 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 			fwOpts.SNATV6 = &firewallDrivers.SNATOpts{
 				SNATAddress: srcIP,
 				Subnet:      subnet,
+				Exclude:     wireguardPeerSubnets(wgPeers, 6),
 			}
 
 			if n.config["ipv6.nat.order"] == "after" {
@@ -1747,6 +1822,13 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 				}
 
 				vxlan.Remote = tunRemote
+			} else if util.IsTrue(getConfig("evpn")) {
+				// Skip partial configs.
+				if tunLocal == nil {
+					continue
+				}
+
+				// The remote VTEPs are learned through BGP EVPN.
 			} else {
 				if tunGroup == nil {
 					tunGroup = net.IPv4(239, 0, 0, 1) // 239.0.0.1
@@ -2005,12 +2087,32 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 		return err
 	}
 
+	// Setup network load balancers.
+	err = n.loadBalancerSetupFirewall()
+	if err != nil {
+		return err
+	}
+
 	// Setup BGP.
 	err = n.bgpSetup(oldConfig)
 	if err != nil {
 		return err
 	}
 
+	// Setup BGP EVPN.
+	err = n.evpnSetup()
+	if err != nil {
+		return err
+	}
+
+	// Setup WireGuard.
+	if n.netType == "wireguard" {
+		err = n.wireguardSetup(wgPeers)
+		if err != nil {
+			return err
+		}
+	}
+
 	reverter.Success()
 
 	return nil
@@ -2030,11 +2132,28 @@ func (n *bridge) Stop() error {
 		return err
 	}
 
+	// Clear BGP EVPN.
+	err = n.evpnClear()
+	if err != nil {
+		return err
+	}
+
+	// Stop the load balancer health checks.
+	n.loadBalancerStopHealthChecks()
+
 	err = n.deleteChildren()
 	if err != nil {
 		return fmt.Errorf("Failed to delete bridge children interfaces: %w", err)
 	}
 
+	// Delete the WireGuard interface.
+	if n.netType == "wireguard" {
+		err = n.wireguardStop()
+		if err != nil {
+			return err
+		}
+	}
+
 	// Destroy the bridge interface
 	if n.config["bridge.driver"] == "openvswitch" {
 		vswitch, err := n.state.OVS()
@@ -2214,6 +2333,191 @@ func (n *bridge) getTunnels() []string {
 	return tunnels
 }
 
+// evpnTunnels returns the VXLAN tunnels of the network which use BGP EVPN.
+func (n *bridge) evpnTunnels() []evpnTunnel {
+	tunnels := []evpnTunnel{}
+
+	for _, tunnel := range n.getTunnels() {
+		getConfig := func(key string) string {
+			return n.config[fmt.Sprintf("tunnel.%s.%s", tunnel, key)]
+		}
+
+		if getConfig("protocol") != "vxlan" || util.IsFalseOrEmpty(getConfig("evpn")) {
+			continue
+		}
+
+		// Skip partial configs, matching the tunnel setup.
+		vtep := net.ParseIP(getConfig("local"))
+		if vtep == nil {
+			continue
+		}
+
+		vni := uint64(1)
+		if getConfig("id") != "" {
+			var err error
+
+			vni, err = strconv.ParseUint(getConfig("id"), 10, 32)
+			if err != nil {
+				continue
+			}
+		}
+
+		tunnels = append(tunnels, evpnTunnel{
+			devName: fmt.Sprintf("%s-%s", n.name, tunnel),
+			vni:     uint32(vni),
+			vtep:    vtep,
+		})
+	}
+
+	return tunnels
+}
+
+// evpnPeers returns the BGP peers to exchange EVPN routes with: those of the network as well as the other
+// cluster members, reached on their cluster address.
+func (n *bridge) evpnPeers() ([]evpnPeer, error) {
+	peers := []evpnPeer{}
+
+	for _, peer := range n.bgpGetPeers(n.config) {
+		fields := strings.Split(peer, ",")
+		asn, err := strconv.ParseUint(fields[1], 10, 32)
+		if err != nil {
+			return nil, err
+		}
+
+		var holdTime uint64
+		if fields[3] != "" {
+			holdTime, err = strconv.ParseUint(fields[3], 10, 32)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		peers = append(peers, evpnPeer{
+			address:  net.ParseIP(fields[0]),
+			asn:      uint32(asn),
+			password: fields[2],
+			holdTime: holdTime,
+		})
+	}
+
+	if !n.state.ServerClustered {
+		return peers, nil
+	}
+
+	var members []db.NodeInfo
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		members, err = tx.GetNodes(ctx)
+		if err != nil {
+			return fmt.Errorf("Failed getting cluster members: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, member := range members {
+		if member.Name == n.state.ServerName {
+			continue
+		}
+
+		host, _, err := net.SplitHostPort(member.Address)
+		if err != nil {
+			host = member.Address
+		}
+
+		address := net.ParseIP(host)
+		if address == nil {
+			continue
+		}
+
+		// Skip members which are already BGP peers of the network.
+		if slices.ContainsFunc(peers, func(peer evpnPeer) bool { return peer.address.Equal(address) }) {
+			continue
+		}
+
+		peers = append(peers, evpnPeer{
+			address: address,
+			asn:     uint32(n.state.GlobalConfig.BGPASN()),
+		})
+	}
+
+	return peers, nil
+}
+
+// evpnSetup sets up the BGP peers and starts the synchronization of the tunnels which use BGP EVPN.
+func (n *bridge) evpnSetup() error {
+	// Clear any existing state.
+	err := n.evpnClear()
+	if err != nil {
+		return err
+	}
+
+	tunnels := n.evpnTunnels()
+	if len(tunnels) == 0 {
+		return nil
+	}
+
+	peers, err := n.evpnPeers()
+	if err != nil {
+		return err
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	for _, peer := range peers {
+		err := n.state.BGP.AddEVPNPeer(peer.address, peer.asn, peer.password, peer.holdTime)
+		if err != nil {
+			return fmt.Errorf("Failed adding BGP EVPN peer %q: %w", peer.address, err)
+		}
+
+		reverter.Add(func() { _ = n.state.BGP.RemoveEVPNPeer(peer.address) })
+	}
+
+	evpnNetworksMu.Lock()
+	defer evpnNetworksMu.Unlock()
+
+	e := &evpnNetwork{peers: peers}
+	e.start(n.state.BGP, evpnOwner(n.id), n.name, tunnels, n.logger)
+	evpnNetworks[n.id] = e
+
+	reverter.Success()
+
+	return nil
+}
+
+// evpnClear stops the synchronization of the EVPN tunnels and removes their routes and BGP peers.
+func (n *bridge) evpnClear() error {
+	evpnNetworksMu.Lock()
+	defer evpnNetworksMu.Unlock()
+
+	e, found := evpnNetworks[n.id]
+	if !found {
+		return nil
+	}
+
+	e.stop()
+	delete(evpnNetworks, n.id)
+
+	err := n.state.BGP.RemoveEVPNRoutesByOwner(evpnOwner(n.id))
+	if err != nil {
+		return err
+	}
+
+	for _, peer := range e.peers {
+		err := n.state.BGP.RemoveEVPNPeer(peer.address)
+		if err != nil && !errors.Is(err, bgp.ErrPeerNotFound) {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // bootRoutesV4 returns a list of IPv4 boot routes on the network's device.
 func (n *bridge) bootRoutesV4() ([]ip.Route, error) {
 	r := &ip.Route{
@@ -2364,7 +2668,7 @@ func (n *bridge) bridgeProjectNetworks(projectNetworks map[string]map[int64]api.
 			network := ni // Local var creating pointer to rather than iterator.
 
 			// Skip non-bridge networks.
-			if network.Type != "bridge" {
+			if network.Type != "bridge" && network.Type != "wireguard" {
 				continue
 			}
 
@@ -2819,46 +3123,7 @@ func (n *bridge) ForwardCreate(forward api.NetworkForwardsPost, clientType reque
 
 			// If we are the first forward on this bridge, enable hairpin mode on active NIC ports.
 			if len(listenAddresses) <= 1 {
-				filter := dbCluster.InstanceFilter{Node: &n.state.ServerName}
-
-				err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-					return tx.InstanceList(ctx, func(inst db.InstanceArgs, p api.Project) error {
-						// Get the instance's effective network project name.
-						instNetworkProject := project.NetworkProjectFromRecord(&p)
-
-						if instNetworkProject != api.ProjectDefaultName {
-							return nil // Managed bridge networks can only exist in default project.
-						}
-
-						devices := db.ExpandInstanceDevices(inst.Devices.Clone(), inst.Profiles)
-
-						// Iterate through each of the instance's devices, looking for bridged NICs
-						// that are linked to this network.
-						for devName, devConfig := range devices {
-							if devConfig["type"] != "nic" {
-								continue
-							}
-
-							// Check whether the NIC device references our network..
-							if !NICUsesNetwork(devConfig, &api.Network{Name: n.Name()}) {
-								continue
-							}
-
-							hostName := inst.Config[fmt.Sprintf("volatile.%s.host_name", devName)]
-							if InterfaceExists(hostName) {
-								link := &ip.Link{Name: hostName}
-								err = link.BridgeLinkSetHairpin(true)
-								if err != nil {
-									return fmt.Errorf("Error enabling hairpin mode on bridge port %q: %w", link.Name, err)
-								}
-
-								n.logger.Debug("Enabled hairpin mode on NIC bridge port", logger.Ctx{"inst": inst.Name, "project": inst.Project, "device": devName, "dev": link.Name})
-							}
-						}
-
-						return nil
-					}, filter)
-				})
+				err = n.enableNICHairpinMode()
 				if err != nil {
 					return err
 				}
@@ -3213,6 +3478,620 @@ func (n *bridge) forwardSetupFirewall() error {
 	return nil
 }
 
+// loadBalancerGet returns the load balancer with the given listen address and its database ID.
+func (n *bridge) loadBalancerGet(listenAddress string) (int64, *api.NetworkLoadBalancer, error) {
+	var loadBalancerID int64
+	var loadBalancer *api.NetworkLoadBalancer
+
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		networkID := n.ID()
+
+		dbLoadBalancers, err := dbCluster.GetNetworkLoadBalancers(ctx, tx.Tx(), dbCluster.NetworkLoadBalancerFilter{
+			NetworkID:     &networkID,
+			ListenAddress: &listenAddress,
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(dbLoadBalancers) != 1 {
+			return api.StatusErrorf(http.StatusNotFound, "Network load balancer not found")
+		}
+
+		loadBalancerID = dbLoadBalancers[0].ID
+		loadBalancer, err = dbLoadBalancers[0].ToAPI(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return -1, nil, err
+	}
+
+	return loadBalancerID, loadBalancer, nil
+}
+
+// LoadBalancerCreate creates a network load balancer.
+// Load balancers aren't member specific, so they are only supported on standalone servers.
+func (n *bridge) LoadBalancerCreate(loadBalancer api.NetworkLoadBalancersPost, clientType request.ClientType) error {
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	if clientType == request.ClientTypeNormal {
+		// The backend health and the firewall rules are local to each member, unlike the load balancer
+		// records, so don't allow load balancers on clustered bridges.
+		if n.state.ServerClustered {
+			return api.StatusErrorf(http.StatusBadRequest, "Network load balancers aren't supported on bridge networks in a cluster")
+		}
+
+		_, _, err := n.loadBalancerGet(loadBalancer.ListenAddress)
+		if err == nil {
+			return api.StatusErrorf(http.StatusConflict, "A load balancer for that listen address already exists")
+		}
+
+		// Convert listen address to subnet so we can check its valid and can be used.
+		listenAddressNet, err := ParseIPToNet(loadBalancer.ListenAddress)
+		if err != nil {
+			return fmt.Errorf("Failed parsing %q: %w", loadBalancer.ListenAddress, err)
+		}
+
+		_, err = n.loadBalancerValidate(listenAddressNet.IP, &loadBalancer.NetworkLoadBalancerPut)
+		if err != nil {
+			return err
+		}
+
+		externalSubnetsInUse, err := n.getExternalSubnetInUse()
+		if err != nil {
+			return err
+		}
+
+		// Check the listen address subnet doesn't fall within any existing network external subnets.
+		for _, externalSubnetUser := range externalSubnetsInUse {
+			// Check if usage is from our own network.
+			if externalSubnetUser.networkProject == n.project && externalSubnetUser.networkName == n.name {
+				// Skip checking conflict with our own network's subnet or SNAT address.
+				// But do not allow other conflict with other usage types within our own network.
+				if externalSubnetUser.usageType == subnetUsageNetwork || externalSubnetUser.usageType == subnetUsageNetworkSNAT {
+					continue
+				}
+			}
+
+			if SubnetContains(&externalSubnetUser.subnet, listenAddressNet) || SubnetContains(listenAddressNet, &externalSubnetUser.subnet) {
+				// This error is purposefully vague so that it doesn't reveal any names of
+				// resources potentially outside of the network.
+				return fmt.Errorf("Load balancer listen address %q overlaps with another network or NIC", listenAddressNet.String())
+			}
+		}
+
+		var loadBalancerID int64
+
+		err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			// Create load balancer DB record.
+			lb := dbCluster.NetworkLoadBalancer{
+				NetworkID:     n.ID(),
+				ListenAddress: loadBalancer.ListenAddress,
+				Description:   loadBalancer.Description,
+				Backends:      loadBalancer.Backends,
+				Ports:         loadBalancer.Ports,
+			}
+
+			loadBalancerID, err = dbCluster.CreateNetworkLoadBalancer(ctx, tx.Tx(), lb)
+			if err != nil {
+				return err
+			}
+
+			// Save the load balancer configuration.
+			err = dbCluster.CreateNetworkLoadBalancerConfig(ctx, tx.Tx(), loadBalancerID, loadBalancer.Config)
+			if err != nil {
+				return err
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		reverter.Add(func() {
+			_ = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+				return dbCluster.DeleteNetworkLoadBalancer(ctx, tx.Tx(), n.ID(), loadBalancerID)
+			})
+
+			_ = n.loadBalancerSetupFirewall()
+		})
+	}
+
+	err := n.loadBalancerSetupFirewall()
+	if err != nil {
+		return err
+	}
+
+	// Enable hairpin mode on active NIC bridge ports in case an instance connects to a load balancer targeting it.
+	if n.config["bridge.driver"] != "openvswitch" {
+		for _, ipVersion := range []uint{4, 6} {
+			if BridgeNetfilterEnabled(ipVersion) == nil {
+				err = n.enableNICHairpinMode()
+				if err != nil {
+					return err
+				}
+
+				break
+			}
+		}
+	}
+
+	reverter.Success()
+
+	return nil
+}
+
+// LoadBalancerUpdate updates a network load balancer.
+func (n *bridge) LoadBalancerUpdate(listenAddress string, req api.NetworkLoadBalancerPut, clientType request.ClientType) error {
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	if clientType == request.ClientTypeNormal {
+		curLoadBalancerID, curLoadBalancer, err := n.loadBalancerGet(listenAddress)
+		if err != nil {
+			return err
+		}
+
+		_, err = n.loadBalancerValidate(net.ParseIP(curLoadBalancer.ListenAddress), &req)
+		if err != nil {
+			return err
+		}
+
+		curEtagHash, err := localUtil.EtagHash(curLoadBalancer.Etag())
+		if err != nil {
+			return err
+		}
+
+		newLoadBalancer := api.NetworkLoadBalancer{
+			ListenAddress:          curLoadBalancer.ListenAddress,
+			NetworkLoadBalancerPut: req,
+		}
+
+		newLoadBalancerEtagHash, err := localUtil.EtagHash(newLoadBalancer.Etag())
+		if err != nil {
+			return err
+		}
+
+		if curEtagHash == newLoadBalancerEtagHash {
+			return nil // Nothing has changed.
+		}
+
+		err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			lb := dbCluster.NetworkLoadBalancer{
+				NetworkID:     n.ID(),
+				ListenAddress: listenAddress,
+				Description:   newLoadBalancer.Description,
+				Backends:      newLoadBalancer.Backends,
+				Ports:         newLoadBalancer.Ports,
+			}
+
+			err = dbCluster.UpdateNetworkLoadBalancer(ctx, tx.Tx(), n.ID(), listenAddress, lb)
+			if err != nil {
+				return err
+			}
+
+			err = dbCluster.UpdateNetworkLoadBalancerConfig(ctx, tx.Tx(), curLoadBalancerID, newLoadBalancer.Config)
+			if err != nil {
+				return err
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		reverter.Add(func() {
+			_ = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+				lb := dbCluster.NetworkLoadBalancer{
+					NetworkID:     n.ID(),
+					ListenAddress: listenAddress,
+					Description:   curLoadBalancer.Description,
+					Backends:      curLoadBalancer.Backends,
+					Ports:         curLoadBalancer.Ports,
+				}
+
+				err = dbCluster.UpdateNetworkLoadBalancer(ctx, tx.Tx(), n.ID(), listenAddress, lb)
+				if err != nil {
+					return err
+				}
+
+				err = dbCluster.UpdateNetworkLoadBalancerConfig(ctx, tx.Tx(), curLoadBalancerID, curLoadBalancer.Config)
+				if err != nil {
+					return err
+				}
+
+				return nil
+			})
+
+			_ = n.loadBalancerSetupFirewall()
+		})
+	}
+
+	err := n.loadBalancerSetupFirewall()
+	if err != nil {
+		return err
+	}
+
+	reverter.Success()
+
+	return nil
+}
+
+// LoadBalancerState returns the current state of the load balancer, as seen from this member.
+// The backend health is only tracked in memory by the health checks running on this member.
+func (n *bridge) LoadBalancerState(lb api.NetworkLoadBalancer) (*api.NetworkLoadBalancerState, error) {
+	lbState := &api.NetworkLoadBalancerState{}
+
+	if !util.IsTrue(lb.Config["healthcheck"]) {
+		return lbState, nil
+	}
+
+	loadBalancerHealthChecksMu.Lock()
+	check := loadBalancerHealthChecks[loadBalancerHealthCheckKey(n.project, n.name, lb.ListenAddress)]
+	loadBalancerHealthChecksMu.Unlock()
+
+	lbState.BackendHealth = map[string]api.NetworkLoadBalancerStateBackendHealth{}
+
+	for _, backend := range lb.Backends {
+		backendHealth := api.NetworkLoadBalancerStateBackendHealth{}
+		backendHealth.Address = backend.TargetAddress
+		backendHealth.Ports = []api.NetworkLoadBalancerStateBackendHealthPort{}
+
+		// Parse the backend target port(s).
+		var targetPorts []uint64
+		for _, pr := range util.SplitNTrimSpace(backend.TargetPort, ",", -1, true) {
+			portFirst, portRange, err := ParsePortRange(pr)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid target port in backend %q: %w", backend.Name, err)
+			}
+
+			for i := range portRange {
+				targetPorts = append(targetPorts, uint64(portFirst+i))
+			}
+		}
+
+		for _, lbPort := range lb.Ports {
+			if !slices.Contains(lbPort.TargetBackend, backend.Name) {
+				continue
+			}
+
+			// Check valid listen port(s) supplied.
+			listenPortRanges := util.SplitNTrimSpace(lbPort.ListenPort, ",", -1, true)
+			if len(listenPortRanges) <= 0 {
+				return nil, fmt.Errorf("Missing listen port in port specification %q", lbPort.ListenPort)
+			}
+
+			listenPortIndex := 0
+			for _, pr := range listenPortRanges {
+				portFirst, portRange, err := ParsePortRange(pr)
+				if err != nil {
+					return nil, fmt.Errorf("Invalid listen port in port specification %q: %w", lbPort.ListenPort, err)
+				}
+
+				for i := range portRange {
+					port := portFirst + i
+
+					// Find the target port the same way as when applying the load balancer.
+					targetPort := uint64(port)
+					if len(targetPorts) == 1 {
+						targetPort = targetPorts[0]
+					} else if len(targetPorts) > 1 && listenPortIndex < len(targetPorts) {
+						targetPort = targetPorts[listenPortIndex]
+					}
+
+					listenPortIndex++
+
+					status := loadBalancerHealthUnknown
+					if check != nil {
+						status = check.status(loadBalancerHealthTarget{protocol: lbPort.Protocol, address: net.ParseIP(backend.TargetAddress).String(), port: targetPort})
+					}
+
+					portHealth := api.NetworkLoadBalancerStateBackendHealthPort{
+						Protocol: lbPort.Protocol,
+						Port:     int(port),
+						Status:   status,
+					}
+
+					backendHealth.Ports = append(backendHealth.Ports, portHealth)
+				}
+			}
+		}
+
+		lbState.BackendHealth[backend.Name] = backendHealth
+	}
+
+	return lbState, nil
+}
+
+// LoadBalancerDelete deletes a network load balancer.
+func (n *bridge) LoadBalancerDelete(listenAddress string, clientType request.ClientType) error {
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	if clientType == request.ClientTypeNormal {
+		loadBalancerID, loadBalancer, err := n.loadBalancerGet(listenAddress)
+		if err != nil {
+			return err
+		}
+
+		err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return dbCluster.DeleteNetworkLoadBalancer(ctx, tx.Tx(), n.ID(), loadBalancerID)
+		})
+		if err != nil {
+			return err
+		}
+
+		reverter.Add(func() {
+			_ = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+				lb := dbCluster.NetworkLoadBalancer{
+					NetworkID:     n.ID(),
+					ListenAddress: loadBalancer.ListenAddress,
+					Description:   loadBalancer.Description,
+					Backends:      loadBalancer.Backends,
+					Ports:         loadBalancer.Ports,
+				}
+
+				loadBalancerID, err = dbCluster.CreateNetworkLoadBalancer(ctx, tx.Tx(), lb)
+				if err != nil {
+					return err
+				}
+
+				return dbCluster.CreateNetworkLoadBalancerConfig(ctx, tx.Tx(), loadBalancerID, loadBalancer.Config)
+			})
+
+			_ = n.loadBalancerSetupFirewall()
+		})
+	}
+
+	err := n.loadBalancerSetupFirewall()
+	if err != nil {
+		return err
+	}
+
+	reverter.Success()
+
+	return nil
+}
+
+// loadBalancerConvertToFirewall converts the load balancer port maps into the rules applied by the firewall,
+// one for each listen port.
+func (n *bridge) loadBalancerConvertToFirewall(listenAddress net.IP, portMaps []*loadBalancerPortMap, hashed bool) []firewallDrivers.LoadBalancer {
+	var loadBalancers []firewallDrivers.LoadBalancer
+
+	for _, portMap := range portMaps {
+		for i, lp := range portMap.listenPorts {
+			lb := firewallDrivers.LoadBalancer{
+				ListenAddress: listenAddress,
+				Protocol:      portMap.protocol,
+				ListenPort:    lp,
+				Hashed:        hashed,
+			}
+
+			for _, target := range portMap.targets {
+				targetPort := lp // Default to using same port as listen port for target port.
+				targetPortsLen := len(target.ports)
+
+				if targetPortsLen == 1 {
+					// If a single target port is specified, forward all listen ports to it.
+					targetPort = target.ports[0]
+				} else if targetPortsLen > 1 {
+					// If more than 1 target port specified, use listen port index to get the
+					// target port to use.
+					targetPort = target.ports[i]
+				}
+
+				lb.Targets = append(lb.Targets, firewallDrivers.LoadBalancerTarget{
+					Address: target.address,
+					Port:    targetPort,
+				})
+			}
+
+			// Ports without backends are left alone.
+			if len(lb.Targets) == 0 {
+				continue
+			}
+
+			loadBalancers = append(loadBalancers, lb)
+		}
+	}
+
+	return loadBalancers
+}
+
+// loadBalancerSetupFirewall applies all network load balancers defined for this network.
+// It also starts or stops the health checks of their backends, leaving out the backends found offline.
+func (n *bridge) loadBalancerSetupFirewall() error {
+	var loadBalancers []*api.NetworkLoadBalancer
+
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		networkID := n.ID()
+
+		dbLoadBalancers, err := dbCluster.GetNetworkLoadBalancers(ctx, tx.Tx(), dbCluster.NetworkLoadBalancerFilter{
+			NetworkID: &networkID,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, dbLoadBalancer := range dbLoadBalancers {
+			loadBalancer, err := dbLoadBalancer.ToAPI(ctx, tx.Tx())
+			if err != nil {
+				return err
+			}
+
+			loadBalancers = append(loadBalancers, loadBalancer)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading network load balancers: %w", err)
+	}
+
+	// Health checks call back into here once a backend changes state, so hold the lock until the rules
+	// are applied to avoid racing with them.
+	loadBalancerHealthChecksMu.Lock()
+	defer loadBalancerHealthChecksMu.Unlock()
+
+	var fwLoadBalancers []firewallDrivers.LoadBalancer
+	checkKeys := map[string]struct{}{}
+
+	for _, loadBalancer := range loadBalancers {
+		listenAddress := net.ParseIP(loadBalancer.ListenAddress)
+
+		portMaps, err := n.loadBalancerValidate(listenAddress, &loadBalancer.NetworkLoadBalancerPut)
+		if err != nil {
+			return fmt.Errorf("Failed validating load balancer for listen address %q: %w", loadBalancer.ListenAddress, err)
+		}
+
+		lbRules := n.loadBalancerConvertToFirewall(listenAddress, portMaps, loadBalancer.Config["balancing.mode"] == "hash")
+
+		if util.IsTrue(loadBalancer.Config["healthcheck"]) {
+			key := loadBalancerHealthCheckKey(n.project, n.name, loadBalancer.ListenAddress)
+			checkKeys[key] = struct{}{}
+
+			check, err := n.loadBalancerHealthCheck(key, loadBalancer, lbRules)
+			if err != nil {
+				return err
+			}
+
+			lbRules = check.filter(lbRules)
+		}
+
+		fwLoadBalancers = append(fwLoadBalancers, lbRules...)
+	}
+
+	// Stop the health checks of the load balancers which were removed or had them disabled.
+	prefix := loadBalancerHealthCheckKey(n.project, n.name, "")
+	for key, check := range loadBalancerHealthChecks {
+		_, found := checkKeys[key]
+		if found || !strings.HasPrefix(key, prefix) {
+			continue
+		}
+
+		check.stop()
+		delete(loadBalancerHealthChecks, key)
+	}
+
+	err = n.state.Firewall.NetworkApplyLoadBalancers(n.name, fwLoadBalancers)
+	if err != nil {
+		return fmt.Errorf("Failed applying firewall load balancers: %w", err)
+	}
+
+	return nil
+}
+
+// loadBalancerHealthCheck returns the running health check of the load balancer, starting it if needed.
+// Must be called with loadBalancerHealthChecksMu held.
+func (n *bridge) loadBalancerHealthCheck(key string, loadBalancer *api.NetworkLoadBalancer, lbRules []firewallDrivers.LoadBalancer) (*loadBalancerHealthCheck, error) {
+	hash, err := localUtil.EtagHash(loadBalancer.Etag())
+	if err != nil {
+		return nil, err
+	}
+
+	// Re-apply the rules once a backend goes online or offline.
+	onChange := func() {
+		if !n.isRunning() {
+			return
+		}
+
+		err := n.loadBalancerSetupFirewall()
+		if err != nil {
+			n.logger.Error("Failed applying load balancers after backend health change", logger.Ctx{"err": err})
+		}
+	}
+
+	check := loadBalancerHealthChecks[key]
+	if check != nil && check.hash == hash {
+		check.setOnChange(onChange)
+		return check, nil
+	}
+
+	if check != nil {
+		check.stop()
+		delete(loadBalancerHealthChecks, key)
+	}
+
+	check, err = newLoadBalancerHealthCheck(hash, loadBalancer.Config, loadBalancerHealthTargets(lbRules), onChange)
+	if err != nil {
+		return nil, fmt.Errorf("Failed setting up health check for load balancer %q: %w", loadBalancer.ListenAddress, err)
+	}
+
+	check.start()
+	loadBalancerHealthChecks[key] = check
+
+	return check, nil
+}
+
+// loadBalancerStopHealthChecks stops the health checks of all the load balancers of this network.
+func (n *bridge) loadBalancerStopHealthChecks() {
+	loadBalancerHealthChecksMu.Lock()
+	defer loadBalancerHealthChecksMu.Unlock()
+
+	prefix := loadBalancerHealthCheckKey(n.project, n.name, "")
+	for key, check := range loadBalancerHealthChecks {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+
+		check.stop()
+		delete(loadBalancerHealthChecks, key)
+	}
+}
+
+// enableNICHairpinMode enables hairpin mode on the bridge ports of the active NICs connected to this network.
+// This lets instances connect to the listen address of a forward or load balancer targeting them, when
+// br_netfilter is enabled.
+func (n *bridge) enableNICHairpinMode() error {
+	filter := dbCluster.InstanceFilter{Node: &n.state.ServerName}
+
+	return n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.InstanceList(ctx, func(inst db.InstanceArgs, p api.Project) error {
+			// Get the instance's effective network project name.
+			instNetworkProject := project.NetworkProjectFromRecord(&p)
+
+			if instNetworkProject != api.ProjectDefaultName {
+				return nil // Managed bridge networks can only exist in default project.
+			}
+
+			devices := db.ExpandInstanceDevices(inst.Devices.Clone(), inst.Profiles)
+
+			// Iterate through each of the instance's devices, looking for bridged NICs
+			// that are linked to this network.
+			for devName, devConfig := range devices {
+				if devConfig["type"] != "nic" {
+					continue
+				}
+
+				// Check whether the NIC device references our network..
+				if !NICUsesNetwork(devConfig, &api.Network{Name: n.Name()}) {
+					continue
+				}
+
+				hostName := inst.Config[fmt.Sprintf("volatile.%s.host_name", devName)]
+				if InterfaceExists(hostName) {
+					link := &ip.Link{Name: hostName}
+					err := link.BridgeLinkSetHairpin(true)
+					if err != nil {
+						return fmt.Errorf("Error enabling hairpin mode on bridge port %q: %w", link.Name, err)
+					}
+
+					n.logger.Debug("Enabled hairpin mode on NIC bridge port", logger.Ctx{"inst": inst.Name, "project": inst.Project, "device": devName, "dev": link.Name})
+				}
+			}
+
+			return nil
+		}, filter)
+	})
+}
+
 // Leases returns a list of leases for the bridged network. It will reach out to other cluster members as needed.
 // The projectName passed here refers to the initial project from the API request which may differ from the network's project.
 func (n *bridge) Leases(projectName string, clientType request.ClientType) ([]api.NetworkLease, error) {
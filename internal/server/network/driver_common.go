@@ -1291,6 +1291,32 @@ func (n *common) loadBalancerValidate(listenAddress net.IP, forward *api.Network
 
 	// Check the configuration.
 	lbOptions := map[string]func(value string) error{
+		// gendoc:generate(entity=network_load_balancer, group=common, key=balancing.mode)
+		// Possible values are `round-robin` (each new connection goes to the next backend) and `hash`
+		// (connections are spread based on a hash of the source address, so a client sticks to a backend).
+		// Hashing is only available with the `nftables` firewall driver.
+		// ---
+		//  type: string
+		//  condition: bridge network
+		//  defaultdesc: `round-robin`
+		//  shortdesc: How connections are spread over the backends
+		"balancing.mode": func(value string) error {
+			if n.netType != "bridge" {
+				return errors.New("Only load balancers on bridge networks support selecting the balancing mode")
+			}
+
+			err := validate.Optional(validate.IsOneOf("round-robin", "hash"))(value)
+			if err != nil {
+				return err
+			}
+
+			if value == "hash" && n.state.Firewall != nil && n.state.Firewall.String() == "xtables" {
+				return errors.New("Hashed load balancing is not supported with the xtables firewall driver")
+			}
+
+			return nil
+		},
+
 		// gendoc:generate(entity=network_load_balancer, group=common, key=healthcheck)
 		//
 		// ---
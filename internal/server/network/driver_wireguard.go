@@ -0,0 +1,722 @@
+package network
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/lxc/incus/v6/internal/server/cluster/request"
+	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/ip"
+	"github.com/lxc/incus/v6/internal/server/network/acl"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	internalUtil "github.com/lxc/incus/v6/internal/util"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/revert"
+	"github.com/lxc/incus/v6/shared/subprocess"
+	"github.com/lxc/incus/v6/shared/util"
+	"github.com/lxc/incus/v6/shared/validate"
+)
+
+// Default listen port for the WireGuard interface.
+const wireguardListenPortDefault = 51820
+
+// wireguard represents a WireGuard network.
+// It's a bridge network whose traffic to the peers' allowed IPs is routed through a WireGuard interface.
+type wireguard struct {
+	bridge
+}
+
+// DBType returns the network type DB ID.
+func (n *wireguard) DBType() db.NetworkType {
+	return db.NetworkTypeWireguard
+}
+
+// Info returns the network driver info.
+func (n *wireguard) Info() Info {
+	info := n.bridge.Info()
+	info.Peering = true
+
+	return info
+}
+
+// Create checks whether the network can be created.
+func (n *wireguard) Create(clientType request.ClientType) error {
+	// Every server needs its own key pair and peers.
+	if n.state.ServerClustered {
+		return errors.New("WireGuard networks aren't supported on clusters")
+	}
+
+	if InterfaceExists(n.wireguardInterfaceName()) {
+		return fmt.Errorf("Network interface %q already exists", n.wireguardInterfaceName())
+	}
+
+	return n.bridge.Create(clientType)
+}
+
+// State returns the network state, including the WireGuard details.
+func (n *wireguard) State() (*api.NetworkState, error) {
+	state, err := n.common.State()
+	if err != nil {
+		return nil, err
+	}
+
+	// Don't generate the key here, it only exists once the network has been started.
+	privateKey, err := n.wireguardReadPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	var publicKey string
+	if privateKey != "" {
+		publicKey, err = wireguardPublicKey(privateKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	state.Wireguard = &api.NetworkStateWireguard{
+		Interface:  n.wireguardInterfaceName(),
+		PublicKey:  publicKey,
+		ListenPort: n.wireguardListenPort(),
+	}
+
+	return state, nil
+}
+
+// PeerCreate creates a WireGuard peer.
+func (n *wireguard) PeerCreate(peer api.NetworkPeersPost) error {
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	// Default type is wireguard.
+	if peer.Type == "" {
+		peer.Type = "wireguard"
+	}
+
+	if peer.Type != "wireguard" {
+		return api.StatusErrorf(http.StatusBadRequest, "Only wireguard peers are supported on WireGuard networks")
+	}
+
+	if peer.TargetProject != "" || peer.TargetNetwork != "" || peer.TargetIntegration != "" {
+		return api.StatusErrorf(http.StatusBadRequest, "WireGuard peers can't have a target")
+	}
+
+	peers, err := n.wireguardPeers()
+	if err != nil {
+		return err
+	}
+
+	for _, existingPeer := range peers {
+		if peer.Name == existingPeer.Name {
+			return api.StatusErrorf(http.StatusConflict, "A peer for that name already exists")
+		}
+	}
+
+	err = n.wireguardPeerValidate(peer.Name, &peer.NetworkPeerPut, peers)
+	if err != nil {
+		return err
+	}
+
+	var peerID int64
+
+	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		record := dbCluster.NetworkPeer{
+			NetworkID:   n.ID(),
+			Name:        peer.Name,
+			Description: peer.Description,
+			Type:        dbCluster.NetworkPeerTypeWireguard,
+		}
+
+		peerID, err = dbCluster.CreateNetworkPeer(ctx, tx.Tx(), record)
+		if err != nil {
+			return err
+		}
+
+		return dbCluster.CreateNetworkPeerConfig(ctx, tx.Tx(), peerID, peer.Config)
+	})
+	if err != nil {
+		return err
+	}
+
+	reverter.Add(func() {
+		_ = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			err := dbCluster.DeleteNetworkPeer(ctx, tx.Tx(), n.ID(), peerID)
+			if errors.Is(err, dbCluster.ErrNotFound) {
+				return nil
+			}
+
+			return err
+		})
+	})
+
+	err = n.wireguardApply()
+	if err != nil {
+		return err
+	}
+
+	reverter.Success()
+	return nil
+}
+
+// PeerUpdate updates a WireGuard peer.
+func (n *wireguard) PeerUpdate(peerName string, req api.NetworkPeerPut) error {
+	var curPeer *api.NetworkPeer
+	var dbCurPeer *dbCluster.NetworkPeer
+
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		dbCurPeer, err = dbCluster.GetNetworkPeer(ctx, tx.Tx(), n.id, peerName)
+		if err != nil {
+			return fmt.Errorf("Failed getting network peer DB object: %w", err)
+		}
+
+		curPeer, err = dbCurPeer.ToAPI(ctx, tx.Tx())
+		if err != nil {
+			return fmt.Errorf("Failed converting network peer DB object to API object: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	peers, err := n.wireguardPeers()
+	if err != nil {
+		return err
+	}
+
+	err = n.wireguardPeerValidate(peerName, &req, peers)
+	if err != nil {
+		return err
+	}
+
+	curPeerEtagHash, err := localUtil.EtagHash(curPeer.Etag())
+	if err != nil {
+		return err
+	}
+
+	newPeer := api.NetworkPeer{
+		Name:           curPeer.Name,
+		NetworkPeerPut: req,
+	}
+
+	newPeerEtagHash, err := localUtil.EtagHash(newPeer.Etag())
+	if err != nil {
+		return err
+	}
+
+	if curPeerEtagHash == newPeerEtagHash {
+		return nil // Nothing has changed.
+	}
+
+	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		dbCurPeer.Description = newPeer.Description
+
+		err := dbCluster.UpdateNetworkPeer(ctx, tx.Tx(), n.id, dbCurPeer.Name, *dbCurPeer)
+		if err != nil {
+			return fmt.Errorf("Failed to update network peer: %w", err)
+		}
+
+		err = dbCluster.UpdateNetworkPeerConfig(ctx, tx.Tx(), dbCurPeer.ID, newPeer.Config)
+		if err != nil {
+			return fmt.Errorf("Failed to update network peer config: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return n.wireguardApply()
+}
+
+// PeerDelete deletes a WireGuard peer.
+func (n *wireguard) PeerDelete(peerName string) error {
+	isUsed, err := n.peerIsUsed(peerName)
+	if err != nil {
+		return err
+	}
+
+	if isUsed {
+		return errors.New("Cannot delete a peer that is in use")
+	}
+
+	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		dbPeer, err := dbCluster.GetNetworkPeer(ctx, tx.Tx(), n.id, peerName)
+		if err != nil {
+			return fmt.Errorf("Failed getting network peer DB object: %w", err)
+		}
+
+		return dbCluster.DeleteNetworkPeer(ctx, tx.Tx(), n.id, dbPeer.ID)
+	})
+	if err != nil {
+		return err
+	}
+
+	return n.wireguardApply()
+}
+
+// wireguardApply applies the peer changes to the running network.
+// The whole network is set up again as the peers' allowed IPs also affect the DHCP routes and NAT rules.
+func (n *wireguard) wireguardApply() error {
+	if !n.isRunning() {
+		return nil
+	}
+
+	return n.setup(n.config)
+}
+
+// wireguardPeerValidate validates a WireGuard peer against the other peers of the network.
+func (n *wireguard) wireguardPeerValidate(peerName string, peer *api.NetworkPeerPut, peers []*api.NetworkPeer) error {
+	err := acl.ValidName(peerName)
+	if err != nil {
+		return err
+	}
+
+	if slices.Contains(acl.ReservedNetworkSubects, peerName) {
+		return fmt.Errorf("Name cannot be one of the reserved network subjects: %v", acl.ReservedNetworkSubects)
+	}
+
+	rules := map[string]func(value string) error{
+		// gendoc:generate(entity=network_peer, group=wireguard, key=wireguard.public_key)
+		//
+		// ---
+		//  type: string
+		//  required: yes
+		//  shortdesc: Public key of the peer (base64)
+		"wireguard.public_key": validate.Required(validateWireguardKey),
+
+		// gendoc:generate(entity=network_peer, group=wireguard, key=wireguard.allowed_ips)
+		// Traffic to those subnets is routed to the peer, and traffic from the peer is only accepted from them.
+		// ---
+		//  type: string
+		//  required: yes
+		//  shortdesc: Comma-separated list of subnets reachable through the peer
+		"wireguard.allowed_ips": validate.Required(validate.IsListOf(validate.IsNetwork)),
+
+		// gendoc:generate(entity=network_peer, group=wireguard, key=wireguard.endpoint)
+		// Without an endpoint, the peer must connect first.
+		// ---
+		//  type: string
+		//  defaultdesc: -
+		//  shortdesc: Address and port of the peer (`<host>:<port>`)
+		"wireguard.endpoint": validate.Optional(validate.IsListenAddress(true, false, true)),
+
+		// gendoc:generate(entity=network_peer, group=wireguard, key=wireguard.persistent_keepalive)
+		//
+		// ---
+		//  type: integer
+		//  defaultdesc: `0` (disabled)
+		//  shortdesc: Interval (in seconds) at which keepalive packets are sent to the peer
+		"wireguard.persistent_keepalive": validate.Optional(validate.IsInRange(0, 65535)),
+
+		// gendoc:generate(entity=network_peer, group=wireguard, key=wireguard.preshared_key)
+		//
+		// ---
+		//  type: string
+		//  defaultdesc: -
+		//  shortdesc: Additional symmetric key shared with the peer (base64)
+		"wireguard.preshared_key": validate.Optional(validateWireguardKey),
+
+		// gendoc:generate(entity=network_peer, group=wireguard, key=user.*)
+		//
+		// ---
+		//  type: string
+		//  shortdesc: User defined key/value configuration
+	}
+
+	for k, validator := range rules {
+		err := validator(peer.Config[k])
+		if err != nil {
+			return fmt.Errorf("Invalid value for peer option %q: %w", k, err)
+		}
+	}
+
+	for k := range peer.Config {
+		_, found := rules[k]
+		if found {
+			continue
+		}
+
+		// User keys are not validated.
+		if strings.HasPrefix(k, "user.") {
+			continue
+		}
+
+		return fmt.Errorf("Invalid option %q", k)
+	}
+
+	// Check the allowed IPs don't overlap with the network or the other peers.
+	allowedIPs := wireguardAllowedIPs(peer.Config)
+
+	for _, key := range []string{"ipv4.address", "ipv6.address"} {
+		subnet, err := ParseIPCIDRToNet(n.config[key])
+		if err != nil {
+			continue
+		}
+
+		for _, allowedIP := range allowedIPs {
+			if SubnetContains(allowedIP, subnet) || SubnetContains(subnet, allowedIP) {
+				return fmt.Errorf("Allowed IPs %q overlap with the network subnet %q", allowedIP.String(), subnet.String())
+			}
+		}
+	}
+
+	for _, otherPeer := range peers {
+		if otherPeer.Name == peerName {
+			continue
+		}
+
+		if otherPeer.Config["wireguard.public_key"] == peer.Config["wireguard.public_key"] {
+			return api.StatusErrorf(http.StatusConflict, "Peer %q already uses that public key", otherPeer.Name)
+		}
+
+		for _, otherAllowedIP := range wireguardAllowedIPs(otherPeer.Config) {
+			for _, allowedIP := range allowedIPs {
+				if SubnetContains(allowedIP, otherAllowedIP) || SubnetContains(otherAllowedIP, allowedIP) {
+					return api.StatusErrorf(http.StatusConflict, "Allowed IPs %q overlap with those of peer %q", allowedIP.String(), otherPeer.Name)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateWireguardKey validates a base64 encoded WireGuard key.
+func validateWireguardKey(value string) error {
+	key, err := base64.StdEncoding.DecodeString(value)
+	if err != nil || len(key) != 32 {
+		return errors.New("Invalid WireGuard key, must be 32 bytes encoded in base64")
+	}
+
+	return nil
+}
+
+// wireguardPublicKey returns the base64 encoded public key matching the private key.
+func wireguardPublicKey(privateKey string) (string, error) {
+	keyBytes, err := base64.StdEncoding.DecodeString(privateKey)
+	if err != nil {
+		return "", fmt.Errorf("Failed decoding WireGuard private key: %w", err)
+	}
+
+	key, err := ecdh.X25519().NewPrivateKey(keyBytes)
+	if err != nil {
+		return "", fmt.Errorf("Failed loading WireGuard private key: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(key.PublicKey().Bytes()), nil
+}
+
+// wireguardAllowedIPs returns the subnets from the peer's allowed IPs.
+func wireguardAllowedIPs(peerConfig map[string]string) []*net.IPNet {
+	allowedIPs := []*net.IPNet{}
+
+	for _, value := range util.SplitNTrimSpace(peerConfig["wireguard.allowed_ips"], ",", -1, true) {
+		_, subnet, err := net.ParseCIDR(value)
+		if err != nil {
+			continue
+		}
+
+		allowedIPs = append(allowedIPs, subnet)
+	}
+
+	return allowedIPs
+}
+
+// wireguardPeerSubnets returns the allowed IPs of all peers for the IP family (4 or 6).
+func wireguardPeerSubnets(peers []*api.NetworkPeer, ipVersion uint) []*net.IPNet {
+	var subnets []*net.IPNet
+
+	for _, peer := range peers {
+		for _, subnet := range wireguardAllowedIPs(peer.Config) {
+			if (subnet.IP.To4() != nil) == (ipVersion == 4) {
+				subnets = append(subnets, subnet)
+			}
+		}
+	}
+
+	return subnets
+}
+
+// wireguardDHCPRoutes returns the classless static routes (DHCP option 121) announcing the peers' subnets
+// through the network address. As clients ignore the router option when given classless static routes, a
+// default route through the gateway is added unless already part of the configured routes.
+func wireguardDHCPRoutes(routes string, address net.IP, gateway net.IP, subnets []*net.IPNet) string {
+	if len(subnets) == 0 {
+		return routes
+	}
+
+	entries := util.SplitNTrimSpace(routes, ",", -1, true)
+
+	hasDefault := false
+	for i := 0; i < len(entries); i += 2 {
+		if entries[i] == "0.0.0.0/0" {
+			hasDefault = true
+			break
+		}
+	}
+
+	for _, subnet := range subnets {
+		entries = append(entries, subnet.String(), address.String())
+	}
+
+	if !hasDefault {
+		entries = append(entries, "0.0.0.0/0", gateway.String())
+	}
+
+	return strings.Join(entries, ",")
+}
+
+// wireguardConfig returns the configuration of the WireGuard interface, in the format used by "wg syncconf".
+func wireguardConfig(privateKey string, listenPort int, peers []*api.NetworkPeer) string {
+	var sb strings.Builder
+
+	sb.WriteString("[Interface]\n")
+	fmt.Fprintf(&sb, "PrivateKey = %s\n", privateKey)
+	fmt.Fprintf(&sb, "ListenPort = %d\n", listenPort)
+
+	for _, peer := range peers {
+		sb.WriteString("\n[Peer]\n")
+		fmt.Fprintf(&sb, "PublicKey = %s\n", peer.Config["wireguard.public_key"])
+
+		if peer.Config["wireguard.preshared_key"] != "" {
+			fmt.Fprintf(&sb, "PresharedKey = %s\n", peer.Config["wireguard.preshared_key"])
+		}
+
+		allowedIPs := []string{}
+		for _, subnet := range wireguardAllowedIPs(peer.Config) {
+			allowedIPs = append(allowedIPs, subnet.String())
+		}
+
+		fmt.Fprintf(&sb, "AllowedIPs = %s\n", strings.Join(allowedIPs, ", "))
+
+		if peer.Config["wireguard.endpoint"] != "" {
+			fmt.Fprintf(&sb, "Endpoint = %s\n", peer.Config["wireguard.endpoint"])
+		}
+
+		if peer.Config["wireguard.persistent_keepalive"] != "" {
+			fmt.Fprintf(&sb, "PersistentKeepalive = %s\n", peer.Config["wireguard.persistent_keepalive"])
+		}
+	}
+
+	return sb.String()
+}
+
+// wireguardInterfaceName returns the name of the WireGuard interface of the network.
+func (n *bridge) wireguardInterfaceName() string {
+	return fmt.Sprintf("incuswg%d", n.id)
+}
+
+// wireguardListenPort returns the listen port of the WireGuard interface.
+func (n *bridge) wireguardListenPort() int {
+	port, err := strconv.Atoi(n.config["wireguard.listen_port"])
+	if err != nil {
+		return wireguardListenPortDefault
+	}
+
+	return port
+}
+
+// wireguardReadPrivateKey returns the private key of the WireGuard interface, or an empty string if missing.
+// It's stored alongside the network state rather than in its configuration to keep it out of the API.
+func (n *bridge) wireguardReadPrivateKey() (string, error) {
+	content, err := os.ReadFile(internalUtil.VarPath("networks", n.name, "wireguard.key"))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", nil
+		}
+
+		return "", fmt.Errorf("Failed reading WireGuard private key: %w", err)
+	}
+
+	return strings.TrimSpace(string(content)), nil
+}
+
+// wireguardPrivateKey returns the private key of the WireGuard interface, generating it if missing.
+func (n *bridge) wireguardPrivateKey() (string, error) {
+	privateKey, err := n.wireguardReadPrivateKey()
+	if err != nil {
+		return "", err
+	}
+
+	if privateKey != "" {
+		return privateKey, nil
+	}
+
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return "", fmt.Errorf("Failed generating WireGuard private key: %w", err)
+	}
+
+	privateKey = base64.StdEncoding.EncodeToString(key.Bytes())
+
+	err = os.MkdirAll(internalUtil.VarPath("networks", n.name), 0o711)
+	if err != nil {
+		return "", err
+	}
+
+	err = os.WriteFile(internalUtil.VarPath("networks", n.name, "wireguard.key"), []byte(privateKey+"\n"), 0o600)
+	if err != nil {
+		return "", fmt.Errorf("Failed writing WireGuard private key: %w", err)
+	}
+
+	return privateKey, nil
+}
+
+// wireguardValidationRules adds the WireGuard specific validation rules to the bridge rules.
+func (n *bridge) wireguardValidationRules(rules map[string]func(value string) error) {
+	// gendoc:generate(entity=network_wireguard, group=common, key=wireguard.listen_port)
+	//
+	// ---
+	//  type: integer
+	//  defaultdesc: `51820`
+	//  shortdesc: UDP port the WireGuard interface listens on
+	rules["wireguard.listen_port"] = validate.Optional(validate.IsNetworkPort)
+
+	// Network ACLs aren't supported.
+	for k := range rules {
+		if strings.HasPrefix(k, "security.acls") {
+			delete(rules, k)
+		}
+	}
+}
+
+// wireguardPeers returns the WireGuard peers of the network.
+func (n *bridge) wireguardPeers() ([]*api.NetworkPeer, error) {
+	var peers []*api.NetworkPeer
+
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		netID := n.ID()
+		dbPeers, err := dbCluster.GetNetworkPeers(ctx, tx.Tx(), dbCluster.NetworkPeerFilter{NetworkID: &netID})
+		if err != nil {
+			return fmt.Errorf("Failed loading network peer DB objects: %w", err)
+		}
+
+		for _, dbPeer := range dbPeers {
+			peer, err := dbPeer.ToAPI(ctx, tx.Tx())
+			if err != nil {
+				return fmt.Errorf("Failed converting network peer DB object to API object: %w", err)
+			}
+
+			peers = append(peers, peer)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(peers, func(a *api.NetworkPeer, b *api.NetworkPeer) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return peers, nil
+}
+
+// wireguardSetup creates and configures the WireGuard interface, routing the peers' allowed IPs through it.
+func (n *bridge) wireguardSetup(peers []*api.NetworkPeer) error {
+	wgName := n.wireguardInterfaceName()
+	n.logger.Debug("Setting up WireGuard", logger.Ctx{"interface": wgName, "peers": len(peers)})
+
+	if !InterfaceExists(wgName) {
+		wg := &ip.Wireguard{Link: ip.Link{Name: wgName}}
+		err := wg.Add()
+		if err != nil {
+			return fmt.Errorf("Failed creating WireGuard interface %q: %w", wgName, err)
+		}
+	}
+
+	privateKey, err := n.wireguardPrivateKey()
+	if err != nil {
+		return err
+	}
+
+	// Apply the configuration, keeping the sessions of unchanged peers.
+	configPath := internalUtil.VarPath("networks", n.name, "wireguard.conf")
+	err = os.WriteFile(configPath, []byte(wireguardConfig(privateKey, n.wireguardListenPort(), peers)), 0o600)
+	if err != nil {
+		return fmt.Errorf("Failed writing WireGuard configuration: %w", err)
+	}
+
+	_, err = subprocess.RunCommand("wg", "syncconf", wgName, configPath)
+	if err != nil {
+		return fmt.Errorf("Failed configuring WireGuard interface %q: %w", wgName, err)
+	}
+
+	link := &ip.Link{Name: wgName}
+	err = link.SetUp()
+	if err != nil {
+		return err
+	}
+
+	// Route the peers' allowed IPs through the interface.
+	for _, family := range []ip.Family{ip.FamilyV4, ip.FamilyV6} {
+		r := &ip.Route{
+			DevName: wgName,
+			Proto:   "static",
+			Family:  family,
+		}
+
+		err = r.Flush()
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, peer := range peers {
+		for _, subnet := range wireguardAllowedIPs(peer.Config) {
+			family := ip.FamilyV4
+			if subnet.IP.To4() == nil {
+				family = ip.FamilyV6
+			}
+
+			r := &ip.Route{
+				DevName: wgName,
+				Route:   subnet,
+				Proto:   "static",
+				Family:  family,
+			}
+
+			err = r.Replace()
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// wireguardStop deletes the WireGuard interface.
+func (n *bridge) wireguardStop() error {
+	wgName := n.wireguardInterfaceName()
+	if !InterfaceExists(wgName) {
+		return nil
+	}
+
+	link := &ip.Link{Name: wgName}
+	err := link.Delete()
+	if err != nil {
+		return fmt.Errorf("Failed deleting WireGuard interface %q: %w", wgName, err)
+	}
+
+	return nil
+}
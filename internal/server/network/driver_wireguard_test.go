@@ -0,0 +1,89 @@
+package network
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+func TestWireguardPublicKey(t *testing.T) {
+	// Test vector from RFC 7748 section 6.1.
+	publicKey, err := wireguardPublicKey("dwdtCnMYpX08FsFyUbJmRd9ML4frwJkqsXf7pR25LCo=")
+	require.NoError(t, err)
+	assert.Equal(t, "hSDwCYkwp1R0i33ctD73Wg2/Og0mOBr066SpjqqbTmo=", publicKey)
+
+	_, err = wireguardPublicKey("invalid")
+	assert.Error(t, err)
+
+	assert.NoError(t, validateWireguardKey("hSDwCYkwp1R0i33ctD73Wg2/Og0mOBr066SpjqqbTmo="))
+	assert.Error(t, validateWireguardKey("aGVsbG8="))
+}
+
+func TestWireguardConfig(t *testing.T) {
+	peers := []*api.NetworkPeer{
+		{
+			Name: "site1",
+			NetworkPeerPut: api.NetworkPeerPut{Config: map[string]string{
+				"wireguard.public_key":           "hSDwCYkwp1R0i33ctD73Wg2/Og0mOBr066SpjqqbTmo=",
+				"wireguard.allowed_ips":          "10.2.0.0/24, fd42:2::/64",
+				"wireguard.endpoint":             "203.0.113.2:51820",
+				"wireguard.persistent_keepalive": "25",
+			}},
+		},
+		{
+			Name: "site2",
+			NetworkPeerPut: api.NetworkPeerPut{Config: map[string]string{
+				"wireguard.public_key":  "3p7bfXt9wbTTW2HC7OQ1Nz+DQ8hbeGdNrfx+FG+IK08=",
+				"wireguard.allowed_ips": "10.3.0.0/24",
+			}},
+		},
+	}
+
+	expected := `[Interface]
+PrivateKey = dwdtCnMYpX08FsFyUbJmRd9ML4frwJkqsXf7pR25LCo=
+ListenPort = 51820
+
+[Peer]
+PublicKey = hSDwCYkwp1R0i33ctD73Wg2/Og0mOBr066SpjqqbTmo=
+AllowedIPs = 10.2.0.0/24, fd42:2::/64
+Endpoint = 203.0.113.2:51820
+PersistentKeepalive = 25
+
+[Peer]
+PublicKey = 3p7bfXt9wbTTW2HC7OQ1Nz+DQ8hbeGdNrfx+FG+IK08=
+AllowedIPs = 10.3.0.0/24
+`
+
+	assert.Equal(t, expected, wireguardConfig("dwdtCnMYpX08FsFyUbJmRd9ML4frwJkqsXf7pR25LCo=", 51820, peers))
+
+	subnets := wireguardPeerSubnets(peers, 4)
+	require.Len(t, subnets, 2)
+	assert.Equal(t, "10.2.0.0/24", subnets[0].String())
+	assert.Equal(t, "10.3.0.0/24", subnets[1].String())
+
+	subnets = wireguardPeerSubnets(peers, 6)
+	require.Len(t, subnets, 1)
+	assert.Equal(t, "fd42:2::/64", subnets[0].String())
+}
+
+func TestWireguardDHCPRoutes(t *testing.T) {
+	_, subnet, err := net.ParseCIDR("10.2.0.0/24")
+	require.NoError(t, err)
+
+	address := net.ParseIP("10.1.0.1")
+	gateway := net.ParseIP("10.1.0.254")
+
+	// Nothing to announce without peers.
+	assert.Empty(t, wireguardDHCPRoutes("", address, gateway, nil))
+	assert.Equal(t, "192.0.2.0/24,10.1.0.5", wireguardDHCPRoutes("192.0.2.0/24,10.1.0.5", address, gateway, nil))
+
+	// A default route is added as clients then ignore the router option.
+	assert.Equal(t, "10.2.0.0/24,10.1.0.1,0.0.0.0/0,10.1.0.254", wireguardDHCPRoutes("", address, gateway, []*net.IPNet{subnet}))
+
+	// Unless already configured.
+	assert.Equal(t, "0.0.0.0/0,10.1.0.5,10.2.0.0/24,10.1.0.1", wireguardDHCPRoutes("0.0.0.0/0, 10.1.0.5", address, gateway, []*net.IPNet{subnet}))
+}
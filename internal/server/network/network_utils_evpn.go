@@ -0,0 +1,263 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lxc/incus/v6/internal/server/bgp"
+	"github.com/lxc/incus/v6/internal/server/ip"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// evpnSyncInterval is how often the EVPN routes and the forwarding database of the tunnels are reconciled.
+const evpnSyncInterval = 5 * time.Second
+
+// evpnTunnel is a VXLAN tunnel whose remote VTEPs and MAC addresses are exchanged through BGP EVPN.
+type evpnTunnel struct {
+	devName string
+	vni     uint32
+	vtep    net.IP
+}
+
+// evpnPeer is a BGP peer with which EVPN routes are exchanged.
+type evpnPeer struct {
+	address  net.IP
+	asn      uint32
+	password string
+	holdTime uint64
+}
+
+// evpnNetwork holds the running EVPN state of a bridge network.
+type evpnNetwork struct {
+	// BGP peers added for the network, so they can be removed even if the configuration changed.
+	peers []evpnPeer
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// evpnNetworksMu serializes the setup and teardown of the EVPN state of the networks.
+var evpnNetworksMu sync.Mutex
+
+// evpnNetworks holds the running EVPN state, keyed by network ID.
+var evpnNetworks = map[int64]*evpnNetwork{}
+
+// evpnOwner returns the BGP owner of the EVPN routes of a network.
+func evpnOwner(networkID int64) string {
+	return fmt.Sprintf("network_%d_evpn", networkID)
+}
+
+// start synchronizes the tunnels in the background, until stopped.
+func (e *evpnNetwork) start(server *bgp.Server, owner string, bridgeName string, tunnels []evpnTunnel, l logger.Logger) {
+	ctx, cancel := context.WithCancel(context.Background())
+	e.cancel = cancel
+	e.done = make(chan struct{})
+
+	go func() {
+		defer close(e.done)
+
+		ticker := time.NewTicker(evpnSyncInterval)
+		defer ticker.Stop()
+
+		// Routes advertised for each tunnel.
+		advertised := make([]map[string]bgp.EVPNRoute, len(tunnels))
+		for i := range tunnels {
+			advertised[i] = map[string]bgp.EVPNRoute{}
+		}
+
+		for {
+			for i, tunnel := range tunnels {
+				err := evpnSync(server, owner, bridgeName, tunnel, advertised[i])
+				if err != nil {
+					l.Warn("Failed synchronizing EVPN tunnel", logger.Ctx{"tunnel": tunnel.devName, "err": err})
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// stop stops synchronizing the tunnels and waits for any ongoing synchronization to complete.
+func (e *evpnNetwork) stop() {
+	if e.cancel == nil {
+		return
+	}
+
+	e.cancel()
+	<-e.done
+}
+
+// evpnSync advertises the MAC addresses learned on the bridge ports along with the tunnel VTEP, and updates
+// the forwarding database of the tunnel from the routes of the other VTEPs.
+func evpnSync(server *bgp.Server, owner string, bridgeName string, tunnel evpnTunnel, advertised map[string]bgp.EVPNRoute) error {
+	bridgeEntries, err := (&ip.FDB{DevName: bridgeName}).Show()
+	if err != nil {
+		return err
+	}
+
+	// Advertise the new local routes.
+	localRoutes := evpnLocalRoutes(bridgeName, tunnel, bridgeEntries)
+	wanted := make(map[string]struct{}, len(localRoutes))
+
+	for _, route := range localRoutes {
+		wanted[route.String()] = struct{}{}
+
+		_, found := advertised[route.String()]
+		if found {
+			continue
+		}
+
+		err := server.AddEVPNRoute(route, owner)
+		if err != nil {
+			return err
+		}
+
+		advertised[route.String()] = route
+	}
+
+	// Withdraw the routes of the MAC addresses which aren't known anymore.
+	for key, route := range advertised {
+		_, found := wanted[key]
+		if found {
+			continue
+		}
+
+		err := server.RemoveEVPNRoute(route, owner)
+		if err != nil && !errors.Is(err, bgp.ErrPrefixNotFound) {
+			return err
+		}
+
+		delete(advertised, key)
+	}
+
+	// Update the forwarding database of the tunnel.
+	routes, err := server.EVPNRoutes(tunnel.vni)
+	if err != nil {
+		return err
+	}
+
+	tunnelEntries, err := (&ip.FDB{DevName: tunnel.devName}).Show()
+	if err != nil {
+		return err
+	}
+
+	add, remove := evpnFDBChanges(tunnel, tunnelEntries, routes)
+
+	// Remove first so that moved MAC addresses can be added again.
+	for _, entry := range remove {
+		err := entry.Delete()
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, entry := range add {
+		err := entry.Add()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// evpnLocalRoutes returns the routes to advertise for the tunnel: the VTEP itself, along with the unicast MAC
+// addresses learned on the bridge ports other than the tunnel.
+func evpnLocalRoutes(bridgeName string, tunnel evpnTunnel, entries []ip.FDB) []bgp.EVPNRoute {
+	routes := []bgp.EVPNRoute{{VNI: tunnel.vni, VTEP: tunnel.vtep}}
+	seen := map[string]struct{}{}
+
+	for _, entry := range entries {
+		// Skip the addresses of the bridge and its ports, those of remote hosts and multicast addresses.
+		if entry.Permanent || entry.DstIP != nil || entry.DevName == bridgeName || entry.DevName == tunnel.devName {
+			continue
+		}
+
+		if len(entry.MAC) != 6 || entry.MAC[0]&0x01 != 0 {
+			continue
+		}
+
+		// Entries are per VLAN.
+		_, found := seen[entry.MAC.String()]
+		if found {
+			continue
+		}
+
+		seen[entry.MAC.String()] = struct{}{}
+		routes = append(routes, bgp.EVPNRoute{VNI: tunnel.vni, VTEP: tunnel.vtep, MAC: entry.MAC})
+	}
+
+	return routes
+}
+
+// evpnFDBChanges returns the forwarding database entries to add to and remove from the tunnel for it to match
+// the routes of the other VTEPs. Every VTEP gets an entry for the all-zero MAC address, so that broadcast and
+// unknown traffic gets replicated to it.
+func evpnFDBChanges(tunnel evpnTunnel, entries []ip.FDB, routes []bgp.EVPNRoute) ([]ip.FDB, []ip.FDB) {
+	entryKey := func(mac net.HardwareAddr, dstIP net.IP) string {
+		return fmt.Sprintf("%s/%s", mac.String(), dstIP.String())
+	}
+
+	zeroMAC := net.HardwareAddr{0, 0, 0, 0, 0, 0}
+
+	wanted := map[string]ip.FDB{}
+	for _, route := range routes {
+		if route.VNI != tunnel.vni || route.VTEP.Equal(tunnel.vtep) {
+			continue
+		}
+
+		mac := route.MAC
+		if mac == nil {
+			mac = zeroMAC
+		}
+
+		wanted[entryKey(mac, route.VTEP)] = ip.FDB{DevName: tunnel.devName, MAC: mac, DstIP: route.VTEP}
+	}
+
+	// Only the entries towards remote VTEPs are managed.
+	current := map[string]ip.FDB{}
+	for _, entry := range entries {
+		if entry.DevName != tunnel.devName || entry.DstIP == nil {
+			continue
+		}
+
+		current[entryKey(entry.MAC, entry.DstIP)] = ip.FDB{DevName: tunnel.devName, MAC: entry.MAC, DstIP: entry.DstIP}
+	}
+
+	add := []ip.FDB{}
+	for key, entry := range wanted {
+		_, found := current[key]
+		if !found {
+			add = append(add, entry)
+		}
+	}
+
+	remove := []ip.FDB{}
+	for key, entry := range current {
+		_, found := wanted[key]
+		if !found {
+			remove = append(remove, entry)
+		}
+	}
+
+	// Sort the changes so they're applied in a consistent order.
+	compare := func(a ip.FDB, b ip.FDB) int {
+		return strings.Compare(entryKey(a.MAC, a.DstIP), entryKey(b.MAC, b.DstIP))
+	}
+
+	slices.SortFunc(add, compare)
+	slices.SortFunc(remove, compare)
+
+	return add, remove
+}
@@ -0,0 +1,90 @@
+package network
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/internal/server/bgp"
+	"github.com/lxc/incus/v6/internal/server/ip"
+)
+
+func mustMAC(t *testing.T, s string) net.HardwareAddr {
+	mac, err := net.ParseMAC(s)
+	require.NoError(t, err)
+
+	return mac
+}
+
+func TestEVPNLocalRoutes(t *testing.T) {
+	tunnel := evpnTunnel{devName: "br0-evpn", vni: 100, vtep: net.ParseIP("192.0.2.1")}
+
+	entries := []ip.FDB{
+		// Learned on an instance port, once per VLAN.
+		{DevName: "veth1", MAC: mustMAC(t, "00:16:3e:00:00:01")},
+		{DevName: "veth1", MAC: mustMAC(t, "00:16:3e:00:00:01")},
+
+		// Address of the port itself.
+		{DevName: "veth1", MAC: mustMAC(t, "fe:00:00:00:00:01"), Permanent: true},
+
+		// Addresses of the bridge.
+		{DevName: "br0", MAC: mustMAC(t, "00:16:3e:00:00:ff"), Permanent: true},
+		{DevName: "br0", MAC: mustMAC(t, "33:33:00:00:00:01"), Permanent: true},
+
+		// Learned on the tunnel, or towards a remote VTEP.
+		{DevName: "br0-evpn", MAC: mustMAC(t, "00:16:3e:00:00:02")},
+		{DevName: "br0-evpn", MAC: mustMAC(t, "00:16:3e:00:00:02"), DstIP: net.ParseIP("192.0.2.2"), Permanent: true},
+
+		// Multicast address learned on a port.
+		{DevName: "veth2", MAC: mustMAC(t, "01:00:5e:00:00:01")},
+	}
+
+	routes := evpnLocalRoutes("br0", tunnel, entries)
+	require.Len(t, routes, 2)
+	assert.Equal(t, "100/192.0.2.1/", routes[0].String())
+	assert.Equal(t, "100/192.0.2.1/00:16:3e:00:00:01", routes[1].String())
+}
+
+func TestEVPNFDBChanges(t *testing.T) {
+	tunnel := evpnTunnel{devName: "br0-evpn", vni: 100, vtep: net.ParseIP("192.0.2.1")}
+
+	routes := []bgp.EVPNRoute{
+		// Local routes.
+		{VNI: 100, VTEP: net.ParseIP("192.0.2.1")},
+		{VNI: 100, VTEP: net.ParseIP("192.0.2.1"), MAC: mustMAC(t, "00:16:3e:00:00:01")},
+
+		// Remote routes.
+		{VNI: 100, VTEP: net.ParseIP("192.0.2.2")},
+		{VNI: 100, VTEP: net.ParseIP("192.0.2.2"), MAC: mustMAC(t, "00:16:3e:00:00:02")},
+		{VNI: 100, VTEP: net.ParseIP("192.0.2.3")},
+
+		// Route of another VNI.
+		{VNI: 200, VTEP: net.ParseIP("192.0.2.4")},
+	}
+
+	entries := []ip.FDB{
+		// Already present.
+		{DevName: "br0-evpn", MAC: mustMAC(t, "00:00:00:00:00:00"), DstIP: net.ParseIP("192.0.2.2"), Permanent: true},
+
+		// Withdrawn.
+		{DevName: "br0-evpn", MAC: mustMAC(t, "00:16:3e:00:00:03"), DstIP: net.ParseIP("192.0.2.2"), Permanent: true},
+
+		// Learned by the bridge, not managed.
+		{DevName: "br0-evpn", MAC: mustMAC(t, "00:16:3e:00:00:04")},
+	}
+
+	add, remove := evpnFDBChanges(tunnel, entries, routes)
+
+	require.Len(t, add, 2)
+	assert.Equal(t, "00:00:00:00:00:00", add[0].MAC.String())
+	assert.Equal(t, "192.0.2.3", add[0].DstIP.String())
+	assert.Equal(t, "00:16:3e:00:00:02", add[1].MAC.String())
+	assert.Equal(t, "192.0.2.2", add[1].DstIP.String())
+	assert.Equal(t, "br0-evpn", add[1].DevName)
+
+	require.Len(t, remove, 1)
+	assert.Equal(t, "00:16:3e:00:00:03", remove[0].MAC.String())
+	assert.Equal(t, "192.0.2.2", remove[0].DstIP.String())
+}
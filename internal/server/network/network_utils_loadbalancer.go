@@ -0,0 +1,345 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	firewallDrivers "github.com/lxc/incus/v6/internal/server/firewall/drivers"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// Health status of a load balancer backend, matching those reported for OVN load balancers.
+const (
+	loadBalancerHealthUnknown = "unknown"
+	loadBalancerHealthOnline  = "online"
+	loadBalancerHealthOffline = "offline"
+)
+
+// loadBalancerHealthTarget identifies a backend address and port probed by a health check.
+type loadBalancerHealthTarget struct {
+	protocol string
+	address  string
+	port     uint64
+}
+
+// loadBalancerHealthStatus is the health of a probed backend address and port.
+type loadBalancerHealthStatus struct {
+	status    string
+	successes int
+	failures  int
+}
+
+// loadBalancerHealthCheck periodically probes the backends of a load balancer which is implemented by the firewall.
+type loadBalancerHealthCheck struct {
+	// Hash of the load balancer the check was started for, so it's only restarted on changes.
+	hash string
+
+	interval     time.Duration
+	timeout      time.Duration
+	successCount int
+	failureCount int
+
+	cancel context.CancelFunc
+
+	mu       sync.Mutex
+	statuses map[loadBalancerHealthTarget]*loadBalancerHealthStatus
+
+	// Called once a backend went online or offline.
+	onChange func()
+}
+
+// loadBalancerHealthChecksMu serializes the setup of the health checks, as well as the application of the
+// firewall rules depending on them.
+var loadBalancerHealthChecksMu sync.Mutex
+
+// loadBalancerHealthChecks holds the running health checks, keyed by network and listen address.
+var loadBalancerHealthChecks = map[string]*loadBalancerHealthCheck{}
+
+// loadBalancerHealthCheckKey returns the key of the health check of a load balancer.
+func loadBalancerHealthCheckKey(projectName string, networkName string, listenAddress string) string {
+	return fmt.Sprintf("%s/%s/%s", projectName, networkName, listenAddress)
+}
+
+// newLoadBalancerHealthCheck returns a health check of the provided targets using the load balancer configuration.
+func newLoadBalancerHealthCheck(hash string, config map[string]string, targets []loadBalancerHealthTarget, onChange func()) (*loadBalancerHealthCheck, error) {
+	// Defaults match those of OVN.
+	settings := map[string]int{
+		"healthcheck.interval":      10,
+		"healthcheck.timeout":       30,
+		"healthcheck.success_count": 3,
+		"healthcheck.failure_count": 3,
+	}
+
+	for key := range settings {
+		if config[key] == "" {
+			continue
+		}
+
+		value, err := strconv.Atoi(config[key])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid value for %q: %w", key, err)
+		}
+
+		if value < 1 {
+			return nil, fmt.Errorf("Invalid value for %q, must be at least 1", key)
+		}
+
+		settings[key] = value
+	}
+
+	check := &loadBalancerHealthCheck{
+		hash:         hash,
+		interval:     time.Duration(settings["healthcheck.interval"]) * time.Second,
+		timeout:      time.Duration(settings["healthcheck.timeout"]) * time.Second,
+		successCount: settings["healthcheck.success_count"],
+		failureCount: settings["healthcheck.failure_count"],
+		statuses:     make(map[loadBalancerHealthTarget]*loadBalancerHealthStatus, len(targets)),
+		onChange:     onChange,
+	}
+
+	for _, target := range targets {
+		check.statuses[target] = &loadBalancerHealthStatus{status: loadBalancerHealthUnknown}
+	}
+
+	return check, nil
+}
+
+// loadBalancerHealthTargets returns the distinct backend addresses and ports used by the load balancer rules.
+func loadBalancerHealthTargets(loadBalancers []firewallDrivers.LoadBalancer) []loadBalancerHealthTarget {
+	seen := map[loadBalancerHealthTarget]struct{}{}
+	targets := []loadBalancerHealthTarget{}
+
+	for _, lb := range loadBalancers {
+		for _, lbTarget := range lb.Targets {
+			target := loadBalancerHealthTarget{protocol: lb.Protocol, address: lbTarget.Address.String(), port: lbTarget.Port}
+
+			_, found := seen[target]
+			if found {
+				continue
+			}
+
+			seen[target] = struct{}{}
+			targets = append(targets, target)
+		}
+	}
+
+	return targets
+}
+
+// start starts probing the targets in the background, until stopped.
+func (c *loadBalancerHealthCheck) start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+
+	go func() {
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+
+		for {
+			c.run(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// stop stops probing the targets.
+func (c *loadBalancerHealthCheck) stop() {
+	if c.cancel != nil {
+		c.cancel()
+	}
+}
+
+// run probes all the targets once and calls onChange if any of them went online or offline.
+func (c *loadBalancerHealthCheck) run(ctx context.Context) {
+	c.mu.Lock()
+	targets := make([]loadBalancerHealthTarget, 0, len(c.statuses))
+	for target := range c.statuses {
+		targets = append(targets, target)
+	}
+
+	c.mu.Unlock()
+
+	results := make([]error, len(targets))
+
+	wg := sync.WaitGroup{}
+	for i, target := range targets {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			results[i] = probeLoadBalancerTarget(ctx, target, c.timeout)
+		}()
+	}
+
+	wg.Wait()
+
+	// Don't record anything if stopped while probing, the failures would only be due to the cancellation.
+	if ctx.Err() != nil {
+		return
+	}
+
+	changed := false
+	for i, target := range targets {
+		if c.record(target, results[i] == nil) {
+			logger.Debug("Load balancer backend health changed", logger.Ctx{"protocol": target.protocol, "address": target.address, "port": target.port, "status": c.status(target), "err": results[i]})
+			changed = true
+		}
+	}
+
+	if !changed {
+		return
+	}
+
+	c.mu.Lock()
+	onChange := c.onChange
+	c.mu.Unlock()
+
+	if onChange != nil {
+		onChange()
+	}
+}
+
+// setOnChange replaces the function called once a backend went online or offline.
+func (c *loadBalancerHealthCheck) setOnChange(onChange func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.onChange = onChange
+}
+
+// record records the result of a probe of the target and returns whether its status changed.
+func (c *loadBalancerHealthCheck) record(target loadBalancerHealthTarget, success bool) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s, found := c.statuses[target]
+	if !found {
+		return false
+	}
+
+	newStatus := s.status
+	if success {
+		s.successes++
+		s.failures = 0
+
+		if s.successes >= c.successCount {
+			newStatus = loadBalancerHealthOnline
+		}
+	} else {
+		s.failures++
+		s.successes = 0
+
+		if s.failures >= c.failureCount {
+			newStatus = loadBalancerHealthOffline
+		}
+	}
+
+	if newStatus == s.status {
+		return false
+	}
+
+	s.status = newStatus
+
+	return true
+}
+
+// status returns the health status of the target.
+func (c *loadBalancerHealthCheck) status(target loadBalancerHealthTarget) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s, found := c.statuses[target]
+	if !found {
+		return loadBalancerHealthUnknown
+	}
+
+	return s.status
+}
+
+// filter removes the offline targets from the load balancer rules, as well as the rules left without targets.
+// Targets which haven't been found offline yet are kept so that traffic flows while the first probes run.
+func (c *loadBalancerHealthCheck) filter(loadBalancers []firewallDrivers.LoadBalancer) []firewallDrivers.LoadBalancer {
+	filtered := make([]firewallDrivers.LoadBalancer, 0, len(loadBalancers))
+
+	for _, lb := range loadBalancers {
+		targets := make([]firewallDrivers.LoadBalancerTarget, 0, len(lb.Targets))
+		for _, lbTarget := range lb.Targets {
+			target := loadBalancerHealthTarget{protocol: lb.Protocol, address: lbTarget.Address.String(), port: lbTarget.Port}
+			if c.status(target) == loadBalancerHealthOffline {
+				continue
+			}
+
+			targets = append(targets, lbTarget)
+		}
+
+		if len(targets) == 0 {
+			continue
+		}
+
+		lb.Targets = targets
+		filtered = append(filtered, lb)
+	}
+
+	return filtered
+}
+
+// probeLoadBalancerTarget checks whether the target accepts connections.
+// TCP targets must accept a connection. As there is no such thing for UDP, UDP targets are sent an empty
+// datagram and only considered down if it gets rejected (ICMP port unreachable).
+func probeLoadBalancerTarget(ctx context.Context, target loadBalancerHealthTarget, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	address := net.JoinHostPort(target.address, strconv.FormatUint(target.port, 10))
+
+	dialer := net.Dialer{}
+	conn, err := dialer.DialContext(ctx, target.protocol, address)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = conn.Close() }()
+
+	if target.protocol != "udp" {
+		return nil
+	}
+
+	// Wait a little for a rejection, but no more than the timeout.
+	deadline := time.Now().Add(time.Second)
+	ctxDeadline, ok := ctx.Deadline()
+	if ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+
+	err = conn.SetDeadline(deadline)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn.Write([]byte{})
+	if err != nil {
+		return err
+	}
+
+	_, err = conn.Read(make([]byte, 1))
+	if err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return nil // No rejection received.
+		}
+
+		return err
+	}
+
+	return nil
+}
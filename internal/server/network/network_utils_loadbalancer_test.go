@@ -0,0 +1,62 @@
+package network
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	firewallDrivers "github.com/lxc/incus/v6/internal/server/firewall/drivers"
+)
+
+func TestLoadBalancerHealthCheck(t *testing.T) {
+	loadBalancers := []firewallDrivers.LoadBalancer{
+		{
+			ListenAddress: net.ParseIP("192.0.2.1"),
+			Protocol:      "tcp",
+			ListenPort:    80,
+			Targets: []firewallDrivers.LoadBalancerTarget{
+				{Address: net.ParseIP("10.0.0.2"), Port: 8080},
+				{Address: net.ParseIP("10.0.0.3"), Port: 8080},
+			},
+		},
+		{
+			ListenAddress: net.ParseIP("192.0.2.1"),
+			Protocol:      "tcp",
+			ListenPort:    81,
+			Targets: []firewallDrivers.LoadBalancerTarget{
+				{Address: net.ParseIP("10.0.0.2"), Port: 8080},
+			},
+		},
+	}
+
+	targets := loadBalancerHealthTargets(loadBalancers)
+	require.Len(t, targets, 2)
+
+	check, err := newLoadBalancerHealthCheck("", map[string]string{"healthcheck.failure_count": "2", "healthcheck.success_count": "1"}, targets, nil)
+	require.NoError(t, err)
+
+	down := loadBalancerHealthTarget{protocol: "tcp", address: "10.0.0.2", port: 8080}
+	assert.Equal(t, loadBalancerHealthUnknown, check.status(down))
+
+	// A single failure isn't enough to consider the backend offline.
+	assert.False(t, check.record(down, false))
+	assert.Len(t, check.filter(loadBalancers), 2)
+
+	assert.True(t, check.record(down, false))
+	assert.Equal(t, loadBalancerHealthOffline, check.status(down))
+
+	// The rule left without targets is dropped.
+	filtered := check.filter(loadBalancers)
+	require.Len(t, filtered, 1)
+	assert.Equal(t, uint64(80), filtered[0].ListenPort)
+	assert.Equal(t, []firewallDrivers.LoadBalancerTarget{{Address: net.ParseIP("10.0.0.3"), Port: 8080}}, filtered[0].Targets)
+
+	assert.True(t, check.record(down, true))
+	assert.Equal(t, loadBalancerHealthOnline, check.status(down))
+	assert.Len(t, check.filter(loadBalancers), 2)
+
+	_, err = newLoadBalancerHealthCheck("", map[string]string{"healthcheck.interval": "0"}, targets, nil)
+	assert.Error(t, err)
+}
@@ -28,6 +28,7 @@ type NetworkZone interface {
 	GetRecord(name string) (*api.NetworkZoneRecord, error)
 	UpdateRecord(name string, req api.NetworkZoneRecordPut, clientType request.ClientType) error
 	DeleteRecord(name string) error
+	UpdateRecords(current []api.NetworkZoneRecord, changed []api.NetworkZoneRecord) error
 
 	// Internal validation.
 	validateName(name string) error
@@ -3,8 +3,10 @@ package zone
 import (
 	"context"
 	"fmt"
+	"maps"
 	"net/http"
 	"slices"
+	"strings"
 
 	"github.com/miekg/dns"
 
@@ -224,6 +226,116 @@ func (d *zone) DeleteRecord(name string) error {
 	return nil
 }
 
+// UpdateRecords applies a set of record changes in a single transaction, the changed records without
+// entries being deleted. It fails without applying anything if the zone records no longer match the
+// current ones, so that the changes can be computed from the records without them being modified meanwhile.
+func (d *zone) UpdateRecords(current []api.NetworkZoneRecord, changed []api.NetworkZoneRecord) error {
+	// Validate.
+	for _, record := range changed {
+		err := d.validateName(record.Name)
+		if err != nil {
+			return err
+		}
+
+		err = d.validateRecordConfig(record.NetworkZoneRecordPut)
+		if err != nil {
+			return err
+		}
+
+		err = d.validateEntries(record.NetworkZoneRecordPut)
+		if err != nil {
+			return err
+		}
+	}
+
+	return d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		zoneID := int(d.id)
+		filter := dbCluster.NetworkZoneRecordFilter{
+			NetworkZoneID: &zoneID,
+		}
+
+		dbRecords, err := dbCluster.GetNetworkZoneRecords(ctx, tx.Tx(), filter)
+		if err != nil {
+			return err
+		}
+
+		// Check that the records didn't change.
+		records := make([]api.NetworkZoneRecord, 0, len(dbRecords))
+		for _, dbRecord := range dbRecords {
+			apiRecord, err := dbRecord.ToAPI(ctx, tx.Tx())
+			if err != nil {
+				return err
+			}
+
+			records = append(records, *apiRecord)
+		}
+
+		sameRecord := func(a api.NetworkZoneRecord, b api.NetworkZoneRecord) bool {
+			return a.Name == b.Name && a.Description == b.Description && slices.Equal(a.Entries, b.Entries) && maps.Equal(a.Config, b.Config)
+		}
+
+		if !slices.EqualFunc(records, current, sameRecord) {
+			return api.StatusErrorf(http.StatusConflict, "Network zone records changed during the update")
+		}
+
+		for _, record := range changed {
+			i := slices.IndexFunc(dbRecords, func(dbRecord dbCluster.NetworkZoneRecord) bool { return strings.EqualFold(dbRecord.Name, record.Name) })
+			if i < 0 {
+				if len(record.Entries) == 0 {
+					continue
+				}
+
+				// Add the new record.
+				dbRecord := dbCluster.NetworkZoneRecord{
+					NetworkZoneID: zoneID,
+					Name:          record.Name,
+					Description:   record.Description,
+					Entries:       record.Entries,
+				}
+
+				id, err := dbCluster.CreateNetworkZoneRecord(ctx, tx.Tx(), dbRecord)
+				if err != nil {
+					return err
+				}
+
+				err = dbCluster.CreateNetworkZoneRecordConfig(ctx, tx.Tx(), id, record.Config)
+				if err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			dbRecord := dbRecords[i]
+			if len(record.Entries) == 0 {
+				// Delete the record.
+				err = dbCluster.DeleteNetworkZoneRecord(ctx, tx.Tx(), zoneID, dbRecord.ID)
+				if err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			// Update the record.
+			dbRecord.Description = record.Description
+			dbRecord.Entries = record.Entries
+
+			err = dbCluster.UpdateNetworkZoneRecord(ctx, tx.Tx(), zoneID, dbRecord.Name, dbRecord)
+			if err != nil {
+				return err
+			}
+
+			err = dbCluster.UpdateNetworkZoneRecordConfig(ctx, tx.Tx(), int64(dbRecord.ID), record.Config)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
 // validateRecordConfig checks the config and rules are valid.
 func (d *zone) validateRecordConfig(info api.NetworkZoneRecordPut) error {
 	rules := map[string]func(value string) error{}
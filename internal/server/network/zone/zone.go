@@ -168,6 +168,42 @@ func (d *zone) validateConfig(info *api.NetworkZonePut) error {
 	//  shortdesc: Comma-separated list of DNS server FQDNs (for NS records)
 	rules["dns.nameservers"] = validate.IsListOf(validate.IsAny)
 
+	// gendoc:generate(entity=network_zone, group=common, key=dns.query.enabled)
+	// When enabled, the built-in DNS server answers regular queries (`A`, `AAAA`, `PTR`, `TXT`, `SRV`, ...) for names within the zone.
+	// ---
+	//  type: bool
+	//  required: no
+	//  defaultdesc: `false`
+	//  shortdesc: Whether to answer DNS queries for the zone records
+	rules["dns.query.enabled"] = validate.Optional(validate.IsBool)
+
+	// gendoc:generate(entity=network_zone, group=common, key=dns.query.restricted)
+	// When restricted, only the zone peers (`peers.NAME.*`) are allowed to query the zone records.
+	// ---
+	//  type: bool
+	//  required: no
+	//  defaultdesc: `true`
+	//  shortdesc: Whether to only answer DNS queries from the zone peers
+	rules["dns.query.restricted"] = validate.Optional(validate.IsBool)
+
+	// gendoc:generate(entity=network_zone, group=common, key=dns.update.enabled)
+	// When enabled, the built-in DNS server accepts dynamic updates (RFC 2136) of the zone records, signed with the TSIG key of a zone peer.
+	// ---
+	//  type: bool
+	//  required: no
+	//  defaultdesc: `false`
+	//  shortdesc: Whether to accept dynamic DNS updates for the zone
+	rules["dns.update.enabled"] = validate.Optional(validate.IsBool)
+
+	// gendoc:generate(entity=network_zone, group=common, key=dns.update.peers)
+	//
+	// ---
+	//  type: string set
+	//  required: no
+	//  defaultdesc: all peers with a key
+	//  shortdesc: Comma-separated list of peers allowed to send dynamic DNS updates
+	rules["dns.update.peers"] = validate.Optional(validate.IsListOf(validate.IsAny))
+
 	// gendoc:generate(entity=network_zone, group=common, key=network.nat)
 	//
 	// ---
@@ -0,0 +1,32 @@
+package oci
+
+import (
+	"archive/tar"
+	"os"
+	"path/filepath"
+
+	ispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// IsArchive returns whether the file at path is an OCI archive, that is an uncompressed tarball of an OCI image layout.
+func IsArchive(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+
+	defer func() { _ = f.Close() }()
+
+	// Only the headers are read, the content of the entries is skipped over.
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			return false
+		}
+
+		if filepath.Clean(hdr.Name) == ispec.ImageLayoutFile && hdr.Typeflag == tar.TypeReg {
+			return true
+		}
+	}
+}
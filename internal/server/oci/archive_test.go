@@ -0,0 +1,57 @@
+package oci
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTarball writes a tarball holding the given files (name to content) to path.
+func writeTarball(t *testing.T, path string, files map[string]string, compress bool) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0o644, Size: int64(len(content))}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, tw.Close())
+
+	data := buf.Bytes()
+	if compress {
+		var gzBuf bytes.Buffer
+		gw := gzip.NewWriter(&gzBuf)
+		_, err := gw.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, gw.Close())
+		data = gzBuf.Bytes()
+	}
+
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+}
+
+func TestIsArchive(t *testing.T) {
+	dir := t.TempDir()
+
+	layout := filepath.Join(dir, "layout.tar")
+	writeTarball(t, layout, map[string]string{"./oci-layout": `{"imageLayoutVersion": "1.0.0"}`, "index.json": "{}"}, false)
+	assert.True(t, IsArchive(layout))
+
+	image := filepath.Join(dir, "image.tar")
+	writeTarball(t, image, map[string]string{"metadata.yaml": "architecture: x86_64", "rootfs/oci-layout": ""}, false)
+	assert.False(t, IsArchive(image))
+
+	compressed := filepath.Join(dir, "image.tar.gz")
+	writeTarball(t, compressed, map[string]string{"metadata.yaml": "architecture: x86_64"}, true)
+	assert.False(t, IsArchive(compressed))
+
+	assert.False(t, IsArchive(filepath.Join(dir, "missing")))
+}
@@ -0,0 +1,220 @@
+package oci
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/apex/log"
+	"github.com/kballard/go-shellquote"
+	ispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/opencontainers/umoci"
+	"github.com/opencontainers/umoci/mutate"
+
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/osarch"
+)
+
+// architectures maps Incus architecture IDs to their OCI names.
+var architectures = map[int]string{
+	osarch.ARCH_32BIT_INTEL_X86:             "386",
+	osarch.ARCH_64BIT_INTEL_X86:             "amd64",
+	osarch.ARCH_32BIT_ARMV6_LITTLE_ENDIAN:   "arm",
+	osarch.ARCH_32BIT_ARMV7_LITTLE_ENDIAN:   "arm",
+	osarch.ARCH_32BIT_ARMV8_LITTLE_ENDIAN:   "arm",
+	osarch.ARCH_64BIT_ARMV8_LITTLE_ENDIAN:   "arm64",
+	osarch.ARCH_64BIT_POWERPC_BIG_ENDIAN:    "ppc64",
+	osarch.ARCH_64BIT_POWERPC_LITTLE_ENDIAN: "ppc64le",
+	osarch.ARCH_64BIT_S390_BIG_ENDIAN:       "s390x",
+	osarch.ARCH_64BIT_MIPS:                  "mips64le",
+	osarch.ARCH_64BIT_RISCV_LITTLE_ENDIAN:   "riscv64",
+	osarch.ARCH_64BIT_LOONGARCH:             "loong64",
+}
+
+// logHandler forwards the umoci logs to our logger.
+type logHandler struct{}
+
+// HandleLog implements a proxy between apex/log and our logger.
+func (h *logHandler) HandleLog(e *log.Entry) error {
+	logger.Debug("Creating OCI image", logger.Ctx{"log": e.Message})
+	return nil
+}
+
+// Architecture returns the OCI name of an Incus architecture.
+func Architecture(architecture string) (string, error) {
+	archID, err := osarch.ArchitectureID(architecture)
+	if err != nil {
+		return "", err
+	}
+
+	name, ok := architectures[archID]
+	if !ok {
+		return "", fmt.Errorf("Architecture %q isn't supported for OCI images", architecture)
+	}
+
+	return name, nil
+}
+
+// ImageConfig returns the OCI image configuration matching an instance configuration.
+// The entry point, working directory and user come from the "oci.*" keys and the environment from the "environment.*" keys.
+func ImageConfig(config map[string]string) (ispec.ImageConfig, error) {
+	imageConfig := ispec.ImageConfig{
+		Entrypoint: []string{"/sbin/init"},
+		WorkingDir: config["oci.cwd"],
+	}
+
+	if config["oci.entrypoint"] != "" {
+		entrypoint, err := shellquote.Split(config["oci.entrypoint"])
+		if err != nil {
+			return ispec.ImageConfig{}, fmt.Errorf("Invalid entry point %q: %w", config["oci.entrypoint"], err)
+		}
+
+		imageConfig.Entrypoint = entrypoint
+	}
+
+	if config["oci.uid"] != "" {
+		imageConfig.User = config["oci.uid"]
+
+		if config["oci.gid"] != "" {
+			imageConfig.User += ":" + config["oci.gid"]
+		}
+	}
+
+	for k, v := range config {
+		name, ok := strings.CutPrefix(k, "environment.")
+		if ok {
+			imageConfig.Env = append(imageConfig.Env, name+"="+v)
+		}
+	}
+
+	slices.Sort(imageConfig.Env)
+
+	return imageConfig, nil
+}
+
+// CreateImage creates an OCI image layout at path, holding a single image tagged with tag.
+// The image is made of a single layer built from the rootfs tarball, as generated by the instance export.
+func CreateImage(ctx context.Context, path string, tag string, rootfs io.Reader, architecture string, config ispec.ImageConfig) error {
+	arch, err := Architecture(architecture)
+	if err != nil {
+		return err
+	}
+
+	// Set the custom handler.
+	log.SetHandler(&logHandler{})
+	defer log.SetHandler(nil)
+
+	engineExt, err := umoci.CreateLayout(path)
+	if err != nil {
+		return fmt.Errorf("Failed creating OCI layout: %w", err)
+	}
+
+	defer func() { _ = engineExt.Close() }()
+
+	err = umoci.NewImage(engineExt, tag)
+	if err != nil {
+		return fmt.Errorf("Failed creating OCI image: %w", err)
+	}
+
+	descriptorPaths, err := engineExt.ResolveReference(ctx, tag)
+	if err != nil {
+		return err
+	}
+
+	if len(descriptorPaths) != 1 {
+		return fmt.Errorf("Unexpected number of manifests for tag %q: %d", tag, len(descriptorPaths))
+	}
+
+	mutator, err := mutate.New(engineExt, descriptorPaths[0])
+	if err != nil {
+		return err
+	}
+
+	created := time.Now().UTC()
+
+	// Stream the layer.
+	layerReader, layerWriter := io.Pipe()
+	defer func() { _ = layerReader.Close() }()
+
+	go func() {
+		_ = layerWriter.CloseWithError(writeLayer(rootfs, layerWriter))
+	}()
+
+	history := &ispec.History{
+		Created:   &created,
+		CreatedBy: "incus publish",
+	}
+
+	_, err = mutator.Add(ctx, ispec.MediaTypeImageLayer, layerReader, history, mutate.GzipCompressor, nil)
+	if err != nil {
+		return fmt.Errorf("Failed adding image layer: %w", err)
+	}
+
+	err = mutator.Set(ctx, config, mutate.Meta{Created: created, Architecture: arch, OS: "linux"}, nil, nil)
+	if err != nil {
+		return fmt.Errorf("Failed setting image configuration: %w", err)
+	}
+
+	descriptorPath, err := mutator.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("Failed committing image: %w", err)
+	}
+
+	err = engineExt.UpdateReference(ctx, tag, descriptorPath.Root())
+	if err != nil {
+		return err
+	}
+
+	// Drop the blobs of the initial empty image.
+	return engineExt.GC(ctx)
+}
+
+// writeLayer turns an instance rootfs tarball into an OCI layer, making all paths relative to the root.
+func writeLayer(r io.Reader, w io.Writer) error {
+	tr := tar.NewReader(r)
+	tw := tar.NewWriter(w)
+
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return err
+		}
+
+		name := strings.TrimPrefix(hdr.Name, "/")
+		if name == "" {
+			// Skip the root directory itself.
+			continue
+		}
+
+		if hdr.Typeflag == tar.TypeDir && !strings.HasSuffix(name, "/") {
+			name += "/"
+		}
+
+		hdr.Name = name
+
+		if hdr.Typeflag == tar.TypeLink {
+			hdr.Linkname = strings.TrimPrefix(hdr.Linkname, "/")
+		}
+
+		err = tw.WriteHeader(hdr)
+		if err != nil {
+			return err
+		}
+
+		_, err = io.Copy(tw, tr)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tw.Close()
+}
@@ -0,0 +1,116 @@
+package oci
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"path/filepath"
+	"testing"
+
+	ispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/opencontainers/umoci"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rootfsTarball returns a tarball laid out like the rootfs part of an instance export.
+func rootfsTarball(t *testing.T) []byte {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+
+	entries := []*tar.Header{
+		{Name: "", Typeflag: tar.TypeDir, Mode: 0o755},
+		{Name: "/etc", Typeflag: tar.TypeDir, Mode: 0o755},
+		{Name: "/etc/hostname", Typeflag: tar.TypeReg, Mode: 0o644, Size: 3},
+		{Name: "/etc/hostname.bak", Typeflag: tar.TypeLink, Linkname: "/etc/hostname"},
+	}
+
+	for _, hdr := range entries {
+		require.NoError(t, tw.WriteHeader(hdr))
+		if hdr.Size > 0 {
+			_, err := tw.Write([]byte("c1\n"))
+			require.NoError(t, err)
+		}
+	}
+
+	require.NoError(t, tw.Close())
+
+	return buf.Bytes()
+}
+
+func TestImageConfig(t *testing.T) {
+	config, err := ImageConfig(map[string]string{
+		"oci.entrypoint":    "/usr/bin/app --name 'my app'",
+		"oci.cwd":           "/srv",
+		"oci.uid":           "1000",
+		"oci.gid":           "100",
+		"environment.PATH":  "/usr/bin",
+		"environment.DEBUG": "1",
+		"limits.cpu":        "2",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"/usr/bin/app", "--name", "my app"}, config.Entrypoint)
+	assert.Equal(t, "/srv", config.WorkingDir)
+	assert.Equal(t, "1000:100", config.User)
+	assert.Equal(t, []string{"DEBUG=1", "PATH=/usr/bin"}, config.Env)
+
+	// System containers default to their init system.
+	config, err = ImageConfig(map[string]string{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/sbin/init"}, config.Entrypoint)
+	assert.Empty(t, config.User)
+
+	_, err = ImageConfig(map[string]string{"oci.entrypoint": "/bin/sh -c 'unterminated"})
+	assert.Error(t, err)
+}
+
+func TestWriteLayer(t *testing.T) {
+	var out bytes.Buffer
+	err := writeLayer(bytes.NewReader(rootfsTarball(t)), &out)
+	require.NoError(t, err)
+
+	names := []string{}
+	tr := tar.NewReader(&out)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		require.NoError(t, err)
+		names = append(names, hdr.Name)
+
+		if hdr.Typeflag == tar.TypeLink {
+			assert.Equal(t, "etc/hostname", hdr.Linkname)
+		}
+	}
+
+	assert.Equal(t, []string{"etc/", "etc/hostname", "etc/hostname.bak"}, names)
+}
+
+func TestCreateImage(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "layout")
+
+	err := CreateImage(ctx, path, "latest", bytes.NewReader(rootfsTarball(t)), "x86_64", ispec.ImageConfig{Entrypoint: []string{"/sbin/init"}})
+	require.NoError(t, err)
+
+	engineExt, err := umoci.OpenLayout(path)
+	require.NoError(t, err)
+
+	defer func() { _ = engineExt.Close() }()
+
+	descriptorPaths, err := engineExt.ResolveReference(ctx, "latest")
+	require.NoError(t, err)
+	require.Len(t, descriptorPaths, 1)
+
+	stat, err := umoci.Stat(ctx, engineExt, descriptorPaths[0].Descriptor())
+	require.NoError(t, err)
+	assert.Len(t, stat.History, 1)
+
+	err = CreateImage(ctx, filepath.Join(t.TempDir(), "layout"), "latest", bytes.NewReader(rootfsTarball(t)), "ppc", ispec.ImageConfig{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,476 @@
+package oci
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	ispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/opencontainers/umoci/oci/config/convert"
+	"golang.org/x/sys/unix"
+
+	"github.com/lxc/incus/v6/internal/linux"
+	"github.com/lxc/incus/v6/internal/rsync"
+	internalUtil "github.com/lxc/incus/v6/internal/util"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/subprocess"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+// Layer annotations marking the seekable formats which can be pulled lazily.
+const (
+	annotationEstargzTOC          = "containerd.io/snapshot/stargz/toc.digest"
+	annotationZstdChunkedManifest = "io.github.containers.zstd-chunked.manifest-checksum"
+)
+
+// ErrPrefetchRunning is returned when the layers of a lazily pulled root filesystem are already being fetched.
+var ErrPrefetchRunning = errors.New("The image layers are already being fetched")
+
+// LazyImage describes the image backing a lazily pulled root filesystem.
+type LazyImage struct {
+	// Canonical reference of the image in its registry.
+	Reference string `json:"reference"`
+
+	// Digests of the image layers, from the bottom one up.
+	Layers []string `json:"layers"`
+}
+
+var storeMu sync.Mutex
+
+var prefetching sync.Map
+
+// IsLazyLayer returns whether a layer with the provided annotations can be pulled lazily.
+// Only eStargz and zstd:chunked layers carry the index needed to fetch individual files.
+func IsLazyLayer(annotations map[string]string) bool {
+	return annotations[annotationEstargzTOC] != "" || annotations[annotationZstdChunkedManifest] != ""
+}
+
+// LayerStoreAvailable returns whether the tool providing the layer store is installed.
+func LayerStoreAvailable() bool {
+	_, err := exec.LookPath("stargz-store")
+	return err == nil
+}
+
+// layerStorePath returns the path at which the layer store is mounted.
+func layerStorePath() string {
+	return internalUtil.VarPath("oci", "store")
+}
+
+// layerStorePIDPath returns the path of the file tracking the layer store process.
+func layerStorePIDPath() string {
+	return internalUtil.VarPath("oci", "store.pid")
+}
+
+// layerStoreAuthPath returns the path of the directory holding the registry credentials of the layer store.
+// It's laid out like a Docker client configuration directory.
+func layerStoreAuthPath() string {
+	return internalUtil.VarPath("oci", "auth")
+}
+
+// SetLayerStoreCredentials records the credentials included in the URL of an OCI registry so that the layer store
+// can fetch layers from it. URLs without credentials are ignored.
+func SetLayerStoreCredentials(server string) error {
+	uri, err := url.Parse(server)
+	if err != nil {
+		return err
+	}
+
+	if uri.User == nil {
+		return nil
+	}
+
+	storeMu.Lock()
+	defer storeMu.Unlock()
+
+	authPath := layerStoreAuthPath()
+	err = os.MkdirAll(authPath, 0o700)
+	if err != nil {
+		return err
+	}
+
+	configPath := filepath.Join(authPath, "config.json")
+
+	config := map[string]map[string]map[string]string{}
+	data, err := os.ReadFile(configPath)
+	if err == nil {
+		err = json.Unmarshal(data, &config)
+		if err != nil {
+			return fmt.Errorf("Failed parsing OCI registry credentials: %w", err)
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	if config["auths"] == nil {
+		config["auths"] = map[string]map[string]string{}
+	}
+
+	// The Docker Hub credentials are looked up under its legacy index URL.
+	host := uri.Host
+	if host == "docker.io" {
+		host = "https://index.docker.io/v1/"
+	}
+
+	config["auths"][host] = map[string]string{"auth": base64.StdEncoding.EncodeToString([]byte(uri.User.String()))}
+
+	data, err = json.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(configPath+".tmp", data, 0o600)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(configPath+".tmp", configPath)
+}
+
+// ensureLayerStore starts the layer store unless it's already mounted.
+// The store is a FUSE file system exposing the content of remote layers, fetching it on demand.
+func ensureLayerStore() error {
+	storeMu.Lock()
+	defer storeMu.Unlock()
+
+	storePath := layerStorePath()
+	if linux.IsMountPoint(storePath) {
+		return nil
+	}
+
+	if !LayerStoreAvailable() {
+		return errors.New("Lazily pulled images require \"stargz-store\" be present on the system")
+	}
+
+	cachePath := internalUtil.VarPath("oci", "cache")
+	authPath := layerStoreAuthPath()
+	for _, path := range []string{storePath, cachePath, authPath} {
+		err := os.MkdirAll(path, 0o700)
+		if err != nil {
+			return err
+		}
+	}
+
+	// The store reads the registry credentials from the Docker client configuration.
+	logPath := internalUtil.LogPath("oci-store.log")
+	p, err := subprocess.NewProcess("env", []string{"DOCKER_CONFIG=" + authPath, "stargz-store", "--root", cachePath, storePath}, logPath, logPath)
+	if err != nil {
+		return err
+	}
+
+	// The store is detached from the daemon so that running containers keep working across restarts.
+	err = p.Start(context.Background())
+	if err != nil {
+		return fmt.Errorf("Failed starting the OCI layer store: %w", err)
+	}
+
+	err = p.Save(layerStorePIDPath())
+	if err != nil {
+		_ = p.Stop()
+		return err
+	}
+
+	for range 50 {
+		if linux.IsMountPoint(storePath) {
+			return nil
+		}
+
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	_ = p.Stop()
+	_ = os.Remove(layerStorePIDPath())
+
+	return fmt.Errorf("Timed out waiting for the OCI layer store, see %q for details", logPath)
+}
+
+// StopLayerStore stops the layer store unless lazily pulled root filesystems are still mounted on top of it.
+func StopLayerStore() error {
+	storeMu.Lock()
+	defer storeMu.Unlock()
+
+	storePath := layerStorePath()
+	if linux.IsMountPoint(storePath) {
+		err := unix.Unmount(storePath, 0)
+		if errors.Is(err, unix.EBUSY) {
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("Failed unmounting the OCI layer store: %w", err)
+		}
+	}
+
+	pidPath := layerStorePIDPath()
+	if !util.PathExists(pidPath) {
+		return nil
+	}
+
+	p, err := subprocess.ImportProcess(pidPath)
+	if err != nil {
+		return err
+	}
+
+	err = p.Stop()
+	if err != nil && !errors.Is(err, subprocess.ErrNotRunning) {
+		return fmt.Errorf("Failed stopping the OCI layer store: %w", err)
+	}
+
+	return os.Remove(pidPath)
+}
+
+// lazyPath returns the path holding the lazy root filesystem data of the instance at path.
+func lazyPath(path string, name ...string) string {
+	return filepath.Join(append([]string{path, "lazy"}, name...)...)
+}
+
+// IsLazy returns whether the instance at path has a lazily pulled root filesystem.
+func IsLazy(path string) bool {
+	return util.PathExists(lazyPath(path, "image.json"))
+}
+
+// loadLazyImage returns the image backing the lazily pulled root filesystem of the instance at path.
+func loadLazyImage(path string) (*LazyImage, error) {
+	data, err := os.ReadFile(lazyPath(path, "image.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var image LazyImage
+	err = json.Unmarshal(data, &image)
+	if err != nil {
+		return nil, err
+	}
+
+	return &image, nil
+}
+
+// layerPaths returns the paths of the image layers in the layer store, from the bottom one up.
+func (i *LazyImage) layerPaths() []string {
+	// The store lays out the layers by base64 encoded image reference and layer digest.
+	ref := base64.StdEncoding.EncodeToString([]byte(i.Reference))
+
+	paths := make([]string, 0, len(i.Layers))
+	for _, digest := range i.Layers {
+		paths = append(paths, filepath.Join(layerStorePath(), ref, digest, "diff"))
+	}
+
+	return paths
+}
+
+// CreateLazyRootfs sets up a lazily pulled root filesystem for the instance at path.
+// The OCI runtime configuration is generated from the image configuration, much like an unpacked image would have it.
+func CreateLazyRootfs(path string, image LazyImage, config ispec.Image) error {
+	for _, dir := range []string{"upper", "work"} {
+		err := os.MkdirAll(lazyPath(path, dir), 0o755)
+		if err != nil {
+			return err
+		}
+	}
+
+	data, err := json.Marshal(image)
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(lazyPath(path, "image.json"), data, 0o600)
+	if err != nil {
+		return err
+	}
+
+	// Mount the root filesystem so that user names can be resolved.
+	err = MountLazyRootfs(path)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = UnmountLazyRootfs(path) }()
+
+	spec, err := convert.ToRuntimeSpec(filepath.Join(path, "rootfs"), config)
+	if err != nil {
+		return fmt.Errorf("Failed generating OCI runtime configuration: %w", err)
+	}
+
+	data, err = json.Marshal(spec)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(path, "config.json"), data, 0o600)
+}
+
+// MountLazyRootfs mounts the lazily pulled root filesystem of the instance at path, unless already mounted.
+// The image layers come from the layer store and the instance's own changes go to its volume.
+func MountLazyRootfs(path string) error {
+	rootfsPath := filepath.Join(path, "rootfs")
+	if linux.IsMountPoint(rootfsPath) {
+		return nil
+	}
+
+	image, err := loadLazyImage(path)
+	if err != nil {
+		return err
+	}
+
+	err = ensureLayerStore()
+	if err != nil {
+		return err
+	}
+
+	options, err := lazyMountOptions(path, image)
+	if err != nil {
+		return err
+	}
+
+	err = unix.Mount("overlay", rootfsPath, "overlay", 0, options)
+	if err != nil {
+		return fmt.Errorf("Failed mounting lazily pulled root filesystem: %w", err)
+	}
+
+	return nil
+}
+
+// lazyMountOptions prepares the overlay mount of the lazily pulled root filesystem of the instance at path and
+// returns its mount options. The image layers are linked under short names to keep the options within a page.
+func lazyMountOptions(path string, image *LazyImage) (string, error) {
+	layersPath := lazyPath(path, "layers")
+	err := os.RemoveAll(layersPath)
+	if err != nil {
+		return "", err
+	}
+
+	err = os.Mkdir(layersPath, 0o700)
+	if err != nil {
+		return "", err
+	}
+
+	// Overlay expects the top layer first.
+	layerPaths := image.layerPaths()
+	lowerDirs := make([]string, 0, len(layerPaths))
+	for i := len(layerPaths) - 1; i >= 0; i-- {
+		linkPath := filepath.Join(layersPath, fmt.Sprintf("%d", i))
+
+		err = os.Symlink(layerPaths[i], linkPath)
+		if err != nil {
+			return "", err
+		}
+
+		lowerDirs = append(lowerDirs, linkPath)
+	}
+
+	return fmt.Sprintf("lowerdir=%s,upperdir=%s,workdir=%s", strings.Join(lowerDirs, ":"), lazyPath(path, "upper"), lazyPath(path, "work")), nil
+}
+
+// UnmountLazyRootfs unmounts the lazily pulled root filesystem of the instance at path, if mounted.
+func UnmountLazyRootfs(path string) error {
+	rootfsPath := filepath.Join(path, "rootfs")
+	if !linux.IsMountPoint(rootfsPath) {
+		return nil
+	}
+
+	err := unix.Unmount(rootfsPath, unix.MNT_DETACH)
+	if err != nil {
+		return fmt.Errorf("Failed unmounting lazily pulled root filesystem: %w", err)
+	}
+
+	return nil
+}
+
+// PrefetchLazyRootfs reads the whole content of the layers backing the instance at path, so that the layer
+// store fetches everything that wasn't accessed yet. It returns once all the layers are available locally.
+func PrefetchLazyRootfs(ctx context.Context, path string) error {
+	_, running := prefetching.LoadOrStore(path, nil)
+	if running {
+		return ErrPrefetchRunning
+	}
+
+	defer prefetching.Delete(path)
+
+	image, err := loadLazyImage(path)
+	if err != nil {
+		return err
+	}
+
+	err = ensureLayerStore()
+	if err != nil {
+		return err
+	}
+
+	for _, layerPath := range image.layerPaths() {
+		err = filepath.WalkDir(layerPath, func(filePath string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			if !d.Type().IsRegular() {
+				return nil
+			}
+
+			f, err := os.Open(filePath)
+			if err != nil {
+				return err
+			}
+
+			defer func() { _ = f.Close() }()
+
+			_, err = io.Copy(io.Discard, f)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("Failed fetching layer %q: %w", filepath.Base(filepath.Dir(layerPath)), err)
+		}
+	}
+
+	logger.Debug("Fetched all layers of lazily pulled image", logger.Ctx{"image": image.Reference, "path": path})
+
+	return nil
+}
+
+// FlattenLazyRootfs turns the lazily pulled root filesystem of the instance at path into a regular one,
+// copying over the merged content of the image layers and the instance changes.
+// The instance must be stopped and its volume mounted.
+func FlattenLazyRootfs(path string) error {
+	err := MountLazyRootfs(path)
+	if err != nil {
+		return err
+	}
+
+	rootfsPath := filepath.Join(path, "rootfs")
+	flatPath := lazyPath(path, "rootfs")
+
+	_, err = rsync.LocalCopy(rootfsPath+"/", flatPath, "", true)
+	if err != nil {
+		return fmt.Errorf("Failed copying lazily pulled root filesystem: %w", err)
+	}
+
+	err = UnmountLazyRootfs(path)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(rootfsPath)
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(flatPath, rootfsPath)
+	if err != nil {
+		return err
+	}
+
+	return os.RemoveAll(lazyPath(path))
+}
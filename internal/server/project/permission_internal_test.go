@@ -5,6 +5,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/idmap"
 )
 
@@ -75,3 +77,27 @@ func TestParseHostIDMapRange(t *testing.T) {
 		assert.Equal(t, idmaps, expected)
 	}
 }
+
+func TestGetTotalsAcrossProjectEntitiesVolumeLimits(t *testing.T) {
+	info := &projectInfo{
+		Project: api.Project{Name: "p1"},
+		Instances: []api.Instance{
+			{Name: "c1", Project: "p1", Type: "container"},
+		},
+		Volumes: []db.StorageVolumeArgs{
+			{Name: "v1", PoolName: "default", Config: map[string]string{"limits.iops": "100", "limits.bandwidth": "10MiB"}},
+			{Name: "v2", PoolName: "default", Config: map[string]string{"limits.iops": "50"}},
+		},
+	}
+
+	keys := []string{"limits.disk.iops", "limits.disk.bandwidth"}
+
+	totals, err := getTotalsAcrossProjectEntities(info, keys, true)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(150), totals["limits.disk.iops"])
+	assert.Equal(t, int64(10*1024*1024), totals["limits.disk.bandwidth"])
+
+	// Volumes without a limit can't be accounted for.
+	_, err = getTotalsAcrossProjectEntities(info, keys, false)
+	assert.Error(t, err)
+}
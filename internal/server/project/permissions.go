@@ -265,8 +265,8 @@ func AllowVolumeCreation(tx *db.ClusterTx, projectName string, poolName string,
 		return nil
 	}
 
-	// If "limits.disk" is not set, there's nothing to do.
-	if info.Project.Config["limits.disk"] == "" {
+	// If no volume limits are set, there's nothing to do.
+	if !hasVolumeLimits(info.Project.Config) {
 		return nil
 	}
 
@@ -830,10 +830,34 @@ func CheckRestrictedDevicesDiskPaths(projectConfig map[string]string, sourcePath
 var allAggregateLimits = []string{
 	"limits.cpu",
 	"limits.disk",
+	"limits.disk.bandwidth",
+	"limits.disk.iops",
 	"limits.memory",
 	"limits.processes",
 }
 
+// volumeAggregateLimits maps the aggregate limits only accounting for custom volumes to the
+// volume config key they sum up.
+var volumeAggregateLimits = map[string]string{
+	"limits.disk.bandwidth": "limits.bandwidth",
+	"limits.disk.iops":      "limits.iops",
+}
+
+// hasVolumeLimits returns whether any of the limits applying to custom volumes is set.
+func hasVolumeLimits(config map[string]string) bool {
+	if config["limits.disk"] != "" {
+		return true
+	}
+
+	for key := range volumeAggregateLimits {
+		if config[key] != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
 // allRestrictions lists all available 'restrict.*' config keys along with their default setting.
 var allRestrictions = map[string]string{
 	"restricted.backups":                   "block",
@@ -974,8 +998,8 @@ func AllowVolumeUpdate(tx *db.ClusterTx, projectName, volumeName string, req api
 		return nil
 	}
 
-	// If "limits.disk" is not set, there's nothing to do.
-	if info.Project.Config["limits.disk"] == "" {
+	// If no volume limits are set, there's nothing to do.
+	if !hasVolumeLimits(info.Project.Config) {
 		return nil
 	}
 
@@ -1081,6 +1105,10 @@ func AllowProjectUpdate(tx *db.ClusterTx, projectName string, config map[string]
 		case "limits.memory":
 			fallthrough
 		case "limits.disk":
+			fallthrough
+		case "limits.disk.bandwidth":
+			fallthrough
+		case "limits.disk.iops":
 			aggregateKeys = append(aggregateKeys, key)
 		}
 	}
@@ -1377,6 +1405,29 @@ func getTotalsAcrossProjectEntities(info *projectInfo, keys []string, skipUnset
 				totals[key] += limit
 			}
 		}
+
+		volumeKey, ok := volumeAggregateLimits[key]
+		if ok {
+			parser := aggregateLimitConfigValueParsers[key]
+
+			for _, volume := range info.Volumes {
+				value, ok := volume.Config[volumeKey]
+				if !ok || value == "" {
+					if skipUnset {
+						continue
+					}
+
+					return nil, fmt.Errorf("Custom volume %q in project %q has no %q config set", volume.Name, info.Project.Name, volumeKey)
+				}
+
+				limit, err := parser(value)
+				if err != nil {
+					return nil, fmt.Errorf("Parse %q for custom volume %q in project %q: %w", volumeKey, volume.Name, info.Project.Name, err)
+				}
+
+				totals[key] += limit
+			}
+		}
 	}
 
 	for _, instance := range info.Instances {
@@ -1399,6 +1450,12 @@ func getInstanceLimits(inst api.Instance, keys []string, skipUnset bool) (map[st
 	limits := map[string]int64{}
 
 	for _, key := range keys {
+		// Skip limits which only account for custom volumes.
+		_, ok := volumeAggregateLimits[key]
+		if ok {
+			continue
+		}
+
 		var limit int64
 		keyName := key
 
@@ -1526,6 +1583,12 @@ var aggregateLimitConfigValueParsers = map[string]func(string) (int64, error){
 	"limits.disk": func(value string) (int64, error) {
 		return units.ParseByteSizeString(value)
 	},
+	"limits.disk.bandwidth": func(value string) (int64, error) {
+		return units.ParseByteSizeString(value)
+	},
+	"limits.disk.iops": func(value string) (int64, error) {
+		return strconv.ParseInt(value, 10, 64)
+	},
 }
 
 var aggregateLimitConfigValuePrinters = map[string]func(int64) string{
@@ -1541,6 +1604,12 @@ var aggregateLimitConfigValuePrinters = map[string]func(int64) string{
 	"limits.disk": func(limit int64) string {
 		return units.GetByteSizeStringIEC(limit, 1)
 	},
+	"limits.disk.bandwidth": func(limit int64) string {
+		return units.GetByteSizeStringIEC(limit, 1)
+	},
+	"limits.disk.iops": func(limit int64) string {
+		return fmt.Sprintf("%d", limit)
+	},
 }
 
 // FilterUsedBy filters a UsedBy list based on project access.
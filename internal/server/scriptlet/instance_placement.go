@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"slices"
 
 	"go.starlark.net/starlark"
 
@@ -11,9 +13,12 @@ import (
 	"github.com/lxc/incus/v6/internal/server/db"
 	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
 	internalInstance "github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/internal/server/network"
+	"github.com/lxc/incus/v6/internal/server/project"
 	scriptletLoad "github.com/lxc/incus/v6/internal/server/scriptlet/load"
 	"github.com/lxc/incus/v6/internal/server/scriptlet/log"
 	"github.com/lxc/incus/v6/internal/server/state"
+	storagePools "github.com/lxc/incus/v6/internal/server/storage"
 	"github.com/lxc/incus/v6/shared/api"
 	apiScriptlet "github.com/lxc/incus/v6/shared/api/scriptlet"
 	"github.com/lxc/incus/v6/shared/logger"
@@ -21,8 +26,9 @@ import (
 	"github.com/lxc/incus/v6/shared/scriptlet"
 )
 
-// InstancePlacementRun runs the instance placement scriptlet and returns the chosen cluster member target.
-func InstancePlacementRun(ctx context.Context, l logger.Logger, s *state.State, req *apiScriptlet.InstancePlacement, candidateMembers []db.NodeInfo, leaderAddress string) (*db.NodeInfo, error) {
+// InstancePlacementRun runs the instance placement scriptlet and returns the chosen cluster member targets,
+// ordered by preference. An empty list is returned if the scriptlet didn't select any target.
+func InstancePlacementRun(ctx context.Context, l logger.Logger, s *state.State, req *apiScriptlet.InstancePlacement, candidateMembers []db.NodeInfo, leaderAddress string) ([]db.NodeInfo, error) {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
@@ -30,6 +36,17 @@ func InstancePlacementRun(ctx context.Context, l logger.Logger, s *state.State,
 
 	var targetMember *db.NodeInfo
 
+	// getCandidateMember returns the candidate member with the given name.
+	getCandidateMember := func(memberName string) *db.NodeInfo {
+		for i := range candidateMembers {
+			if candidateMembers[i].Name == memberName {
+				return &candidateMembers[i]
+			}
+		}
+
+		return nil
+	}
+
 	setTargetFunc := func(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
 		var memberName string
 
@@ -38,13 +55,7 @@ func InstancePlacementRun(ctx context.Context, l logger.Logger, s *state.State,
 			return nil, err
 		}
 
-		for i := range candidateMembers {
-			if candidateMembers[i].Name == memberName {
-				targetMember = &candidateMembers[i]
-				break
-			}
-		}
-
+		targetMember = getCandidateMember(memberName)
 		if targetMember == nil {
 			l.Error("Instance placement scriptlet set invalid member target", logger.Ctx{"member": memberName})
 			return nil, fmt.Errorf("Invalid member name: %s", memberName)
@@ -175,10 +186,10 @@ func InstancePlacementRun(ctx context.Context, l logger.Logger, s *state.State,
 	}
 
 	getInstancesFunc := func(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
-		var project string
+		var projectName string
 		var location string
 
-		err := starlark.UnpackArgs(b.Name(), args, kwargs, "project??", &project, "location??", &location)
+		err := starlark.UnpackArgs(b.Name(), args, kwargs, "project??", &projectName, "location??", &location)
 		if err != nil {
 			return nil, err
 		}
@@ -188,12 +199,12 @@ func InstancePlacementRun(ctx context.Context, l logger.Logger, s *state.State,
 		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
 			var objects []dbCluster.Instance
 
-			if project != "" || location != "" {
+			if projectName != "" || location != "" {
 				// Prepare a filter.
 				filter := dbCluster.InstanceFilter{}
 
-				if project != "" {
-					filter.Project = &project
+				if projectName != "" {
+					filter.Project = &projectName
 				}
 
 				if location != "" {
@@ -358,6 +369,209 @@ func InstancePlacementRun(ctx context.Context, l logger.Logger, s *state.State,
 		return rv, nil
 	}
 
+	getStoragePoolResourcesFunc := func(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var memberName string
+		var poolName string
+
+		err := starlark.UnpackArgs(b.Name(), args, kwargs, "member_name", &memberName, "pool_name", &poolName)
+		if err != nil {
+			return nil, err
+		}
+
+		var res *api.ResourcesStoragePool
+
+		// Get the local storage pool usage.
+		if memberName == s.ServerName {
+			pool, err := storagePools.LoadByName(s, poolName)
+			if err != nil {
+				return nil, err
+			}
+
+			res, err = pool.GetResources()
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			// Get remote member storage pool usage.
+			targetMember := getCandidateMember(memberName)
+			if targetMember == nil {
+				return nil, fmt.Errorf("Invalid member name: %s", memberName)
+			}
+
+			client, err := cluster.Connect(targetMember.Address, s.Endpoints.NetworkCert(), s.ServerCert(), nil, true)
+			if err != nil {
+				return nil, err
+			}
+
+			res, err = client.GetStoragePoolResources(poolName)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		rv, err := scriptlet.StarlarkMarshal(res)
+		if err != nil {
+			return nil, fmt.Errorf("Marshalling storage pool resources for %q on %q failed: %w", poolName, memberName, err)
+		}
+
+		return rv, nil
+	}
+
+	getNetworkStateFunc := func(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var memberName string
+		var networkName string
+		projectName := req.Project
+
+		err := starlark.UnpackArgs(b.Name(), args, kwargs, "member_name", &memberName, "network_name", &networkName, "project??", &projectName)
+		if err != nil {
+			return nil, err
+		}
+
+		if projectName == "" {
+			projectName = api.ProjectDefaultName
+		}
+
+		var networkState *api.NetworkState
+
+		// Get the local network state.
+		if memberName == s.ServerName {
+			networkProjectName, _, err := project.NetworkProject(s.DB.Cluster, projectName)
+			if err != nil {
+				return nil, err
+			}
+
+			n, err := network.LoadByName(s, networkProjectName, networkName)
+			if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+				return nil, err
+			}
+
+			if n != nil {
+				networkState, err = n.State()
+			} else {
+				networkState, err = resources.GetNetworkState(networkName)
+			}
+
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			// Get remote member network state.
+			targetMember := getCandidateMember(memberName)
+			if targetMember == nil {
+				return nil, fmt.Errorf("Invalid member name: %s", memberName)
+			}
+
+			client, err := cluster.Connect(targetMember.Address, s.Endpoints.NetworkCert(), s.ServerCert(), nil, true)
+			if err != nil {
+				return nil, err
+			}
+
+			networkState, err = client.UseProject(projectName).GetNetworkState(networkName)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		rv, err := scriptlet.StarlarkMarshal(networkState)
+		if err != nil {
+			return nil, fmt.Errorf("Marshalling network state for %q on %q failed: %w", networkName, memberName, err)
+		}
+
+		return rv, nil
+	}
+
+	getInstancesByConfigFunc := func(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var key string
+		var value string
+		var projectName string
+
+		err := starlark.UnpackArgs(b.Name(), args, kwargs, "key", &key, "value", &value, "project??", &projectName)
+		if err != nil {
+			return nil, err
+		}
+
+		instanceList := []api.Instance{}
+
+		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			filter := dbCluster.InstanceFilter{}
+			if projectName != "" {
+				filter.Project = &projectName
+			}
+
+			objects, err := dbCluster.GetInstances(ctx, tx.Tx(), filter)
+			if err != nil {
+				return err
+			}
+
+			objectDevices, err := dbCluster.GetAllInstanceDevices(ctx, tx.Tx())
+			if err != nil {
+				return err
+			}
+
+			profileConfigs, err := dbCluster.GetAllProfileConfigs(ctx, tx.Tx())
+			if err != nil {
+				return err
+			}
+
+			profileDevices, err := dbCluster.GetAllProfileDevices(ctx, tx.Tx())
+			if err != nil {
+				return err
+			}
+
+			// Only keep the instances whose expanded configuration matches.
+			for _, obj := range objects {
+				instance, err := obj.ToAPI(ctx, tx.Tx(), objectDevices, profileConfigs, profileDevices)
+				if err != nil {
+					return err
+				}
+
+				if instance.ExpandedConfig[key] != value {
+					continue
+				}
+
+				instanceList = append(instanceList, *instance)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		rv, err := scriptlet.StarlarkMarshal(instanceList)
+		if err != nil {
+			return nil, fmt.Errorf("Marshalling instances failed: %w", err)
+		}
+
+		return rv, nil
+	}
+
+	getClusterGroupMembersFunc := func(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var group string
+
+		err := starlark.UnpackArgs(b.Name(), args, kwargs, "group", &group)
+		if err != nil {
+			return nil, err
+		}
+
+		var members []string
+
+		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			members, err = tx.GetClusterGroupNodes(ctx, group)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		rv, err := scriptlet.StarlarkMarshal(members)
+		if err != nil {
+			return nil, fmt.Errorf("Marshalling cluster group members failed: %w", err)
+		}
+
+		return rv, nil
+	}
+
 	getProjectFunc := func(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
 		var name string
 
@@ -462,6 +676,10 @@ func InstancePlacementRun(ctx context.Context, l logger.Logger, s *state.State,
 		"get_instances_count":          starlark.NewBuiltin("get_instances_count", getInstancesCountFunc),
 		"get_cluster_members":          starlark.NewBuiltin("get_cluster_members", getClusterMembersFunc),
 		"get_project":                  starlark.NewBuiltin("get_project", getProjectFunc),
+		"get_storage_pool_resources":   starlark.NewBuiltin("get_storage_pool_resources", getStoragePoolResourcesFunc),
+		"get_network_state":            starlark.NewBuiltin("get_network_state", getNetworkStateFunc),
+		"get_instances_by_config":      starlark.NewBuiltin("get_instances_by_config", getInstancesByConfigFunc),
+		"get_cluster_group_members":    starlark.NewBuiltin("get_cluster_group_members", getClusterGroupMembersFunc),
 	}
 
 	prog, thread, err := scriptletLoad.InstancePlacementProgram()
@@ -511,9 +729,53 @@ func InstancePlacementRun(ctx context.Context, l logger.Logger, s *state.State,
 		return nil, fmt.Errorf("Failed to run: %w", err)
 	}
 
+	// A list of member names can be returned to rank the candidates, most preferred first.
+	if v.Type() == "list" || v.Type() == "tuple" {
+		iterable, ok := v.(starlark.Iterable)
+		if !ok {
+			return nil, fmt.Errorf("Failed with unexpected return value: %v", v)
+		}
+
+		targetMembers := []db.NodeInfo{}
+
+		iter := iterable.Iterate()
+		defer iter.Done()
+
+		var item starlark.Value
+		for iter.Next(&item) {
+			memberName, ok := starlark.AsString(item)
+			if !ok {
+				return nil, fmt.Errorf("Failed with unexpected member name in return value: %v", item)
+			}
+
+			member := getCandidateMember(memberName)
+			if member == nil {
+				l.Error("Instance placement scriptlet returned invalid member target", logger.Ctx{"member": memberName})
+				return nil, fmt.Errorf("Invalid member name: %s", memberName)
+			}
+
+			// Skip duplicates.
+			if slices.ContainsFunc(targetMembers, func(m db.NodeInfo) bool { return m.Name == member.Name }) {
+				continue
+			}
+
+			targetMembers = append(targetMembers, *member)
+		}
+
+		if len(targetMembers) > 0 {
+			l.Info("Instance placement scriptlet ranked member targets", logger.Ctx{"members": len(targetMembers), "first": targetMembers[0].Name})
+		}
+
+		return targetMembers, nil
+	}
+
 	if v.Type() != "NoneType" {
 		return nil, fmt.Errorf("Failed with unexpected return value: %v", v)
 	}
 
-	return targetMember, nil
+	if targetMember == nil {
+		return []db.NodeInfo{}, nil
+	}
+
+	return []db.NodeInfo{*targetMember}, nil
 }
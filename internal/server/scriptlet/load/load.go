@@ -31,6 +31,10 @@ func InstancePlacementCompile(name string, src string) (*starlark.Program, error
 		"get_instances_count",
 		"get_cluster_members",
 		"get_project",
+		"get_storage_pool_resources",
+		"get_network_state",
+		"get_instances_by_config",
+		"get_cluster_group_members",
 	})
 }
 
@@ -19,6 +19,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/minio/minio-go/v7"
 	"golang.org/x/sync/errgroup"
 	"gopkg.in/yaml.v2"
@@ -393,6 +394,12 @@ func (b *backend) Delete(clientType request.ClientType, op *operations.Operation
 		return fmt.Errorf("Failed to remove directory %q: %w", path, err)
 	}
 
+	// Delete the volume encryption key.
+	err = drivers.DeletePoolEncryptionKey(b.name)
+	if err != nil {
+		return err
+	}
+
 	unavailablePoolsMu.Lock()
 	delete(unavailablePools, b.Name())
 	unavailablePoolsMu.Unlock()
@@ -860,46 +867,9 @@ func (b *backend) CreateInstanceFromBackup(srcBackup backup.Info, srcData io.Rea
 			_ = b.state.Authorizer.DeleteStoragePoolVolume(b.state.ShutdownCtx, inst.Project().Name, b.Name(), volType.Singular(), inst.Name(), "")
 		})
 
-		for i, backupFileSnap := range srcBackup.Snapshots {
-			var volumeSnapDescription string
-			var volumeSnapConfig map[string]string
-			var volumeSnapExpiryDate time.Time
-			var volumeSnapCreationDate time.Time
-
-			// Check if snapshot volume config is available for restore and matches snapshot name.
-			if srcBackup.Config != nil {
-				if len(srcBackup.Config.Snapshots) >= i-1 && srcBackup.Config.Snapshots[i] != nil && srcBackup.Config.Snapshots[i].Name == backupFileSnap {
-					// Use instance snapshot's creation date if snap info available.
-					volumeSnapCreationDate = srcBackup.Config.Snapshots[i].CreatedAt
-				}
-
-				if len(srcBackup.Config.VolumeSnapshots) >= i-1 && srcBackup.Config.VolumeSnapshots[i] != nil && srcBackup.Config.VolumeSnapshots[i].Name == backupFileSnap {
-					// If the backup restore interface provides volume snapshot config use it,
-					// otherwise use default volume config for the storage pool.
-					volumeSnapDescription = srcBackup.Config.VolumeSnapshots[i].Description
-					volumeSnapConfig = srcBackup.Config.VolumeSnapshots[i].Config
-
-					if srcBackup.Config.VolumeSnapshots[i].ExpiresAt != nil {
-						volumeSnapExpiryDate = *srcBackup.Config.VolumeSnapshots[i].ExpiresAt
-					}
-
-					// Use volume's creation date if available.
-					if !srcBackup.Config.VolumeSnapshots[i].CreatedAt.IsZero() {
-						volumeSnapCreationDate = srcBackup.Config.VolumeSnapshots[i].CreatedAt
-					}
-				}
-			}
-
-			newSnapshotName := drivers.GetSnapshotVolumeName(inst.Name(), backupFileSnap)
-
-			// Validate config and create database entry for new storage volume.
-			// Strip unsupported config keys (in case the export was made from a different type of storage pool).
-			err = VolumeDBCreate(b, inst.Project().Name, newSnapshotName, volumeSnapDescription, volType, true, volumeSnapConfig, volumeSnapCreationDate, volumeSnapExpiryDate, contentType, true, true)
-			if err != nil {
-				return err
-			}
-
-			postHookRevert.Add(func() { _ = VolumeDBDelete(b, inst.Project().Name, newSnapshotName, volType) })
+		err = b.createBackupSnapshotVolumeRecords(inst, srcBackup, volType, contentType, postHookRevert)
+		if err != nil {
+			return err
 		}
 
 		// Generate the effective root device volume for instance.
@@ -995,6 +965,205 @@ func (b *backend) CreateInstanceFromBackup(srcBackup backup.Info, srcData io.Rea
 	return postHook, revertHook, nil
 }
 
+// createBackupSnapshotVolumeRecords creates the database entries for the snapshot volumes restored from a backup.
+func (b *backend) createBackupSnapshotVolumeRecords(inst instance.Instance, srcBackup backup.Info, volType drivers.VolumeType, contentType drivers.ContentType, reverter *revert.Reverter) error {
+	for i, backupFileSnap := range srcBackup.Snapshots {
+		var volumeSnapDescription string
+		var volumeSnapConfig map[string]string
+		var volumeSnapExpiryDate time.Time
+		var volumeSnapCreationDate time.Time
+
+		// Check if snapshot volume config is available for restore and matches snapshot name.
+		if srcBackup.Config != nil {
+			if len(srcBackup.Config.Snapshots) >= i-1 && srcBackup.Config.Snapshots[i] != nil && srcBackup.Config.Snapshots[i].Name == backupFileSnap {
+				// Use instance snapshot's creation date if snap info available.
+				volumeSnapCreationDate = srcBackup.Config.Snapshots[i].CreatedAt
+			}
+
+			if len(srcBackup.Config.VolumeSnapshots) >= i-1 && srcBackup.Config.VolumeSnapshots[i] != nil && srcBackup.Config.VolumeSnapshots[i].Name == backupFileSnap {
+				// If the backup restore interface provides volume snapshot config use it,
+				// otherwise use default volume config for the storage pool.
+				volumeSnapDescription = srcBackup.Config.VolumeSnapshots[i].Description
+				volumeSnapConfig = srcBackup.Config.VolumeSnapshots[i].Config
+
+				if srcBackup.Config.VolumeSnapshots[i].ExpiresAt != nil {
+					volumeSnapExpiryDate = *srcBackup.Config.VolumeSnapshots[i].ExpiresAt
+				}
+
+				// Use volume's creation date if available.
+				if !srcBackup.Config.VolumeSnapshots[i].CreatedAt.IsZero() {
+					volumeSnapCreationDate = srcBackup.Config.VolumeSnapshots[i].CreatedAt
+				}
+			}
+		}
+
+		newSnapshotName := drivers.GetSnapshotVolumeName(inst.Name(), backupFileSnap)
+
+		// Validate config and create database entry for new storage volume.
+		// Strip unsupported config keys (in case the export was made from a different type of storage pool).
+		err := VolumeDBCreate(b, inst.Project().Name, newSnapshotName, volumeSnapDescription, volType, true, volumeSnapConfig, volumeSnapCreationDate, volumeSnapExpiryDate, contentType, true, true)
+		if err != nil {
+			return err
+		}
+
+		reverter.Add(func() { _ = VolumeDBDelete(b, inst.Project().Name, newSnapshotName, volType) })
+	}
+
+	return nil
+}
+
+// UpdateInstanceFromBackup applies an incremental backup file onto the storage device of an existing instance.
+// Like CreateInstanceFromBackup, it returns a post hook to run once the snapshots included in the backup have
+// been added to the database and a revert hook that undoes the storage changes should something subsequently fail.
+func (b *backend) UpdateInstanceFromBackup(inst instance.Instance, srcBackup backup.Info, srcData io.ReadSeeker, op *operations.Operation) (func(instance.Instance) error, revert.Hook, error) {
+	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "parent": srcBackup.Parent, "snapshots": srcBackup.Snapshots, "optimizedStorage": *srcBackup.OptimizedStorage})
+	l.Debug("UpdateInstanceFromBackup started")
+	defer l.Debug("UpdateInstanceFromBackup finished")
+
+	if srcBackup.Parent == "" {
+		return nil, nil, errors.New("Backup isn't an incremental backup")
+	}
+
+	volType, err := InstanceTypeToVolumeType(inst.Type())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	contentType := InstanceContentType(inst)
+
+	// Load storage volume from database.
+	dbVol, err := VolumeDBGet(b, inst.Project().Name, inst.Name(), volType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Generate the effective root device volume for instance.
+	volStorageName := project.Instance(inst.Project().Name, inst.Name())
+	vol := b.GetVolume(volType, contentType, volStorageName, dbVol.Config)
+	err = b.applyInstanceRootDiskOverrides(inst, &vol)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	importRevert := revert.New()
+	defer importRevert.Fail()
+
+	// The backup only contains the changes since its parent snapshot, so start from the state of that snapshot.
+	err = vol.SnapshotsExist([]string{srcBackup.Parent}, op)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Parent snapshot %q of the backup not found: %w", srcBackup.Parent, err)
+	}
+
+	// Reject invalid backups before touching the volume.
+	err = drivers.ValidateIncrementalBackup(vol, srcBackup, srcData)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Invalid incremental backup: %w", err)
+	}
+
+	// Keep the current state of the volume so that it can be put back if the backup can't be applied.
+	currentSnapName := fmt.Sprintf("backup-update-%s", uuid.New().String())
+	currentSnapVol, err := vol.NewSnapshot(currentSnapName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = b.driver.CreateVolumeSnapshot(currentSnapVol, op)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed saving the current state of the instance: %w", err)
+	}
+
+	keepCurrent := true
+	deleteCurrent := func() { _ = b.driver.DeleteVolumeSnapshot(currentSnapVol, op) }
+
+	err = b.driver.RestoreVolume(vol, srcBackup.Parent, op)
+	if err != nil {
+		// Drivers which can only restore the most recent snapshot (ZFS) can't keep the current state.
+		l.Warn("Applying incremental backup without keeping the current state of the instance", logger.Ctx{"err": err})
+		deleteCurrent()
+		keepCurrent = false
+
+		err = b.driver.RestoreVolume(vol, srcBackup.Parent, op)
+	}
+
+	if err != nil {
+		var snapErr drivers.ErrDeleteSnapshots
+		if errors.As(err, &snapErr) {
+			return nil, nil, fmt.Errorf("Parent snapshot %q of the backup isn't the most recent snapshot of the instance", srcBackup.Parent)
+		}
+
+		return nil, nil, fmt.Errorf("Failed restoring parent snapshot %q: %w", srcBackup.Parent, err)
+	}
+
+	if keepCurrent {
+		// Added first so that it runs after the driver reverted its own changes.
+		importRevert.Add(func() {
+			err := b.driver.RestoreVolume(vol, currentSnapName, op)
+			if err != nil {
+				l.Error("Failed restoring the state of the instance prior to the incremental backup", logger.Ctx{"snapshot": currentSnapVol.Name(), "err": err})
+				return
+			}
+
+			deleteCurrent()
+		})
+	}
+
+	// Apply the backup on top of the existing storage volume(s).
+	volPostHook, revertHook, err := b.driver.CreateVolumeFromBackup(vol, srcBackup, srcData, op)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if revertHook != nil {
+		importRevert.Add(revertHook)
+	}
+
+	if len(srcBackup.Snapshots) > 0 {
+		err = b.ensureInstanceSnapshotSymlink(inst.Type(), inst.Project().Name, inst.Name())
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	postHook := func(inst instance.Instance) error {
+		l.Debug("UpdateInstanceFromBackup post hook started")
+		defer l.Debug("UpdateInstanceFromBackup post hook finished")
+
+		postHookRevert := revert.New()
+		defer postHookRevert.Fail()
+
+		err := b.createBackupSnapshotVolumeRecords(inst, srcBackup, volType, contentType, postHookRevert)
+		if err != nil {
+			return err
+		}
+
+		// Save any changes that have occurred to the instance's config to the on-disk backup.yaml file.
+		err = b.UpdateInstanceBackupFile(inst, false, op)
+		if err != nil {
+			return fmt.Errorf("Failed updating backup file: %w", err)
+		}
+
+		// If the driver returned a post hook, run it now.
+		if volPostHook != nil {
+			err = volPostHook(vol)
+			if err != nil {
+				return err
+			}
+		}
+
+		// The backup is now fully applied, drop the saved state.
+		if keepCurrent {
+			deleteCurrent()
+		}
+
+		postHookRevert.Success()
+		return nil
+	}
+
+	cleanup := importRevert.Clone().Fail // Clone before calling importRevert.Success() so we can return the Fail func.
+	importRevert.Success()
+	return postHook, cleanup, nil
+}
+
 // CreateInstanceFromCopy copies an instance volume and optionally its snapshots to new volume(s).
 func (b *backend) CreateInstanceFromCopy(inst instance.Instance, src instance.Instance, snapshots bool, allowInconsistent bool, op *operations.Operation) error {
 	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "src": src.Name(), "snapshots": snapshots})
@@ -1006,6 +1175,11 @@ func (b *backend) CreateInstanceFromCopy(inst instance.Instance, src instance.In
 		return err
 	}
 
+	err = InstanceLazyRootfsCheck(src)
+	if err != nil {
+		return err
+	}
+
 	if inst.Type() != src.Type() {
 		return errors.New("Instance types must match")
 	}
@@ -1133,7 +1307,8 @@ func (b *backend) CreateInstanceFromCopy(inst instance.Instance, src instance.In
 		l.Debug("CreateInstanceFromCopy cross-pool mode detected")
 
 		// Negotiate the migration type to use.
-		offeredTypes := srcPool.MigrationTypes(contentType, false, snapshots, false, true)
+		srcVol := srcPool.GetVolume(volType, contentType, project.Instance(src.Project().Name, src.Name()), srcConfig.Volume.Config)
+		offeredTypes := encryptedVolumeMigrationTypes(srcPool, srcVol, true, srcPool.MigrationTypes(contentType, false, snapshots, false, true))
 		offerHeader := localMigration.TypesToHeader(offeredTypes...)
 		migrationTypes, err := localMigration.MatchTypes(offerHeader, FallbackMigrationType(contentType), b.MigrationTypes(contentType, false, snapshots, false, true))
 		if err != nil {
@@ -1376,7 +1551,7 @@ func (b *backend) RefreshCustomVolume(projectName string, srcProjectName string,
 		l.Debug("RefreshCustomVolume cross-pool mode detected")
 
 		// Negotiate the migration type to use.
-		offeredTypes := srcPool.MigrationTypes(contentType, true, snapshots, false, true)
+		offeredTypes := encryptedVolumeMigrationTypes(srcPool, srcVol, true, srcPool.MigrationTypes(contentType, true, snapshots, false, true))
 		offerHeader := localMigration.TypesToHeader(offeredTypes...)
 		migrationTypes, err := localMigration.MatchTypes(offerHeader, FallbackMigrationType(contentType), b.MigrationTypes(contentType, true, snapshots, false, true))
 		if err != nil {
@@ -1504,6 +1679,11 @@ func (b *backend) RefreshInstance(inst instance.Instance, src instance.Instance,
 		return errors.New("Instance types must match")
 	}
 
+	err := InstanceLazyRootfsCheck(src)
+	if err != nil {
+		return err
+	}
+
 	volType, err := InstanceTypeToVolumeType(inst.Type())
 	if err != nil {
 		return err
@@ -1623,7 +1803,8 @@ func (b *backend) RefreshInstance(inst instance.Instance, src instance.Instance,
 		l.Debug("RefreshInstance cross-pool mode detected")
 
 		// Negotiate the migration type to use.
-		offeredTypes := srcPool.MigrationTypes(contentType, true, snapshots, false, true)
+		srcPoolVol := srcPool.GetVolume(volType, contentType, srcVolStorageName, srcConfig.Volume.Config)
+		offeredTypes := encryptedVolumeMigrationTypes(srcPool, srcPoolVol, true, srcPool.MigrationTypes(contentType, true, snapshots, false, true))
 		offerHeader := localMigration.TypesToHeader(offeredTypes...)
 		migrationTypes, err := localMigration.MatchTypes(offerHeader, FallbackMigrationType(contentType), b.MigrationTypes(contentType, true, snapshots, false, true))
 		if err != nil {
@@ -1720,6 +1901,13 @@ func (b *backend) imageFiller(fingerprint string, op *operations.Operation) func
 			}
 		}
 
+		release, err := ImageFileMaterialize(fingerprint)
+		if err != nil {
+			return -1, err
+		}
+
+		defer release()
+
 		imageFile := internalUtil.VarPath("images", fingerprint)
 		return ImageUnpack(imageFile, vol, rootBlockPath, b.state.OS, allowUnsafeResize, tracker)
 	}
@@ -2114,7 +2302,7 @@ func (b *backend) CreateInstanceFromMigration(inst instance.Instance, conn io.Re
 				}
 
 				// Make sure that the image is available locally too (not guaranteed in clusters).
-				imageExists = err == nil && util.PathExists(internalUtil.VarPath("images", fingerprint))
+				imageExists = err == nil && ImageFileExists(fingerprint)
 			}
 
 			if imageExists {
@@ -2441,6 +2629,12 @@ func (b *backend) UpdateInstance(inst instance.Instance, newDesc string, newConf
 			return errors.New(`Instance volume "block.filesystem" property cannot be changed`)
 		}
 
+		// Check that the volume's block.encryption property isn't being changed.
+		_, ok := changedConfig["block.encryption"]
+		if ok {
+			return errors.New(`Instance volume "block.encryption" property cannot be changed`)
+		}
+
 		// Load storage volume from database.
 		dbVol, err := VolumeDBGet(b, inst.Project().Name, inst.Name(), volType)
 		if err != nil {
@@ -2505,6 +2699,11 @@ func (b *backend) MigrateInstance(inst instance.Instance, conn io.ReadWriteClose
 	l.Debug("MigrateInstance started")
 	defer l.Debug("MigrateInstance finished")
 
+	err := InstanceLazyRootfsCheck(inst)
+	if err != nil {
+		return err
+	}
+
 	volType, err := InstanceTypeToVolumeType(inst.Type())
 	if err != nil {
 		return err
@@ -2662,11 +2861,17 @@ func (b *backend) CleanupInstancePaths(inst instance.Instance, op *operations.Op
 }
 
 // BackupInstance creates an instance backup.
-func (b *backend) BackupInstance(inst instance.Instance, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots bool, op *operations.Operation) error {
-	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "optimized": optimized, "snapshots": snapshots})
+// If parent is set, only the changes since that snapshot are included (along with the snapshots taken after it).
+func (b *backend) BackupInstance(inst instance.Instance, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots bool, parent string, op *operations.Operation) error {
+	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "optimized": optimized, "snapshots": snapshots, "parent": parent})
 	l.Debug("BackupInstance started")
 	defer l.Debug("BackupInstance finished")
 
+	err := InstanceLazyRootfsCheck(inst)
+	if err != nil {
+		return err
+	}
+
 	volType, err := InstanceTypeToVolumeType(inst.Type())
 	if err != nil {
 		return err
@@ -2695,7 +2900,7 @@ func (b *backend) BackupInstance(inst instance.Instance, tarWriter *instancewrit
 	}
 
 	var snapNames []string
-	if snapshots {
+	if snapshots || parent != "" {
 		// Get snapshots in age order, oldest first, and pass names to storage driver.
 		instSnapshots, err := inst.Snapshots()
 		if err != nil {
@@ -2707,9 +2912,21 @@ func (b *backend) BackupInstance(inst instance.Instance, tarWriter *instancewrit
 			_, snapName, _ := api.GetParentAndSnapshotName(instSnapshot.Name())
 			snapNames = append(snapNames, snapName)
 		}
+
+		// Only include the snapshots taken after the parent in incremental backups.
+		if parent != "" {
+			snapNames, err = SnapshotsSince(snapNames, parent)
+			if err != nil {
+				return err
+			}
+		}
+
+		if !snapshots {
+			snapNames = nil
+		}
 	}
 
-	err = b.driver.BackupVolume(vol, tarWriter, optimized, snapNames, op)
+	err = b.driver.BackupVolume(vol, tarWriter, optimized, snapNames, parent, op)
 	if err != nil {
 		return err
 	}
@@ -3023,6 +3240,11 @@ func (b *backend) CreateInstanceSnapshot(inst instance.Instance, src instance.In
 		return errors.New("Source instance cannot be a snapshot")
 	}
 
+	err := InstanceLazyRootfsCheck(src)
+	if err != nil {
+		return err
+	}
+
 	// Check we can convert the instance to the volume type needed.
 	volType, err := InstanceTypeToVolumeType(inst.Type())
 	if err != nil {
@@ -3512,11 +3734,16 @@ func (b *backend) EnsureImage(fingerprint string, op *operations.Operation) erro
 		// setting for new volumes.
 		blockFSChanged := imgVol.IsBlockBacked() && imgVol.Config()["block.filesystem"] != tmpImgVol.Config()["block.filesystem"]
 
+		// Check if the volume's encryption differs from the pool's current setting for new volumes.
+		encryptionChanged := tmpImgVol.IsEncrypted() != imgVol.IsEncrypted()
+
 		// If the existing image volume no longer matches the pool's settings for new volumes then we need
 		// to delete and re-create it.
-		if blockModeChanged || blockFSChanged {
+		if blockModeChanged || blockFSChanged || encryptionChanged {
 			if blockModeChanged {
 				l.Debug("Block mode has changed, regenerating image volume")
+			} else if encryptionChanged {
+				l.Debug("Encryption of pool has changed since cached image volume created, regenerating image volume")
 			} else {
 				l.Debug("Block volume filesystem of pool has changed since cached image volume created, regenerating image volume")
 			}
@@ -3704,7 +3931,10 @@ func volumeConfigsMatch(vol1, vol2 drivers.Volume) bool {
 	// they're considered unequal ("" != "8KiB"), preventing the use of a matching optimized image.
 	blockSizeChanged := vol1.IsBlockBacked() && vol1.Config()["zfs.blocksize"] != vol2.Config()["zfs.blocksize"]
 
-	return !blockModeChanged && !blockFSChanged && !blockSizeChanged
+	// Encrypted volumes can only be cloned from images using the same encryption.
+	encryptionChanged := vol1.IsEncrypted() != vol2.IsEncrypted()
+
+	return !blockModeChanged && !blockFSChanged && !blockSizeChanged && !encryptionChanged
 }
 
 // DeleteImage removes an image from the database and underlying storage device if needed.
@@ -4834,7 +5064,7 @@ func (b *backend) CreateCustomVolumeFromCopy(projectName string, srcProjectName
 	l.Debug("CreateCustomVolumeFromCopy cross-pool mode detected")
 
 	// Negotiate the migration type to use.
-	offeredTypes := srcPool.MigrationTypes(contentType, false, snapshots, false, true)
+	offeredTypes := encryptedVolumeMigrationTypes(srcPool, srcVol, true, srcPool.MigrationTypes(contentType, false, snapshots, false, true))
 	offerHeader := localMigration.TypesToHeader(offeredTypes...)
 	migrationTypes, err := localMigration.MatchTypes(offerHeader, FallbackMigrationType(contentType), b.MigrationTypes(contentType, false, snapshots, false, true))
 	if err != nil {
@@ -5433,6 +5663,18 @@ func (b *backend) UpdateCustomVolume(projectName string, volName string, newDesc
 			return errors.New(`Custom volume "block.filesystem" property cannot be changed`)
 		}
 
+		// Check that the volume's block.encryption property isn't being changed.
+		_, ok := changedConfig["block.encryption"]
+		if ok {
+			return errors.New(`Custom volume "block.encryption" property cannot be changed`)
+		}
+
+		// Check that the replica marker isn't being changed, it's only managed by replication.
+		_, ok = changedConfig["volatile.replication.source"]
+		if ok {
+			return errors.New(`Custom volume "volatile.replication.source" property cannot be changed`)
+		}
+
 		// Check for config changing that is not allowed when running instances are using it.
 		if changedConfig["security.shifted"] != "" {
 			err = VolumeUsedByInstanceDevices(b.state, b.name, projectName, &curVol.StorageVolume, true, func(dbInst db.InstanceArgs, project api.Project, usedByDevices []string) error {
@@ -5585,11 +5827,78 @@ func (b *backend) UpdateCustomVolume(projectName string, volName string, newDesc
 		}
 	}
 
+	// Re-apply the I/O limits of the instances using the volume (done once the new limits are in the database).
+	_, iopsChanged := changedConfig["limits.iops"]
+	_, bandwidthChanged := changedConfig["limits.bandwidth"]
+	if iopsChanged || bandwidthChanged {
+		err = b.reloadVolumeDevices(projectName, &curVol.StorageVolume)
+		if err != nil {
+			return fmt.Errorf("Failed applying the new I/O limits to the instances using the volume: %w", err)
+		}
+	}
+
 	b.state.Events.SendLifecycle(projectName, lifecycle.StorageVolumeUpdated.Event(newVol, string(newVol.Type()), projectName, op, nil))
 
 	return nil
 }
 
+// reloadVolumeDevices reloads the disk devices using the custom volume in all running instances.
+func (b *backend) reloadVolumeDevices(projectName string, vol *api.StorageVolume) error {
+	type instDevice struct {
+		args    db.InstanceArgs
+		devices []string
+	}
+
+	instDevices := []instDevice{}
+	err := VolumeUsedByInstanceDevices(b.state, b.name, projectName, vol, true, func(dbInst db.InstanceArgs, project api.Project, usedByDevices []string) error {
+		instDevices = append(instDevices, instDevice{args: dbInst, devices: usedByDevices})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range instDevices {
+		c, err := ConnectIfInstanceIsRemote(b.state, entry.args.Project, entry.args.Name, nil)
+		if err != nil {
+			return err
+		}
+
+		if c != nil {
+			// Send a remote notification.
+			values := url.Values{}
+			values.Set("devices", strings.Join(entry.devices, ","))
+
+			uri := fmt.Sprintf("/internal/instances/%d/ondevicereload?%s", entry.args.ID, values.Encode())
+			_, _, err := c.RawQuery("GET", uri, nil, "")
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		// Update the local instance.
+		inst, err := instance.LoadByProjectAndName(b.state, entry.args.Project, entry.args.Name)
+		if err != nil {
+			return err
+		}
+
+		if !inst.IsRunning() {
+			continue
+		}
+
+		for _, devName := range entry.devices {
+			err = inst.ReloadDevice(devName)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 // UpdateCustomVolumeSnapshot updates the description of a custom volume snapshot.
 // Volume config is not allowed to be updated and will return an error.
 func (b *backend) UpdateCustomVolumeSnapshot(projectName string, volName string, newDesc string, newConfig map[string]string, newExpiryDate time.Time, op *operations.Operation) error {
@@ -7113,7 +7422,7 @@ func (b *backend) BackupCustomVolume(projectName string, volName string, tarWrit
 
 	vol := b.GetVolume(drivers.VolumeTypeCustom, drivers.ContentType(volume.ContentType), volStorageName, volume.Config)
 
-	err = b.driver.BackupVolume(vol, tarWriter, optimized, snapNames, op)
+	err = b.driver.BackupVolume(vol, tarWriter, optimized, snapNames, "", op)
 	if err != nil {
 		return err
 	}
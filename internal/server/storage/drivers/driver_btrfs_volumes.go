@@ -156,7 +156,7 @@ func (d *btrfs) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Op
 func (d *btrfs) CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcData io.ReadSeeker, op *operations.Operation) (VolumePostHook, revert.Hook, error) {
 	// Handle the non-optimized tarballs through the generic unpacker.
 	if !*srcBackup.OptimizedStorage {
-		return genericVFSBackupUnpack(d, d.state.OS, vol, srcBackup.Snapshots, srcData, op)
+		return genericVFSBackupUnpack(d, d.state.OS, vol, srcBackup.Snapshots, srcBackup.Parent, srcData, op)
 	}
 
 	volExists, err := d.HasVolume(vol)
@@ -164,7 +164,17 @@ func (d *btrfs) CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcDat
 		return nil, nil, err
 	}
 
-	if volExists {
+	// Incremental backups get received on top of their parent snapshot.
+	if srcBackup.Parent != "" {
+		if !volExists {
+			return nil, nil, errors.New("Cannot apply incremental backup, volume doesn't exist on target")
+		}
+
+		err = vol.SnapshotsExist([]string{srcBackup.Parent}, op)
+		if err != nil {
+			return nil, nil, err
+		}
+	} else if volExists {
 		return nil, nil, errors.New("Cannot restore volume, already exists on target")
 	}
 
@@ -180,8 +190,12 @@ func (d *btrfs) CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcDat
 			_ = d.DeleteVolumeSnapshot(snapVol, op)
 		}
 
-		// And lastly the main volume.
-		_ = d.DeleteVolume(vol, op)
+		// And lastly the main volume, which for incremental backups is rolled back to the parent.
+		if srcBackup.Parent != "" {
+			_ = d.RestoreVolume(vol, srcBackup.Parent, op)
+		} else {
+			_ = d.DeleteVolume(vol, op)
+		}
 	}
 	// Only execute the revert function if we have had an error internally.
 	reverter.Add(revertHook)
@@ -374,7 +388,14 @@ func (d *btrfs) CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcDat
 			return nil, nil, err
 		}
 
-		// Clear the target for the subvol to use.
+		// Clear the target for the subvol to use (incremental backups replace the existing subvolume).
+		if srcBackup.Parent != "" && d.isSubvolume(copyOp.dest) {
+			err = d.deleteSubvolume(copyOp.dest, true)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
 		_ = os.Remove(copyOp.dest)
 
 		// Move unpacked subvolume into its final location.
@@ -1527,7 +1548,7 @@ func (d *btrfs) migrateVolumeOptimized(vol Volume, conn io.ReadWriteCloser, volS
 
 // BackupVolume copies a volume (and optionally its snapshots) to a specified target path.
 // This driver does not support optimized backups.
-func (d *btrfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, op *operations.Operation) error {
+func (d *btrfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, parent string, op *operations.Operation) error {
 	// Handle the non-optimized tarballs through the generic packer.
 	if !optimized {
 		// Because the generic backup method will not take a consistent backup if files are being modified
@@ -1546,12 +1567,18 @@ func (d *btrfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWr
 			vol.mountCustomPath = snapshotPath
 		}
 
-		return genericVFSBackupVolume(d, vol, tarWriter, snapshots, op)
+		return genericVFSBackupVolume(d, vol, tarWriter, snapshots, parent, op)
 	}
 
 	// Optimized backup.
 
-	if len(snapshots) > 0 {
+	if parent != "" {
+		// Check requested snapshots and their parent exist in storage.
+		err := vol.SnapshotsExist(append([]string{parent}, snapshots...), op)
+		if err != nil {
+			return err
+		}
+	} else if len(snapshots) > 0 {
 		// Check requested snapshot match those in storage.
 		err := vol.SnapshotsMatch(snapshots, op)
 		if err != nil {
@@ -1701,6 +1728,11 @@ func (d *btrfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWr
 
 	// Backup snapshots if populated.
 	lastVolPath := "" // Used as parent for differential exports.
+	if parent != "" {
+		// Incremental backups are sent relative to the parent snapshot.
+		parentVol, _ := vol.NewSnapshot(parent)
+		lastVolPath = parentVol.MountPath()
+	}
 	for _, snapName := range snapshots {
 		snapVol, _ := vol.NewSnapshot(snapName)
 
@@ -304,6 +304,7 @@ func (d *ceph) Validate(config map[string]string) error {
 		"ceph.rbd.du":             validate.Optional(validate.IsBool),
 		"ceph.rbd.features":       validate.IsAny,
 		"ceph.user.name":          validate.IsAny,
+		"encryption.kms":          luksValidateKMS,
 		"volatile.pool.pristine":  validate.IsAny,
 	}
 
@@ -113,6 +113,9 @@ func (d *ceph) rbdCreateVolume(vol Volume, size string) error {
 		return err
 	}
 
+	// Account for the encryption header.
+	sizeBytes = luksSizeBytes(vol, sizeBytes)
+
 	cmd := []string{
 		"--id", d.config["ceph.user.name"],
 		"--cluster", d.config["ceph.cluster_name"],
@@ -187,6 +190,17 @@ func (d *ceph) rbdMapVolume(vol Volume) (string, error) {
 	return devPath, nil
 }
 
+// rbdVolumeDevPath returns the path of the device holding the data of a mapped RBD volume.
+// For encrypted volumes, the volume is opened if needed and the path of its cleartext device is returned.
+func (d *ceph) rbdVolumeDevPath(vol Volume, rbdDevPath string) (string, error) {
+	if !vol.IsEncrypted() {
+		return rbdDevPath, nil
+	}
+
+	// Snapshots are mapped read-only.
+	return d.luksOpen(vol, rbdDevPath, vol.IsSnapshot())
+}
+
 // rbdUnmapVolume unmaps a given RBD storage volume.
 // This is a precondition in order to delete an RBD storage volume can.
 func (d *ceph) rbdUnmapVolume(vol Volume, unmapUntilEINVAL bool) error {
@@ -195,8 +209,14 @@ func (d *ceph) rbdUnmapVolume(vol Volume, unmapUntilEINVAL bool) error {
 
 	ourDeactivate := false
 
+	// Close the encrypted volume first.
+	_, err := d.luksClose(vol)
+	if err != nil {
+		return err
+	}
+
 again:
-	_, err := subprocess.RunCommand(
+	_, err = subprocess.RunCommand(
 		"rbd",
 		"--id", d.config["ceph.user.name"],
 		"--cluster", d.config["ceph.cluster_name"],
@@ -1326,7 +1346,7 @@ func (d *ceph) resizeVolume(vol Volume, sizeBytes int64, allowShrink bool) error
 		"--id", d.config["ceph.user.name"],
 		"--cluster", d.config["ceph.cluster_name"],
 		"--pool", d.config["ceph.osd.pool_name"],
-		"--size", fmt.Sprintf("%dB", sizeBytes),
+		"--size", fmt.Sprintf("%dB", luksSizeBytes(vol, sizeBytes)),
 		d.getRBDVolumeName(vol, "", false),
 	)
 
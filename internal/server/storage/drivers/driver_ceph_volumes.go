@@ -93,6 +93,9 @@ func (d *ceph) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Ope
 				return err
 			}
 
+			// Account for the encryption header.
+			poolVolSizeBytes = luksSizeBytes(vol, poolVolSizeBytes)
+
 			// If the cached volume size is different than the pool volume size, then we can't use the
 			// deleted cached image volume and instead we will rename it to a random UUID so it can't
 			// be restored in the future and a new cached image volume will be created instead.
@@ -156,6 +159,14 @@ func (d *ceph) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Ope
 
 	reverter.Add(func() { _ = d.rbdUnmapVolume(vol, true) })
 
+	// Setup encryption and use the cleartext device from here on.
+	if vol.IsEncrypted() {
+		devPath, err = d.luksFormat(vol, devPath)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Get filesystem.
 	RBDFilesystem := vol.ConfigBlockFilesystem()
 
@@ -319,13 +330,26 @@ func (d *ceph) getVolumeSize(volumeName string) (int64, error) {
 
 // CreateVolumeFromBackup re-creates a volume from its exported state.
 func (d *ceph) CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcData io.ReadSeeker, op *operations.Operation) (VolumePostHook, revert.Hook, error) {
-	return genericVFSBackupUnpack(d, d.state.OS, vol, srcBackup.Snapshots, srcData, op)
+	return genericVFSBackupUnpack(d, d.state.OS, vol, srcBackup.Snapshots, srcBackup.Parent, srcData, op)
 }
 
 // CreateVolumeFromCopy provides same-pool volume copying functionality.
 func (d *ceph) CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots bool, allowInconsistent bool, op *operations.Operation) error {
 	var err error
 
+	// Copies and clones keep the source encryption, so use the generic copy if it differs.
+	if vol.IsEncrypted() != srcVol.IsEncrypted() {
+		var srcSnapshots []Volume
+		if copySnapshots && !srcVol.IsSnapshot() {
+			srcSnapshots, err = srcVol.Snapshots(op)
+			if err != nil {
+				return err
+			}
+		}
+
+		return genericVFSCopyVolume(d, nil, vol, srcVol, srcSnapshots, false, allowInconsistent, op)
+	}
+
 	reverter := revert.New()
 	defer reverter.Fail()
 
@@ -333,13 +357,18 @@ func (d *ceph) CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots boo
 	// ensure permissions on mount path inside the volume are correct, and resize the volume to specified size.
 	postCreateTasks := func(v Volume) error {
 		// Map the RBD volume.
-		devPath, err := d.rbdMapVolume(v)
+		rbdDevPath, err := d.rbdMapVolume(v)
 		if err != nil {
 			return err
 		}
 
 		defer func() { _ = d.rbdUnmapVolume(v, true) }()
 
+		devPath, err := d.rbdVolumeDevPath(v, rbdDevPath)
+		if err != nil {
+			return err
+		}
+
 		if vol.contentType == ContentTypeFS {
 			// Re-generate the UUID. Do this first as ensuring permissions and setting quota can
 			// rely on being able to mount the volume.
@@ -630,13 +659,18 @@ func (d *ceph) CreateVolumeFromMigration(vol Volume, conn io.ReadWriteCloser, vo
 	}
 
 	// Map the RBD volume.
-	devPath, err := d.rbdMapVolume(vol)
+	rbdDevPath, err := d.rbdMapVolume(vol)
 	if err != nil {
 		return err
 	}
 
 	defer func() { _ = d.rbdUnmapVolume(vol, true) }()
 
+	devPath, err := d.rbdVolumeDevPath(vol, rbdDevPath)
+	if err != nil {
+		return err
+	}
+
 	// Re-generate the UUID.
 	err = d.generateUUID(vol.ConfigBlockFilesystem(), devPath)
 	if err != nil {
@@ -837,6 +871,7 @@ func (d *ceph) FillVolumeConfig(vol Volume) error {
 // commonVolumeRules returns validation rules which are common for pool and volume.
 func (d *ceph) commonVolumeRules() map[string]func(value string) error {
 	return map[string]func(value string) error{
+		"block.encryption":    validate.Optional(validate.IsOneOf("luks")),
 		"block.filesystem":    validate.Optional(validate.IsOneOf(blockBackedAllowedFilesystems...)),
 		"block.mount_options": validate.IsAny,
 	}
@@ -855,7 +890,12 @@ func (d *ceph) ValidateVolume(vol Volume, removeUnknownKeys bool) error {
 		delete(commonRules, "block.mount_options")
 	}
 
-	return d.validateVolume(vol, commonRules, removeUnknownKeys)
+	err := d.validateVolume(vol, commonRules, removeUnknownKeys)
+	if err != nil {
+		return err
+	}
+
+	return d.luksValidateKeyStore(vol, d.isRemote())
 }
 
 // UpdateVolume applies config changes to the volume.
@@ -970,7 +1010,7 @@ func (d *ceph) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, o
 		return nil
 	}
 
-	ourMap, devPath, err := d.getRBDMappedDevPath(vol, true)
+	ourMap, rbdDevPath, err := d.getRBDMappedDevPath(vol, true)
 	if err != nil {
 		return err
 	}
@@ -979,6 +1019,11 @@ func (d *ceph) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, o
 		defer func() { _ = d.rbdUnmapVolume(vol, true) }()
 	}
 
+	devPath, err := d.rbdVolumeDevPath(vol, rbdDevPath)
+	if err != nil {
+		return err
+	}
+
 	oldSizeBytes, err := BlockDiskSizeBytes(devPath)
 	if err != nil {
 		return fmt.Errorf("Error getting current size: %w", err)
@@ -1007,6 +1052,10 @@ func (d *ceph) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, o
 				return fmt.Errorf("Filesystem %q cannot be shrunk: %w", fsType, ErrCannotBeShrunk)
 			}
 
+			if vol.IsEncrypted() {
+				return fmt.Errorf("Encrypted volumes cannot be shrunk: %w", ErrCannotBeShrunk)
+			}
+
 			if inUse {
 				return ErrInUse // We don't allow online shrinking of filesystem volumes.
 			}
@@ -1030,6 +1079,11 @@ func (d *ceph) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, o
 				return err
 			}
 
+			err = d.luksResize(vol)
+			if err != nil {
+				return err
+			}
+
 			// Grow the filesystem to fill block device.
 			err = growFileSystem(fsType, devPath, vol)
 			if err != nil {
@@ -1055,6 +1109,11 @@ func (d *ceph) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, o
 			return err
 		}
 
+		err = d.luksResize(vol)
+		if err != nil {
+			return err
+		}
+
 		// Move the VM GPT alt header to end of disk if needed (not needed in unsafe resize mode as it is
 		// expected the caller will do all necessary post resize actions themselves).
 		if vol.IsVMBlock() && !allowUnsafeResize {
@@ -1071,6 +1130,10 @@ func (d *ceph) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, o
 // GetVolumeDiskPath returns the location of a root disk block device.
 func (d *ceph) GetVolumeDiskPath(vol Volume) (string, error) {
 	if vol.IsVMBlock() || (vol.volType == VolumeTypeCustom && IsContentBlock(vol.contentType)) {
+		if vol.IsEncrypted() {
+			return d.luksDiskPath(vol)
+		}
+
 		_, devPath, err := d.getRBDMappedDevPath(vol, false)
 		return devPath, err
 	}
@@ -1194,7 +1257,7 @@ func (d *ceph) MountVolume(vol Volume, op *operations.Operation) error {
 	defer reverter.Fail()
 
 	// Activate RBD volume if needed.
-	activated, volDevPath, err := d.getRBDMappedDevPath(vol, true)
+	activated, rbdDevPath, err := d.getRBDMappedDevPath(vol, true)
 	if err != nil {
 		return err
 	}
@@ -1203,6 +1266,11 @@ func (d *ceph) MountVolume(vol Volume, op *operations.Operation) error {
 		reverter.Add(func() { _ = d.rbdUnmapVolume(vol, true) })
 	}
 
+	volDevPath, err := d.rbdVolumeDevPath(vol, rbdDevPath)
+	if err != nil {
+		return err
+	}
+
 	if vol.contentType == ContentTypeFS {
 		mountPath := vol.MountPath()
 		if !linux.IsMountPoint(mountPath) {
@@ -1471,8 +1539,8 @@ func (d *ceph) MigrateVolume(vol Volume, conn io.ReadWriteCloser, volSrcArgs *lo
 }
 
 // BackupVolume creates an exported version of a volume.
-func (d *ceph) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, op *operations.Operation) error {
-	return genericVFSBackupVolume(d, vol, tarWriter, snapshots, op)
+func (d *ceph) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, parent string, op *operations.Operation) error {
+	return genericVFSBackupVolume(d, vol, tarWriter, snapshots, parent, op)
 }
 
 // CreateVolumeSnapshot creates a snapshot of a volume.
@@ -1621,7 +1689,7 @@ func (d *ceph) MountVolumeSnapshot(snapVol Volume, op *operations.Operation) err
 
 		// Clone snapshot.
 		cloneName := fmt.Sprintf("%s_%s_start_clone", parentName, snapshotOnlyName)
-		cloneVol := NewVolume(d, d.name, VolumeType("snapshots"), ContentTypeFS, cloneName, nil, nil)
+		cloneVol := NewVolume(d, d.name, VolumeType("snapshots"), ContentTypeFS, cloneName, snapVol.config, snapVol.poolConfig)
 
 		err = d.rbdCreateClone(parentVol, prefixedSnapOnlyName, cloneVol)
 		if err != nil {
@@ -1638,6 +1706,11 @@ func (d *ceph) MountVolumeSnapshot(snapVol Volume, op *operations.Operation) err
 
 		reverter.Add(func() { _ = d.rbdUnmapVolume(cloneVol, true) })
 
+		rbdDevPath, err = d.rbdVolumeDevPath(cloneVol, rbdDevPath)
+		if err != nil {
+			return err
+		}
+
 		RBDFilesystem := snapVol.ConfigBlockFilesystem()
 		mountFlags, mountOptions := linux.ResolveMountOptions(strings.Split(snapVol.ConfigBlockMountOptions(), ","))
 
@@ -1663,7 +1736,12 @@ func (d *ceph) MountVolumeSnapshot(snapVol Volume, op *operations.Operation) err
 		d.logger.Debug("Mounted RBD volume snapshot", logger.Ctx{"dev": rbdDevPath, "path": mountPath, "options": mountOptions})
 	} else if snapVol.contentType == ContentTypeBlock {
 		// Activate RBD volume if needed.
-		_, _, err := d.getRBDMappedDevPath(snapVol, true)
+		_, rbdDevPath, err := d.getRBDMappedDevPath(snapVol, true)
+		if err != nil {
+			return err
+		}
+
+		_, err = d.rbdVolumeDevPath(snapVol, rbdDevPath)
 		if err != nil {
 			return err
 		}
@@ -1711,7 +1789,7 @@ func (d *ceph) UnmountVolumeSnapshot(snapVol Volume, op *operations.Operation) (
 
 		parentName, snapshotOnlyName, _ := api.GetParentAndSnapshotName(snapVol.name)
 		cloneName := fmt.Sprintf("%s_%s_start_clone", parentName, snapshotOnlyName)
-		cloneVol := NewVolume(d, d.name, VolumeType("snapshots"), ContentTypeFS, cloneName, nil, nil)
+		cloneVol := NewVolume(d, d.name, VolumeType("snapshots"), ContentTypeFS, cloneName, snapVol.config, snapVol.poolConfig)
 
 		err = d.rbdUnmapVolume(cloneVol, true)
 		if err != nil {
@@ -1819,13 +1897,18 @@ func (d *ceph) RestoreVolume(vol Volume, snapshotName string, op *operations.Ope
 	}
 
 	// Map the RBD volume.
-	devPath, err := d.rbdMapVolume(snapVol)
+	rbdDevPath, err := d.rbdMapVolume(snapVol)
 	if err != nil {
 		return err
 	}
 
 	defer func() { _ = d.rbdUnmapVolume(snapVol, true) }()
 
+	devPath, err := d.rbdVolumeDevPath(snapVol, rbdDevPath)
+	if err != nil {
+		return err
+	}
+
 	// Re-generate the UUID.
 	err = d.generateUUID(snapVol.ConfigBlockFilesystem(), devPath)
 	if err != nil {
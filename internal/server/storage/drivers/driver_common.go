@@ -125,6 +125,16 @@ func (d *common) fillVolumeConfig(vol *Volume, excludedKeys ...string) error {
 			continue
 		}
 
+		// Scheduled backups are only relevant for custom volumes.
+		if vol.Type() != VolumeTypeCustom && strings.HasPrefix(volKey, "backups.") {
+			continue
+		}
+
+		// I/O limits are only relevant for custom volumes.
+		if vol.Type() != VolumeTypeCustom && strings.HasPrefix(volKey, "limits.") {
+			continue
+		}
+
 		if vol.config[volKey] == "" {
 			vol.config[volKey] = d.config[k]
 		}
@@ -424,7 +434,7 @@ func (d *common) MigrateVolume(vol Volume, conn io.ReadWriteCloser, volSrcArgs *
 }
 
 // BackupVolume creates an exported version of a volume.
-func (d *common) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, op *operations.Operation) error {
+func (d *common) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, parent string, op *operations.Operation) error {
 	return ErrNotSupported
 }
 
@@ -3,6 +3,7 @@ package drivers
 import (
 	"fmt"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"golang.org/x/sys/unix"
@@ -12,6 +13,7 @@ import (
 	internalUtil "github.com/lxc/incus/v6/internal/util"
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/util"
+	"github.com/lxc/incus/v6/shared/validate"
 )
 
 type dir struct {
@@ -38,7 +40,7 @@ func (d *dir) Info() Info {
 		Name:                         "dir",
 		Version:                      "1",
 		DefaultVMBlockFilesystemSize: deviceConfig.DefaultVMBlockFilesystemSize,
-		OptimizedImages:              false,
+		OptimizedImages:              d.reflinkSupported(),
 		PreservesInodes:              false,
 		Remote:                       d.isRemote(),
 		VolumeTypes:                  []VolumeType{VolumeTypeBucket, VolumeTypeCustom, VolumeTypeImage, VolumeTypeContainer, VolumeTypeVM},
@@ -93,6 +95,14 @@ func (d *dir) Create() error {
 		return fmt.Errorf("Source path '%s' isn't empty", sourcePath)
 	}
 
+	// Record whether copy-on-write clones can be used, so that this can't change during the pool's lifetime.
+	supported, err := reflinkCheck(sourcePath)
+	if err != nil {
+		return fmt.Errorf("Failed checking for reflink support: %w", err)
+	}
+
+	d.config["volatile.dir.reflink"] = strconv.FormatBool(supported)
+
 	return nil
 }
 
@@ -115,7 +125,11 @@ func (d *dir) Delete(op *operations.Operation) error {
 
 // Validate checks that all provide keys are supported and that no conflicting or missing configuration is present.
 func (d *dir) Validate(config map[string]string) error {
-	return d.validatePool(config, nil, nil)
+	rules := map[string]func(value string) error{
+		"volatile.dir.reflink": validate.Optional(validate.IsBool),
+	}
+
+	return d.validatePool(config, rules, nil)
 }
 
 // Update applies any driver changes required from a configuration change.
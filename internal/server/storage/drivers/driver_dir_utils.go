@@ -2,13 +2,186 @@ package drivers
 
 import (
 	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
 
+	"github.com/lxc/incus/v6/internal/server/operations"
 	"github.com/lxc/incus/v6/internal/server/storage/quota"
+	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/logger"
 	"github.com/lxc/incus/v6/shared/revert"
 	"github.com/lxc/incus/v6/shared/units"
+	"github.com/lxc/incus/v6/shared/util"
 )
 
+// reflinkSupported returns whether the pool is on a filesystem supporting copy-on-write clones (such as
+// XFS with reflink enabled or btrfs), in which case copies and snapshots use clones rather than rsync.
+// This is detected when creating the pool and recorded in its configuration.
+func (d *dir) reflinkSupported() bool {
+	return util.IsTrue(d.config["volatile.dir.reflink"])
+}
+
+// restoreVolumeReflink replaces the volume with a copy-on-write clone of the snapshot.
+// The clone is made next to the volume and only then swapped in, so that the volume is left untouched if
+// cloning fails.
+func (d *dir) restoreVolumeReflink(vol Volume, snapVol Volume) error {
+	volPath := vol.MountPath()
+
+	tmpPath, err := os.MkdirTemp(filepath.Dir(volPath), fmt.Sprintf(".%s.restore_", filepath.Base(volPath)))
+	if err != nil {
+		return fmt.Errorf("Failed creating temporary directory: %w", err)
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	reverter.Add(func() { _ = os.RemoveAll(tmpPath) })
+
+	d.Logger().Debug("Restoring volume from clone", logger.Ctx{"sourcePath": snapVol.MountPath(), "targetPath": volPath})
+	err = reflinkCopy(snapVol.MountPath(), tmpPath, false)
+	if err != nil {
+		return err
+	}
+
+	// Apply the volume's quota to the clone. For VMs, this is the quota of the config filesystem volume
+	// sharing the directory with the disk file.
+	quotaVol := vol
+	if vol.IsVMBlock() {
+		quotaVol = vol.NewVMBlockFilesystemVolume()
+	}
+
+	volID, err := d.getVolID(quotaVol.volType, quotaVol.name)
+	if err != nil {
+		return err
+	}
+
+	sizeBytes, err := units.ParseByteSizeString(quotaVol.ConfigSize())
+	if err != nil {
+		return err
+	}
+
+	if sizeBytes > 0 && vol.IsVMBlock() {
+		// Add the size of the VM image to the filesystem size (to ignore it from the quota).
+		blockSize, err := BlockDiskSizeBytes(filepath.Join(tmpPath, genericVolumeDiskFile))
+		if err != nil {
+			return err
+		}
+
+		sizeBytes += blockSize
+	}
+
+	err = d.setQuota(tmpPath, volID, sizeBytes)
+	if err != nil {
+		return err
+	}
+
+	// Swap the clone in.
+	oldPath := tmpPath + ".old"
+
+	err = os.Rename(volPath, oldPath)
+	if err != nil {
+		return fmt.Errorf("Failed moving volume out of the way: %w", err)
+	}
+
+	reverter.Add(func() { _ = os.Rename(oldPath, volPath) })
+
+	err = os.Rename(tmpPath, volPath)
+	if err != nil {
+		return fmt.Errorf("Failed replacing volume with its restored copy: %w", err)
+	}
+
+	reverter.Success()
+
+	err = os.RemoveAll(oldPath)
+	if err != nil {
+		d.logger.Warn("Failed removing previous volume content", logger.Ctx{"path": oldPath, "err": err})
+	}
+
+	// Ensure the restored directory has the correct permissions set.
+	return vol.EnsureMountPath(false)
+}
+
+// copyVolumeReflink copies a volume and its snapshots using copy-on-write clones.
+// As with rsync copies, files vanishing from the main volume during the copy are ignored if allowInconsistent is true.
+func (d *dir) copyVolumeReflink(vol Volume, srcVol Volume, srcSnapshots []Volume, allowInconsistent bool, op *operations.Operation) error {
+	if vol.contentType != srcVol.contentType {
+		return errors.New("Content type of source and target must be the same")
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	// Create the main volume, this also sets up its quota.
+	err := d.CreateVolume(vol, nil, op)
+	if err != nil {
+		return err
+	}
+
+	reverter.Add(func() { _ = d.DeleteVolume(vol, op) })
+
+	// Clone the snapshots straight into their own snapshot directories.
+	if !srcVol.IsSnapshot() {
+		for _, srcSnapshot := range srcSnapshots {
+			_, snapName, _ := api.GetParentAndSnapshotName(srcSnapshot.name)
+			snapVol := NewVolume(d, d.name, vol.volType, vol.contentType, GetSnapshotVolumeName(vol.name, snapName), vol.config, vol.poolConfig)
+
+			err = snapVol.EnsureMountPath(false)
+			if err != nil {
+				return err
+			}
+
+			reverter.Add(func() { _ = d.DeleteVolumeSnapshot(snapVol, op) })
+
+			d.Logger().Debug("Cloning volume snapshot", logger.Ctx{"sourcePath": srcSnapshot.MountPath(), "targetPath": snapVol.MountPath()})
+			err = reflinkCopy(srcSnapshot.MountPath(), snapVol.MountPath(), false)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	d.Logger().Debug("Cloning volume", logger.Ctx{"sourcePath": srcVol.MountPath(), "targetPath": vol.MountPath()})
+	err = reflinkCopy(srcVol.MountPath(), vol.MountPath(), allowInconsistent)
+	if err != nil {
+		return err
+	}
+
+	// Grow the cloned disk file to the requested size if needed.
+	if IsContentBlock(vol.contentType) {
+		sizeBytes, err := units.ParseByteSizeString(vol.ConfigSize())
+		if err != nil {
+			return err
+		}
+
+		rootBlockPath, err := d.GetVolumeDiskPath(vol)
+		if err != nil {
+			return err
+		}
+
+		resized, err := ensureVolumeBlockFile(vol, rootBlockPath, sizeBytes, false)
+		if err != nil && !errors.Is(err, ErrCannotBeShrunk) {
+			return err
+		}
+
+		if vol.IsVMBlock() && resized {
+			err = d.moveGPTAltHeader(rootBlockPath)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// Ensure the cloned directory has the correct permissions set.
+	err = vol.EnsureMountPath(false)
+	if err != nil {
+		return err
+	}
+
+	reverter.Success()
+	return nil
+}
+
 // withoutGetVolID returns a copy of this struct but with a volIDFunc which will cause quotas to be skipped.
 func (d *dir) withoutGetVolID() Driver {
 	newDriver := &dir{}
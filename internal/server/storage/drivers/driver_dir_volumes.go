@@ -101,14 +101,15 @@ func (d *dir) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Oper
 // CreateVolumeFromBackup restores a backup tarball onto the storage device.
 func (d *dir) CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcData io.ReadSeeker, op *operations.Operation) (VolumePostHook, revert.Hook, error) {
 	// Run the generic backup unpacker
-	postHook, revertHook, err := genericVFSBackupUnpack(d.withoutGetVolID(), d.state.OS, vol, srcBackup.Snapshots, srcData, op)
+	postHook, revertHook, err := genericVFSBackupUnpack(d.withoutGetVolID(), d.state.OS, vol, srcBackup.Snapshots, srcBackup.Parent, srcData, op)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	// genericVFSBackupUnpack returns a nil postHook when volume's type is VolumeTypeCustom which
 	// doesn't need any post hook processing after DB record creation.
-	if postHook != nil {
+	// Incremental backups are applied to an existing volume which already has its quota set up.
+	if postHook != nil && srcBackup.Parent == "" {
 		// Define a post hook function that can be run once the backup config has been restored.
 		// This will setup the quota using the restored config.
 		postHookWrapper := func(vol Volume) error {
@@ -150,6 +151,11 @@ func (d *dir) CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots bool
 		}
 	}
 
+	// Use copy-on-write clones if supported by the underlying filesystem.
+	if d.reflinkSupported() {
+		return d.copyVolumeReflink(vol, srcVol, srcSnapshots, allowInconsistent, op)
+	}
+
 	// Run the generic copy.
 	return genericVFSCopyVolume(d, d.setupInitialQuota, vol, srcVol, srcSnapshots, false, allowInconsistent, op)
 }
@@ -420,8 +426,8 @@ func (d *dir) MigrateVolume(vol Volume, conn io.ReadWriteCloser, volSrcArgs *mig
 
 // BackupVolume copies a volume (and optionally its snapshots) to a specified target path.
 // This driver does not support optimized backups.
-func (d *dir) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, op *operations.Operation) error {
-	return genericVFSBackupVolume(d, vol, tarWriter, snapshots, op)
+func (d *dir) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, parent string, op *operations.Operation) error {
+	return genericVFSBackupVolume(d, vol, tarWriter, snapshots, parent, op)
 }
 
 // CreateVolumeSnapshot creates a snapshot of a volume.
@@ -440,6 +446,20 @@ func (d *dir) CreateVolumeSnapshot(snapVol Volume, op *operations.Operation) err
 	snapPath := snapVol.MountPath()
 	reverter.Add(func() { _ = os.RemoveAll(snapPath) })
 
+	// Clone the whole volume, including any disk file, if supported by the underlying filesystem.
+	if d.reflinkSupported() {
+		srcPath := GetVolumeMountPath(d.name, snapVol.volType, parentName)
+		d.Logger().Debug("Cloning volume", logger.Ctx{"sourcePath": srcPath, "targetPath": snapPath})
+
+		err = reflinkCopy(srcPath, snapPath, false)
+		if err != nil {
+			return err
+		}
+
+		reverter.Success()
+		return nil
+	}
+
 	if snapVol.contentType != ContentTypeBlock || snapVol.volType != VolumeTypeCustom {
 		var rsyncArgs []string
 
@@ -583,6 +603,11 @@ func (d *dir) RestoreVolume(vol Volume, snapshotName string, op *operations.Oper
 
 	volPath := vol.MountPath()
 
+	// Replace the volume with a clone of the snapshot if supported by the underlying filesystem.
+	if d.reflinkSupported() {
+		return d.restoreVolumeReflink(vol, snapVol)
+	}
+
 	// Restore filesystem volume.
 	if vol.contentType != ContentTypeBlock || vol.volType != VolumeTypeCustom {
 		var rsyncArgs []string
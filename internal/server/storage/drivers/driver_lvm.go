@@ -579,6 +579,7 @@ func (d *lvm) Delete(op *operations.Operation) error {
 
 func (d *lvm) Validate(config map[string]string) error {
 	rules := map[string]func(value string) error{
+		"encryption.kms":    luksValidateKMS,
 		"lvm.vg_name":       validate.IsAny,
 		"lvm.metadata_size": validate.Optional(validate.IsSize),
 	}
@@ -811,6 +812,16 @@ func (d *lvm) GetResources() (*api.ResourcesStoragePool, error) {
 
 		res.Space.Total = totalSize
 		res.Space.Used = usedSize
+
+		// Running out of metadata space is as fatal as running out of data space, so report it too.
+		metaTotal, metaUsed, err := d.thinPoolMetadataUsage(volDevPath)
+		if err != nil && !errors.Is(err, ErrNotSupported) {
+			return nil, err
+		}
+
+		if err == nil {
+			res.Metadata = &api.ResourcesStoragePoolSpace{Total: metaTotal, Used: metaUsed}
+		}
 	} else {
 		// If thinpools are not in use, calculate used space in volume group.
 		args := []string{
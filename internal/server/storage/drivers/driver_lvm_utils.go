@@ -354,6 +354,9 @@ func (d *lvm) createLogicalVolume(vgName, thinPoolName string, vol Volume, makeT
 		return err
 	}
 
+	// Account for the encryption header.
+	lvSizeBytes = luksSizeBytes(vol, lvSizeBytes)
+
 	lvFullName := d.lvmFullVolumeName(vol.volType, vol.contentType, vol.name)
 
 	args := []string{
@@ -403,6 +406,14 @@ func (d *lvm) createLogicalVolume(vgName, thinPoolName string, vol Volume, makeT
 		return err
 	}
 
+	// Setup encryption and use the cleartext device from here on.
+	if vol.IsEncrypted() {
+		volDevPath, err = d.luksFormat(vol, volDevPath)
+		if err != nil {
+			return err
+		}
+	}
+
 	if vol.contentType == ContentTypeFS {
 		_, err = makeFSType(volDevPath, vol.ConfigBlockFilesystem(), nil)
 		if err != nil {
@@ -610,6 +621,21 @@ func (d *lvm) lvmDevPath(pathName string) (string, error) {
 	return filepath.Join("/dev", filepath.Base(target)), nil
 }
 
+// volumeDevPath returns the path of the device holding the data of an active volume.
+// For encrypted volumes, the volume is opened if needed and the path of its cleartext device is returned.
+func (d *lvm) volumeDevPath(vol Volume, readOnly bool) (string, error) {
+	volDevPath, err := d.lvmDevPath(d.lvmPath(d.config["lvm.vg_name"], vol.volType, vol.contentType, vol.name))
+	if err != nil {
+		return "", err
+	}
+
+	if vol.IsEncrypted() {
+		return d.luksOpen(vol, volDevPath, readOnly)
+	}
+
+	return volDevPath, nil
+}
+
 // resizeLogicalVolume resizes an LVM logical volume. This function does not resize any filesystem inside the LV.
 func (d *lvm) resizeLogicalVolume(lvPath string, sizeBytes int64) error {
 	isRecent, err := d.lvmVersionIsAtLeast(lvmVersion, "2.03.17")
@@ -742,7 +768,7 @@ func (d *lvm) copyThinpoolVolume(vol, srcVol Volume, srcSnapshots []Volume, refr
 				return err
 			}
 
-			volDevPath, err := d.lvmDevPath(volPath)
+			volDevPath, err := d.volumeDevPath(vol, false)
 			if err != nil {
 				return err
 			}
@@ -839,6 +865,45 @@ func (d *lvm) thinPoolVolumeUsage(volDevPath string) (uint64, uint64, error) {
 	return totalSize, usedSize, nil
 }
 
+// thinPoolMetadataUsage returns the metadata size and used metadata space for a thinpool.
+func (d *lvm) thinPoolMetadataUsage(volDevPath string) (uint64, uint64, error) {
+	args := []string{
+		volDevPath,
+		"--noheadings",
+		"--units", "b",
+		"--nosuffix",
+		"--separator", ",",
+		"-o", "lv_metadata_size,metadata_percent",
+	}
+
+	out, err := subprocess.RunCommand("lvs", args...)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	parts := util.SplitNTrimSpace(out, ",", -1, true)
+	if len(parts) < 2 {
+		return 0, 0, errors.New("Unexpected output from lvs command")
+	}
+
+	total, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("Failed parsing thinpool metadata size (%q): %w", parts[0], err)
+	}
+
+	// Used percentage is not available if the thinpool isn't activated.
+	if parts[1] == "" {
+		return 0, 0, ErrNotSupported
+	}
+
+	metaPerc, err := strconv.ParseFloat(parts[1], 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("Failed parsing thinpool metadata used percentage (%q): %w", parts[1], err)
+	}
+
+	return total, uint64(float64(total) * (metaPerc / 100)), nil
+}
+
 // parseLogicalVolumeSnapshot parses a raw logical volume name (from lvs command) and checks whether it is a
 // snapshot of the supplied parent volume. Returns unescaped parsed snapshot name if snapshot volume recognised,
 // empty string if not. The parent is required due to limitations in the naming scheme that Incus has historically
@@ -950,6 +1015,12 @@ func (d *lvm) deactivateVolume(vol Volume) (bool, error) {
 		return false, err
 	}
 
+	// Close the encrypted volume first.
+	_, err = d.luksClose(vol)
+	if err != nil {
+		return false, err
+	}
+
 	lvmActivation.Lock()
 	defer lvmActivation.Unlock()
 
@@ -123,7 +123,7 @@ func (d *lvm) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Oper
 
 // CreateVolumeFromBackup restores a backup tarball onto the storage device.
 func (d *lvm) CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcData io.ReadSeeker, op *operations.Operation) (VolumePostHook, revert.Hook, error) {
-	return genericVFSBackupUnpack(d, d.state.OS, vol, srcBackup.Snapshots, srcData, op)
+	return genericVFSBackupUnpack(d, d.state.OS, vol, srcBackup.Snapshots, srcBackup.Parent, srcData, op)
 }
 
 // CreateVolumeFromCopy provides same-pool volume copying functionality.
@@ -140,7 +140,8 @@ func (d *lvm) CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots bool
 	}
 
 	// We can use optimised copying when the pool is backed by an LVM thinpool.
-	if d.usesThinpool() {
+	// Snapshots keep the source encryption, so this requires both volumes to use the same encryption.
+	if d.usesThinpool() && vol.IsEncrypted() == srcVol.IsEncrypted() {
 		err = d.copyThinpoolVolume(vol, srcVol, srcSnapshots, false)
 		if err != nil {
 			return err
@@ -312,6 +313,7 @@ func (d *lvm) FillVolumeConfig(vol Volume) error {
 // commonVolumeRules returns validation rules which are common for pool and volume.
 func (d *lvm) commonVolumeRules() map[string]func(value string) error {
 	return map[string]func(value string) error{
+		"block.encryption":    validate.Optional(validate.IsOneOf("luks")),
 		"block.mount_options": validate.IsAny,
 		"block.filesystem":    validate.Optional(validate.IsOneOf(blockBackedAllowedFilesystems...)),
 		"lvm.stripes":         validate.Optional(validate.IsUint32),
@@ -337,6 +339,11 @@ func (d *lvm) ValidateVolume(vol Volume, removeUnknownKeys bool) error {
 		return err
 	}
 
+	err = d.luksValidateKeyStore(vol, d.isRemote())
+	if err != nil {
+		return err
+	}
+
 	if d.usesThinpool() && vol.config["lvm.stripes"] != "" {
 		return errors.New("lvm.stripes cannot be used with thin pool volumes")
 	}
@@ -418,6 +425,9 @@ func (d *lvm) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op
 		return err
 	}
 
+	// Account for the encryption header.
+	sizeBytes = luksSizeBytes(vol, sizeBytes)
+
 	// Read actual size of current volume.
 	volPath := d.lvmPath(d.config["lvm.vg_name"], vol.volType, vol.contentType, vol.name)
 	oldSizeBytes, err := d.logicalVolumeSize(volPath)
@@ -455,6 +465,10 @@ func (d *lvm) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op
 				return fmt.Errorf("Filesystem %q cannot be shrunk: %w", fsType, ErrCannotBeShrunk)
 			}
 
+			if vol.IsEncrypted() {
+				return fmt.Errorf("Encrypted volumes cannot be shrunk: %w", ErrCannotBeShrunk)
+			}
+
 			if inUse {
 				return ErrInUse // We don't allow online shrinking of filesystem volumes.
 			}
@@ -529,7 +543,12 @@ func (d *lvm) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op
 			}
 
 			// Grow the filesystem to fill block device.
-			volDevPath, err := d.lvmDevPath(volPath)
+			volDevPath, err := d.volumeDevPath(vol, false)
+			if err != nil {
+				return err
+			}
+
+			err = d.luksResize(vol)
 			if err != nil {
 				return err
 			}
@@ -564,6 +583,12 @@ func (d *lvm) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op
 			return err
 		}
 
+		// Grow the cleartext device if the encrypted volume is in use.
+		err = d.luksResize(vol)
+		if err != nil {
+			return err
+		}
+
 		// On thick pools, discard the blocks in the additional space when the volume is grown.
 		if !d.usesThinpool() && oldSizeBytes < sizeBytes {
 			// Activate the volume for discarding.
@@ -606,7 +631,12 @@ func (d *lvm) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op
 			}
 
 			// Move the GPT alt header.
-			volDevPath, err := d.lvmDevPath(volPath)
+			volDevPath, err := d.volumeDevPath(vol, false)
+			if err != nil {
+				return err
+			}
+
+			err = d.luksResize(vol)
 			if err != nil {
 				return err
 			}
@@ -624,6 +654,10 @@ func (d *lvm) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op
 // GetVolumeDiskPath returns the location of a disk volume.
 func (d *lvm) GetVolumeDiskPath(vol Volume) (string, error) {
 	if vol.IsVMBlock() || (vol.volType == VolumeTypeCustom && IsContentBlock(vol.contentType)) {
+		if vol.IsEncrypted() {
+			return d.luksDiskPath(vol)
+		}
+
 		return d.lvmDevPath(d.lvmPath(d.config["lvm.vg_name"], vol.volType, vol.contentType, vol.name))
 	}
 
@@ -760,7 +794,7 @@ func (d *lvm) MountVolume(vol Volume, op *operations.Operation) error {
 		mountPath := vol.MountPath()
 		if !linux.IsMountPoint(mountPath) {
 			fsType := vol.ConfigBlockFilesystem()
-			volDevPath, err := d.lvmDevPath(d.lvmPath(d.config["lvm.vg_name"], vol.volType, vol.contentType, vol.name))
+			volDevPath, err := d.volumeDevPath(vol, false)
 			if err != nil {
 				return err
 			}
@@ -786,6 +820,14 @@ func (d *lvm) MountVolume(vol Volume, op *operations.Operation) error {
 			d.logger.Debug("Mounted logical volume", logger.Ctx{"volName": vol.name, "dev": volDevPath, "path": mountPath, "options": mountOptions})
 		}
 	} else if vol.contentType == ContentTypeBlock || vol.contentType == ContentTypeISO {
+		// Open the encrypted volume if needed.
+		if vol.IsEncrypted() {
+			_, err = d.volumeDevPath(vol, false)
+			if err != nil {
+				return err
+			}
+		}
+
 		// For VMs, mount the filesystem volume.
 		if vol.IsVMBlock() {
 			fsVol := vol.NewVMBlockFilesystemVolume()
@@ -998,8 +1040,8 @@ func (d *lvm) MigrateVolume(vol Volume, conn io.ReadWriteCloser, volSrcArgs *mig
 
 // BackupVolume copies a volume (and optionally its snapshots) to a specified target path.
 // This driver does not support optimized backups.
-func (d *lvm) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWriter, _ bool, snapshots []string, op *operations.Operation) error {
-	return genericVFSBackupVolume(d, vol, tarWriter, snapshots, op)
+func (d *lvm) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWriter, _ bool, snapshots []string, parent string, op *operations.Operation) error {
+	return genericVFSBackupVolume(d, vol, tarWriter, snapshots, parent, op)
 }
 
 // CreateVolumeSnapshot creates a snapshot of a volume.
@@ -1161,11 +1203,15 @@ func (d *lvm) MountVolumeSnapshot(snapVol Volume, op *operations.Operation) erro
 		// Get volume path.
 		volPath := d.lvmPath(d.config["lvm.vg_name"], mountVol.volType, mountVol.contentType, mountVol.name)
 
-		volDevPath, err := d.lvmDevPath(volPath)
+		volDevPath, err := d.volumeDevPath(mountVol, !regenerateFSUUID)
 		if err != nil {
 			return err
 		}
 
+		if regenerateFSUUID {
+			reverter.Add(func() { _, _ = d.luksClose(mountVol) })
+		}
+
 		if regenerateFSUUID {
 			tmpVolFsType := mountVol.ConfigBlockFilesystem()
 
@@ -1199,6 +1245,14 @@ func (d *lvm) MountVolumeSnapshot(snapVol Volume, op *operations.Operation) erro
 			return err
 		}
 
+		// Open the encrypted volume if needed.
+		if snapVol.IsEncrypted() {
+			_, err = d.volumeDevPath(snapVol, true)
+			if err != nil {
+				return err
+			}
+		}
+
 		// For VMs, mount the filesystem volume.
 		if snapVol.IsVMBlock() {
 			fsVol := snapVol.NewVMBlockFilesystemVolume()
@@ -1252,6 +1306,12 @@ func (d *lvm) UnmountVolumeSnapshot(snapVol Volume, op *operations.Operation) (b
 		}
 
 		if exists {
+			tmpVol := NewVolume(d, d.name, snapVol.volType, snapVol.contentType, tmpVolName, snapVol.config, snapVol.poolConfig)
+			_, err = d.luksClose(tmpVol)
+			if err != nil {
+				return true, err
+			}
+
 			err = d.removeLogicalVolume(tmpVolPath)
 			if err != nil {
 				return true, fmt.Errorf("Failed to remove temporary LVM snapshot volume %q: %w", tmpVolPath, err)
@@ -1403,7 +1463,7 @@ func (d *lvm) RestoreVolume(vol Volume, snapshotName string, op *operations.Oper
 
 			d.logger.Debug("Regenerating filesystem UUID", logger.Ctx{"dev": volPath, "fs": vol.ConfigBlockFilesystem()})
 
-			volDevPath, err := d.lvmDevPath(volPath)
+			volDevPath, err := d.volumeDevPath(vol, false)
 			if err != nil {
 				return err
 			}
@@ -0,0 +1,256 @@
+package drivers
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/lxc/incus/v6/internal/linux"
+	"github.com/lxc/incus/v6/internal/migration"
+	deviceConfig "github.com/lxc/incus/v6/internal/server/device/config"
+	localMigration "github.com/lxc/incus/v6/internal/server/migration"
+	"github.com/lxc/incus/v6/internal/server/operations"
+	internalUtil "github.com/lxc/incus/v6/internal/util"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/util"
+	"github.com/lxc/incus/v6/shared/validate"
+)
+
+var nfsLoaded bool
+
+type nfs struct {
+	common
+}
+
+// load is used to run one-time action per-driver rather than per-pool.
+func (d *nfs) load() error {
+	// Register the patches.
+	d.patches = map[string]func() error{
+		"storage_lvm_skipactivation":                         nil,
+		"storage_missing_snapshot_records":                   nil,
+		"storage_delete_old_snapshot_records":                nil,
+		"storage_zfs_drop_block_volume_filesystem_extension": nil,
+		"storage_prefix_bucket_names_with_project":           nil,
+	}
+
+	// Done if previously loaded.
+	if nfsLoaded {
+		return nil
+	}
+
+	// Load the kernel module.
+	err := linux.LoadModule("nfs")
+	if err != nil {
+		return fmt.Errorf("Error loading %q module: %w", "nfs", err)
+	}
+
+	nfsLoaded = true
+	return nil
+}
+
+// isRemote returns true indicating this driver uses remote storage.
+func (d *nfs) isRemote() bool {
+	return true
+}
+
+// Info returns info about the driver and its environment.
+func (d *nfs) Info() Info {
+	return Info{
+		Name:                         "nfs",
+		Version:                      "1",
+		DefaultVMBlockFilesystemSize: deviceConfig.DefaultVMBlockFilesystemSize,
+		OptimizedImages:              false,
+		PreservesInodes:              false,
+		Remote:                       d.isRemote(),
+		VolumeTypes:                  []VolumeType{VolumeTypeCustom, VolumeTypeImage, VolumeTypeContainer, VolumeTypeVM},
+		VolumeMultiNode:              d.isRemote(),
+		BlockBacking:                 false,
+		RunningCopyFreeze:            true,
+		DirectIO:                     true,
+		IOUring:                      true,
+		MountedRoot:                  true,
+	}
+}
+
+// FillConfig populates the storage pool's configuration file with the default values.
+func (d *nfs) FillConfig() error {
+	if d.config["source"] == "" {
+		return nil
+	}
+
+	host, path, err := nfsParseSource(d.config["source"])
+	if err != nil {
+		return err
+	}
+
+	if d.config["nfs.host"] == "" {
+		d.config["nfs.host"] = host
+	}
+
+	if d.config["nfs.path"] == "" {
+		d.config["nfs.path"] = path
+	}
+
+	return nil
+}
+
+// Create is called during pool creation and is effectively using an empty driver struct.
+// WARNING: The Create() function cannot rely on any of the struct attributes being set.
+func (d *nfs) Create() error {
+	// Config validation.
+	if d.config["source"] == "" {
+		return errors.New("Missing required source, expected <host>:<path>")
+	}
+
+	host, path, err := nfsParseSource(d.config["source"])
+	if err != nil {
+		return err
+	}
+
+	if (d.config["nfs.host"] != "" && d.config["nfs.host"] != host) || (d.config["nfs.path"] != "" && d.config["nfs.path"] != path) {
+		return errors.New("nfs.host and nfs.path must match the source")
+	}
+
+	err = d.FillConfig()
+	if err != nil {
+		return err
+	}
+
+	// Create a temporary mountpoint.
+	mountPath, err := os.MkdirTemp("", "incus_nfs_")
+	if err != nil {
+		return fmt.Errorf("Failed to create temporary directory under: %w", err)
+	}
+
+	defer func() { _ = os.RemoveAll(mountPath) }()
+
+	err = os.Chmod(mountPath, 0o700)
+	if err != nil {
+		return fmt.Errorf("Failed to chmod '%s': %w", mountPath, err)
+	}
+
+	mountPoint := filepath.Join(mountPath, "mount")
+
+	err = os.Mkdir(mountPoint, 0o700)
+	if err != nil {
+		return fmt.Errorf("Failed to create directory '%s': %w", mountPoint, err)
+	}
+
+	// Mount the export.
+	err = d.mountExport(mountPoint)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _, _ = forceUnmount(mountPoint) }()
+
+	// Check that the export is empty.
+	ok, _ := internalUtil.PathIsEmpty(mountPoint)
+	if !ok {
+		return errors.New("Only empty NFS exports can be used as a storage pool")
+	}
+
+	return nil
+}
+
+// Delete removes the storage pool from the storage device.
+func (d *nfs) Delete(op *operations.Operation) error {
+	// Make sure the export is mounted.
+	_, err := d.Mount()
+	if err != nil {
+		return err
+	}
+
+	// On delete, wipe everything in the directory.
+	err = wipeDirectory(GetPoolMountPath(d.name))
+	if err != nil {
+		return err
+	}
+
+	// Unmount the path.
+	_, err = d.Unmount()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate checks that all provide keys are supported and that no conflicting or missing configuration is present.
+func (d *nfs) Validate(config map[string]string) error {
+	rules := map[string]func(value string) error{
+		"nfs.host":          validate.IsAny,
+		"nfs.path":          validate.Optional(validate.IsAbsFilePath),
+		"nfs.mount_options": validate.IsAny,
+	}
+
+	return d.validatePool(config, rules, nil)
+}
+
+// Update applies any driver changes required from a configuration change.
+func (d *nfs) Update(changedConfig map[string]string) error {
+	for _, key := range []string{"source", "nfs.host", "nfs.path"} {
+		_, ok := changedConfig[key]
+		if ok {
+			return fmt.Errorf("The %q property cannot be changed", key)
+		}
+	}
+
+	return nil
+}
+
+// Mount mounts the storage pool.
+func (d *nfs) Mount() (bool, error) {
+	path := GetPoolMountPath(d.name)
+
+	// Check if already mounted.
+	if linux.IsMountPoint(path) {
+		return false, nil
+	}
+
+	err := d.mountExport(path)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Unmount unmounts the storage pool.
+func (d *nfs) Unmount() (bool, error) {
+	return forceUnmount(GetPoolMountPath(d.name))
+}
+
+// GetResources returns the pool resource usage information.
+func (d *nfs) GetResources() (*api.ResourcesStoragePool, error) {
+	return genericVFSGetResources(d)
+}
+
+// MigrationTypes returns the supported migration types and options supported by the driver.
+func (d *nfs) MigrationTypes(contentType ContentType, refresh bool, copySnapshots bool, clusterMove bool, storageMove bool) []localMigration.Type {
+	var transportType migration.MigrationFSType
+	var rsyncFeatures []string
+
+	// Do not pass compression argument to rsync if the associated
+	// config key, that is rsync.compression, is set to false.
+	if util.IsFalse(d.Config()["rsync.compression"]) {
+		rsyncFeatures = []string{"delete", "bidirectional"}
+	} else {
+		rsyncFeatures = []string{"delete", "compress", "bidirectional"}
+	}
+
+	if IsContentBlock(contentType) {
+		transportType = migration.MigrationFSType_BLOCK_AND_RSYNC
+	} else {
+		transportType = migration.MigrationFSType_RSYNC
+	}
+
+	// Do not support xattr transfer as most NFS servers don't support them.
+	return []localMigration.Type{
+		{
+			FSType:   transportType,
+			Features: rsyncFeatures,
+		},
+	}
+}
@@ -0,0 +1,58 @@
+package drivers
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// nfsDefaultMountOptions are the mount options used when nfs.mount_options isn't set.
+const nfsDefaultMountOptions = "vers=4"
+
+// nfsParseSource splits an NFS source of the form "<host>:<path>" into its host and path.
+// IPv6 addresses must be enclosed in square brackets.
+func nfsParseSource(source string) (string, string, error) {
+	var host, path string
+	var ok bool
+
+	if strings.HasPrefix(source, "[") {
+		host, path, ok = strings.Cut(strings.TrimPrefix(source, "["), "]:")
+	} else {
+		host, path, ok = strings.Cut(source, ":")
+	}
+
+	if !ok || host == "" || !strings.HasPrefix(path, "/") {
+		return "", "", fmt.Errorf("Invalid NFS source %q, expected <host>:<path>", source)
+	}
+
+	return host, path, nil
+}
+
+// mountExport mounts the pool's NFS export on the given path.
+func (d *nfs) mountExport(path string) error {
+	host := d.config["nfs.host"]
+
+	// The kernel needs the server address to be resolved ahead of time.
+	addrs, err := net.LookupHost(host)
+	if err != nil {
+		return fmt.Errorf("Failed to resolve NFS server %q: %w", host, err)
+	}
+
+	if len(addrs) == 0 {
+		return fmt.Errorf("NFS server %q didn't resolve to any address", host)
+	}
+
+	source := fmt.Sprintf("%s:%s", host, d.config["nfs.path"])
+	if strings.Contains(host, ":") {
+		source = fmt.Sprintf("[%s]:%s", host, d.config["nfs.path"])
+	}
+
+	options := d.config["nfs.mount_options"]
+	if options == "" {
+		options = nfsDefaultMountOptions
+	}
+
+	options = fmt.Sprintf("%s,addr=%s", options, addrs[0])
+
+	return TryMount(source, path, "nfs", 0, options)
+}
@@ -0,0 +1,38 @@
+package drivers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_nfsParseSource(t *testing.T) {
+	tests := []struct {
+		source   string
+		wantHost string
+		wantPath string
+		wantErr  bool
+	}{
+		{"nfs.example.net:/srv/incus", "nfs.example.net", "/srv/incus", false},
+		{"192.0.2.10:/", "192.0.2.10", "/", false},
+		{"[2001:db8::10]:/srv/incus", "2001:db8::10", "/srv/incus", false},
+		{"nfs.example.net", "", "", true},
+		{"nfs.example.net:srv/incus", "", "", true},
+		{":/srv/incus", "", "", true},
+		{"[2001:db8::10]/srv/incus", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			host, path, err := nfsParseSource(tt.source)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantHost, host)
+			assert.Equal(t, tt.wantPath, path)
+		})
+	}
+}
@@ -0,0 +1,512 @@
+package drivers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+
+	"github.com/lxc/incus/v6/internal/instancewriter"
+	"github.com/lxc/incus/v6/internal/linux"
+	"github.com/lxc/incus/v6/internal/rsync"
+	"github.com/lxc/incus/v6/internal/server/backup"
+	"github.com/lxc/incus/v6/internal/server/migration"
+	"github.com/lxc/incus/v6/internal/server/operations"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/revert"
+	"github.com/lxc/incus/v6/shared/units"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+// CreateVolume creates an empty volume and can optionally fill it by executing the supplied
+// filler function.
+func (d *nfs) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Operation) error {
+	volPath := vol.MountPath()
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	if util.PathExists(vol.MountPath()) {
+		return fmt.Errorf("Volume path %q already exists", vol.MountPath())
+	}
+
+	// Create the volume itself.
+	err := vol.EnsureMountPath(true)
+	if err != nil {
+		return err
+	}
+
+	reverter.Add(func() { _ = os.RemoveAll(volPath) })
+
+	// Get path to disk volume if volume is block or iso.
+	rootBlockPath := ""
+	if IsContentBlock(vol.contentType) {
+		// We expect the filler to copy the VM image into this path.
+		rootBlockPath, err = d.GetVolumeDiskPath(vol)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Run the volume filler function if supplied.
+	err = d.runFiller(vol, rootBlockPath, filler, false)
+	if err != nil {
+		return err
+	}
+
+	// If we are creating a block volume, resize it to the requested size or the default.
+	// For block volumes, we expect the filler function to have converted the qcow2 image to raw into the rootBlockPath.
+	// For ISOs the content will just be copied.
+	if IsContentBlock(vol.contentType) {
+		// Convert to bytes.
+		sizeBytes, err := units.ParseByteSizeString(vol.ConfigSize())
+		if err != nil {
+			return err
+		}
+
+		// Ignore ErrCannotBeShrunk when setting size this just means the filler run above has needed to
+		// increase the volume size beyond the default block volume size.
+		_, err = ensureVolumeBlockFile(vol, rootBlockPath, sizeBytes, false)
+		if err != nil && !errors.Is(err, ErrCannotBeShrunk) {
+			return err
+		}
+
+		// Move the GPT alt header to end of disk if needed and if filler specified.
+		if vol.IsVMBlock() && filler != nil && filler.Fill != nil {
+			err = d.moveGPTAltHeader(rootBlockPath)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	reverter.Success()
+	return nil
+}
+
+// CreateVolumeFromBackup restores a backup tarball onto the storage device.
+func (d *nfs) CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcData io.ReadSeeker, op *operations.Operation) (VolumePostHook, revert.Hook, error) {
+	return genericVFSBackupUnpack(d, d.state.OS, vol, srcBackup.Snapshots, srcBackup.Parent, srcData, op)
+}
+
+// CreateVolumeFromCopy provides same-pool volume copying functionality.
+func (d *nfs) CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots bool, allowInconsistent bool, op *operations.Operation) error {
+	var err error
+	var srcSnapshots []Volume
+
+	if copySnapshots && !srcVol.IsSnapshot() {
+		// Get the list of snapshots from the source.
+		srcSnapshots, err = srcVol.Snapshots(op)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Run the generic copy.
+	return genericVFSCopyVolume(d, nil, vol, srcVol, srcSnapshots, false, allowInconsistent, op)
+}
+
+// CreateVolumeFromMigration creates a volume being sent via a migration.
+func (d *nfs) CreateVolumeFromMigration(vol Volume, conn io.ReadWriteCloser, volTargetArgs migration.VolumeTargetArgs, preFiller *VolumeFiller, op *operations.Operation) error {
+	return genericVFSCreateVolumeFromMigration(d, nil, vol, conn, volTargetArgs, preFiller, op)
+}
+
+// RefreshVolume provides same-pool volume and specific snapshots syncing functionality.
+func (d *nfs) RefreshVolume(vol Volume, srcVol Volume, srcSnapshots []Volume, allowInconsistent bool, op *operations.Operation) error {
+	return genericVFSCopyVolume(d, nil, vol, srcVol, srcSnapshots, true, allowInconsistent, op)
+}
+
+// DeleteVolume deletes a volume of the storage device. If any snapshots of the volume remain then
+// this function will return an error.
+func (d *nfs) DeleteVolume(vol Volume, op *operations.Operation) error {
+	snapshots, err := d.VolumeSnapshots(vol, op)
+	if err != nil {
+		return err
+	}
+
+	if len(snapshots) > 0 {
+		return errors.New("Cannot remove a volume that has snapshots")
+	}
+
+	volPath := vol.MountPath()
+
+	// If the volume doesn't exist, then nothing more to do.
+	if !util.PathExists(volPath) {
+		return nil
+	}
+
+	// Remove the volume from the storage device.
+	err = forceRemoveAll(volPath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("Failed to remove '%s': %w", volPath, err)
+	}
+
+	// Although the volume snapshot directory should already be removed, lets remove it here
+	// to just in case the top-level directory is left.
+	err = deleteParentSnapshotDirIfEmpty(d.name, vol.volType, vol.name)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// HasVolume indicates whether a specific volume exists on the storage pool.
+func (d *nfs) HasVolume(vol Volume) (bool, error) {
+	return genericVFSHasVolume(vol)
+}
+
+// FillVolumeConfig populate volume with default config.
+func (d *nfs) FillVolumeConfig(vol Volume) error {
+	// Filesystem volumes can't be limited in size, so don't inherit the default size of block volumes.
+	if vol.contentType == ContentTypeFS {
+		return d.fillVolumeConfig(&vol, "size")
+	}
+
+	return d.fillVolumeConfig(&vol)
+}
+
+// ValidateVolume validates the supplied volume config. Optionally removes invalid keys from the volume's config.
+func (d *nfs) ValidateVolume(vol Volume, removeUnknownKeys bool) error {
+	err := d.validateVolume(vol, nil, removeUnknownKeys)
+	if err != nil {
+		return err
+	}
+
+	// NFS has no way to limit the size of a directory.
+	if vol.volType == VolumeTypeCustom && vol.contentType == ContentTypeFS && vol.config["size"] != "" && vol.config["size"] != "0" {
+		return errors.New("Custom filesystem volumes on NFS storage pools don't support size limits")
+	}
+
+	return nil
+}
+
+// UpdateVolume applies config changes to the volume.
+func (d *nfs) UpdateVolume(vol Volume, changedConfig map[string]string) error {
+	newSize, sizeChanged := changedConfig["size"]
+	if sizeChanged {
+		err := d.SetVolumeQuota(vol, newSize, false, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SetVolumeQuota applies a size limit on volume.
+// Does nothing if supplied with an empty/zero size for block volumes or for filesystem volumes.
+func (d *nfs) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op *operations.Operation) error {
+	// Convert to bytes.
+	sizeBytes, err := units.ParseByteSizeString(size)
+	if err != nil {
+		return err
+	}
+
+	// For VM block files, resize the file if needed.
+	if vol.contentType == ContentTypeBlock {
+		// Do nothing if size isn't specified.
+		if sizeBytes <= 0 {
+			return nil
+		}
+
+		rootBlockPath, err := d.GetVolumeDiskPath(vol)
+		if err != nil {
+			return err
+		}
+
+		resized, err := ensureVolumeBlockFile(vol, rootBlockPath, sizeBytes, allowUnsafeResize)
+		if err != nil {
+			return err
+		}
+
+		// Move the GPT alt header to end of disk if needed and resize has taken place (not needed in
+		// unsafe resize mode as it is expected the caller will do all necessary post resize actions
+		// themselves).
+		if vol.IsVMBlock() && resized && !allowUnsafeResize {
+			err = d.moveGPTAltHeader(rootBlockPath)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// NFS doesn't support project quotas, filesystem volumes are left unrestricted.
+	return nil
+}
+
+// GetVolumeDiskPath returns the location of a disk volume.
+func (d *nfs) GetVolumeDiskPath(vol Volume) (string, error) {
+	return genericVFSGetVolumeDiskPath(vol)
+}
+
+// ListVolumes returns a list of volumes in storage pool.
+func (d *nfs) ListVolumes() ([]Volume, error) {
+	return genericVFSListVolumes(d)
+}
+
+// MountVolume simulates mounting a volume.
+func (d *nfs) MountVolume(vol Volume, op *operations.Operation) error {
+	unlock, err := vol.MountLock()
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	// Don't attempt to modify the permission of an existing custom volume root.
+	// A user inside the instance may have modified this and we don't want to reset it on restart.
+	if !util.PathExists(vol.MountPath()) || vol.volType != VolumeTypeCustom {
+		err := vol.EnsureMountPath(false)
+		if err != nil {
+			return err
+		}
+	}
+
+	vol.MountRefCountIncrement() // From here on it is up to caller to call UnmountVolume() when done.
+	return nil
+}
+
+// UnmountVolume simulates unmounting a volume.
+// As driver doesn't have volumes to unmount it returns false indicating the volume was already unmounted.
+func (d *nfs) UnmountVolume(vol Volume, keepBlockDev bool, op *operations.Operation) (bool, error) {
+	unlock, err := vol.MountLock()
+	if err != nil {
+		return false, err
+	}
+
+	defer unlock()
+
+	refCount := vol.MountRefCountDecrement()
+	if refCount > 0 {
+		d.logger.Debug("Skipping unmount as in use", logger.Ctx{"volName": vol.name, "refCount": refCount})
+		return false, ErrInUse
+	}
+
+	return false, nil
+}
+
+// RenameVolume renames a volume and its snapshots.
+func (d *nfs) RenameVolume(vol Volume, newVolName string, op *operations.Operation) error {
+	return genericVFSRenameVolume(d, vol, newVolName, op)
+}
+
+// MigrateVolume sends a volume for migration.
+func (d *nfs) MigrateVolume(vol Volume, conn io.ReadWriteCloser, volSrcArgs *migration.VolumeSourceArgs, op *operations.Operation) error {
+	return genericVFSMigrateVolume(d, d.state, vol, conn, volSrcArgs, op)
+}
+
+// BackupVolume copies a volume (and optionally its snapshots) to a specified target path.
+// This driver does not support optimized backups.
+func (d *nfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, parent string, op *operations.Operation) error {
+	return genericVFSBackupVolume(d, vol, tarWriter, snapshots, parent, op)
+}
+
+// CreateVolumeSnapshot creates a snapshot of a volume.
+func (d *nfs) CreateVolumeSnapshot(snapVol Volume, op *operations.Operation) error {
+	parentName, _, _ := api.GetParentAndSnapshotName(snapVol.name)
+
+	// Create snapshot directory.
+	err := snapVol.EnsureMountPath(false)
+	if err != nil {
+		return err
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	snapPath := snapVol.MountPath()
+	reverter.Add(func() { _ = os.RemoveAll(snapPath) })
+
+	if snapVol.contentType != ContentTypeBlock || snapVol.volType != VolumeTypeCustom {
+		var rsyncArgs []string
+
+		if snapVol.IsVMBlock() {
+			rsyncArgs = append(rsyncArgs, "--exclude", genericVolumeDiskFile)
+		}
+
+		bwlimit := d.config["rsync.bwlimit"]
+		srcPath := GetVolumeMountPath(d.name, snapVol.volType, parentName)
+		d.Logger().Debug("Copying filesystem volume", logger.Ctx{"sourcePath": srcPath, "targetPath": snapPath, "bwlimit": bwlimit, "rsyncArgs": rsyncArgs})
+
+		// Copy filesystem volume into snapshot directory.
+		_, err = rsync.LocalCopy(srcPath, snapPath, bwlimit, true, rsyncArgs...)
+		if err != nil {
+			return err
+		}
+	}
+
+	if snapVol.IsVMBlock() || (snapVol.contentType == ContentTypeBlock && snapVol.volType == VolumeTypeCustom) {
+		parentVol := NewVolume(d, d.name, snapVol.volType, snapVol.contentType, parentName, nil, d.config)
+		srcDevPath, err := d.GetVolumeDiskPath(parentVol)
+		if err != nil {
+			return err
+		}
+
+		targetDevPath, err := d.GetVolumeDiskPath(snapVol)
+		if err != nil {
+			return err
+		}
+
+		d.Logger().Debug("Copying block volume", logger.Ctx{"srcDevPath": srcDevPath, "targetPath": targetDevPath})
+
+		err = ensureSparseFile(targetDevPath, 0)
+		if err != nil {
+			return err
+		}
+
+		err = copyDevice(srcDevPath, targetDevPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	reverter.Success()
+	return nil
+}
+
+// DeleteVolumeSnapshot removes a snapshot from the storage device. The volName and snapshotName
+// must be bare names and should not be in the format "volume/snapshot".
+func (d *nfs) DeleteVolumeSnapshot(snapVol Volume, op *operations.Operation) error {
+	snapPath := snapVol.MountPath()
+
+	// Remove the snapshot from the storage device.
+	err := forceRemoveAll(snapPath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("Failed to remove '%s': %w", snapPath, err)
+	}
+
+	parentName, _, _ := api.GetParentAndSnapshotName(snapVol.name)
+
+	// Remove the parent snapshot directory if this is the last snapshot being removed.
+	err = deleteParentSnapshotDirIfEmpty(d.name, snapVol.volType, parentName)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MountVolumeSnapshot sets up a read-only mount on top of the snapshot to avoid accidental modifications.
+func (d *nfs) MountVolumeSnapshot(snapVol Volume, op *operations.Operation) error {
+	unlock, err := snapVol.MountLock()
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	snapPath := snapVol.MountPath()
+
+	// Don't attempt to modify the permission of an existing custom volume root.
+	// A user inside the instance may have modified this and we don't want to reset it on restart.
+	if !util.PathExists(snapPath) || snapVol.volType != VolumeTypeCustom {
+		err := snapVol.EnsureMountPath(false)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = mountReadOnly(snapPath, snapPath)
+	if err != nil {
+		return err
+	}
+
+	snapVol.MountRefCountIncrement() // From here on it is up to caller to call UnmountVolumeSnapshot() when done.
+	return nil
+}
+
+// UnmountVolumeSnapshot removes the read-only mount placed on top of a snapshot.
+func (d *nfs) UnmountVolumeSnapshot(snapVol Volume, op *operations.Operation) (bool, error) {
+	unlock, err := snapVol.MountLock()
+	if err != nil {
+		return false, err
+	}
+
+	defer unlock()
+
+	mountPath := snapVol.MountPath()
+
+	refCount := snapVol.MountRefCountDecrement()
+
+	if linux.IsMountPoint(mountPath) {
+		if refCount > 0 {
+			d.logger.Debug("Skipping unmount as in use", logger.Ctx{"volName": snapVol.name, "refCount": refCount})
+			return false, ErrInUse
+		}
+
+		snapPath := snapVol.MountPath()
+		return forceUnmount(snapPath)
+	}
+
+	return false, nil
+}
+
+// VolumeSnapshots returns a list of snapshots for the volume (in no particular order).
+func (d *nfs) VolumeSnapshots(vol Volume, op *operations.Operation) ([]string, error) {
+	return genericVFSVolumeSnapshots(d, vol, op)
+}
+
+// RestoreVolume restores a volume from a snapshot.
+func (d *nfs) RestoreVolume(vol Volume, snapshotName string, op *operations.Operation) error {
+	snapVol, err := vol.NewSnapshot(snapshotName)
+	if err != nil {
+		return err
+	}
+
+	srcPath := snapVol.MountPath()
+	if !util.PathExists(srcPath) {
+		return errors.New("Snapshot not found")
+	}
+
+	volPath := vol.MountPath()
+
+	// Restore filesystem volume.
+	if vol.contentType != ContentTypeBlock || vol.volType != VolumeTypeCustom {
+		var rsyncArgs []string
+
+		if vol.IsVMBlock() {
+			rsyncArgs = append(rsyncArgs, "--exclude", genericVolumeDiskFile)
+		}
+
+		bwlimit := d.config["rsync.bwlimit"]
+		_, err := rsync.LocalCopy(srcPath, volPath, bwlimit, true, rsyncArgs...)
+		if err != nil {
+			return fmt.Errorf("Failed to rsync volume: %w", err)
+		}
+	}
+
+	// Restore block volume.
+	if vol.IsVMBlock() || (vol.contentType == ContentTypeBlock && vol.volType == VolumeTypeCustom) {
+		srcDevPath, err := d.GetVolumeDiskPath(snapVol)
+		if err != nil {
+			return err
+		}
+
+		targetDevPath, err := d.GetVolumeDiskPath(vol)
+		if err != nil {
+			return err
+		}
+
+		d.Logger().Debug("Restoring block volume", logger.Ctx{"srcDevPath": srcDevPath, "targetPath": targetDevPath})
+
+		err = ensureSparseFile(targetDevPath, 0)
+		if err != nil {
+			return err
+		}
+
+		err = copyDevice(srcDevPath, targetDevPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RenameVolumeSnapshot renames a volume snapshot.
+func (d *nfs) RenameVolumeSnapshot(snapVol Volume, newSnapshotName string, op *operations.Operation) error {
+	return genericVFSRenameVolumeSnapshot(d, snapVol, newSnapshotName, op)
+}
@@ -95,6 +95,9 @@ func (d *zfs) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Oper
 					return err
 				}
 
+				// Account for the encryption header.
+				poolVolSizeBytes = luksSizeBytes(vol, poolVolSizeBytes)
+
 				// If the cached volume size is different than the pool volume size, then we can't use the
 				// deleted cached image volume and instead we will rename it to a random UUID so it can't
 				// be restored in the future and a new cached image volume will be created instead.
@@ -175,8 +178,8 @@ func (d *zfs) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Oper
 	} else {
 		var opts []string
 
-		if vol.contentType == ContentTypeFS {
-			// Use volmode=dev so volume is visible as we need to run makeFSType.
+		if vol.contentType == ContentTypeFS || vol.IsEncrypted() {
+			// Use volmode=dev so volume is visible as we need to run makeFSType or setup encryption.
 			opts = []string{"volmode=dev"}
 		} else {
 			// Use volmode=none so volume is invisible until mounted.
@@ -226,6 +229,9 @@ func (d *zfs) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Oper
 			return err
 		}
 
+		// Account for the encryption header.
+		sizeBytes = luksSizeBytes(vol, sizeBytes)
+
 		// Create the volume dataset.
 		err = d.createVolume(d.dataset(vol, false), sizeBytes, opts...)
 		if err != nil {
@@ -235,7 +241,7 @@ func (d *zfs) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Oper
 		// After this point we'll have a volume, so setup revert.
 		reverter.Add(func() { _ = d.DeleteVolume(vol, op) })
 
-		if vol.contentType == ContentTypeFS {
+		if vol.contentType == ContentTypeFS || vol.IsEncrypted() {
 			// Wait up to 30 seconds for the device to appear.
 			ctx, cancel := context.WithTimeout(d.state.ShutdownCtx, 30*time.Second)
 			defer cancel()
@@ -245,9 +251,22 @@ func (d *zfs) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Oper
 				return err
 			}
 
-			zfsFilesystem := vol.ConfigBlockFilesystem()
+			// Setup encryption and use the cleartext device from here on.
+			if vol.IsEncrypted() {
+				devPath, err = d.luksFormat(vol, devPath)
+				if err != nil {
+					return err
+				}
+			}
+
+			if vol.contentType == ContentTypeFS {
+				_, err = makeFSType(devPath, vol.ConfigBlockFilesystem(), nil)
+				if err != nil {
+					return err
+				}
+			}
 
-			_, err = makeFSType(devPath, zfsFilesystem, nil)
+			_, err = d.luksClose(vol)
 			if err != nil {
 				return err
 			}
@@ -364,7 +383,7 @@ func (d *zfs) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Oper
 func (d *zfs) CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcData io.ReadSeeker, op *operations.Operation) (VolumePostHook, revert.Hook, error) {
 	// Handle the non-optimized tarballs through the generic unpacker.
 	if !*srcBackup.OptimizedStorage {
-		return genericVFSBackupUnpack(d, d.state.OS, vol, srcBackup.Snapshots, srcData, op)
+		return genericVFSBackupUnpack(d, d.state.OS, vol, srcBackup.Snapshots, srcBackup.Parent, srcData, op)
 	}
 
 	volExists, err := d.HasVolume(vol)
@@ -372,7 +391,17 @@ func (d *zfs) CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcData
 		return nil, nil, err
 	}
 
-	if volExists {
+	// Incremental backups get received on top of their parent snapshot.
+	if srcBackup.Parent != "" {
+		if !volExists {
+			return nil, nil, errors.New("Cannot apply incremental backup, volume doesn't exist on target")
+		}
+
+		err = vol.SnapshotsExist([]string{srcBackup.Parent}, op)
+		if err != nil {
+			return nil, nil, err
+		}
+	} else if volExists {
 		return nil, nil, errors.New("Cannot restore volume, already exists on target")
 	}
 
@@ -388,8 +417,12 @@ func (d *zfs) CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcData
 			_ = d.DeleteVolumeSnapshot(snapVol, op)
 		}
 
-		// And lastly the main volume.
-		_ = d.DeleteVolume(vol, op)
+		// And lastly the main volume, which for incremental backups is rolled back to the parent.
+		if srcBackup.Parent != "" {
+			_ = d.RestoreVolume(vol, srcBackup.Parent, op)
+		} else {
+			_ = d.DeleteVolume(vol, op)
+		}
 	}
 
 	// Only execute the revert function if we have had an error internally.
@@ -573,6 +606,19 @@ func (d *zfs) CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcData
 func (d *zfs) CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots bool, allowInconsistent bool, op *operations.Operation) error {
 	var err error
 
+	// Cloning would keep the source encryption, so use the generic copy if it differs.
+	if vol.IsEncrypted() != srcVol.IsEncrypted() {
+		var srcSnapshots []Volume
+		if copySnapshots && !srcVol.IsSnapshot() {
+			srcSnapshots, err = srcVol.Snapshots(op)
+			if err != nil {
+				return err
+			}
+		}
+
+		return genericVFSCopyVolume(d, nil, vol, srcVol, srcSnapshots, false, allowInconsistent, op)
+	}
+
 	// Revert handling
 	reverter := revert.New()
 	defer reverter.Fail()
@@ -1580,6 +1626,7 @@ func (d *zfs) HasVolume(vol Volume) (bool, error) {
 // commonVolumeRules returns validation rules which are common for pool and volume.
 func (d *zfs) commonVolumeRules() map[string]func(value string) error {
 	return map[string]func(value string) error{
+		"block.encryption":     validate.Optional(validate.IsOneOf("luks")),
 		"block.filesystem":     validate.Optional(validate.IsOneOf(blockBackedAllowedFilesystems...)),
 		"block.mount_options":  validate.IsAny,
 		"zfs.block_mode":       validate.Optional(validate.IsBool),
@@ -1604,6 +1651,7 @@ func (d *zfs) ValidateVolume(vol Volume, removeUnknownKeys bool) error {
 		delete(commonRules, "block.filesystem")
 		delete(commonRules, "block.mount_options")
 	} else if vol.volType == VolumeTypeCustom && !vol.IsBlockBacked() {
+		delete(commonRules, "block.encryption")
 		delete(commonRules, "block.filesystem")
 		delete(commonRules, "block.mount_options")
 	}
@@ -1779,6 +1827,9 @@ func (d *zfs) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op
 			return err
 		}
 
+		// Account for the encryption header.
+		sizeBytes = luksSizeBytes(vol, sizeBytes)
+
 		oldSizeBytesStr, err := d.getDatasetProperty(d.dataset(vol, false), "volsize")
 		if err != nil {
 			return err
@@ -1824,6 +1875,10 @@ func (d *zfs) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op
 					return fmt.Errorf("Filesystem %q cannot be shrunk: %w", fsType, ErrCannotBeShrunk)
 				}
 
+				if vol.IsEncrypted() {
+					return fmt.Errorf("Encrypted volumes cannot be shrunk: %w", ErrCannotBeShrunk)
+				}
+
 				if inUse {
 					return ErrInUse // We don't allow online shrinking of filesystem block volumes.
 				}
@@ -1849,6 +1904,11 @@ func (d *zfs) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op
 					return err
 				}
 
+				err = d.luksResize(vol)
+				if err != nil {
+					return err
+				}
+
 				// Grow the filesystem to fill block device.
 				err = growFileSystem(fsType, volDevPath, vol)
 				if err != nil {
@@ -1877,6 +1937,12 @@ func (d *zfs) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op
 			if err != nil {
 				return err
 			}
+
+			// Grow the cleartext device if the encrypted volume is in use.
+			err = d.luksResize(vol)
+			if err != nil {
+				return err
+			}
 		}
 
 		// Move the VM GPT alt header to end of disk if needed (not needed in unsafe resize mode as
@@ -2003,6 +2069,10 @@ func (d *zfs) getVolumeDiskPathFromDataset(dataset string) (string, error) {
 
 // GetVolumeDiskPath returns the location of a root disk block device.
 func (d *zfs) GetVolumeDiskPath(vol Volume) (string, error) {
+	if vol.IsEncrypted() {
+		return d.luksDiskPath(vol)
+	}
+
 	// Wait up to 30 seconds for the device to appear.
 	ctx, cancel := context.WithTimeout(d.state.ShutdownCtx, 30*time.Second)
 	defer cancel()
@@ -2146,6 +2216,7 @@ func (d *zfs) activateVolume(vol Volume) (bool, error) {
 		return false, err
 	}
 
+	activated := false
 	if current != "dev" {
 		// For block backed volumes, we make their associated device appear.
 		err = d.setDatasetProperties(dataset, "volmode=dev")
@@ -2154,23 +2225,32 @@ func (d *zfs) activateVolume(vol Volume) (bool, error) {
 		}
 
 		reverter.Add(func() { _ = d.setDatasetProperties(dataset, fmt.Sprintf("volmode=%s", current)) })
+		activated = true
+	}
 
-		// Wait up to 30 seconds for the device to appear.
-		ctx, cancel := context.WithTimeout(d.state.ShutdownCtx, 30*time.Second)
-		defer cancel()
+	// Wait up to 30 seconds for the device to appear.
+	ctx, cancel := context.WithTimeout(d.state.ShutdownCtx, 30*time.Second)
+	defer cancel()
 
-		_, err := d.tryGetVolumeDiskPathFromDataset(ctx, dataset)
-		if err != nil {
-			return false, fmt.Errorf("Failed to activate volume: %v", err)
-		}
+	devPath, err := d.tryGetVolumeDiskPathFromDataset(ctx, dataset)
+	if err != nil {
+		return false, fmt.Errorf("Failed to activate volume: %v", err)
+	}
 
+	if activated {
 		d.logger.Debug("Activated ZFS volume", logger.Ctx{"volName": vol.Name(), "dev": dataset})
+	}
 
-		reverter.Success()
-		return true, nil
+	// Open the encrypted volume if needed.
+	if vol.IsEncrypted() {
+		_, err = d.luksOpen(vol, devPath, false)
+		if err != nil {
+			return false, err
+		}
 	}
 
-	return false, nil
+	reverter.Success()
+	return activated, nil
 }
 
 // deactivateVolume deactivates a ZFS volume if activate. Returns true if deactivated, false if not.
@@ -2188,7 +2268,17 @@ func (d *zfs) deactivateVolume(vol Volume) (bool, error) {
 	}
 
 	if current == "dev" {
-		devPath, err := d.GetVolumeDiskPath(vol)
+		// Close the encrypted volume first.
+		_, err = d.luksClose(vol)
+		if err != nil {
+			return false, err
+		}
+
+		// Wait up to 30 seconds for the device to appear.
+		ctx, cancel := context.WithTimeout(d.state.ShutdownCtx, 30*time.Second)
+		defer cancel()
+
+		devPath, err := d.tryGetVolumeDiskPathFromDataset(ctx, dataset)
 		if err != nil {
 			return false, fmt.Errorf("Failed locating zvol for deactivation: %w", err)
 		}
@@ -2786,7 +2876,7 @@ func (d *zfs) readonlySnapshot(vol Volume) (string, revert.Hook, error) {
 }
 
 // BackupVolume creates an exported version of a volume.
-func (d *zfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, op *operations.Operation) error {
+func (d *zfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, parent string, op *operations.Operation) error {
 	// Handle the non-optimized tarballs through the generic packer.
 	if !optimized {
 		// Because the generic backup method will not take a consistent backup if files are being modified
@@ -2805,12 +2895,18 @@ func (d *zfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWrit
 			vol.mountCustomPath = snapshotPath
 		}
 
-		return genericVFSBackupVolume(d, vol, tarWriter, snapshots, op)
+		return genericVFSBackupVolume(d, vol, tarWriter, snapshots, parent, op)
 	}
 
 	// Optimized backup.
 
-	if len(snapshots) > 0 {
+	if parent != "" {
+		// Check requested snapshots and their parent exist in storage.
+		err := vol.SnapshotsExist(append([]string{parent}, snapshots...), op)
+		if err != nil {
+			return err
+		}
+	} else if len(snapshots) > 0 {
 		// Check requested snapshot match those in storage.
 		err := vol.SnapshotsMatch(snapshots, op)
 		if err != nil {
@@ -2821,7 +2917,7 @@ func (d *zfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWrit
 	// Backup VM config volumes first.
 	if vol.IsVMBlock() {
 		fsVol := vol.NewVMBlockFilesystemVolume()
-		err := d.BackupVolume(fsVol, tarWriter, optimized, snapshots, op)
+		err := d.BackupVolume(fsVol, tarWriter, optimized, snapshots, parent, op)
 		if err != nil {
 			return err
 		}
@@ -2882,15 +2978,21 @@ func (d *zfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWrit
 
 	// Handle snapshots.
 	finalParent := ""
+	if parent != "" {
+		// Incremental backups are sent relative to the parent snapshot.
+		parentSnapshot, _ := vol.NewSnapshot(parent)
+		finalParent = d.dataset(parentSnapshot, false)
+	}
+
 	if len(snapshots) > 0 {
 		for i, snapName := range snapshots {
 			snapshot, _ := vol.NewSnapshot(snapName)
 
 			// Figure out parent and current subvolumes.
-			parent := ""
+			snapParent := finalParent
 			if i > 0 {
 				oldSnapshot, _ := vol.NewSnapshot(snapshots[i-1])
-				parent = d.dataset(oldSnapshot, false)
+				snapParent = d.dataset(oldSnapshot, false)
 			}
 
 			// Make a binary zfs backup.
@@ -2906,7 +3008,7 @@ func (d *zfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWrit
 			}
 
 			target := fmt.Sprintf("backup/%s/%s", prefix, fileName)
-			err := sendToFile(d.dataset(snapshot, false), parent, target)
+			err := sendToFile(d.dataset(snapshot, false), snapParent, target)
 			if err != nil {
 				return err
 			}
@@ -3126,6 +3228,24 @@ func (d *zfs) mountVolumeSnapshot(snapVol Volume, snapshotDataset string, mountP
 			d.logger.Debug("Activated ZFS snapshot volume", logger.Ctx{"dev": snapshotDataset})
 		}
 
+		// Open the encrypted snapshot if needed.
+		if snapVol.contentType == ContentTypeBlock && snapVol.IsEncrypted() {
+			ctx, cancel := context.WithTimeout(d.state.ShutdownCtx, 30*time.Second)
+			defer cancel()
+
+			snapDevPath, err := d.tryGetVolumeDiskPathFromDataset(ctx, snapshotDataset)
+			if err != nil {
+				return nil, err
+			}
+
+			_, err = d.luksOpen(snapVol, snapDevPath, true)
+			if err != nil {
+				return nil, err
+			}
+
+			reverter.Add(func() { _, _ = d.luksClose(snapVol) })
+		}
+
 		if snapVol.contentType != ContentTypeBlock && d.isBlockBacked(snapVol) && !linux.IsMountPoint(mountPath) {
 			err = snapVol.EnsureMountPath(false)
 			if err != nil {
@@ -3185,6 +3305,16 @@ func (d *zfs) mountVolumeSnapshot(snapVol Volume, snapshotDataset string, mountP
 				return nil, err
 			}
 
+			// Open the encrypted volume if needed, the temporary volume needs to be writable.
+			if mountVol.IsEncrypted() {
+				volPath, err = d.luksOpen(mountVol, volPath, !regenerateFSUUID)
+				if err != nil {
+					return nil, err
+				}
+
+				reverter.Add(func() { _, _ = d.luksClose(mountVol) })
+			}
+
 			tmpVolFsType := mountVol.ConfigBlockFilesystem()
 
 			if regenerateFSUUID {
@@ -3292,6 +3422,15 @@ func (d *zfs) UnmountVolumeSnapshot(snapVol Volume, op *operations.Operation) (b
 			parentDataset := d.dataset(parentVol, false)
 			dataset := fmt.Sprintf("%s_%s%s", parentDataset, snapshotOnlyName, tmpVolSuffix)
 
+			// Close the encrypted snapshot or temporary volume if needed.
+			tmpVol := NewVolume(d, d.name, snapVol.volType, snapVol.contentType, fmt.Sprintf("%s%s", snapVol.name, tmpVolSuffix), snapVol.config, snapVol.poolConfig)
+			for _, encVol := range []Volume{snapVol, tmpVol} {
+				_, err = d.luksClose(encVol)
+				if err != nil {
+					return true, err
+				}
+			}
+
 			exists, err := d.datasetExists(dataset)
 			if err != nil {
 				return true, fmt.Errorf("Failed to check existence of temporary ZFS snapshot volume %q: %w", dataset, err)
@@ -3320,7 +3459,13 @@ func (d *zfs) UnmountVolumeSnapshot(snapVol Volume, op *operations.Operation) (b
 				return false, ErrInUse
 			}
 
-			err := d.setDatasetProperties(parentDataset, "snapdev=hidden")
+			// Close the encrypted snapshot first.
+			_, err = d.luksClose(snapVol)
+			if err != nil {
+				return false, err
+			}
+
+			err = d.setDatasetProperties(parentDataset, "snapdev=hidden")
 			if err != nil {
 				return false, err
 			}
@@ -3530,7 +3675,7 @@ func (d *zfs) FillVolumeConfig(vol Volume) error {
 	if vol.hasSource || vol.IsVMBlock() || vol.volType == VolumeTypeCustom && vol.contentType == ContentTypeBlock {
 		excludedKeys = []string{"zfs.block_mode", "block.filesystem", "block.mount_options"}
 	} else if vol.volType == VolumeTypeCustom && !vol.IsBlockBacked() {
-		excludedKeys = []string{"block.filesystem", "block.mount_options"}
+		excludedKeys = []string{"block.encryption", "block.filesystem", "block.mount_options"}
 	}
 
 	err := d.fillVolumeConfig(&vol, excludedKeys...)
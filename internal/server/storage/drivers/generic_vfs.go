@@ -1,6 +1,8 @@
 package drivers
 
 import (
+	"archive/tar"
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -11,10 +13,13 @@ import (
 	"strings"
 	"time"
 
+	"gopkg.in/yaml.v2"
+
 	"github.com/lxc/incus/v6/internal/instancewriter"
 	"github.com/lxc/incus/v6/internal/linux"
 	"github.com/lxc/incus/v6/internal/migration"
 	"github.com/lxc/incus/v6/internal/rsync"
+	"github.com/lxc/incus/v6/internal/server/backup"
 	localMigration "github.com/lxc/incus/v6/internal/server/migration"
 	"github.com/lxc/incus/v6/internal/server/operations"
 	"github.com/lxc/incus/v6/internal/server/state"
@@ -521,8 +526,15 @@ func genericVFSGetVolumeDiskPath(vol Volume) (string, error) {
 }
 
 // genericVFSBackupVolume is a generic BackupVolume implementation for VFS-only drivers.
-func genericVFSBackupVolume(d Driver, vol Volume, tarWriter *instancewriter.InstanceTarWriter, snapshots []string, op *operations.Operation) error {
-	if len(snapshots) > 0 {
+// If parent is set, only the changes since the parent snapshot are included in the backup.
+func genericVFSBackupVolume(d Driver, vol Volume, tarWriter *instancewriter.InstanceTarWriter, snapshots []string, parent string, op *operations.Operation) error {
+	if parent != "" {
+		// Check requested snapshots and their parent exist in storage.
+		err := vol.SnapshotsExist(append([]string{parent}, snapshots...), op)
+		if err != nil {
+			return err
+		}
+	} else if len(snapshots) > 0 {
 		// Check requested snapshot match those in storage.
 		err := vol.SnapshotsMatch(snapshots, op)
 		if err != nil {
@@ -530,9 +542,45 @@ func genericVFSBackupVolume(d Driver, vol Volume, tarWriter *instancewriter.Inst
 		}
 	}
 
+	// writeDeletedFiles adds the list of files removed since the parent volume to the backup tarball.
+	writeDeletedFiles := func(mountPath string, parentMountPath string, prefix string, exclude []string) error {
+		deleted, err := listDeletedFiles(mountPath, parentMountPath, exclude...)
+		if err != nil {
+			return fmt.Errorf("Failed listing deleted files: %w", err)
+		}
+
+		deletedData, err := yaml.Marshal(deleted)
+		if err != nil {
+			return err
+		}
+
+		fi := instancewriter.FileInfo{
+			FileName:    fmt.Sprintf("%s.%s", prefix, backupDeltaDeletedExtension),
+			FileSize:    int64(len(deletedData)),
+			FileMode:    0o600,
+			FileModTime: time.Now(),
+		}
+
+		return tarWriter.WriteFileFromReader(bytes.NewReader(deletedData), &fi)
+	}
+
+	// changedSinceParent checks whether a file differs from its counterpart in the parent volume.
+	changedSinceParent := func(srcPath string, fi os.FileInfo, mountPath string, parentMountPath string) bool {
+		if parentMountPath == "" || srcPath == mountPath {
+			return true
+		}
+
+		parentFi, err := os.Lstat(filepath.Join(parentMountPath, strings.TrimPrefix(srcPath, mountPath)))
+		if err != nil {
+			return true
+		}
+
+		return !fileUnchanged(fi, parentFi)
+	}
+
 	// Define a function that can copy a volume into the backup target location.
-	backupVolume := func(v Volume, prefix string) error {
-		return v.MountTask(func(mountPath string, op *operations.Operation) error {
+	backupVolume := func(v Volume, parentVol *Volume, prefix string) error {
+		backupMounted := func(mountPath string, parentMountPath string) error {
 			// Reset hard link cache as we are copying a new volume (instance or snapshot).
 			tarWriter.ResetHardLinkMap()
 
@@ -574,6 +622,11 @@ func genericVFSBackupVolume(d Driver, vol Volume, tarWriter *instancewriter.Inst
 							return nil
 						}
 
+						// Skip any files unchanged since the parent.
+						if !changedSinceParent(srcPath, fi, mountPath, parentMountPath) {
+							return nil
+						}
+
 						name := filepath.Join(prefix, strings.TrimPrefix(srcPath, mountPath))
 						err = tarWriter.WriteFile(name, srcPath, fi, false)
 						if err != nil {
@@ -585,6 +638,68 @@ func genericVFSBackupVolume(d Driver, vol Volume, tarWriter *instancewriter.Inst
 					if err != nil {
 						return err
 					}
+
+					if parentMountPath != "" {
+						err = writeDeletedFiles(mountPath, parentMountPath, prefix, exclude)
+						if err != nil {
+							return err
+						}
+					}
+				}
+
+				from, err := os.Open(blockPath)
+				if err != nil {
+					return fmt.Errorf("Error opening file for reading %q: %w", blockPath, err)
+				}
+
+				defer func() { _ = from.Close() }()
+
+				if parentVol != nil {
+					parentBlockPath, err := d.GetVolumeDiskPath(*parentVol)
+					if err != nil {
+						return fmt.Errorf("Error getting parent block volume disk path: %w", err)
+					}
+
+					parentBlockDiskSize, err := BlockDiskSizeBytes(parentBlockPath)
+					if err != nil {
+						return fmt.Errorf("Error getting block device size %q: %w", parentBlockPath, err)
+					}
+
+					parentFrom, err := os.Open(parentBlockPath)
+					if err != nil {
+						return fmt.Errorf("Error opening file for reading %q: %w", parentBlockPath, err)
+					}
+
+					defer func() { _ = parentFrom.Close() }()
+
+					// Generate the delta in a temporary file as its size is needed for the tarball header.
+					tmpFile, err := os.CreateTemp(internalUtil.VarPath("backups"), fmt.Sprintf("%s_delta", backup.WorkingDirPrefix))
+					if err != nil {
+						return fmt.Errorf("Failed to open temporary file for block delta: %w", err)
+					}
+
+					defer func() { _ = tmpFile.Close() }()
+					defer func() { _ = os.Remove(tmpFile.Name()) }()
+
+					name := fmt.Sprintf("%s.%s", prefix, backupDeltaBlockExtension)
+					d.Logger().Debug("Generating block volume delta", logger.Ctx{"sourcePath": blockPath, "parentPath": parentBlockPath, "file": name})
+
+					err = writeBlockDelta(tmpFile, from, blockDiskSize, parentFrom, parentBlockDiskSize)
+					if err != nil {
+						return fmt.Errorf("Error generating block delta of %q: %w", blockPath, err)
+					}
+
+					tmpFileInfo, err := os.Lstat(tmpFile.Name())
+					if err != nil {
+						return err
+					}
+
+					err = tarWriter.WriteFile(name, tmpFile.Name(), tmpFileInfo, false)
+					if err != nil {
+						return fmt.Errorf("Error copying %q as %q to tarball: %w", tmpFile.Name(), name, err)
+					}
+
+					return tmpFile.Close()
 				}
 
 				name := fmt.Sprintf("%s.%s", prefix, genericVolumeBlockExtension)
@@ -595,12 +710,6 @@ func genericVFSBackupVolume(d Driver, vol Volume, tarWriter *instancewriter.Inst
 				}
 
 				d.Logger().Debug(logMsg, logger.Ctx{"sourcePath": blockPath, "file": name, "size": blockDiskSize})
-				from, err := os.Open(blockPath)
-				if err != nil {
-					return fmt.Errorf("Error opening file for reading %q: %w", blockPath, err)
-				}
-
-				defer func() { _ = from.Close() }()
 
 				fi := instancewriter.FileInfo{
 					FileName:    name,
@@ -637,7 +746,7 @@ func genericVFSBackupVolume(d Driver, vol Volume, tarWriter *instancewriter.Inst
 					}
 				}
 
-				return filepath.Walk(mountPath, func(srcPath string, fi os.FileInfo, err error) error {
+				err = filepath.Walk(mountPath, func(srcPath string, fi os.FileInfo, err error) error {
 					if err != nil {
 						if errors.Is(err, fs.ErrNotExist) {
 							logger.Warnf("File vanished during export: %q, skipping", srcPath)
@@ -647,6 +756,11 @@ func genericVFSBackupVolume(d Driver, vol Volume, tarWriter *instancewriter.Inst
 						return fmt.Errorf("Error walking file during export: %q: %w", srcPath, err)
 					}
 
+					// Skip any files unchanged since the parent.
+					if !changedSinceParent(srcPath, fi, mountPath, parentMountPath) {
+						return nil
+					}
+
 					name := filepath.Join(prefix, strings.TrimPrefix(srcPath, mountPath))
 
 					// Write the file to the tarball with ignoreGrowth enabled so that if the
@@ -659,12 +773,43 @@ func genericVFSBackupVolume(d Driver, vol Volume, tarWriter *instancewriter.Inst
 
 					return nil
 				})
+				if err != nil {
+					return err
+				}
+
+				if parentMountPath != "" {
+					return writeDeletedFiles(mountPath, parentMountPath, prefix, nil)
+				}
 			}
 
 			return nil
+		}
+
+		if parentVol == nil {
+			return v.MountTask(func(mountPath string, op *operations.Operation) error {
+				return backupMounted(mountPath, "")
+			}, op)
+		}
+
+		// Mount the parent alongside the volume so the two can be compared.
+		return parentVol.MountTask(func(parentMountPath string, op *operations.Operation) error {
+			return v.MountTask(func(mountPath string, op *operations.Operation) error {
+				return backupMounted(mountPath, parentMountPath)
+			}, op)
 		}, op)
 	}
 
+	// Each volume is exported relative to the one before it, starting from the parent snapshot.
+	var parentVol *Volume
+	if parent != "" {
+		snapVol, err := vol.NewSnapshot(parent)
+		if err != nil {
+			return err
+		}
+
+		parentVol = &snapVol
+	}
+
 	// Handle snapshots.
 	if len(snapshots) > 0 {
 		snapshotsPrefix := "backup/snapshots"
@@ -681,10 +826,14 @@ func genericVFSBackupVolume(d Driver, vol Volume, tarWriter *instancewriter.Inst
 				return err
 			}
 
-			err = backupVolume(snapVol, prefix)
+			err = backupVolume(snapVol, parentVol, prefix)
 			if err != nil {
 				return err
 			}
+
+			if parentVol != nil {
+				parentVol = &snapVol
+			}
 		}
 	}
 
@@ -696,7 +845,7 @@ func genericVFSBackupVolume(d Driver, vol Volume, tarWriter *instancewriter.Inst
 		prefix = "backup/volume"
 	}
 
-	err := backupVolume(vol, prefix)
+	err := backupVolume(vol, parentVol, prefix)
 	if err != nil {
 		return err
 	}
@@ -704,12 +853,133 @@ func genericVFSBackupVolume(d Driver, vol Volume, tarWriter *instancewriter.Inst
 	return nil
 }
 
+// ValidateIncrementalBackup checks that a non-optimized incremental backup holds valid changes for all the
+// volumes it restores, so that it can be rejected before the volume gets rolled back to its parent.
+// Optimized backups are left to the driver, which checks the streams as it receives them.
+func ValidateIncrementalBackup(vol Volume, srcBackup backup.Info, srcData io.ReadSeeker) error {
+	if srcBackup.Parent == "" || srcBackup.OptimizedStorage == nil || *srcBackup.OptimizedStorage {
+		return nil
+	}
+
+	backupPrefix := "backup/container"
+	backupSnapshotsPrefix := "backup/snapshots"
+	if vol.IsVMBlock() {
+		backupPrefix = "backup/virtual-machine"
+		backupSnapshotsPrefix = "backup/virtual-machine-snapshots"
+	} else if vol.volType == VolumeTypeCustom {
+		backupPrefix = "backup/volume"
+		backupSnapshotsPrefix = "backup/volume-snapshots"
+	}
+
+	prefixes := []string{backupPrefix}
+	for _, snapName := range srcBackup.Snapshots {
+		prefixes = append(prefixes, fmt.Sprintf("%s/%s", backupSnapshotsPrefix, snapName))
+	}
+
+	// List the files holding the changes of each volume.
+	expected := map[string]bool{}
+	for _, prefix := range prefixes {
+		if !vol.IsCustomBlock() {
+			expected[fmt.Sprintf("%s.%s", prefix, backupDeltaDeletedExtension)] = false
+		}
+
+		if vol.contentType == ContentTypeBlock {
+			expected[fmt.Sprintf("%s.%s", prefix, backupDeltaBlockExtension)] = false
+		}
+	}
+
+	_, err := srcData.Seek(0, io.SeekStart)
+	if err != nil {
+		return err
+	}
+
+	_, _, unpacker, err := archive.DetectCompressionFile(srcData)
+	if err != nil {
+		return err
+	}
+
+	tr, cancelFunc, err := archive.CompressedTarReader(context.Background(), srcData, unpacker, internalUtil.VarPath("backups"))
+	if err != nil {
+		return err
+	}
+
+	defer cancelFunc()
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break // End of archive.
+		}
+
+		if err != nil {
+			return fmt.Errorf("Failed reading backup: %w", err)
+		}
+
+		_, found := expected[hdr.Name]
+		if !found {
+			continue
+		}
+
+		if strings.HasSuffix(hdr.Name, "."+backupDeltaDeletedExtension) {
+			deleted := []string{}
+			err = yaml.NewDecoder(tr).Decode(&deleted)
+			if err != nil {
+				return fmt.Errorf("Invalid list of deleted files %q: %w", hdr.Name, err)
+			}
+		} else {
+			err = checkBlockDelta(tr)
+			if err != nil {
+				return fmt.Errorf("Invalid block delta %q: %w", hdr.Name, err)
+			}
+		}
+
+		expected[hdr.Name] = true
+	}
+
+	for name, found := range expected {
+		if !found {
+			return fmt.Errorf("Could not find %q", name)
+		}
+	}
+
+	return nil
+}
+
 // genericVFSBackupUnpack unpacks a non-optimized backup tarball through a storage driver.
 // Returns a post hook function that should be called once the database entries for the restored backup have been
 // created and a revert function that can be used to undo the actions this function performs should something
 // subsequently fail. For VolumeTypeCustom volumes, a nil post hook is returned as it is expected that the DB
 // record be created before the volume is unpacked due to differences in the archive format that allows this.
-func genericVFSBackupUnpack(d Driver, sysOS *sys.OS, vol Volume, snapshots []string, srcData io.ReadSeeker, op *operations.Operation) (VolumePostHook, revert.Hook, error) {
+// If parent is set, the backup is an incremental one which gets applied on top of the existing volume, which the
+// caller must have rolled back to the parent snapshot.
+func genericVFSBackupUnpack(d Driver, sysOS *sys.OS, vol Volume, snapshots []string, parent string, srcData io.ReadSeeker, op *operations.Operation) (VolumePostHook, revert.Hook, error) {
+	// findFile returns a tar reader positioned at the requested file of the backup tarball.
+	findFile := func(r io.ReadSeeker, unpacker []string, srcFile string, outputPath string) (*tar.Reader, context.CancelFunc, error) {
+		tr, cancelFunc, err := archive.CompressedTarReader(context.Background(), r, unpacker, outputPath)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break // End of archive.
+			}
+
+			if err != nil {
+				cancelFunc()
+				return nil, nil, err
+			}
+
+			if hdr.Name == srcFile {
+				return tr, cancelFunc, nil
+			}
+		}
+
+		cancelFunc()
+		return nil, nil, fmt.Errorf("Could not find %q", srcFile)
+	}
+
 	// Define function to unpack a volume from a backup tarball file.
 	unpackVolume := func(r io.ReadSeeker, tarArgs []string, unpacker []string, srcPrefix string, mountPath string) error {
 		volTypeName := "container"
@@ -719,10 +989,32 @@ func genericVFSBackupUnpack(d Driver, sysOS *sys.OS, vol Volume, snapshots []str
 			volTypeName = "custom"
 		}
 
-		// Clear the volume ready for unpack.
-		err := wipeDirectory(mountPath)
-		if err != nil {
-			return fmt.Errorf("Error clearing volume before unpack: %w", err)
+		if parent != "" {
+			// Remove the files deleted since the previous volume.
+			if !vol.IsCustomBlock() {
+				tr, cancelFunc, err := findFile(r, unpacker, fmt.Sprintf("%s.%s", srcPrefix, backupDeltaDeletedExtension), mountPath)
+				if err != nil {
+					return err
+				}
+
+				deleted := []string{}
+				err = yaml.NewDecoder(tr).Decode(&deleted)
+				cancelFunc()
+				if err != nil {
+					return fmt.Errorf("Failed reading list of deleted files: %w", err)
+				}
+
+				err = removeDeletedFiles(mountPath, deleted)
+				if err != nil {
+					return err
+				}
+			}
+		} else {
+			// Clear the volume ready for unpack.
+			err := wipeDirectory(mountPath)
+			if err != nil {
+				return fmt.Errorf("Error clearing volume before unpack: %w", err)
+			}
 		}
 
 		// Unpack the filesystem parts of the volume (for containers and custom filesystem volumes that is
@@ -787,6 +1079,50 @@ func genericVFSBackupUnpack(d Driver, sysOS *sys.OS, vol Volume, snapshots []str
 				return err
 			}
 
+			// Apply the changed blocks on top of the existing disk.
+			if parent != "" {
+				srcFile := fmt.Sprintf("%s.%s", srcPrefix, backupDeltaBlockExtension)
+
+				tr, cancelFunc, err := findFile(r, unpacker, srcFile, mountPath)
+				if err != nil {
+					return err
+				}
+
+				defer cancelFunc()
+
+				// Open block file (use O_CREATE to support drivers that use image files).
+				to, err := os.OpenFile(targetPath, os.O_WRONLY|os.O_CREATE, 0o644)
+				if err != nil {
+					return fmt.Errorf("Error opening file for writing %q: %w", targetPath, err)
+				}
+
+				defer func() { _ = to.Close() }()
+
+				resize := func(size int64) error {
+					currentSize, err := BlockDiskSizeBytes(targetPath)
+					if err != nil {
+						return err
+					}
+
+					if currentSize == size {
+						return nil
+					}
+
+					// Restore the size of the volume at the time of the backup.
+					d.Logger().Debug("Setting volume size from source", logger.Ctx{"source": srcFile, "target": targetPath, "size": size})
+					return d.SetVolumeQuota(vol, fmt.Sprintf("%d", size), true, op)
+				}
+
+				d.Logger().Debug("Applying block volume delta", logger.Ctx{"source": srcFile, "target": targetPath})
+				err = applyBlockDelta(tr, resize, to)
+				if err != nil {
+					return err
+				}
+
+				cancelFunc()
+				return to.Close()
+			}
+
 			srcFile := fmt.Sprintf("%s.%s", srcPrefix, genericVolumeBlockExtension)
 
 			tr, cancelFunc, err := archive.CompressedTarReader(context.Background(), r, unpacker, mountPath)
@@ -881,17 +1217,26 @@ func genericVFSBackupUnpack(d Driver, sysOS *sys.OS, vol Volume, snapshots []str
 		return nil, nil, err
 	}
 
-	if volExists {
-		return nil, nil, errors.New("Cannot restore volume, already exists on target")
-	}
+	if parent != "" {
+		if !volExists {
+			return nil, nil, errors.New("Cannot apply incremental backup, volume doesn't exist on target")
+		}
 
-	// Create new empty volume.
-	err = d.CreateVolume(vol, nil, nil)
-	if err != nil {
-		return nil, nil, err
-	}
+		// Undo any partially applied changes.
+		reverter.Add(func() { _ = d.RestoreVolume(vol, parent, op) })
+	} else {
+		if volExists {
+			return nil, nil, errors.New("Cannot restore volume, already exists on target")
+		}
 
-	reverter.Add(func() { _ = d.DeleteVolume(vol, op) })
+		// Create new empty volume.
+		err = d.CreateVolume(vol, nil, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		reverter.Add(func() { _ = d.DeleteVolume(vol, op) })
+	}
 
 	if len(snapshots) > 0 {
 		// Create new snapshots directory.
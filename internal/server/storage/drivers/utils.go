@@ -654,6 +654,78 @@ func copyDevice(inputPath string, outputPath string) error {
 	return nil
 }
 
+// reflinkCheck returns whether the filesystem backing the given directory supports copy-on-write clones (FICLONE).
+func reflinkCheck(path string) (bool, error) {
+	src, err := os.CreateTemp(path, ".incus_reflink_")
+	if err != nil {
+		return false, err
+	}
+
+	defer func() {
+		_ = src.Close()
+		_ = os.Remove(src.Name())
+	}()
+
+	_, err = src.Write(make([]byte, 4096))
+	if err != nil {
+		return false, err
+	}
+
+	dst, err := os.CreateTemp(path, ".incus_reflink_")
+	if err != nil {
+		return false, err
+	}
+
+	defer func() {
+		_ = dst.Close()
+		_ = os.Remove(dst.Name())
+	}()
+
+	err = unix.IoctlFileClone(int(dst.Fd()), int(src.Fd()))
+	if err != nil {
+		if errors.Is(err, unix.EOPNOTSUPP) || errors.Is(err, unix.EINVAL) || errors.Is(err, unix.ENOTTY) || errors.Is(err, unix.EXDEV) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+// reflinkCopy copies the content of the source directory into the target directory using copy-on-write
+// clones for all files. Fails if the underlying filesystem doesn't support reflinks.
+// If allowInconsistent is true, files vanishing during the copy (e.g. from a running instance) are ignored.
+func reflinkCopy(srcPath string, targetPath string, allowInconsistent bool) error {
+	_, err := subprocess.RunCommandCLocale("cp", "-a", "--reflink=always", fmt.Sprintf("%s/.", srcPath), targetPath)
+	if err != nil {
+		if allowInconsistent && reflinkOnlyVanished(err) {
+			return nil
+		}
+
+		return fmt.Errorf("Failed to clone %q to %q: %w", srcPath, targetPath, err)
+	}
+
+	return nil
+}
+
+// reflinkOnlyVanished returns whether the failed copy only reported files which no longer exist.
+func reflinkOnlyVanished(err error) bool {
+	var runErr subprocess.RunError
+	if !errors.As(err, &runErr) || runErr.StdErr() == nil {
+		return false
+	}
+
+	lines := strings.Split(strings.TrimSpace(runErr.StdErr().String()), "\n")
+	for _, line := range lines {
+		if !strings.HasSuffix(line, "No such file or directory") {
+			return false
+		}
+	}
+
+	return true
+}
+
 // loopFilePath returns the loop file path for a storage pool.
 func loopFilePath(poolName string) string {
 	return filepath.Join(internalUtil.VarPath("disks"), fmt.Sprintf("%s.img", poolName))
@@ -0,0 +1,251 @@
+package drivers
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+// backupDeltaBlockSize is the size of the blocks compared when generating a block volume delta.
+const backupDeltaBlockSize = 64 * 1024
+
+// backupDeltaDeletedExtension extension used for the list of files removed since the parent of an incremental backup.
+const backupDeltaDeletedExtension = "deleted"
+
+// backupDeltaBlockExtension extension used for block volume deltas in incremental backups.
+const backupDeltaBlockExtension = "img.delta"
+
+// writeBlockDelta writes the blocks of src which differ from parent to w.
+// The delta starts with the size of src, followed by a record for each changed block made of the block's
+// offset, its length and its content.
+func writeBlockDelta(w io.Writer, src io.ReaderAt, srcSize int64, parent io.ReaderAt, parentSize int64) error {
+	header := make([]byte, 8)
+	binary.BigEndian.PutUint64(header, uint64(srcSize))
+
+	_, err := w.Write(header)
+	if err != nil {
+		return err
+	}
+
+	srcBuf := make([]byte, backupDeltaBlockSize)
+	parentBuf := make([]byte, backupDeltaBlockSize)
+	record := make([]byte, 12)
+
+	for offset := int64(0); offset < srcSize; offset += backupDeltaBlockSize {
+		length := min(int64(backupDeltaBlockSize), srcSize-offset)
+
+		_, err := src.ReadAt(srcBuf[:length], offset)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("Failed reading block at offset %d: %w", offset, err)
+		}
+
+		// Skip blocks which are unchanged since the parent.
+		if offset+length <= parentSize {
+			_, err := parent.ReadAt(parentBuf[:length], offset)
+			if err != nil && !errors.Is(err, io.EOF) {
+				return fmt.Errorf("Failed reading parent block at offset %d: %w", offset, err)
+			}
+
+			if bytes.Equal(srcBuf[:length], parentBuf[:length]) {
+				continue
+			}
+		}
+
+		binary.BigEndian.PutUint64(record[0:8], uint64(offset))
+		binary.BigEndian.PutUint32(record[8:12], uint32(length))
+
+		_, err = w.Write(record)
+		if err != nil {
+			return err
+		}
+
+		_, err = w.Write(srcBuf[:length])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyBlockDelta applies a delta generated by writeBlockDelta to dst.
+// The resize function is called with the size recorded in the delta before any block gets written.
+func applyBlockDelta(r io.Reader, resize func(size int64) error, dst io.WriterAt) error {
+	header := make([]byte, 8)
+
+	_, err := io.ReadFull(r, header)
+	if err != nil {
+		return fmt.Errorf("Failed reading delta header: %w", err)
+	}
+
+	size := int64(binary.BigEndian.Uint64(header))
+
+	err = resize(size)
+	if err != nil {
+		return err
+	}
+
+	buf := make([]byte, backupDeltaBlockSize)
+	record := make([]byte, 12)
+
+	for {
+		_, err := io.ReadFull(r, record)
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("Failed reading delta record: %w", err)
+		}
+
+		offset := int64(binary.BigEndian.Uint64(record[0:8]))
+		length := int64(binary.BigEndian.Uint32(record[8:12]))
+		if offset < 0 || length > backupDeltaBlockSize || offset+length > size {
+			return fmt.Errorf("Invalid delta record for offset %d and length %d", offset, length)
+		}
+
+		_, err = io.ReadFull(r, buf[:length])
+		if err != nil {
+			return fmt.Errorf("Failed reading delta block at offset %d: %w", offset, err)
+		}
+
+		_, err = dst.WriteAt(buf[:length], offset)
+		if err != nil {
+			return fmt.Errorf("Failed writing block at offset %d: %w", offset, err)
+		}
+	}
+}
+
+// discardWriterAt is an io.WriterAt discarding everything written to it.
+type discardWriterAt struct{}
+
+func (discardWriterAt) WriteAt(p []byte, off int64) (int, error) {
+	return len(p), nil
+}
+
+// checkBlockDelta reads a delta generated by writeBlockDelta, checking that all its records are valid.
+func checkBlockDelta(r io.Reader) error {
+	return applyBlockDelta(r, func(size int64) error { return nil }, discardWriterAt{})
+}
+
+// fileUnchanged returns whether a file is the same as its parent counterpart, using the same quick check as
+// rsync (type, permissions, ownership, size and modification time).
+func fileUnchanged(fi os.FileInfo, parentFi os.FileInfo) bool {
+	if fi.Mode() != parentFi.Mode() || fi.Size() != parentFi.Size() || !fi.ModTime().Equal(parentFi.ModTime()) {
+		return false
+	}
+
+	stat, ok := fi.Sys().(*syscall.Stat_t)
+	parentStat, parentOk := parentFi.Sys().(*syscall.Stat_t)
+	if ok && parentOk && (stat.Uid != parentStat.Uid || stat.Gid != parentStat.Gid) {
+		return false
+	}
+
+	return true
+}
+
+// listDeletedFiles returns the paths (relative to parentPath) which exist in parentPath but not in path.
+// Paths which changed type are also included so that they can be replaced on restore.
+func listDeletedFiles(path string, parentPath string, exclude ...string) ([]string, error) {
+	deleted := []string{}
+
+	err := filepath.Walk(parentPath, func(parentFilePath string, parentFi os.FileInfo, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+
+			return err
+		}
+
+		if parentFilePath == parentPath {
+			return nil
+		}
+
+		relPath := strings.TrimPrefix(parentFilePath, parentPath)
+		if util.StringHasPrefix(filepath.Join(path, relPath), exclude...) {
+			return nil
+		}
+
+		fi, err := os.Lstat(filepath.Join(path, relPath))
+		if err == nil && fi.Mode().Type() == parentFi.Mode().Type() {
+			return nil
+		}
+
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+
+		deleted = append(deleted, relPath)
+
+		// No need to look into a directory which is gone.
+		if parentFi.IsDir() {
+			return filepath.SkipDir
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return deleted, nil
+}
+
+// removeDeletedFiles removes the paths listed by listDeletedFiles from path.
+// As the list comes from the backup and the volume content is controlled by its users, the paths are resolved
+// within path without following any symlink, rejecting those which go through one.
+func removeDeletedFiles(path string, deleted []string) error {
+	root, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = root.Close() }()
+
+	for _, relPath := range deleted {
+		relPath = filepath.Clean(string(filepath.Separator) + relPath)
+		if relPath == string(filepath.Separator) {
+			continue
+		}
+
+		dir, name := filepath.Split(relPath)
+
+		fd, err := unix.Openat2(int(root.Fd()), "."+dir, &unix.OpenHow{
+			Flags:   unix.O_PATH | unix.O_DIRECTORY | unix.O_CLOEXEC,
+			Resolve: unix.RESOLVE_IN_ROOT | unix.RESOLVE_NO_SYMLINKS | unix.RESOLVE_NO_MAGICLINKS | unix.RESOLVE_NO_XDEV,
+		})
+		if err != nil {
+			// Nothing to remove if the parent is already gone.
+			if errors.Is(err, unix.ENOENT) {
+				continue
+			}
+
+			if errors.Is(err, unix.ELOOP) || errors.Is(err, unix.ENOTDIR) || errors.Is(err, unix.EXDEV) {
+				return fmt.Errorf("Refusing to remove %q as its path goes through a symlink or mount", relPath)
+			}
+
+			return fmt.Errorf("Failed opening parent of %q: %w", relPath, err)
+		}
+
+		// Remove through the parent directory handle, RemoveAll doesn't follow the symlinks below it.
+		err = os.RemoveAll(filepath.Join(fmt.Sprintf("/proc/self/fd/%d", fd), name))
+		_ = unix.Close(fd)
+		if err != nil {
+			return fmt.Errorf("Failed removing %q: %w", relPath, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package drivers
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memWriterAt is a growable in-memory io.WriterAt.
+type memWriterAt struct {
+	buf []byte
+}
+
+func (m *memWriterAt) WriteAt(p []byte, off int64) (int, error) {
+	if int(off)+len(p) > len(m.buf) {
+		m.buf = append(m.buf, make([]byte, int(off)+len(p)-len(m.buf))...)
+	}
+
+	copy(m.buf[off:], p)
+	return len(p), nil
+}
+
+// Test writeBlockDelta and applyBlockDelta.
+func TestBlockDelta(t *testing.T) {
+	parent := bytes.Repeat([]byte{1}, 3*backupDeltaBlockSize)
+
+	// Change the second block and grow the volume by half a block.
+	src := append([]byte{}, parent...)
+	src[backupDeltaBlockSize+10] = 2
+	src = append(src, bytes.Repeat([]byte{3}, backupDeltaBlockSize/2)...)
+
+	var delta bytes.Buffer
+	err := writeBlockDelta(&delta, bytes.NewReader(src), int64(len(src)), bytes.NewReader(parent), int64(len(parent)))
+	require.NoError(t, err)
+
+	// The delta is valid, but not once truncated.
+	require.NoError(t, checkBlockDelta(bytes.NewReader(delta.Bytes())))
+	assert.Error(t, checkBlockDelta(bytes.NewReader(delta.Bytes()[:delta.Len()-1])))
+
+	// Header, two records and two blocks (one full and one partial).
+	assert.Equal(t, 8+2*12+backupDeltaBlockSize+backupDeltaBlockSize/2, delta.Len())
+
+	dst := &memWriterAt{buf: append([]byte{}, parent...)}
+	var newSize int64
+	err = applyBlockDelta(&delta, func(size int64) error {
+		newSize = size
+		return nil
+	}, dst)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(len(src)), newSize)
+	assert.Equal(t, src, dst.buf)
+}
+
+// Test fileUnchanged and listDeletedFiles.
+func TestDeletedFiles(t *testing.T) {
+	parentPath := t.TempDir()
+	path := t.TempDir()
+
+	mtime := time.Now().Add(-time.Hour)
+	for _, root := range []string{parentPath, path} {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, "dir", "sub"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, "same"), []byte("same"), 0o644))
+		require.NoError(t, os.Chtimes(filepath.Join(root, "same"), mtime, mtime))
+	}
+
+	require.NoError(t, os.WriteFile(filepath.Join(parentPath, "removed"), []byte("removed"), 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(parentPath, "olddir", "sub"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(parentPath, "retyped"), []byte("file"), 0o644))
+	require.NoError(t, os.Mkdir(filepath.Join(path, "retyped"), 0o755))
+
+	fi, err := os.Lstat(filepath.Join(path, "same"))
+	require.NoError(t, err)
+
+	parentFi, err := os.Lstat(filepath.Join(parentPath, "same"))
+	require.NoError(t, err)
+
+	assert.True(t, fileUnchanged(fi, parentFi))
+
+	require.NoError(t, os.WriteFile(filepath.Join(path, "same"), []byte("diff"), 0o644))
+	fi, err = os.Lstat(filepath.Join(path, "same"))
+	require.NoError(t, err)
+
+	assert.False(t, fileUnchanged(fi, parentFi))
+
+	deleted, err := listDeletedFiles(path, parentPath)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"/olddir", "/removed", "/retyped"}, deleted)
+
+	// Apply the list to a copy of the parent.
+	require.NoError(t, removeDeletedFiles(parentPath, append(deleted, "/../escape", "/")))
+	assert.NoDirExists(t, filepath.Join(parentPath, "olddir"))
+	assert.NoFileExists(t, filepath.Join(parentPath, "removed"))
+	assert.DirExists(t, filepath.Join(parentPath, "dir", "sub"))
+
+	// Paths going through a symlink are rejected, leaving the symlink target alone.
+	outside := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(outside, "file"), []byte("file"), 0o644))
+	require.NoError(t, os.Symlink(outside, filepath.Join(parentPath, "link")))
+
+	assert.Error(t, removeDeletedFiles(parentPath, []string{"/link/file"}))
+	assert.FileExists(t, filepath.Join(outside, "file"))
+
+	// Removing the symlink itself only removes the symlink.
+	require.NoError(t, removeDeletedFiles(parentPath, []string{"/link", "/missing/file"}))
+	assert.NoFileExists(t, filepath.Join(parentPath, "link"))
+	assert.FileExists(t, filepath.Join(outside, "file"))
+}
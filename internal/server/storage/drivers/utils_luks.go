@@ -0,0 +1,253 @@
+package drivers
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	internalUtil "github.com/lxc/incus/v6/internal/util"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/subprocess"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+// luksHeaderSize is the space reserved for the LUKS header at the start of encrypted volumes.
+// Encrypted volumes are grown by this amount so that their usable size matches the configured size.
+const luksHeaderSize = 16 * 1024 * 1024
+
+// luksKeySize is the size of the keys generated for the server-side key store.
+const luksKeySize = 64
+
+// luksKeyStorePath returns the path of the server-side key store.
+func luksKeyStorePath() string {
+	return internalUtil.VarPath("security", "storage")
+}
+
+// luksValidateKMS validates the name of a key management plugin.
+func luksValidateKMS(value string) error {
+	if value == "" {
+		return nil
+	}
+
+	if value != filepath.Base(value) || value == "." || value == ".." {
+		return fmt.Errorf("Invalid key management plugin name %q", value)
+	}
+
+	return nil
+}
+
+// luksValidateKeyStore checks that the key of an encrypted volume can be made available to all the servers
+// using the pool. The server-side key store is local to each server so remote pools need a key management plugin.
+func (d *common) luksValidateKeyStore(vol Volume, remote bool) error {
+	if !remote || vol.config["block.encryption"] == "" || d.config["encryption.kms"] != "" {
+		return nil
+	}
+
+	return errors.New(`Encrypted volumes on remote storage pools require a key management plugin to be set in "encryption.kms"`)
+}
+
+// DeletePoolEncryptionKey removes the key of the pool from the server-side key store, if any.
+func DeletePoolEncryptionKey(poolName string) error {
+	err := os.Remove(filepath.Join(luksKeyStorePath(), fmt.Sprintf("%s.key", poolName)))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("Failed deleting encryption key of pool %q: %w", poolName, err)
+	}
+
+	return nil
+}
+
+// luksSizeBytes returns the size of the backing device needed to store sizeBytes of data in the volume.
+func luksSizeBytes(vol Volume, sizeBytes int64) int64 {
+	if !vol.IsEncrypted() || sizeBytes <= 0 {
+		return sizeBytes
+	}
+
+	return sizeBytes + luksHeaderSize
+}
+
+// luksKey returns the key used to unlock the encrypted volumes of the pool.
+// When a key management plugin is configured through "encryption.kms", the key is retrieved from it.
+// Otherwise the key is read from the server-side key store and, if create is true, generated if missing.
+func (d *common) luksKey(create bool) ([]byte, error) {
+	kms := d.config["encryption.kms"]
+	if kms != "" {
+		err := luksValidateKMS(kms)
+		if err != nil {
+			return nil, err
+		}
+
+		var key bytes.Buffer
+		err = subprocess.RunCommandWithFds(context.TODO(), nil, &key, filepath.Join(luksKeyStorePath(), "kms", kms), "get", d.name)
+		if err != nil {
+			return nil, fmt.Errorf("Failed retrieving encryption key of pool %q from %q: %w", d.name, kms, err)
+		}
+
+		if key.Len() == 0 {
+			return nil, fmt.Errorf("Key management plugin %q returned an empty key for pool %q", kms, d.name)
+		}
+
+		return key.Bytes(), nil
+	}
+
+	keyPath := filepath.Join(luksKeyStorePath(), fmt.Sprintf("%s.key", d.name))
+
+	key, err := os.ReadFile(keyPath)
+	if err == nil {
+		return key, nil
+	}
+
+	if !create || !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("Failed reading encryption key of pool %q: %w", d.name, err)
+	}
+
+	err = os.MkdirAll(luksKeyStorePath(), 0o700)
+	if err != nil {
+		return nil, fmt.Errorf("Failed creating key store: %w", err)
+	}
+
+	key = make([]byte, luksKeySize)
+	_, err = rand.Read(key)
+	if err != nil {
+		return nil, fmt.Errorf("Failed generating encryption key: %w", err)
+	}
+
+	f, err := os.OpenFile(keyPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		if errors.Is(err, fs.ErrExist) {
+			// Another volume was created concurrently, use its key.
+			return d.luksKey(false)
+		}
+
+		return nil, fmt.Errorf("Failed creating encryption key of pool %q: %w", d.name, err)
+	}
+
+	defer func() { _ = f.Close() }()
+
+	_, err = f.Write(key)
+	if err != nil {
+		return nil, fmt.Errorf("Failed writing encryption key of pool %q: %w", d.name, err)
+	}
+
+	err = f.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	d.logger.Info("Generated volume encryption key", logger.Ctx{"path": keyPath})
+
+	return key, nil
+}
+
+// luksMapperName returns the device mapper name used for the cleartext device of an encrypted volume.
+func (d *common) luksMapperName(vol Volume) string {
+	hash := sha256.Sum256(fmt.Appendf(nil, "%s/%s/%s/%s", d.name, vol.volType, vol.contentType, vol.name))
+	return fmt.Sprintf("incus-luks-%x", hash[:16])
+}
+
+// luksDevPath returns the path of the cleartext device of an encrypted volume.
+func (d *common) luksDevPath(vol Volume) string {
+	return filepath.Join("/dev/mapper", d.luksMapperName(vol))
+}
+
+// luksDiskPath returns the path of the cleartext device of an opened encrypted volume.
+func (d *common) luksDiskPath(vol Volume) (string, error) {
+	devPath := d.luksDevPath(vol)
+	if !util.PathExists(devPath) {
+		return "", fmt.Errorf("Encrypted volume %q isn't opened: %w", vol.name, os.ErrNotExist)
+	}
+
+	return devPath, nil
+}
+
+// luksFormat sets up encryption on the block device or file at devPath and opens it.
+// Returns the path of the cleartext device.
+func (d *common) luksFormat(vol Volume, devPath string) (string, error) {
+	key, err := d.luksKey(true)
+	if err != nil {
+		return "", err
+	}
+
+	// Use a fixed data offset so the space used by the header is known in advance.
+	offset := fmt.Sprintf("%d", luksHeaderSize/512)
+	err = subprocess.RunCommandWithFds(context.TODO(), bytes.NewReader(key), nil, "cryptsetup", "luksFormat", "--batch-mode", "--type", "luks2", "--offset", offset, "--key-file", "-", devPath)
+	if err != nil {
+		return "", fmt.Errorf("Failed setting up encryption of volume %q: %w", vol.name, err)
+	}
+
+	d.logger.Debug("Formatted encrypted volume", logger.Ctx{"volName": vol.name, "dev": devPath})
+
+	return d.luksOpen(vol, devPath, false)
+}
+
+// luksOpen opens the encrypted volume stored on the block device or file at devPath if not already
+// opened. Returns the path of the cleartext device.
+func (d *common) luksOpen(vol Volume, devPath string, readOnly bool) (string, error) {
+	mappedPath := d.luksDevPath(vol)
+	if util.PathExists(mappedPath) {
+		return mappedPath, nil
+	}
+
+	key, err := d.luksKey(false)
+	if err != nil {
+		return "", err
+	}
+
+	args := []string{"open", "--type", "luks", "--allow-discards", "--key-file", "-"}
+	if readOnly {
+		args = append(args, "--readonly")
+	}
+
+	args = append(args, devPath, d.luksMapperName(vol))
+
+	err = subprocess.RunCommandWithFds(context.TODO(), bytes.NewReader(key), nil, "cryptsetup", args...)
+	if err != nil {
+		return "", fmt.Errorf("Failed opening encrypted volume %q: %w", vol.name, err)
+	}
+
+	d.logger.Debug("Opened encrypted volume", logger.Ctx{"volName": vol.name, "dev": devPath, "path": mappedPath})
+
+	return mappedPath, nil
+}
+
+// luksClose closes the cleartext device of an encrypted volume if opened.
+// Returns true if the device was closed, false if it wasn't opened.
+func (d *common) luksClose(vol Volume) (bool, error) {
+	if !util.PathExists(d.luksDevPath(vol)) {
+		return false, nil
+	}
+
+	// Keep trying to close a few times in case the device is still being flushed.
+	_, err := subprocess.TryRunCommand("cryptsetup", "close", d.luksMapperName(vol))
+	if err != nil {
+		return false, fmt.Errorf("Failed closing encrypted volume %q: %w", vol.name, err)
+	}
+
+	d.logger.Debug("Closed encrypted volume", logger.Ctx{"volName": vol.name})
+
+	return true, nil
+}
+
+// luksResize grows the cleartext device of an opened encrypted volume to match its backing device.
+func (d *common) luksResize(vol Volume) error {
+	if !util.PathExists(d.luksDevPath(vol)) {
+		return nil
+	}
+
+	key, err := d.luksKey(false)
+	if err != nil {
+		return err
+	}
+
+	err = subprocess.RunCommandWithFds(context.TODO(), bytes.NewReader(key), nil, "cryptsetup", "resize", "--key-file", "-", d.luksMapperName(vol))
+	if err != nil {
+		return fmt.Errorf("Failed resizing encrypted volume %q: %w", vol.name, err)
+	}
+
+	return nil
+}
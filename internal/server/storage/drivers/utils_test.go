@@ -1,9 +1,16 @@
 package drivers
 
 import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/shared/subprocess"
 )
 
 // Test GetVolumeMountPath.
@@ -35,3 +42,102 @@ func TestGetVolumeMountPath(t *testing.T) {
 	expected = GetPoolMountPath(poolName) + "/virtual-machines/testvol"
 	assert.Equal(t, expected, path)
 }
+
+// Test reflinkCheck.
+func TestReflinkCheck(t *testing.T) {
+	dir := t.TempDir()
+
+	// The result depends on the filesystem, but the check must not fail nor leave files behind.
+	_, err := reflinkCheck(dir)
+	require.NoError(t, err)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	// Missing directory.
+	_, err = reflinkCheck(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
+
+// Test reflinkCopy.
+func TestReflinkCopy(t *testing.T) {
+	srcPath := t.TempDir()
+	targetPath := t.TempDir()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(srcPath, "sub"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(srcPath, "file"), []byte("data"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(srcPath, "sub", "nested"), []byte("nested"), 0o644))
+	require.NoError(t, os.Symlink("file", filepath.Join(srcPath, "link")))
+
+	supported, err := reflinkCheck(targetPath)
+	require.NoError(t, err)
+
+	err = reflinkCopy(srcPath, targetPath, false)
+	if !supported {
+		// Copies must never silently fall back to regular copies.
+		assert.Error(t, err)
+		return
+	}
+
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(targetPath, "file"))
+	require.NoError(t, err)
+	assert.Equal(t, "data", string(data))
+
+	data, err = os.ReadFile(filepath.Join(targetPath, "sub", "nested"))
+	require.NoError(t, err)
+	assert.Equal(t, "nested", string(data))
+
+	fi, err := os.Lstat(filepath.Join(targetPath, "file"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), fi.Mode().Perm())
+
+	target, err := os.Readlink(filepath.Join(targetPath, "link"))
+	require.NoError(t, err)
+	assert.Equal(t, "file", target)
+
+	// The clone is independent from its source.
+	require.NoError(t, os.WriteFile(filepath.Join(targetPath, "file"), []byte("changed"), 0o600))
+
+	data, err = os.ReadFile(filepath.Join(srcPath, "file"))
+	require.NoError(t, err)
+	assert.Equal(t, "data", string(data))
+}
+
+// Test reflinkOnlyVanished.
+func TestReflinkOnlyVanished(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		expect bool
+	}{
+		{
+			name:   "Vanished files",
+			err:    subprocess.NewRunError("cp", nil, errors.New("exit status 1"), nil, bytes.NewBufferString("cp: cannot stat '/src/./a': No such file or directory\ncp: cannot stat '/src/./b': No such file or directory\n")),
+			expect: true,
+		},
+		{
+			name:   "Other failure",
+			err:    subprocess.NewRunError("cp", nil, errors.New("exit status 1"), nil, bytes.NewBufferString("cp: cannot stat '/src/./a': No such file or directory\ncp: failed to clone '/dst/b': Operation not supported\n")),
+			expect: false,
+		},
+		{
+			name:   "No output",
+			err:    subprocess.NewRunError("cp", nil, errors.New("exit status 1"), nil, &bytes.Buffer{}),
+			expect: false,
+		},
+		{
+			name:   "Not a command failure",
+			err:    errors.New("No such file or directory"),
+			expect: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expect, reflinkOnlyVanished(tt.err))
+		})
+	}
+}
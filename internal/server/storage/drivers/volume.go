@@ -427,11 +427,41 @@ func (v Volume) SnapshotsMatch(snapNames []string, op *operations.Operation) err
 	return nil
 }
 
+// SnapshotsExist checks that all of the snapshot names provided exist in storage.
+// Unlike SnapshotsMatch, other snapshots may also exist in storage.
+func (v Volume) SnapshotsExist(snapNames []string, op *operations.Operation) error {
+	if v.IsSnapshot() {
+		return errors.New("Volume is a snapshot")
+	}
+
+	snapshots, err := v.driver.VolumeSnapshots(v, op)
+	if err != nil {
+		return err
+	}
+
+	for _, snapName := range snapNames {
+		if !slices.Contains(snapshots, snapName) {
+			return fmt.Errorf("Snapshot %q expected but not in storage", snapName)
+		}
+	}
+
+	return nil
+}
+
 // IsBlockBacked indicates whether storage device is block backed.
 func (v Volume) IsBlockBacked() bool {
 	return v.driver.isBlockBacked(v) || v.mountFilesystemProbe
 }
 
+// IsEncrypted indicates whether the volume's block device or file is encrypted.
+func (v Volume) IsEncrypted() bool {
+	if v.config["block.encryption"] == "" {
+		return false
+	}
+
+	return v.driver.isBlockBacked(v) || v.contentType == ContentTypeBlock
+}
+
 // Type returns the volume type.
 func (v Volume) Type() VolumeType {
 	return v.volType
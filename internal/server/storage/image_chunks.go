@@ -0,0 +1,307 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/lxc/incus/v6/internal/chunkstore"
+	"github.com/lxc/incus/v6/internal/server/locking"
+	internalUtil "github.com/lxc/incus/v6/internal/util"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/revert"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+// ImageChunksDir is the name of the directory holding the image chunk store inside the images directory.
+const ImageChunksDir = "chunks"
+
+// imageChunkIndexSuffix is the suffix of the chunk index replacing an image file in the images directory.
+const imageChunkIndexSuffix = ".idx"
+
+// imageChunksMu prevents pruning the chunk store while image files are added to it or rebuilt from it.
+var imageChunksMu sync.RWMutex
+
+// imageFilesMu protects imageFilesUsers.
+var imageFilesMu sync.Mutex
+
+// imageFilesUsers counts the users of the image files that were rebuilt from the chunk store.
+// Changes are made with the lock of the image held.
+var imageFilesUsers = map[string]int{}
+
+// imageFileLock locks the files of an image.
+func imageFileLock(fingerprint string) (locking.UnlockFunc, error) {
+	return locking.Lock(context.TODO(), fmt.Sprintf("image_file_%s", fingerprint))
+}
+
+// imageFileUsersAdd changes the number of users of the image files and returns the new value.
+func imageFileUsersAdd(fingerprint string, delta int) int {
+	imageFilesMu.Lock()
+	defer imageFilesMu.Unlock()
+
+	imageFilesUsers[fingerprint] += delta
+	users := imageFilesUsers[fingerprint]
+	if users <= 0 {
+		delete(imageFilesUsers, fingerprint)
+	}
+
+	return users
+}
+
+// imageChunkStore returns the chunk store holding the image files.
+func imageChunkStore() *chunkstore.Store {
+	return chunkstore.New(internalUtil.VarPath("images", ImageChunksDir))
+}
+
+// imageFileNames returns the paths of the (potential) files of an image.
+func imageFileNames(fingerprint string) []string {
+	path := internalUtil.VarPath("images", fingerprint)
+
+	return []string{path, path + ".rootfs"}
+}
+
+// ImageFileExists returns whether the files of an image are available locally, either as is or in the chunk store.
+func ImageFileExists(fingerprint string) bool {
+	path := internalUtil.VarPath("images", fingerprint)
+
+	return util.PathExists(path) || util.PathExists(path+imageChunkIndexSuffix)
+}
+
+// ImageFileMaterialize makes sure that the files of an image are present in the images directory, rebuilding
+// them from the chunk store if needed. The returned function must be called once the files aren't needed anymore.
+func ImageFileMaterialize(fingerprint string) (revert.Hook, error) {
+	unlock, err := imageFileLock(fingerprint)
+	if err != nil {
+		return nil, err
+	}
+
+	defer unlock()
+
+	imageChunksMu.RLock()
+	defer imageChunksMu.RUnlock()
+
+	if imageFileUsersAdd(fingerprint, 0) == 0 {
+		for _, path := range imageFileNames(fingerprint) {
+			if util.PathExists(path) || !util.PathExists(path+imageChunkIndexSuffix) {
+				continue
+			}
+
+			err = imageFileExport(path)
+			if err != nil {
+				imageFileCleanup(fingerprint)
+				return nil, fmt.Errorf("Failed rebuilding image file %q from chunk store: %w", filepath.Base(path), err)
+			}
+		}
+	}
+
+	imageFileUsersAdd(fingerprint, 1)
+
+	return func() {
+		unlock, err := imageFileLock(fingerprint)
+		if err != nil {
+			logger.Warn("Failed locking image files", logger.Ctx{"fingerprint": fingerprint, "err": err})
+			return
+		}
+
+		defer unlock()
+
+		if imageFileUsersAdd(fingerprint, -1) > 0 {
+			return
+		}
+
+		imageFileCleanup(fingerprint)
+	}, nil
+}
+
+// imageFileCleanup removes the image files which were rebuilt from the chunk store.
+// Must be called with the lock of the image held.
+func imageFileCleanup(fingerprint string) {
+	for _, path := range imageFileNames(fingerprint) {
+		if !util.PathExists(path + imageChunkIndexSuffix) {
+			continue
+		}
+
+		err := os.Remove(path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			logger.Warn("Failed removing rebuilt image file", logger.Ctx{"file": path, "err": err})
+		}
+	}
+}
+
+// imageFileExport rebuilds an image file from its chunk index.
+func imageFileExport(path string) error {
+	idx, err := chunkstore.ReadIndex(path + imageChunkIndexSuffix)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path+".tmp", os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	err = imageChunkStore().Export(idx, f)
+	if err != nil {
+		_ = os.Remove(path + ".tmp")
+		return err
+	}
+
+	err = f.Close()
+	if err != nil {
+		_ = os.Remove(path + ".tmp")
+		return err
+	}
+
+	return os.Rename(path+".tmp", path)
+}
+
+// ImageFileChunk moves the files of an image into the chunk store, replacing them with chunk indexes.
+// Images whose files are currently in use are left alone.
+func ImageFileChunk(fingerprint string) error {
+	unlock, err := imageFileLock(fingerprint)
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	imageChunksMu.RLock()
+	defer imageChunksMu.RUnlock()
+
+	if imageFileUsersAdd(fingerprint, 0) > 0 {
+		return nil
+	}
+
+	for _, path := range imageFileNames(fingerprint) {
+		if !util.PathExists(path) {
+			continue
+		}
+
+		// The file was rebuilt from an existing index, simply drop it.
+		if util.PathExists(path + imageChunkIndexSuffix) {
+			err = os.Remove(path)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+
+		idx, err := imageChunkStore().Import(f)
+		_ = f.Close()
+		if err != nil {
+			return fmt.Errorf("Failed adding image file %q to chunk store: %w", filepath.Base(path), err)
+		}
+
+		err = idx.Write(path + imageChunkIndexSuffix)
+		if err != nil {
+			return err
+		}
+
+		err = os.Remove(path)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ImageFileUnchunk rebuilds the files of an image from the chunk store and removes their chunk indexes.
+func ImageFileUnchunk(fingerprint string) error {
+	unlock, err := imageFileLock(fingerprint)
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	imageChunksMu.RLock()
+	defer imageChunksMu.RUnlock()
+
+	for _, path := range imageFileNames(fingerprint) {
+		if !util.PathExists(path + imageChunkIndexSuffix) {
+			continue
+		}
+
+		if !util.PathExists(path) {
+			err = imageFileExport(path)
+			if err != nil {
+				return fmt.Errorf("Failed rebuilding image file %q from chunk store: %w", filepath.Base(path), err)
+			}
+		}
+
+		err = os.Remove(path + imageChunkIndexSuffix)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ImageFileDelete removes the files of an image, as well as their chunk indexes.
+// The chunks themselves are only removed by ImageChunksPrune.
+func ImageFileDelete(fingerprint string) error {
+	unlock, err := imageFileLock(fingerprint)
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	for _, path := range imageFileNames(fingerprint) {
+		for _, name := range []string{path, path + imageChunkIndexSuffix} {
+			err = os.Remove(name)
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return fmt.Errorf("Error deleting image file %q: %w", name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ImageChunksPrune removes the chunks which aren't referenced by any image anymore.
+func ImageChunksPrune() (int, error) {
+	imageChunksMu.Lock()
+	defer imageChunksMu.Unlock()
+
+	entries, err := os.ReadDir(internalUtil.VarPath("images"))
+	if err != nil {
+		return 0, err
+	}
+
+	indexes := []*chunkstore.Index{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), imageChunkIndexSuffix) {
+			continue
+		}
+
+		idx, err := chunkstore.ReadIndex(internalUtil.VarPath("images", entry.Name()))
+		if err != nil {
+			// The image was deleted in the meantime.
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+
+			return 0, err
+		}
+
+		indexes = append(indexes, idx)
+	}
+
+	return imageChunkStore().Prune(indexes)
+}
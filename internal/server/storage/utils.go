@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
+	"net/http"
 	"os"
 	"path/filepath"
 	"slices"
@@ -23,6 +24,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/db/cluster"
 	"github.com/lxc/incus/v6/internal/server/instance"
 	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
+	localMigration "github.com/lxc/incus/v6/internal/server/migration"
 	"github.com/lxc/incus/v6/internal/server/node"
 	"github.com/lxc/incus/v6/internal/server/operations"
 	"github.com/lxc/incus/v6/internal/server/project"
@@ -505,6 +507,25 @@ func poolAndVolumeCommonRules(vol *drivers.Volume) map[string]func(string) error
 		rules["initial.mode"] = validate.Optional(validate.IsInt64)
 	}
 
+	// Scheduled backups are only relevant for custom volumes.
+	if (vol == nil) || (vol != nil && vol.Type() == drivers.VolumeTypeCustom) {
+		rules["backups.schedule"] = validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly", "@never"}))
+		rules["backups.expiry"] = func(value string) error {
+			// Validate expression
+			_, err := internalInstance.GetExpiry(time.Time{}, value)
+			return err
+		}
+
+		rules["backups.retention"] = validate.Optional(validate.IsUint32)
+		rules["backups.target"] = validate.Optional(internalInstance.ValidBackupTarget)
+	}
+
+	// I/O limits are only relevant for custom volumes.
+	if (vol == nil) || (vol != nil && vol.Type() == drivers.VolumeTypeCustom) {
+		rules["limits.iops"] = validate.Optional(validate.IsUint32)
+		rules["limits.bandwidth"] = validate.Optional(validate.IsSize)
+	}
+
 	// security.shared is only relevant for custom block volumes.
 	if (vol == nil) || (vol != nil && vol.Type() == drivers.VolumeTypeCustom && vol.ContentType() == drivers.ContentTypeBlock) {
 		rules["security.shared"] = validate.Optional(validate.IsBool)
@@ -557,9 +578,46 @@ func validateVolumeCommonRules(vol drivers.Volume) map[string]func(string) error
 		rules["volatile.rootfs.size"] = validate.Optional(validate.IsInt64)
 	}
 
+	// Replication is only supported for custom volumes.
+	if vol.Type() == drivers.VolumeTypeCustom {
+		rules["replication.target"] = validate.Optional(ValidReplicationTarget)
+		rules["replication.schedule"] = validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly"}))
+		rules["replication.mode"] = validate.Optional(validate.IsOneOf("incremental", "full"))
+		rules["volatile.replication.last_sync"] = validate.IsAny
+		rules["volatile.replication.status"] = validate.IsAny
+		rules["volatile.replication.source"] = validate.IsAny
+	}
+
 	return rules
 }
 
+// ParseReplicationTarget splits a replication.target value into the name of the replication remote of the
+// server configuration and the name of the target storage pool. The remote is empty when the target is a
+// local storage pool.
+func ParseReplicationTarget(value string) (string, string, error) {
+	if strings.Contains(value, "://") {
+		return "", "", errors.New("Remote server URLs can't be used directly, configure the remote on the server as replication.remotes.<name>.* and use <name>:<pool>")
+	}
+
+	remoteName, poolName, ok := strings.Cut(value, ":")
+	if !ok {
+		remoteName = ""
+		poolName = value
+	}
+
+	if poolName == "" || strings.ContainsAny(poolName, "/:") || (ok && (remoteName == "" || strings.ContainsAny(remoteName, "./"))) {
+		return "", "", fmt.Errorf("Invalid replication target %q, expected <pool> or <remote>:<pool>", value)
+	}
+
+	return remoteName, poolName, nil
+}
+
+// ValidReplicationTarget validates a replication.target value.
+func ValidReplicationTarget(value string) error {
+	_, _, err := ParseReplicationTarget(value)
+	return err
+}
+
 // ImageUnpack unpacks a filesystem image into the destination path.
 // There are several formats that images can come in:
 // Container Format A: Separate metadata tarball and root squashfs file.
@@ -1050,6 +1108,60 @@ func FallbackMigrationType(contentType drivers.ContentType) migration.MigrationF
 	return migration.MigrationFSType_RSYNC
 }
 
+// encryptedVolumeMigrationTypes limits the migration types of an encrypted volume to the non-optimized ones.
+// Optimized transfers copy the data still encrypted with the key of the source pool, which the target only has
+// when both retrieve it from the same key management plugin ("encryption.kms") for the same pool.
+func encryptedVolumeMigrationTypes(pool Pool, vol drivers.Volume, crossPool bool, types []localMigration.Type) []localMigration.Type {
+	if !vol.IsEncrypted() || (!crossPool && pool.Driver().Config()["encryption.kms"] != "") {
+		return types
+	}
+
+	fallback := FallbackMigrationType(vol.ContentType())
+
+	filtered := make([]localMigration.Type, 0, len(types))
+	for _, t := range types {
+		if t.FSType == fallback {
+			filtered = append(filtered, t)
+		}
+	}
+
+	if len(filtered) == 0 {
+		filtered = append(filtered, localMigration.Type{FSType: fallback})
+	}
+
+	return filtered
+}
+
+// VolumeMigrationTypes returns the migration types to offer when sending the volume to another server or
+// cluster member.
+func VolumeMigrationTypes(pool Pool, vol drivers.Volume, copySnapshots bool) []localMigration.Type {
+	// The refresh argument passed to MigrationTypes() is always set to false here.
+	// The migration source/sender doesn't need to care whether or not it's doing a refresh as the migration
+	// sink/receiver will know this, and adjust the migration types accordingly.
+	// The same applies for clusterMove and storageMove, which are set to the most optimized defaults.
+	types := pool.MigrationTypes(vol.ContentType(), false, copySnapshots, true, false)
+
+	return encryptedVolumeMigrationTypes(pool, vol, false, types)
+}
+
+// InstanceMigrationTypes returns the migration types to offer when sending the instance's volume to another
+// server or cluster member.
+func InstanceMigrationTypes(pool Pool, inst instance.Instance, copySnapshots bool) ([]localMigration.Type, error) {
+	volType, err := InstanceTypeToVolumeType(inst.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	dbVol, err := VolumeDBGet(pool, inst.Project().Name, inst.Name(), volType)
+	if err != nil {
+		return nil, err
+	}
+
+	vol := pool.GetVolume(volType, InstanceContentType(inst), project.Instance(inst.Project().Name, inst.Name()), dbVol.Config)
+
+	return VolumeMigrationTypes(pool, vol, copySnapshots), nil
+}
+
 // InstanceMount mounts an instance's storage volume (if not already mounted).
 // Please call InstanceUnmount when finished.
 func InstanceMount(pool Pool, inst instance.Instance, op *operations.Operation) (*MountInfo, error) {
@@ -1225,3 +1337,24 @@ func VolumeSnapshotsToMigrationSnapshots(snapshots []*api.StorageVolumeSnapshot,
 
 	return migrationSnapshots, nil
 }
+
+// SnapshotsSince returns the snapshot names which follow the parent snapshot in the (oldest first) list provided.
+func SnapshotsSince(snapNames []string, parent string) ([]string, error) {
+	index := slices.Index(snapNames, parent)
+	if index < 0 {
+		return nil, fmt.Errorf("Parent snapshot %q not found", parent)
+	}
+
+	return snapNames[index+1:], nil
+}
+
+// InstanceLazyRootfsCheck returns an error if the root filesystem of the instance is still lazily pulled from an
+// OCI registry. The instance volume then only holds the changes made on top of the image layers, so it can't be
+// snapshotted, backed up, copied or moved until all the layers were fetched and the instance restarted.
+func InstanceLazyRootfsCheck(inst instance.Instance) error {
+	if inst.LocalConfig()["volatile.container.oci.lazy"] == "" {
+		return nil
+	}
+
+	return api.StatusErrorf(http.StatusBadRequest, "Instance %q has a lazily pulled root filesystem, wait for all the image layers to be fetched and restart it first", inst.Name())
+}
@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReplicationTarget(t *testing.T) {
+	tests := []struct {
+		name       string
+		value      string
+		wantRemote string
+		wantPool   string
+		wantErr    bool
+	}{
+		{name: "local pool", value: "backup", wantPool: "backup"},
+		{name: "remote pool", value: "offsite:backup", wantRemote: "offsite", wantPool: "backup"},
+		{name: "empty", value: "", wantErr: true},
+		{name: "local pool with slash", value: "backup/vol", wantErr: true},
+		{name: "URL", value: "https://incus.example.net:8443/backup", wantErr: true},
+		{name: "missing remote", value: ":backup", wantErr: true},
+		{name: "missing pool", value: "offsite:", wantErr: true},
+		{name: "remote with dot", value: "off.site:backup", wantErr: true},
+		{name: "nested pool path", value: "offsite:backup/vol", wantErr: true},
+		{name: "too many separators", value: "offsite:backup:vol", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remote, poolName, err := ParseReplicationTarget(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantRemote, remote)
+			assert.Equal(t, tt.wantPool, poolName)
+		})
+	}
+}
@@ -495,6 +495,31 @@ var APIExtensions = []string{
 	"server_logging_webhook",
 	"storage_driver_truenas",
 	"container_disk_tmpfs",
+	"metrics_push",
+	"server_logging_otlp",
+	"server_logging_nats",
+	"event_journal",
+	"instances_placement_scriptlet_ranking",
+	"instances_placement_rules",
+	"cluster_rolling_restart",
+	"container_live_migration_fallback",
+	"backup_incremental",
+	"backup_scheduled",
+	"storage_driver_nfs",
+	"storage_dir_reflink",
+	"storage_volume_replication",
+	"storage_volume_limits",
+	"storage_volume_encryption",
+	"storage_pool_usage_alerts",
+	"images_chunk_store",
+	"image_publish_oci",
+	"image_import_oci",
+	"images_oci_lazy_pull",
+	"network_load_balancer_bridge",
+	"network_zone_dns_query",
+	"network_zone_dns_update",
+	"network_type_wireguard",
+	"network_bridge_evpn",
 }
 
 // APIExtensionsCount returns the number of available API extensions.
@@ -256,6 +256,86 @@ type ClusterMemberStatePost struct {
 	Mode string `json:"mode" yaml:"mode"`
 }
 
+// ClusterRollingRestartPost represents the fields required to start a rolling restart of the cluster.
+//
+// swagger:model
+//
+// API extension: cluster_rolling_restart.
+type ClusterRollingRestartPost struct {
+	// How the members get restarted. Valid modes are "restart" (restarted by the server)
+	// and "upgrade" (upgraded and restarted externally).
+	// Example: restart
+	Mode string `json:"mode" yaml:"mode"`
+
+	// The cluster members to process, in order (defaults to all members)
+	// Example: ["server01", "server02"]
+	Members []string `json:"members" yaml:"members"`
+
+	// Override the configured evacuation mode.
+	// Example: migrate
+	EvacuateMode string `json:"evacuate_mode" yaml:"evacuate_mode"`
+
+	// How long to wait for each member to come back (in seconds, defaults to 600)
+	// Example: 600
+	Timeout int `json:"timeout" yaml:"timeout"`
+}
+
+// ClusterRollingRestartPut represents the fields required to control a running rolling restart.
+//
+// swagger:model
+//
+// API extension: cluster_rolling_restart.
+type ClusterRollingRestartPut struct {
+	// The action to be performed. Valid actions are "pause", "resume" and "abort".
+	// Example: pause
+	Action string `json:"action" yaml:"action"`
+}
+
+// ClusterRollingRestart represents the progress of a rolling restart.
+//
+// swagger:model
+//
+// API extension: cluster_rolling_restart.
+type ClusterRollingRestart struct {
+	// How the members get restarted
+	// Example: restart
+	Mode string `json:"mode" yaml:"mode"`
+
+	// Whether the rolling restart is paused
+	// Example: false
+	Paused bool `json:"paused" yaml:"paused"`
+
+	// The cluster member currently being processed
+	// Example: server01
+	Current string `json:"current" yaml:"current"`
+
+	// The number of cluster members processed so far
+	// Example: 1
+	Completed int `json:"completed" yaml:"completed"`
+
+	// The per-member progress, in processing order
+	Members []ClusterRollingRestartMember `json:"members" yaml:"members"`
+}
+
+// ClusterRollingRestartMember represents the progress of a rolling restart for a single cluster member.
+//
+// swagger:model
+//
+// API extension: cluster_rolling_restart.
+type ClusterRollingRestartMember struct {
+	// Name of the cluster member
+	// Example: server01
+	Name string `json:"name" yaml:"name"`
+
+	// Current step for the cluster member
+	// Example: evacuating
+	Status string `json:"status" yaml:"status"`
+
+	// Additional information about the current step
+	// Example: Waiting for the member to come back online
+	Message string `json:"message" yaml:"message"`
+}
+
 // ClusterGroupsPost represents the fields available for a new cluster group.
 //
 // swagger:model
@@ -41,6 +41,12 @@ type Event struct {
 	//
 	// API extension: event_project
 	Project string `yaml:"project,omitempty" json:"project,omitempty"`
+
+	// Sequence ID of the event in the server's event journal
+	// Example: 1234
+	//
+	// API extension: event_journal
+	ID uint64 `yaml:"id,omitempty" json:"id,omitempty"`
 }
 
 // ToLogging creates log record for the event.
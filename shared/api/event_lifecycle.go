@@ -119,6 +119,9 @@ const (
 	EventLifecycleStoragePoolCreated                = "storage-pool-created"
 	EventLifecycleStoragePoolDeleted                = "storage-pool-deleted"
 	EventLifecycleStoragePoolUpdated                = "storage-pool-updated"
+	EventLifecycleStoragePoolUsageCritical          = "storage-pool-usage-critical"
+	EventLifecycleStoragePoolUsageResolved          = "storage-pool-usage-resolved"
+	EventLifecycleStoragePoolUsageWarning           = "storage-pool-usage-warning"
 	EventLifecycleStorageVolumeBackupCreated        = "storage-volume-backup-created"
 	EventLifecycleStorageVolumeBackupDeleted        = "storage-volume-backup-deleted"
 	EventLifecycleStorageVolumeBackupRenamed        = "storage-volume-backup-renamed"
@@ -68,6 +68,12 @@ type ImagesPost struct {
 	//
 	// API extension: image_create_aliases
 	Aliases []ImageAlias `json:"aliases" yaml:"aliases"`
+
+	// OCI registry to push the image to (for format "oci")
+	// Example: https://registry.example.com
+	//
+	// API extension: image_publish_oci
+	Target string `json:"target,omitempty" yaml:"target,omitempty"`
 }
 
 // ImagesPostSource represents the source of a new image
@@ -68,6 +68,12 @@ type InstanceBackupsPost struct {
 	//
 	// API extension: backup_s3_upload
 	Target *BackupTarget `json:"target" yaml:"target"`
+
+	// Name of the instance snapshot or backup to take an incremental backup from
+	// Example: snap0
+	//
+	// API extension: backup_incremental
+	Parent string `json:"parent" yaml:"parent"`
 }
 
 // InstanceBackup represents an instance backup.
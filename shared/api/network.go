@@ -188,6 +188,11 @@ type NetworkState struct {
 	//
 	// API extension: network_state_ovn
 	OVN *NetworkStateOVN `json:"ovn" yaml:"ovn"`
+
+	// Additional WireGuard network information
+	//
+	// API extension: network_type_wireguard
+	Wireguard *NetworkStateWireguard `json:"wireguard" yaml:"wireguard"`
 }
 
 // NetworkStateAddress represents a network address
@@ -347,3 +352,22 @@ type NetworkStateOVN struct {
 	// API extension: network_ovn_state_addresses
 	UplinkIPv6 string `json:"uplink_ipv6" yaml:"uplink_ipv6"`
 }
+
+// NetworkStateWireguard represents WireGuard specific state
+//
+// swagger:model
+//
+// API extension: network_type_wireguard.
+type NetworkStateWireguard struct {
+	// WireGuard interface name
+	// Example: incuswg3
+	Interface string `json:"interface" yaml:"interface"`
+
+	// Public key of the WireGuard interface
+	// Example: xTIBA5rboUvnH4htodjb6e697QjLERt1NAB4mZqp8Dg=
+	PublicKey string `json:"public_key" yaml:"public_key"`
+
+	// UDP port the WireGuard interface listens on
+	// Example: 51820
+	ListenPort int `json:"listen_port" yaml:"listen_port"`
+}
@@ -801,6 +801,11 @@ type ResourcesStoragePool struct {
 
 	// Disk inode usage
 	Inodes ResourcesStoragePoolInodes `json:"inodes,omitempty" yaml:"inodes,omitempty"`
+
+	// Metadata space usage (thin pools only)
+	//
+	// API extension: storage_pool_usage_alerts
+	Metadata *ResourcesStoragePoolSpace `json:"metadata,omitempty" yaml:"metadata,omitempty"`
 }
 
 // ResourcesStoragePoolSpace represents the space available to a given storage pool
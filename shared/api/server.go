@@ -1,5 +1,9 @@
 package api
 
+import (
+	"time"
+)
+
 // ServerEnvironment represents the read-only environment fields of a server configuration.
 type ServerEnvironment struct {
 	// List of addresses the server is listening on
@@ -101,6 +105,12 @@ type ServerEnvironment struct {
 	// Example: 1453969
 	ServerPid int `json:"server_pid" yaml:"server_pid"`
 
+	// Time at which the daemon started
+	// Example: 2021-03-23T17:38:37.753398689-04:00
+	//
+	// API extension: cluster_rolling_restart
+	ServerStartTime time.Time `json:"server_start_time" yaml:"server_start_time"`
+
 	// Server version
 	// Example: 4.11
 	ServerVersion string `json:"server_version" yaml:"server_version"`
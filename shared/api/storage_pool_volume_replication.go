@@ -0,0 +1,12 @@
+package api
+
+// StorageVolumeReplicationPost represents the fields required to act on the replication of a storage volume
+//
+// swagger:model
+//
+// API extension: storage_volume_replication.
+type StorageVolumeReplicationPost struct {
+	// Replication action (sync or promote)
+	// Example: sync
+	Action string `json:"action" yaml:"action"`
+}
@@ -593,6 +593,16 @@ func IsPCIAddress(value string) error {
 	return nil
 }
 
+// IsCertificateFingerprint validates whether a value is a full SHA-256 certificate fingerprint.
+func IsCertificateFingerprint(value string) error {
+	match, _ := regexp.MatchString(`^[0-9a-fA-F]{64}$`, value)
+	if !match {
+		return errors.New("Invalid certificate fingerprint, expected 64 hexadecimal characters")
+	}
+
+	return nil
+}
+
 // IsCompressionAlgorithm validates whether a value is a valid compression algorithm and is available on the system.
 func IsCompressionAlgorithm(value string) error {
 	if value == "none" {
@@ -64,6 +64,29 @@ func ExampleIsPCIAddress() {
 	// , false
 }
 
+func ExampleIsCertificateFingerprint() {
+	tests := []string{
+		"2e4ed9f4d01b1f0dc4ad74d5a0c4d1a77fd8d1d6ac5b84b4d20d1b5d2a1e0d9f", // valid
+		"2E4ED9F4D01B1F0DC4AD74D5A0C4D1A77FD8D1D6AC5B84B4D20D1B5D2A1E0D9F", // valid
+		"2e4ed9f4d01b", // too short
+		"2e4ed9f4d01b1f0dc4ad74d5a0c4d1a77fd8d1d6ac5b84b4d20d1b5d2a1e0d9fa", // too long
+		"2e4ed9f4d01b1f0dc4ad74d5a0c4d1a77fd8d1d6ac5b84b4d20d1b5d2a1e0d9g",  // invalid hex
+		"",
+	}
+
+	for _, v := range tests {
+		err := validate.IsCertificateFingerprint(v)
+		fmt.Printf("%s, %t\n", v, err == nil)
+	}
+
+	// Output: 2e4ed9f4d01b1f0dc4ad74d5a0c4d1a77fd8d1d6ac5b84b4d20d1b5d2a1e0d9f, true
+	// 2E4ED9F4D01B1F0DC4AD74D5A0C4D1A77FD8D1D6AC5B84B4D20D1B5D2A1E0D9F, true
+	// 2e4ed9f4d01b, false
+	// 2e4ed9f4d01b1f0dc4ad74d5a0c4d1a77fd8d1d6ac5b84b4d20d1b5d2a1e0d9fa, false
+	// 2e4ed9f4d01b1f0dc4ad74d5a0c4d1a77fd8d1d6ac5b84b4d20d1b5d2a1e0d9g, false
+	// , false
+}
+
 func ExampleOptional() {
 	tests := []string{
 		"",